@@ -2,12 +2,18 @@ import logger from '@/helpers/loggers/logger'
 import contactNumberVerificationRaw from '../assets/sms-templates/message.json'
 
 // Define the types of templates available
-type Templates = 'contactNumber' | 'changePassword'
+type Templates =
+    | 'contactNumber'
+    | 'changePassword'
+    | 'signInCode'
+    | 'signInLink'
 
 // Precompile the Handlebars templates
 const TEMPLATE_MAP: Record<Templates, string> = {
     contactNumber: contactNumberVerificationRaw['ContactNumber'],
     changePassword: contactNumberVerificationRaw['ChangePassword'],
+    signInCode: contactNumberVerificationRaw['SignInCode'],
+    signInLink: contactNumberVerificationRaw['SignInLink'],
 }
 
 /**
@@ -1,17 +1,26 @@
 import otpVerificationRaw from '../assets/email-templates/account-otp-verification.html?raw'
 import accountVerificationRaw from '../assets/email-templates/account-verification.html?raw'
 import accountChangepasswordRaw from '../assets/email-templates/account-change-password.html?raw'
+import accountSignInCodeRaw from '../assets/email-templates/account-signin-code.html?raw'
+import accountSignInLinkRaw from '../assets/email-templates/account-signin-link.html?raw'
 import DOMPurify from 'isomorphic-dompurify'
 import logger from '@/helpers/loggers/logger'
 
 // Define the types of templates available
-type Templates = 'otp' | 'verification' | 'changePassword'
+type Templates =
+    | 'otp'
+    | 'verification'
+    | 'changePassword'
+    | 'signInCode'
+    | 'signInLink'
 
 // Precompile the Handlebars templates
 const TEMPLATE_MAP: Record<Templates, string> = {
     otp: otpVerificationRaw,
     verification: accountVerificationRaw,
     changePassword: accountChangepasswordRaw,
+    signInCode: accountSignInCodeRaw,
+    signInLink: accountSignInLinkRaw,
 }
 
 /**
@@ -0,0 +1,42 @@
+import APIService from './api-service'
+import { IApiKeyRequest, IApiKeyResource, TEntityId } from '../types'
+
+/**
+ * Service class to manage the API keys of companies. The key itself is only
+ * returned by create and rotate.
+ */
+export default class ApiKeyService {
+    private static readonly BASE_ENDPOINT = '/api-key'
+
+    public static async getAll(
+        companyId?: TEntityId
+    ): Promise<IApiKeyResource[]> {
+        const response = await APIService.get<IApiKeyResource[]>(
+            ApiKeyService.BASE_ENDPOINT,
+            companyId !== undefined ? { companyId } : undefined
+        )
+        return response.data
+    }
+
+    public static async create(
+        apiKeyData: IApiKeyRequest
+    ): Promise<IApiKeyResource> {
+        const response = await APIService.post<
+            IApiKeyRequest,
+            IApiKeyResource
+        >(ApiKeyService.BASE_ENDPOINT, apiKeyData)
+        return response.data
+    }
+
+    public static async rotate(id: TEntityId): Promise<IApiKeyResource> {
+        const endpoint = `${ApiKeyService.BASE_ENDPOINT}/${id}/rotate`
+        const response = await APIService.post<void, IApiKeyResource>(endpoint)
+        return response.data
+    }
+
+    public static async revoke(id: TEntityId): Promise<IApiKeyResource> {
+        const endpoint = `${ApiKeyService.BASE_ENDPOINT}/${id}`
+        const response = await APIService.delete<IApiKeyResource>(endpoint)
+        return response.data
+    }
+}
@@ -16,6 +16,44 @@ export default class APIService {
         withCredentials: true,
     })
 
+    // Access tokens are short-lived: a 401 refreshes the session once, shared
+    // by concurrent requests, and replays the request.
+    private static refreshing: Promise<void> | null = null
+
+    private static refreshInterceptor: number =
+        APIService.httpClient.interceptors.response.use(
+            (response) => response,
+            async (error) => {
+                const config = error?.config as
+                    | (InternalAxiosRequestConfig & { _refreshed?: boolean })
+                    | undefined
+                if (
+                    !axios.isAxiosError(error) ||
+                    error.response?.status !== 401 ||
+                    !config ||
+                    config._refreshed ||
+                    /auth\/(refresh|signin|signout)/.test(config.url ?? '')
+                ) {
+                    return Promise.reject(error)
+                }
+                config._refreshed = true
+                if (!APIService.refreshing) {
+                    APIService.refreshing = APIService.httpClient
+                        .post('/auth/refresh')
+                        .then(() => undefined)
+                        .finally(() => {
+                            APIService.refreshing = null
+                        })
+                }
+                try {
+                    await APIService.refreshing
+                } catch {
+                    return Promise.reject(error)
+                }
+                return APIService.httpClient(config)
+            }
+        )
+
     private static getDefaultUrl(): string {
         const environment =
             import.meta.env.VITE_HORIZON_CORP_ENVIRONMENT ||
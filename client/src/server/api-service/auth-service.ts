@@ -4,12 +4,21 @@ import APIService from './api-service'
 import { getSMSContent, getEmailContent } from '@/lib'
 import {
     IUserData,
+    TAccountType,
     ISignUpRequest,
     ISignInRequest,
     INewPasswordRequest,
     IVerifyEmailRequest,
     IForgotPasswordRequest,
     IChangePasswordRequest,
+    IImpersonateRequest,
+    IImpersonateResponse,
+    IPasswordlessRequest,
+    IPasswordlessSignInRequest,
+    IOIDCProvider,
+    IOIDCAuthorizeRequest,
+    IOIDCAuthorizeResponse,
+    IOIDCSignInRequest,
     IVerifyContactNumberRequest,
     ISendEmailVerificationRequest,
     ISendContactNumberVerificationRequest,
@@ -48,6 +57,12 @@ export default class AuthService extends APIService {
         await AuthService.post(endpoint)
     }
 
+    // POST - /auth/refresh
+    public static async refresh(): Promise<void> {
+        const endpoint = `${AuthService.BASE_ENDPOINT}/refresh`
+        await AuthService.post(endpoint)
+    }
+
     // POST - /auth/forgot-password
     public static async forgotPassword(
         data: IForgotPasswordRequest
@@ -58,6 +73,67 @@ export default class AuthService extends APIService {
         await AuthService.post<IForgotPasswordRequest, void>(endpoint, data)
     }
 
+    // POST - /auth/passwordless
+    public static async requestPasswordless(
+        data: IPasswordlessRequest
+    ): Promise<void> {
+        const link = data.method === 'link'
+        data.emailTemplate = getEmailContent(link ? 'signInLink' : 'signInCode')
+        data.contactTemplate = getSMSContent(link ? 'signInLink' : 'signInCode')
+        const endpoint = `${AuthService.BASE_ENDPOINT}/passwordless`
+        await AuthService.post<IPasswordlessRequest, void>(endpoint, data)
+    }
+
+    // POST - /auth/passwordless/signin
+    public static async passwordlessSignIn(
+        data: IPasswordlessSignInRequest
+    ): Promise<IUserData> {
+        const endpoint = `${AuthService.BASE_ENDPOINT}/passwordless/signin`
+        return (
+            await AuthService.post<IPasswordlessSignInRequest, IUserData>(
+                endpoint,
+                data
+            )
+        ).data
+    }
+
+    // POST - /auth/passwordless/link, with the token of /auth/magic-link/:token
+    public static async magicLinkSignIn(token: string): Promise<IUserData> {
+        const endpoint = `${AuthService.BASE_ENDPOINT}/passwordless/link`
+        return (
+            await AuthService.post<{ token: string }, IUserData>(endpoint, {
+                token,
+            })
+        ).data
+    }
+
+    // GET - /auth/oidc/providers
+    public static async oidcProviders(): Promise<IOIDCProvider[]> {
+        const endpoint = `${AuthService.BASE_ENDPOINT}/oidc/providers`
+        return (await AuthService.get<IOIDCProvider[]>(endpoint)).data
+    }
+
+    // POST - /auth/oidc/authorize, returns the identity provider URL to open
+    public static async oidcAuthorize(
+        data: IOIDCAuthorizeRequest
+    ): Promise<IOIDCAuthorizeResponse> {
+        const endpoint = `${AuthService.BASE_ENDPOINT}/oidc/authorize`
+        return (
+            await AuthService.post<
+                IOIDCAuthorizeRequest,
+                IOIDCAuthorizeResponse
+            >(endpoint, data)
+        ).data
+    }
+
+    // POST - /auth/oidc/callback
+    public static async oidcSignIn(data: IOIDCSignInRequest): Promise<IUserData> {
+        const endpoint = `${AuthService.BASE_ENDPOINT}/oidc/callback`
+        return (
+            await AuthService.post<IOIDCSignInRequest, IUserData>(endpoint, data)
+        ).data
+    }
+
     // POST - /auth/change-password
     public static async changePassword(
         data: IChangePasswordRequest
@@ -126,4 +202,33 @@ export default class AuthService extends APIService {
         const endpoint = `${AuthService.BASE_ENDPOINT}/new-password`
         await AuthService.post<INewPasswordRequest>(endpoint, data)
     }
+
+    // PUT - /auth/accounts/${accountType}/${id}/require-password-reset
+    public static async requirePasswordReset(
+        accountType: TAccountType,
+        id: number
+    ): Promise<void> {
+        const endpoint = `${AuthService.BASE_ENDPOINT}/accounts/${accountType}/${id}/require-password-reset`
+        await AuthService.put(endpoint)
+    }
+
+    // POST - /auth/accounts/impersonate
+    public static async impersonate(
+        data: IImpersonateRequest
+    ): Promise<IImpersonateResponse> {
+        const endpoint = `${AuthService.BASE_ENDPOINT}/accounts/impersonate`
+        return (
+            await AuthService.post<IImpersonateRequest, IImpersonateResponse>(
+                endpoint,
+                data
+            )
+        ).data
+    }
+
+    // POST - /auth/impersonate/stop, then refresh to resume the Admin session
+    public static async stopImpersonation(): Promise<void> {
+        const endpoint = `${AuthService.BASE_ENDPOINT}/impersonate/stop`
+        await AuthService.post(endpoint)
+        await AuthService.refresh()
+    }
 }
@@ -10,6 +10,9 @@ import {
     ICompanyRequest,
     ICompanyResource,
     ICompanyPaginatedResource,
+    ICompanyPasswordlessRequest,
+    ICompanyAttendanceRequest,
+    ICompanyGeofenceRequest,
 } from '@/server/types'
 
 /**
@@ -194,4 +197,43 @@ export default class CompanyService {
         )
         return response.data
     }
+
+    // PUT - /company/:id/passwordless
+    public static async updatePasswordless(
+        id: TEntityId,
+        data: ICompanyPasswordlessRequest
+    ): Promise<ICompanyResource> {
+        const endpoint = `${CompanyService.BASE_ENDPOINT}/${id}/passwordless`
+        const response = await APIService.put<
+            ICompanyPasswordlessRequest,
+            ICompanyResource
+        >(endpoint, data)
+        return response.data
+    }
+
+    // PUT - /company/:id/attendance
+    public static async updateAttendance(
+        id: TEntityId,
+        data: ICompanyAttendanceRequest
+    ): Promise<ICompanyResource> {
+        const endpoint = `${CompanyService.BASE_ENDPOINT}/${id}/attendance`
+        const response = await APIService.put<
+            ICompanyAttendanceRequest,
+            ICompanyResource
+        >(endpoint, data)
+        return response.data
+    }
+
+    // PUT - /company/:id/geofence
+    public static async updateGeofence(
+        id: TEntityId,
+        data: ICompanyGeofenceRequest
+    ): Promise<ICompanyResource> {
+        const endpoint = `${CompanyService.BASE_ENDPOINT}/${id}/geofence`
+        const response = await APIService.put<
+            ICompanyGeofenceRequest,
+            ICompanyResource
+        >(endpoint, data)
+        return response.data
+    }
 }
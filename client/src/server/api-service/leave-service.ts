@@ -0,0 +1,134 @@
+import qs from 'query-string'
+
+import APIService from './api-service'
+import {
+    TEntityId,
+    ILeaveBalanceRequest,
+    ILeaveBalanceResource,
+    ILeaveRequestRequest,
+    ILeaveRequestResource,
+    ILeaveReviewRequest,
+    ILeaveTypeRequest,
+    ILeaveTypeResource,
+} from '../types'
+
+/**
+ * Service class to manage leave types, file leave and review it.
+ */
+export default class LeaveService {
+    private static readonly BASE_ENDPOINT = '/leave'
+    private static readonly TYPE_ENDPOINT = '/leave-type'
+
+    public static async getAllTypes(): Promise<ILeaveTypeResource[]> {
+        const response = await APIService.get<ILeaveTypeResource[]>(
+            LeaveService.TYPE_ENDPOINT
+        )
+        return response.data
+    }
+
+    public static async createType(
+        data: ILeaveTypeRequest
+    ): Promise<ILeaveTypeResource> {
+        const response = await APIService.post<
+            ILeaveTypeRequest,
+            ILeaveTypeResource
+        >(LeaveService.TYPE_ENDPOINT, data)
+        return response.data
+    }
+
+    public static async updateType(
+        id: TEntityId,
+        data: ILeaveTypeRequest
+    ): Promise<ILeaveTypeResource> {
+        const response = await APIService.put<
+            ILeaveTypeRequest,
+            ILeaveTypeResource
+        >(`${LeaveService.TYPE_ENDPOINT}/${id}`, data)
+        return response.data
+    }
+
+    public static async deleteType(id: TEntityId): Promise<void> {
+        await APIService.delete<void>(`${LeaveService.TYPE_ENDPOINT}/${id}`)
+    }
+
+    public static async getAll(): Promise<ILeaveRequestResource[]> {
+        const response = await APIService.get<ILeaveRequestResource[]>(
+            LeaveService.BASE_ENDPOINT
+        )
+        return response.data
+    }
+
+    public static async getById(id: TEntityId): Promise<ILeaveRequestResource> {
+        const response = await APIService.get<ILeaveRequestResource>(
+            `${LeaveService.BASE_ENDPOINT}/${id}`
+        )
+        return response.data
+    }
+
+    public static async create(
+        data: ILeaveRequestRequest
+    ): Promise<ILeaveRequestResource> {
+        const response = await APIService.post<
+            ILeaveRequestRequest,
+            ILeaveRequestResource
+        >(LeaveService.BASE_ENDPOINT, data)
+        return response.data
+    }
+
+    // Withdraws a pending request no one has approved yet
+    public static async delete(id: TEntityId): Promise<void> {
+        await APIService.delete<void>(`${LeaveService.BASE_ENDPOINT}/${id}`)
+    }
+
+    // GET - /leave/balance, employees get their own without employeeId
+    public static async getBalances(
+        employeeId?: TEntityId
+    ): Promise<ILeaveBalanceResource[]> {
+        const url = qs.stringifyUrl(
+            {
+                url: `${LeaveService.BASE_ENDPOINT}/balance`,
+                query: { employeeId },
+            },
+            { skipNull: true }
+        )
+        const response = await APIService.get<ILeaveBalanceResource[]>(url)
+        return response.data
+    }
+
+    // PUT - /leave/balance
+    public static async setBalance(
+        data: ILeaveBalanceRequest
+    ): Promise<ILeaveBalanceResource[]> {
+        const response = await APIService.put<
+            ILeaveBalanceRequest,
+            ILeaveBalanceResource[]
+        >(`${LeaveService.BASE_ENDPOINT}/balance`, data)
+        return response.data
+    }
+
+    // POST - /leave/:id/approve
+    public static async approve(
+        id: TEntityId,
+        data: ILeaveReviewRequest = {}
+    ): Promise<ILeaveRequestResource> {
+        const endpoint = `${LeaveService.BASE_ENDPOINT}/${id}/approve`
+        const response = await APIService.post<
+            ILeaveReviewRequest,
+            ILeaveRequestResource
+        >(endpoint, data)
+        return response.data
+    }
+
+    // POST - /leave/:id/reject
+    public static async reject(
+        id: TEntityId,
+        data: ILeaveReviewRequest = {}
+    ): Promise<ILeaveRequestResource> {
+        const endpoint = `${LeaveService.BASE_ENDPOINT}/${id}/reject`
+        const response = await APIService.post<
+            ILeaveReviewRequest,
+            ILeaveRequestResource
+        >(endpoint, data)
+        return response.data
+    }
+}
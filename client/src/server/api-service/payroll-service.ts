@@ -0,0 +1,83 @@
+import APIService from './api-service'
+import { downloadFile } from '../helpers'
+
+import {
+    TEntityId,
+    IPayPeriodRequest,
+    IPayPeriodResource,
+    IPayPeriodSummaryResource,
+} from '../types'
+
+/**
+ * Service class to handle pay periods and their timesheet summaries.
+ */
+export default class PayrollService {
+    private static readonly BASE_ENDPOINT = '/payroll'
+
+    public static async getAll(): Promise<IPayPeriodResource[]> {
+        const response = await APIService.get<IPayPeriodResource[]>(
+            PayrollService.BASE_ENDPOINT
+        )
+        return response.data
+    }
+
+    public static async create(
+        data: IPayPeriodRequest
+    ): Promise<IPayPeriodResource> {
+        const response = await APIService.post<
+            IPayPeriodRequest,
+            IPayPeriodResource
+        >(PayrollService.BASE_ENDPOINT, data)
+        return response.data
+    }
+
+    public static async update(
+        id: TEntityId,
+        data: IPayPeriodRequest
+    ): Promise<IPayPeriodResource> {
+        const endpoint = `${PayrollService.BASE_ENDPOINT}/${id}`
+        const response = await APIService.put<
+            IPayPeriodRequest,
+            IPayPeriodResource
+        >(endpoint, data)
+        return response.data
+    }
+
+    public static async delete(id: TEntityId): Promise<void> {
+        await APIService.delete<void>(`${PayrollService.BASE_ENDPOINT}/${id}`)
+    }
+
+    // POST - /payroll/:id/lock
+    public static async lock(id: TEntityId): Promise<IPayPeriodResource> {
+        const endpoint = `${PayrollService.BASE_ENDPOINT}/${id}/lock`
+        const response = await APIService.post<void, IPayPeriodResource>(
+            endpoint
+        )
+        return response.data
+    }
+
+    // POST - /payroll/:id/unlock
+    public static async unlock(id: TEntityId): Promise<IPayPeriodResource> {
+        const endpoint = `${PayrollService.BASE_ENDPOINT}/${id}/unlock`
+        const response = await APIService.post<void, IPayPeriodResource>(
+            endpoint
+        )
+        return response.data
+    }
+
+    // GET - /payroll/:id/summary
+    public static async getSummary(
+        id: TEntityId
+    ): Promise<IPayPeriodSummaryResource> {
+        const endpoint = `${PayrollService.BASE_ENDPOINT}/${id}/summary`
+        const response =
+            await APIService.get<IPayPeriodSummaryResource>(endpoint)
+        return response.data
+    }
+
+    // GET - /payroll/:id/export
+    public static async exportSummary(id: TEntityId): Promise<void> {
+        const url = `${PayrollService.BASE_ENDPOINT}/${id}/export`
+        await downloadFile(url, `timesheet_summary_${id}.csv`)
+    }
+}
@@ -11,6 +11,7 @@ import {
     IChangeUsernameRequest,
     IAccountSettingRequest,
     IChangeContactNumberRequest,
+    ISessionResource,
 } from '../types'
 
 export default class ProfileService extends APIService {
@@ -64,4 +65,22 @@ export default class ProfileService extends APIService {
             data
         )
     }
+
+    // GET - /profile/sessions
+    public static async sessions(): Promise<ISessionResource[]> {
+        const endpoint = `${ProfileService.BASE_ENDPOINT}/sessions`
+        return (await this.get<ISessionResource[]>(endpoint)).data
+    }
+
+    // DELETE - /profile/sessions/:id
+    public static async revokeSession(id: number): Promise<void> {
+        const endpoint = `${ProfileService.BASE_ENDPOINT}/sessions/${id}`
+        await this.delete(endpoint)
+    }
+
+    // DELETE - /profile/sessions
+    public static async revokeAllSessions(keepCurrent = false): Promise<void> {
+        const endpoint = `${ProfileService.BASE_ENDPOINT}/sessions`
+        await this.delete(endpoint, undefined, { keepCurrent })
+    }
 }
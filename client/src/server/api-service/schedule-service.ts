@@ -0,0 +1,123 @@
+import APIService from './api-service'
+
+import {
+    TEntityId,
+    IShiftRequest,
+    IShiftResource,
+    IHolidayRequest,
+    IHolidayResource,
+    IShiftAssignmentRequest,
+    IShiftAssignmentResource,
+} from '../types'
+
+/**
+ * Service class to handle shifts, schedule assignments and holidays.
+ */
+export default class ScheduleService {
+    private static readonly SHIFT_ENDPOINT = '/shift'
+    private static readonly SCHEDULE_ENDPOINT = '/schedule'
+    private static readonly HOLIDAY_ENDPOINT = '/holiday'
+
+    public static async getShifts(): Promise<IShiftResource[]> {
+        const response = await APIService.get<IShiftResource[]>(
+            ScheduleService.SHIFT_ENDPOINT
+        )
+        return response.data
+    }
+
+    public static async createShift(
+        data: IShiftRequest
+    ): Promise<IShiftResource> {
+        const response = await APIService.post<IShiftRequest, IShiftResource>(
+            ScheduleService.SHIFT_ENDPOINT,
+            data
+        )
+        return response.data
+    }
+
+    public static async updateShift(
+        id: TEntityId,
+        data: IShiftRequest
+    ): Promise<IShiftResource> {
+        const endpoint = `${ScheduleService.SHIFT_ENDPOINT}/${id}`
+        const response = await APIService.put<IShiftRequest, IShiftResource>(
+            endpoint,
+            data
+        )
+        return response.data
+    }
+
+    public static async deleteShift(id: TEntityId): Promise<void> {
+        await APIService.delete<void>(`${ScheduleService.SHIFT_ENDPOINT}/${id}`)
+    }
+
+    public static async getAssignments(): Promise<IShiftAssignmentResource[]> {
+        const response = await APIService.get<IShiftAssignmentResource[]>(
+            ScheduleService.SCHEDULE_ENDPOINT
+        )
+        return response.data
+    }
+
+    public static async createAssignment(
+        data: IShiftAssignmentRequest
+    ): Promise<IShiftAssignmentResource> {
+        const response = await APIService.post<
+            IShiftAssignmentRequest,
+            IShiftAssignmentResource
+        >(ScheduleService.SCHEDULE_ENDPOINT, data)
+        return response.data
+    }
+
+    public static async updateAssignment(
+        id: TEntityId,
+        data: IShiftAssignmentRequest
+    ): Promise<IShiftAssignmentResource> {
+        const endpoint = `${ScheduleService.SCHEDULE_ENDPOINT}/${id}`
+        const response = await APIService.put<
+            IShiftAssignmentRequest,
+            IShiftAssignmentResource
+        >(endpoint, data)
+        return response.data
+    }
+
+    public static async deleteAssignment(id: TEntityId): Promise<void> {
+        await APIService.delete<void>(
+            `${ScheduleService.SCHEDULE_ENDPOINT}/${id}`
+        )
+    }
+
+    public static async getHolidays(): Promise<IHolidayResource[]> {
+        const response = await APIService.get<IHolidayResource[]>(
+            ScheduleService.HOLIDAY_ENDPOINT
+        )
+        return response.data
+    }
+
+    public static async createHoliday(
+        data: IHolidayRequest
+    ): Promise<IHolidayResource> {
+        const response = await APIService.post<
+            IHolidayRequest,
+            IHolidayResource
+        >(ScheduleService.HOLIDAY_ENDPOINT, data)
+        return response.data
+    }
+
+    public static async updateHoliday(
+        id: TEntityId,
+        data: IHolidayRequest
+    ): Promise<IHolidayResource> {
+        const endpoint = `${ScheduleService.HOLIDAY_ENDPOINT}/${id}`
+        const response = await APIService.put<
+            IHolidayRequest,
+            IHolidayResource
+        >(endpoint, data)
+        return response.data
+    }
+
+    public static async deleteHoliday(id: TEntityId): Promise<void> {
+        await APIService.delete<void>(
+            `${ScheduleService.HOLIDAY_ENDPOINT}/${id}`
+        )
+    }
+}
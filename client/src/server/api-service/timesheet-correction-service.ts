@@ -0,0 +1,74 @@
+import APIService from './api-service'
+
+import {
+    TEntityId,
+    ITimesheetCorrectionRequest,
+    ITimesheetCorrectionResource,
+    ITimesheetCorrectionReviewRequest,
+} from '../types'
+
+/**
+ * Service class to file timesheet corrections and review them.
+ */
+export default class TimesheetCorrectionService {
+    private static readonly BASE_ENDPOINT = '/timesheet-correction'
+
+    public static async getAll(): Promise<ITimesheetCorrectionResource[]> {
+        const response = await APIService.get<ITimesheetCorrectionResource[]>(
+            TimesheetCorrectionService.BASE_ENDPOINT
+        )
+        return response.data
+    }
+
+    public static async getById(
+        id: TEntityId
+    ): Promise<ITimesheetCorrectionResource> {
+        const endpoint = `${TimesheetCorrectionService.BASE_ENDPOINT}/${id}`
+        const response =
+            await APIService.get<ITimesheetCorrectionResource>(endpoint)
+        return response.data
+    }
+
+    public static async create(
+        data: ITimesheetCorrectionRequest
+    ): Promise<ITimesheetCorrectionResource> {
+        const response = await APIService.post<
+            ITimesheetCorrectionRequest,
+            ITimesheetCorrectionResource
+        >(TimesheetCorrectionService.BASE_ENDPOINT, data)
+        return response.data
+    }
+
+    // Withdraws a pending correction
+    public static async delete(id: TEntityId): Promise<void> {
+        await APIService.delete<void>(
+            `${TimesheetCorrectionService.BASE_ENDPOINT}/${id}`
+        )
+    }
+
+    // POST - /timesheet-correction/:id/approve
+    public static async approve(
+        id: TEntityId,
+        data: ITimesheetCorrectionReviewRequest = {}
+    ): Promise<ITimesheetCorrectionResource> {
+        const endpoint = `${TimesheetCorrectionService.BASE_ENDPOINT}/${id}/approve`
+        const response = await APIService.post<
+            ITimesheetCorrectionReviewRequest,
+            ITimesheetCorrectionResource
+        >(endpoint, data)
+        return response.data
+    }
+
+    // POST - /timesheet-correction/:id/reject
+    public static async reject(
+        id: TEntityId,
+        data: ITimesheetCorrectionReviewRequest = {}
+    ): Promise<ITimesheetCorrectionResource> {
+        const endpoint = `${TimesheetCorrectionService.BASE_ENDPOINT}/${id}/reject`
+        const response = await APIService.post<
+            ITimesheetCorrectionReviewRequest,
+            ITimesheetCorrectionResource
+        >(endpoint, data)
+        return response.data
+    }
+}
@@ -1,5 +1,13 @@
+import qs from 'query-string'
+
 import APIService from './api-service'
-import { ITimesheetResource, ITimeInRequest, ITimeOutRequest } from '../types'
+import {
+    TEntityId,
+    ITimesheetResource,
+    ITimeInRequest,
+    ITimeOutRequest,
+    IAttendanceDayResource,
+} from '../types'
 
 /**
  * Service class to handle timesheet-specific operations.
@@ -36,4 +44,29 @@ export default class TimesheetService {
         )
         return response.data
     }
+
+    // GET - /timesheet/attendance, dates are YYYY-MM-DD
+    public static async getAttendance(
+        from: string,
+        to: string,
+        employeeId?: TEntityId
+    ): Promise<IAttendanceDayResource[]> {
+        const url = qs.stringifyUrl(
+            {
+                url: `${TimesheetService.BASE_ENDPOINT}/attendance`,
+                query: { from, to, employeeId },
+            },
+            { skipNull: true }
+        )
+        const response = await APIService.get<IAttendanceDayResource[]>(url)
+        return response.data
+    }
+
+    // GET - /timesheet/review, timesheets flagged for review
+    public static async getNeedsReview(): Promise<ITimesheetResource[]> {
+        const response = await APIService.get<ITimesheetResource[]>(
+            `${TimesheetService.BASE_ENDPOINT}/review`
+        )
+        return response.data
+    }
 }
@@ -0,0 +1,33 @@
+import { TEntityId } from './common'
+import { IRolesResource } from './role'
+
+export interface IApiKeyRequest {
+    name: string
+    companyID: TEntityId
+    roleID: TEntityId
+    expiresAt?: string
+    allowedIps?: string[]
+}
+
+export interface IApiKeyResource {
+    id: TEntityId
+    createdAt: string
+    updatedAt: string
+
+    name: string
+    prefix: string
+    allowedIps: string[]
+    expiresAt?: string
+    lastUsedAt?: string
+    lastUsedIp?: string
+    revokedAt?: string
+    companyID: TEntityId
+    roleID: TEntityId
+    role?: IRolesResource
+
+    creatorAccountType: string
+    creatorID: TEntityId
+
+    // only returned when the key is created or rotated
+    key?: string
+}
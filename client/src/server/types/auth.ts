@@ -3,6 +3,60 @@ import { TAccountType, IUserBase } from './common'
 
 export interface IUserData extends IUserBase {
     accountType: TAccountType
+    // Set while an Admin impersonates the user
+    impersonation?: IImpersonation
+}
+
+export interface IImpersonation {
+    adminID: number
+    adminName?: string
+    expiresAt: string
+}
+
+export interface IImpersonateRequest {
+    accountType: Exclude<TAccountType, 'Admin'>
+    id: number
+    reason?: string
+}
+
+export interface IImpersonateResponse {
+    user: IUserData
+    expiresAt: string
+}
+
+export interface IPasswordlessRequest {
+    key: string
+    accountType: Exclude<TAccountType, 'Admin'>
+    method: 'code' | 'link'
+    emailTemplate?: string
+    contactTemplate?: string
+}
+
+export interface IPasswordlessSignInRequest {
+    key: string
+    accountType: Exclude<TAccountType, 'Admin'>
+    code: string
+}
+
+export interface IOIDCProvider {
+    name: string
+    companyId: number
+    accountTypes: Extract<TAccountType, 'Owner' | 'Employee'>[]
+}
+
+export interface IOIDCAuthorizeRequest {
+    provider: string
+    accountType: Extract<TAccountType, 'Owner' | 'Employee'>
+}
+
+export interface IOIDCAuthorizeResponse {
+    url: string
+}
+
+// The code and state the identity provider appends to /auth/oidc/callback
+export interface IOIDCSignInRequest {
+    state: string
+    code: string
 }
 
 export interface IChangePasswordRequest {
@@ -12,6 +66,15 @@ export interface IChangePasswordRequest {
     confirmPassword: string
 }
 
+// Sign-in response (403) of users who have to choose a new password first,
+// resetId is used with /auth/change-password
+export interface IPasswordResetRequiredResponse {
+    error: string
+    passwordResetRequired: true
+    resetId: string
+    recoveryCodes?: string[]
+}
+
 export interface IForgotPasswordRequest {
     key: string
     accountType: TAccountType
@@ -89,3 +152,13 @@ export interface IChangeUsernameRequest {
     password: string
     username: string
 }
+
+export interface ISessionResource {
+    id: number
+    createdAt: string
+    ip: string
+    userAgent: string
+    lastSeenAt: string
+    expiresAt: string
+    current: boolean
+}
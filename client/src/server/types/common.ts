@@ -58,4 +58,7 @@ export interface IUserBase extends ITimeStamps, ILongLat {
     isEmailVerified: boolean
     isContactVerified: boolean
     isSkipVerification: boolean
+
+    passwordChangedAt?: string
+    passwordResetRequired: boolean
 }
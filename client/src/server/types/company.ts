@@ -2,6 +2,7 @@ import { TEntityId } from './common'
 import { IMediaResource } from './media'
 import { IOwnerResource } from './owner'
 import { IBranchResource } from './branch'
+import { TWeekday } from './schedule'
 import { IPaginatedResult } from './paginated-result'
 
 export interface ICompanyRequest {
@@ -18,6 +19,26 @@ export interface ICompanyRequest {
     isAdminVerified?: boolean
 }
 
+export type TCompanyPasswordlessAccountType = 'Owner' | 'Employee' | 'Member'
+
+export interface ICompanyPasswordlessRequest {
+    accountTypes: TCompanyPasswordlessAccountType[]
+}
+
+export interface ICompanyAttendanceRequest {
+    // IANA time zone, e.g. Asia/Manila
+    timezone: string
+    restDays: TWeekday[]
+}
+
+export type TCompanyGeofencePolicy = 'Off' | 'Flag' | 'Reject'
+
+export interface ICompanyGeofenceRequest {
+    policy: TCompanyGeofencePolicy
+    // Radius around the branch in meters
+    radius: number
+}
+
 export interface ICompanyResource {
     id: TEntityId
     name: string
@@ -27,6 +48,14 @@ export interface ICompanyResource {
     latitude?: number
     contactNumber: string
     isAdminVerified: boolean
+    // Account types that may sign in with a code or link instead of a password
+    passwordlessAccountTypes: TCompanyPasswordlessAccountType[]
+    // Calendar the schedules of the company follow
+    timezone: string
+    restDays: TWeekday[]
+    // What happens to punches farther than geofenceRadius from the branch
+    geofencePolicy: TCompanyGeofencePolicy
+    geofenceRadius: number
     owner?: IOwnerResource
     media?: IMediaResource
     branches?: IBranchResource[]
@@ -28,6 +28,7 @@ export interface IFootstepResource {
     owner?: IOwnerResource
     memberId?: TEntityId
     member?: IMemberResource
+    apiKeyID?: TEntityId
 }
 
 export interface IFootstepPaginatedResource
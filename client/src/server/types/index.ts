@@ -35,6 +35,7 @@ export * from './member/member-occupation-history'
 /* Other Types */
 export * from './api'
 export * from './auth'
+export * from './api-key'
 export * from './role'
 export * from './media'
 export * from './gender'
@@ -45,6 +46,10 @@ export * from './contact'
 export * from './feedback'
 export * from './footstep'
 export * from './timesheet'
+export * from './timesheet-correction'
+export * from './schedule'
+export * from './payroll'
+export * from './leave'
 export * from './notification'
 export * from './member/member'
 export * from './paginated-result'
@@ -0,0 +1,84 @@
+import { ITimeStamps, TEntityId } from './common'
+import { IEmployeeResource } from './employee'
+
+export type TLeaveAccrualPeriod = 'None' | 'Monthly' | 'Yearly'
+
+export type TLeaveStatus = 'Pending' | 'Approved' | 'Rejected'
+
+// Only paid leave types accrue, up to maxBalance when it is set
+export interface ILeaveTypeRequest {
+    name: string
+    description?: string
+    paid: boolean
+    accrualPeriod: TLeaveAccrualPeriod
+    accrualDays: number
+    maxBalance: number
+    approvalLevels: number
+    companyID: TEntityId
+}
+
+export interface ILeaveTypeResource extends ITimeStamps {
+    id: TEntityId
+    name: string
+    description: string
+    paid: boolean
+    accrualPeriod: TLeaveAccrualPeriod
+    accrualDays: number
+    maxBalance: number
+    approvalLevels: number
+    companyID: TEntityId
+}
+
+export interface ILeaveBalanceRequest {
+    employeeID: TEntityId
+    leaveTypeID: TEntityId
+    balance: number
+}
+
+export interface ILeaveBalanceResource {
+    employeeID: TEntityId
+    leaveTypeID: TEntityId
+    leaveType: ILeaveTypeResource
+    balance: number
+    // Days of pending requests, not taken from the balance yet
+    pending: number
+    available: number
+}
+
+export interface ILeaveRequestRequest {
+    leaveTypeID: TEntityId
+    startDate: Date
+    endDate: Date
+    reason: string
+}
+
+export interface ILeaveReviewRequest {
+    note?: string
+}
+
+export interface ILeaveApprovalResource {
+    id: TEntityId
+    createdAt: string
+    level: number
+    approved: boolean
+    reviewerAccountType: string
+    reviewerID: TEntityId
+    note: string
+}
+
+export interface ILeaveRequestResource extends ITimeStamps {
+    id: TEntityId
+    startDate: string
+    endDate: string
+    // Regular working days covered, without rest days and holidays
+    days: number
+    reason: string
+    status: TLeaveStatus
+    approvalLevels: number
+    approvedLevels: number
+    employeeID: TEntityId
+    employee?: IEmployeeResource
+    leaveTypeID: TEntityId
+    leaveType?: ILeaveTypeResource
+    approvals?: ILeaveApprovalResource[]
+}
@@ -42,6 +42,8 @@ export interface IMemberResource extends IUserBase {
     isEmailVerified: boolean
     isContactVerified: boolean
     isSkipVerification: boolean
+    passwordChangedAt?: string
+    passwordResetRequired: boolean
 
     role?: IRolesResource
     gender?: IGenderResource
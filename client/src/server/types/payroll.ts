@@ -0,0 +1,45 @@
+import { ITimeStamps, TEntityId } from './common'
+
+export interface IPayPeriodRequest {
+    name: string
+    startDate: string
+    endDate: string
+    companyID: TEntityId
+}
+
+export interface IPayPeriodResource extends ITimeStamps {
+    id: TEntityId
+    name: string
+    startDate: string
+    endDate: string
+    // Set once the period is locked and its timesheets can't be changed
+    lockedAt?: string
+    companyID: TEntityId
+}
+
+export interface ITimesheetSummaryResource {
+    employeeID: TEntityId
+    employeeName: string
+    daysPresent: number
+    absentDays: number
+    // Working days on approved leave, paid or not
+    leaveDays: number
+    paidLeaveDays: number
+    // Timesheets still open, not counted toward hours
+    openTimesheets: number
+    // Timesheets flagged for review, like those closed automatically, not
+    // counted toward hours until a correction is approved
+    reviewTimesheets: number
+    lateMinutes: number
+    undertimeMinutes: number
+    regularHours: number
+    overtimeHours: number
+    // Hours worked from 22:00 to 06:00, overlapping the other hours
+    nightHours: number
+    holidayHours: number
+}
+
+export interface IPayPeriodSummaryResource {
+    payPeriod: IPayPeriodResource
+    data: ITimesheetSummaryResource[]
+}
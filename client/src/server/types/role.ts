@@ -1,22 +1,28 @@
 import { TEntityId } from './common'
 
+export type TPermissionAction = 'read' | 'create' | 'update' | 'delete'
+
+export type TPermissionScope = 'own' | 'branch' | 'company' | 'all'
+
+export interface IPermissionResource {
+    resource: string
+    action: TPermissionAction
+    scope: TPermissionScope
+}
+
+export interface IRolePermissionsRequest {
+    permissions: IPermissionResource[]
+}
+
 export interface IRolesRequest {
     name: string
     description?: string
     color?: string
     apiKey: string
-    readRole?: boolean
-    writeRole?: boolean
-    updateRole?: boolean
-    deleteRole?: boolean
-    readErrorDetails?: boolean
-    writeErrorDetails?: boolean
-    updateErrorDetails?: boolean
-    deleteErrorDetails?: boolean
-    readGender?: boolean
-    writeGender?: boolean
-    updateGender?: boolean
-    deleteGender?: boolean
+}
+
+export interface IRoleTwoFactorRequest {
+    required: boolean
 }
 
 export interface IRolesResource {
@@ -26,19 +32,9 @@ export interface IRolesResource {
 
     color?: string
     apiKey?: string
+    requireTwoFactor?: boolean
     createdAt: Date
     updatedAt: Date
 
-    readRole?: boolean
-    writeRole?: boolean
-    updateRole?: boolean
-    deleteRole?: boolean
-    readErrorDetails?: boolean
-    writeErrorDetails?: boolean
-    updateErrorDetails?: boolean
-    deleteErrorDetails?: boolean
-    readGender?: boolean
-    writeGender?: boolean
-    updateGender?: boolean
-    deleteGender?: boolean
+    permissions?: IPermissionResource[]
 }
@@ -0,0 +1,85 @@
+import { ITimeStamps, TEntityId } from './common'
+import { IBranchResource } from './branch'
+import { IEmployeeResource } from './employee'
+
+export type TWeekday = 'Sun' | 'Mon' | 'Tue' | 'Wed' | 'Thu' | 'Fri' | 'Sat'
+
+export type THolidayKind = 'Regular' | 'Special'
+
+export type TDayType =
+    | 'Regular'
+    | 'Rest Day'
+    | 'Regular Holiday'
+    | 'Special Holiday'
+
+export type TAttendanceStatus =
+    | 'On Time'
+    | 'Late'
+    | 'Undertime'
+    | 'Overtime'
+    | 'Absent'
+    | 'Unscheduled'
+    | 'On Leave'
+
+export interface IShiftRequest {
+    name: string
+    // HH:MM, a shift that ends at or before its start ends the next day
+    startTime: string
+    endTime: string
+    breakMinutes: number
+    lateGraceMinutes: number
+    undertimeGraceMinutes: number
+    overtimeGraceMinutes: number
+    companyID: TEntityId
+}
+
+export interface IShiftResource extends ITimeStamps {
+    id: TEntityId
+    name: string
+    startTime: string
+    endTime: string
+    breakMinutes: number
+    lateGraceMinutes: number
+    undertimeGraceMinutes: number
+    overtimeGraceMinutes: number
+    companyID: TEntityId
+}
+
+export interface IShiftAssignmentRequest {
+    effectiveFrom: string
+    effectiveTo?: string
+    // Comma separated weekdays, the company rest days apply when empty
+    restDays?: string
+    shiftID: TEntityId
+    // One of employeeID or branchID
+    employeeID?: TEntityId
+    branchID?: TEntityId
+}
+
+export interface IShiftAssignmentResource extends ITimeStamps {
+    id: TEntityId
+    effectiveFrom: string
+    effectiveTo?: string
+    restDays: TWeekday[]
+    shiftID: TEntityId
+    shift?: IShiftResource
+    employeeID?: TEntityId
+    employee?: IEmployeeResource
+    branchID?: TEntityId
+    branch?: IBranchResource
+}
+
+export interface IHolidayRequest {
+    date: string
+    name: string
+    kind: THolidayKind
+    companyID: TEntityId
+}
+
+export interface IHolidayResource extends ITimeStamps {
+    id: TEntityId
+    date: string
+    name: string
+    kind: THolidayKind
+    companyID: TEntityId
+}
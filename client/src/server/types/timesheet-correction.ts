@@ -0,0 +1,41 @@
+import { ITimeStamps, TEntityId } from './common'
+import { IEmployeeResource } from './employee'
+import { ITimesheetResource } from './timesheet'
+
+export type TTimesheetCorrectionType = 'Missing Punch' | 'Wrong Time'
+
+export type TTimesheetCorrectionStatus = 'Pending' | 'Approved' | 'Rejected'
+
+// Without a timesheetID the correction adds a timesheet, and needs both
+// times; with one, the times left out are kept
+export interface ITimesheetCorrectionRequest {
+    timesheetID?: TEntityId
+    type: TTimesheetCorrectionType
+    timeIn?: Date
+    timeOut?: Date
+    reason: string
+}
+
+export interface ITimesheetCorrectionReviewRequest {
+    note?: string
+}
+
+export interface ITimesheetCorrectionResource extends ITimeStamps {
+    id: TEntityId
+    type: TTimesheetCorrectionType
+    timeIn?: Date
+    timeOut?: Date
+    reason: string
+    status: TTimesheetCorrectionStatus
+    // Times of the timesheet before the correction was approved
+    originalTimeIn?: Date
+    originalTimeOut?: Date
+    reviewerAccountType: string
+    reviewerID?: TEntityId
+    reviewedAt?: string
+    reviewNote: string
+    employeeID: TEntityId
+    employee?: IEmployeeResource
+    timesheetID?: TEntityId
+    timesheet?: ITimesheetResource
+}
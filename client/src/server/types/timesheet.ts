@@ -1,12 +1,21 @@
 import { TEntityId } from './common'
 import { IMediaResource } from './media'
+import { IShiftResource, TAttendanceStatus, TDayType } from './schedule'
 
-export interface ITimeInRequest {
+// Where the device was, checked against the geofence of the branch
+export interface IPunchRequest {
+    latitude?: number
+    longitude?: number
+    // Accuracy of the location in meters
+    accuracy?: number
+}
+
+export interface ITimeInRequest extends IPunchRequest {
     timeIn: Date
     mediaIn: IMediaResource
 }
 
-export interface ITimeOutRequest {
+export interface ITimeOutRequest extends IPunchRequest {
     timeOut: Date
     mediaOut: IMediaResource
 }
@@ -22,4 +31,42 @@ export interface ITimesheetResource {
     updatedAt: Date
     mediaIn?: IMediaResource
     mediaOut?: IMediaResource
+    // Attendance against the schedule, computed at time-in and time-out
+    shiftID?: TEntityId
+    shift?: IShiftResource
+    scheduledIn?: Date
+    scheduledOut?: Date
+    dayType: TDayType
+    attendanceStatus: TAttendanceStatus
+    lateMinutes: number
+    undertimeMinutes: number
+    overtimeMinutes: number
+    // Locations of the punches, with their distance from the branch in meters
+    latitudeIn?: number
+    longitudeIn?: number
+    accuracyIn?: number
+    distanceIn?: number
+    outOfRangeIn: boolean
+    latitudeOut?: number
+    longitudeOut?: number
+    accuracyOut?: number
+    distanceOut?: number
+    outOfRangeOut: boolean
+    // Closed by the server after being left open, until a correction of it
+    // is approved
+    autoClosed: boolean
+    needsReview: boolean
+}
+
+export interface IAttendanceDayResource {
+    date: string
+    dayType: TDayType
+    shiftID?: TEntityId
+    scheduledIn?: string
+    scheduledOut?: string
+    // Empty for working days that have not ended yet
+    status: TAttendanceStatus | ''
+    // Approved leave covering the day
+    leaveID?: TEntityId
+    timesheets: ITimesheetResource[]
 }
@@ -1,12 +1,27 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
 	"time"
 )
 
+// OIDCProvider is an OpenID Connect identity provider the staff of a company
+// sign in with. The client is registered at the provider with the redirect URI
+// OIDCRedirectURL.
+type OIDCProvider struct {
+	// Name identifies the provider in the sign-in URLs
+	Name         string   `json:"name"`
+	CompanyID    uint     `json:"companyId"`
+	Issuer       string   `json:"issuer"`
+	ClientID     string   `json:"clientId"`
+	ClientSecret string   `json:"clientSecret"`
+	AccountTypes []string `json:"accountTypes"`
+	Scopes       []string `json:"scopes"`
+}
+
 type AppConfig struct {
 	// Application
 	AppName        string
@@ -17,10 +32,33 @@ type AppConfig struct {
 	AppSeeder      string
 	AppTokenName   string
 	AppToken       []byte
+	TokenHashKey   []byte
 	AppForwardPort []byte
 	AppLogo        string
 	LogLevel       string
 
+	// Token signing
+	JWTAlgorithm        string
+	JWTKeysDir          string
+	JWTSigningKey       []byte
+	JWTVerificationKeys []byte
+	JWTKeyRotation      time.Duration
+
+	// Password policy
+	PasswordMinLength    int
+	PasswordMinClasses   int
+	PasswordMaxAge       time.Duration
+	PasswordHistory      int
+	PasswordBreachedList string
+
+	// Timesheets open longer than TimesheetAutoClose are closed and flagged
+	// for review; 0 disables it
+	TimesheetAutoClose time.Duration
+
+	// OpenID Connect
+	OIDCProviders   []OIDCProvider
+	OIDCRedirectURL string
+
 	// AWS
 	AWSAccessKeyID     string
 	AWSSecretAccessKey string
@@ -115,6 +153,50 @@ func NewAppConfig() *AppConfig {
 		errList = append(errList, fmt.Sprintf("Invalid DB_RETRY_DELAY value '%s', defaulting to 2s", dbRetryDelayStr))
 	}
 
+	// Parse JWT_KEY_ROTATION as a time.Duration, defaulting to 30 days; 0 disables rotation
+	jwtKeyRotation := 720 * time.Hour
+	jwtKeyRotationStr := getEnv("JWT_KEY_ROTATION", "720h")
+	if parsedRotation, err := time.ParseDuration(jwtKeyRotationStr); err == nil {
+		jwtKeyRotation = parsedRotation
+	} else {
+		errList = append(errList, fmt.Sprintf("Invalid JWT_KEY_ROTATION value '%s', defaulting to 720h", jwtKeyRotationStr))
+	}
+
+	// Parse the password policy; PASSWORD_MAX_AGE 0 disables expiry
+	passwordMinLength := parseIntEnv("PASSWORD_MIN_LENGTH", 8, &errList)
+	passwordMinClasses := parseIntEnv("PASSWORD_MIN_CLASSES", 3, &errList)
+	passwordHistory := parseIntEnv("PASSWORD_HISTORY", 5, &errList)
+	passwordMaxAge := time.Duration(0)
+	passwordMaxAgeStr := getEnv("PASSWORD_MAX_AGE", "0")
+	if parsedMaxAge, err := time.ParseDuration(passwordMaxAgeStr); err == nil {
+		passwordMaxAge = parsedMaxAge
+	} else {
+		errList = append(errList, fmt.Sprintf("Invalid PASSWORD_MAX_AGE value '%s', defaulting to 0", passwordMaxAgeStr))
+	}
+
+	// Parse TIMESHEET_AUTO_CLOSE as a time.Duration, defaulting to 16h; 0 disables it
+	timesheetAutoClose := 16 * time.Hour
+	timesheetAutoCloseStr := getEnv("TIMESHEET_AUTO_CLOSE", "16h")
+	if parsedAutoClose, err := time.ParseDuration(timesheetAutoCloseStr); err == nil {
+		timesheetAutoClose = parsedAutoClose
+	} else {
+		errList = append(errList, fmt.Sprintf("Invalid TIMESHEET_AUTO_CLOSE value '%s', defaulting to 16h", timesheetAutoCloseStr))
+	}
+
+	// Parse OIDC_PROVIDERS, a JSON array of the identity providers of the companies
+	oidcProviders, err := parseOIDCProviders(os.Getenv("OIDC_PROVIDERS"))
+	if err != nil {
+		errList = append(errList, fmt.Sprintf("Invalid OIDC_PROVIDERS: %v", err))
+	}
+	appClientUrl := getEnv("APP_CLIENT_URL", "http://client:80")
+
+	// TOKEN_HASH_KEY keys the hashes of one-time tokens and the encryption of
+	// two-factor secrets, apart from APP_TOKEN
+	tokenHashKey := os.Getenv("TOKEN_HASH_KEY")
+	if tokenHashKey == "" {
+		errList = append(errList, "TOKEN_HASH_KEY is required")
+	}
+
 	// If any errors were encountered, print them and return an empty AppConfig
 	if len(errList) > 0 {
 		for _, e := range errList {
@@ -129,14 +211,36 @@ func NewAppConfig() *AppConfig {
 		AppName:      getEnv("APP_NAME", "horizon-corp"),
 		AppVersion:   getEnv("APP_VERSION", "0.0.0"),
 		AppEnv:       getEnv("APP_ENV", ""),
-		AppClientUrl: getEnv("APP_CLIENT_URL", "http://client:80"),
+		AppClientUrl: appClientUrl,
 		AppPort:      getEnv("APP_PORT", "8080"),
 		AppSeeder:    getEnv("APP_SEEDER", "horizon-corp-seed"),
 		AppTokenName: getEnv("APP_TOKEN_NAME", "horizon-corp"),
 		AppToken:     []byte(os.Getenv("APP_TOKEN")),
+		TokenHashKey: []byte(tokenHashKey),
 		AppLogo:      getEnv("APP_LOGO", "https://s3.ap-southeast-2.amazonaws.com/horizon.assets/ecoop-logo.png"),
 		LogLevel:     getEnv("LOG_LEVEL", "info"),
 
+		// Token signing
+		JWTAlgorithm:        getEnv("JWT_ALGORITHM", "EdDSA"),
+		JWTKeysDir:          getEnv("JWT_KEYS_DIR", "keys"),
+		JWTSigningKey:       []byte(os.Getenv("JWT_SIGNING_KEY")),
+		JWTVerificationKeys: []byte(os.Getenv("JWT_VERIFICATION_KEYS")),
+		JWTKeyRotation:      jwtKeyRotation,
+
+		// Password policy
+		PasswordMinLength:    passwordMinLength,
+		PasswordMinClasses:   passwordMinClasses,
+		PasswordMaxAge:       passwordMaxAge,
+		PasswordHistory:      passwordHistory,
+		PasswordBreachedList: os.Getenv("PASSWORD_BREACHED_LIST"),
+
+		// Timesheets
+		TimesheetAutoClose: timesheetAutoClose,
+
+		// OpenID Connect
+		OIDCProviders:   oidcProviders,
+		OIDCRedirectURL: getEnv("OIDC_REDIRECT_URL", appClientUrl+"/auth/oidc/callback"),
+
 		// AWS
 		AWSAccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
 		AWSSecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
@@ -189,6 +293,54 @@ func NewAppConfig() *AppConfig {
 	}
 }
 
+// parseIntEnv returns the integer value of the environment variable named by
+// the key, or defaultValue when it is unset or invalid, which is reported in
+// errList.
+func parseIntEnv(key string, defaultValue int, errList *[]string) int {
+	str := os.Getenv(key)
+	if str == "" {
+		return defaultValue
+	}
+	val, err := strconv.Atoi(str)
+	if err != nil {
+		*errList = append(*errList, fmt.Sprintf("Invalid %s value '%s', defaulting to %d", key, str, defaultValue))
+		return defaultValue
+	}
+	return val
+}
+
+// parseOIDCProviders parses the JSON array of identity providers. Providers
+// sign in Owners and Employees unless accountTypes narrows them down.
+func parseOIDCProviders(data string) ([]OIDCProvider, error) {
+	if data == "" {
+		return nil, nil
+	}
+	var providers []OIDCProvider
+	if err := json.Unmarshal([]byte(data), &providers); err != nil {
+		return nil, err
+	}
+	names := map[string]bool{}
+	for i := range providers {
+		provider := &providers[i]
+		if provider.Name == "" || provider.Issuer == "" || provider.ClientID == "" || provider.CompanyID == 0 {
+			return nil, fmt.Errorf("provider %d needs a name, companyId, issuer and clientId", i)
+		}
+		if names[provider.Name] {
+			return nil, fmt.Errorf("provider name %q is used twice", provider.Name)
+		}
+		names[provider.Name] = true
+		if len(provider.AccountTypes) == 0 {
+			provider.AccountTypes = []string{"Owner", "Employee"}
+		}
+		for _, accountType := range provider.AccountTypes {
+			if accountType != "Owner" && accountType != "Employee" {
+				return nil, fmt.Errorf("provider %q: account type %q cannot sign in with OpenID Connect", provider.Name, accountType)
+			}
+		}
+	}
+	return providers, nil
+}
+
 // getEnv returns the value of the environment variable named by the key.
 // If the variable is not present, it returns defaultValue.
 func getEnv(key, defaultValue string) string {
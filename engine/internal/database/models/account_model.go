@@ -0,0 +1,244 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Account is implemented by the models users sign in as, so that signing up,
+// signing in and verifying are written once for every account type.
+type Account interface {
+	GetID() uint
+	GetPassword() string
+	GetEmail() string
+	GetContactNumber() string
+	GetName() string
+	GetFullName() (firstName, middleName, lastName string)
+	GetStatus() UserStatus
+	GetVerification() AccountVerification
+}
+
+// AccountVerification is what an account has verified, or that it chose to
+// skip verifying.
+type AccountVerification struct {
+	Email         bool
+	ContactNumber bool
+	Skipped       bool
+}
+
+// AccountProfile is what a user fills in to sign up, whatever their account
+// type.
+type AccountProfile struct {
+	FirstName        string
+	LastName         string
+	MiddleName       string
+	PermanentAddress string
+	BirthDate        time.Time
+	Username         string
+	Email            string
+	Password         string
+	ContactNumber    string
+}
+
+// AccountHooks let an account type add its own rules to the auth flows. A
+// hook that returns an error stops the flow with that error.
+type AccountHooks struct {
+	// BeforeSignUp runs before the account is created.
+	BeforeSignUp func(account Account) error
+	// BeforeSignIn runs once the account proved who it is, before the
+	// session starts.
+	BeforeSignIn func(account Account) error
+}
+
+// AccountKind is an account type: how its accounts are stored, looked up and
+// presented. Adding a kind to accountKinds is all a new account type needs to
+// go through the auth flows.
+type AccountKind struct {
+	Name  string
+	Table string
+	// Preloads are the associations of the account in auth responses.
+	Preloads []string
+
+	// Model returns an empty model of the account type, for queries and
+	// updates that go through the audit log.
+	Model func() Account
+	// New builds an account of the type from a sign-up, pending
+	// verification.
+	New        func(profile AccountProfile) Account
+	Create     func(account Account) error
+	FindByID   func(id uint, preloads ...string) (Account, error)
+	FindByKey  func(key string) (Account, error)
+	ToResource func(account Account) interface{}
+	// Footstep attributes the footstep to the account.
+	Footstep func(footstep *Footstep, userId uint)
+
+	Hooks AccountHooks
+}
+
+// accountOf returns the account found by a typed lookup, keeping a missing
+// one nil rather than a typed nil.
+func accountOf[T any, PT interface {
+	*T
+	Account
+}](account PT, err error) (Account, error) {
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+func (m *ModelResource) accountKinds() []*AccountKind {
+	return []*AccountKind{
+		{
+			Name:     "Admin",
+			Table:    "admins",
+			Preloads: []string{"Media", "Role", "Gender"},
+			Model:    func() Account { return &Admin{} },
+			New: func(profile AccountProfile) Account {
+				return &Admin{
+					FirstName:        profile.FirstName,
+					LastName:         profile.LastName,
+					MiddleName:       profile.MiddleName,
+					PermanentAddress: profile.PermanentAddress,
+					BirthDate:        profile.BirthDate,
+					Username:         profile.Username,
+					Email:            profile.Email,
+					Password:         profile.Password,
+					ContactNumber:    profile.ContactNumber,
+					Status:           AdminPending,
+				}
+			},
+			Create: func(account Account) error { return m.AdminCreate(account.(*Admin)) },
+			FindByID: func(id uint, preloads ...string) (Account, error) {
+				return accountOf(m.AdminDB.FindByID(id, preloads...))
+			},
+			FindByKey:  func(key string) (Account, error) { return accountOf(m.AdminFindByEmailUsernameOrContact(key)) },
+			ToResource: func(account Account) interface{} { return m.AdminToResource(account.(*Admin)) },
+			Footstep:   func(footstep *Footstep, userId uint) { footstep.AdminID = &userId },
+		},
+		{
+			Name:     "Owner",
+			Table:    "owners",
+			Preloads: []string{"Media", "Companies", "Gender"},
+			Model:    func() Account { return &Owner{} },
+			New: func(profile AccountProfile) Account {
+				return &Owner{
+					FirstName:        profile.FirstName,
+					LastName:         profile.LastName,
+					MiddleName:       profile.MiddleName,
+					PermanentAddress: profile.PermanentAddress,
+					BirthDate:        profile.BirthDate,
+					Username:         profile.Username,
+					Email:            profile.Email,
+					Password:         profile.Password,
+					ContactNumber:    profile.ContactNumber,
+					Status:           AdminPending,
+				}
+			},
+			Create: func(account Account) error { return m.OwnerCreate(account.(*Owner)) },
+			FindByID: func(id uint, preloads ...string) (Account, error) {
+				return accountOf(m.OwnerDB.FindByID(id, preloads...))
+			},
+			FindByKey:  func(key string) (Account, error) { return accountOf(m.OwnerFindByEmailUsernameOrContact(key)) },
+			ToResource: func(account Account) interface{} { return m.OwnerToResource(account.(*Owner)) },
+			Footstep:   func(footstep *Footstep, userId uint) { footstep.OwnerID = &userId },
+		},
+		{
+			Name:     "Employee",
+			Table:    "employees",
+			Preloads: []string{"Media", "Branch", "Role", "Gender"},
+			Model:    func() Account { return &Employee{} },
+			New: func(profile AccountProfile) Account {
+				return &Employee{
+					FirstName:        profile.FirstName,
+					LastName:         profile.LastName,
+					MiddleName:       profile.MiddleName,
+					PermanentAddress: profile.PermanentAddress,
+					BirthDate:        profile.BirthDate,
+					Username:         profile.Username,
+					Email:            profile.Email,
+					Password:         profile.Password,
+					ContactNumber:    profile.ContactNumber,
+					Status:           AdminPending,
+				}
+			},
+			Create: func(account Account) error { return m.EmployeeCreate(account.(*Employee)) },
+			FindByID: func(id uint, preloads ...string) (Account, error) {
+				return accountOf(m.EmployeeDB.FindByID(id, preloads...))
+			},
+			FindByKey:  func(key string) (Account, error) { return accountOf(m.EmployeeFindByEmailUsernameOrContact(key)) },
+			ToResource: func(account Account) interface{} { return m.EmployeeToResource(account.(*Employee)) },
+			Footstep:   func(footstep *Footstep, userId uint) { footstep.EmployeeID = &userId },
+		},
+		{
+			Name:     "Member",
+			Table:    "members",
+			Preloads: []string{"Media", "Branch", "Role", "Gender"},
+			Model:    func() Account { return &Member{} },
+			New: func(profile AccountProfile) Account {
+				return &Member{
+					FirstName:        profile.FirstName,
+					LastName:         profile.LastName,
+					MiddleName:       profile.MiddleName,
+					PermanentAddress: profile.PermanentAddress,
+					BirthDate:        profile.BirthDate,
+					Username:         profile.Username,
+					Email:            profile.Email,
+					Password:         profile.Password,
+					ContactNumber:    profile.ContactNumber,
+					Status:           AdminPending,
+				}
+			},
+			Create: func(account Account) error { return m.MemberCreate(account.(*Member)) },
+			FindByID: func(id uint, preloads ...string) (Account, error) {
+				return accountOf(m.MemberDB.FindByID(id, preloads...))
+			},
+			FindByKey:  func(key string) (Account, error) { return accountOf(m.MemberFindByEmailUsernameOrContact(key)) },
+			ToResource: func(account Account) interface{} { return m.MemberToResource(account.(*Member)) },
+			Footstep:   func(footstep *Footstep, userId uint) { footstep.MemberID = &userId },
+		},
+	}
+}
+
+// AccountKind returns the account type named accountType.
+func (m *ModelResource) AccountKind(accountType string) (*AccountKind, error) {
+	for _, kind := range m.AccountKinds {
+		if kind.Name == accountType {
+			return kind, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown account type: %s", accountType)
+}
+
+// AccountUpdate sets the columns of updates on the account and returns it
+// with the preloads of its type. Unlike a struct, updates can set a column
+// back to its zero value.
+func (m *ModelResource) AccountUpdate(accountType string, userId uint, updates map[string]interface{}) (Account, error) {
+	kind, err := m.AccountKind(accountType)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.db.Client.Model(kind.Model()).Where("id = ?", userId).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	return kind.FindByID(userId, kind.Preloads...)
+}
+
+// accountTable returns the table of the accounts of accountType.
+func (m *ModelResource) accountTable(accountType string) (string, error) {
+	kind, err := m.AccountKind(accountType)
+	if err != nil {
+		return "", err
+	}
+	return kind.Table, nil
+}
+
+// accountModel returns an empty model of the account type, for updates that
+// go through the audit log.
+func (m *ModelResource) accountModel(accountType string) (Account, error) {
+	kind, err := m.AccountKind(accountType)
+	if err != nil {
+		return nil, err
+	}
+	return kind.Model(), nil
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"strconv"
 	"strings"
@@ -15,20 +16,22 @@ type Admin struct {
 	gorm.Model
 
 	// Fields
-	FirstName          string     `gorm:"type:varchar(255);unsigned" json:"first_name"`
-	LastName           string     `gorm:"type:varchar(255);unsigned" json:"last_name"`
-	MiddleName         string     `gorm:"type:varchar(255)" json:"middle_name"`
-	PermanentAddress   string     `gorm:"type:text" json:"permanent_address"`
-	Description        string     `gorm:"type:text" json:"description"`
-	BirthDate          time.Time  `gorm:"type:date" json:"birth_date"`
-	Username           string     `gorm:"type:varchar(255);unique;unsigned" json:"username"`
-	Email              string     `gorm:"type:varchar(255);unique;unsigned" json:"email"`
-	Password           string     `gorm:"type:varchar(255);unsigned" json:"password"`
-	ContactNumber      string     `gorm:"type:varchar(15);unique;unsigned" json:"contact_number"`
-	IsEmailVerified    bool       `gorm:"default:false" json:"is_email_verified"`
-	IsContactVerified  bool       `gorm:"default:false" json:"is_contact_verified"`
-	IsSkipVerification bool       `gorm:"default:false" json:"is_skip_verification"`
-	Status             UserStatus `gorm:"type:varchar(11);default:'Pending'" json:"status"`
+	FirstName             string     `gorm:"type:varchar(255);unsigned" json:"first_name"`
+	LastName              string     `gorm:"type:varchar(255);unsigned" json:"last_name"`
+	MiddleName            string     `gorm:"type:varchar(255)" json:"middle_name"`
+	PermanentAddress      string     `gorm:"type:text" json:"permanent_address"`
+	Description           string     `gorm:"type:text" json:"description"`
+	BirthDate             time.Time  `gorm:"type:date" json:"birth_date"`
+	Username              string     `gorm:"type:varchar(255);unique;unsigned" json:"username"`
+	Email                 string     `gorm:"type:varchar(255);unique;unsigned" json:"email"`
+	Password              string     `gorm:"type:varchar(255);unsigned" json:"password"`
+	ContactNumber         string     `gorm:"type:varchar(15);unique;unsigned" json:"contact_number"`
+	IsEmailVerified       bool       `gorm:"default:false" json:"is_email_verified"`
+	IsContactVerified     bool       `gorm:"default:false" json:"is_contact_verified"`
+	IsSkipVerification    bool       `gorm:"default:false" json:"is_skip_verification"`
+	PasswordChangedAt     *time.Time `json:"password_changed_at"`
+	PasswordResetRequired bool       `gorm:"default:false" json:"password_reset_required"`
+	Status                UserStatus `gorm:"type:varchar(11);default:'Pending'" json:"status"`
 
 	// Relationship 0 to 1
 	MediaID *uint  `gorm:"type:bigint;unsigned" json:"media_id"`
@@ -52,26 +55,28 @@ type AdminResource struct {
 	CreatedAt   string `json:"createdAt"`
 	UpdatedAt   string `json:"updatedAt"`
 
-	FirstName          string              `json:"firstName"`
-	LastName           string              `json:"lastName"`
-	MiddleName         string              `json:"middleName"`
-	PermanentAddress   string              `json:"permanentAddress"`
-	Description        string              `json:"description"`
-	BirthDate          time.Time           `json:"birthDate"`
-	Username           string              `json:"username"`
-	Email              string              `json:"email"`
-	ContactNumber      string              `json:"contactNumber"`
-	IsEmailVerified    bool                `json:"isEmailVerified"`
-	IsContactVerified  bool                `json:"isContactVerified"`
-	IsSkipVerification bool                `json:"isSkipVerification"`
-	Status             UserStatus          `json:"status"`
-	MediaID            *uint               `json:"mediaID"`
-	Media              *MediaResource      `json:"media"`
-	RoleID             *uint               `json:"roleID"`
-	Role               *RoleResource       `json:"role"`
-	GenderID           *uint               `json:"genderID"`
-	Gender             *GenderResource     `json:"gender"`
-	Footsteps          []*FootstepResource `json:"footsteps"`
+	FirstName             string              `json:"firstName"`
+	LastName              string              `json:"lastName"`
+	MiddleName            string              `json:"middleName"`
+	PermanentAddress      string              `json:"permanentAddress"`
+	Description           string              `json:"description"`
+	BirthDate             time.Time           `json:"birthDate"`
+	Username              string              `json:"username"`
+	Email                 string              `json:"email"`
+	ContactNumber         string              `json:"contactNumber"`
+	IsEmailVerified       bool                `json:"isEmailVerified"`
+	IsContactVerified     bool                `json:"isContactVerified"`
+	IsSkipVerification    bool                `json:"isSkipVerification"`
+	PasswordChangedAt     *string             `json:"passwordChangedAt"`
+	PasswordResetRequired bool                `json:"passwordResetRequired"`
+	Status                UserStatus          `json:"status"`
+	MediaID               *uint               `json:"mediaID"`
+	Media                 *MediaResource      `json:"media"`
+	RoleID                *uint               `json:"roleID"`
+	Role                  *RoleResource       `json:"role"`
+	GenderID              *uint               `json:"genderID"`
+	Gender                *GenderResource     `json:"gender"`
+	Footsteps             []*FootstepResource `json:"footsteps"`
 }
 
 type AdminRequest struct {
@@ -105,26 +110,28 @@ func (m *ModelResource) AdminToResource(admin *Admin) *AdminResource {
 		CreatedAt:   admin.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:   admin.UpdatedAt.Format(time.RFC3339),
 
-		FirstName:          admin.FirstName,
-		LastName:           admin.LastName,
-		MiddleName:         admin.MiddleName,
-		PermanentAddress:   admin.PermanentAddress,
-		Description:        admin.Description,
-		BirthDate:          admin.BirthDate,
-		Username:           admin.Username,
-		Email:              admin.Email,
-		ContactNumber:      admin.ContactNumber,
-		IsEmailVerified:    admin.IsEmailVerified,
-		IsContactVerified:  admin.IsContactVerified,
-		IsSkipVerification: admin.IsSkipVerification,
-		Status:             admin.Status,
-		MediaID:            admin.MediaID,
-		Media:              m.MediaToResource(admin.Media),
-		RoleID:             admin.RoleID,
-		Role:               m.RoleToResource(admin.Role),
-		GenderID:           admin.GenderID,
-		Gender:             m.GenderToResource(admin.Gender),
-		Footsteps:          m.FootstepToResourceList(admin.Footsteps),
+		FirstName:             admin.FirstName,
+		LastName:              admin.LastName,
+		MiddleName:            admin.MiddleName,
+		PermanentAddress:      admin.PermanentAddress,
+		Description:           admin.Description,
+		BirthDate:             admin.BirthDate,
+		Username:              admin.Username,
+		Email:                 admin.Email,
+		ContactNumber:         admin.ContactNumber,
+		IsEmailVerified:       admin.IsEmailVerified,
+		IsContactVerified:     admin.IsContactVerified,
+		IsSkipVerification:    admin.IsSkipVerification,
+		PasswordChangedAt:     formatOptionalTime(admin.PasswordChangedAt),
+		PasswordResetRequired: admin.PasswordResetRequired,
+		Status:                admin.Status,
+		MediaID:               admin.MediaID,
+		Media:                 m.MediaToResource(admin.Media),
+		RoleID:                admin.RoleID,
+		Role:                  m.RoleToResource(admin.Role),
+		GenderID:              admin.GenderID,
+		Gender:                m.GenderToResource(admin.Gender),
+		Footsteps:             m.FootstepToResourceList(admin.Footsteps),
 	}
 }
 
@@ -278,15 +285,25 @@ func (r *ModelResource) AdminFindByEmailUsernameOrContact(input string) (*Admin,
 		return r.AdminGetByUsername(input)
 	}
 }
-func (r *ModelResource) AdminUpdatePassword(id uint, password string) error {
-	newPassword, err := r.cryptoHelpers.HashPassword(password)
-	if err != nil {
-		return err
+
+// Admin is an Account, for the auth flows.
+func (admin *Admin) GetID() uint              { return admin.ID }
+func (admin *Admin) GetPassword() string      { return admin.Password }
+func (admin *Admin) GetEmail() string         { return admin.Email }
+func (admin *Admin) GetContactNumber() string { return admin.ContactNumber }
+func (admin *Admin) GetStatus() UserStatus    { return admin.Status }
+func (admin *Admin) GetName() string          { return fmt.Sprintf("%s %s", admin.FirstName, admin.LastName) }
+func (admin *Admin) GetFullName() (string, string, string) {
+	return admin.FirstName, admin.MiddleName, admin.LastName
+}
+func (admin *Admin) GetVerification() AccountVerification {
+	return AccountVerification{
+		Email:         admin.IsEmailVerified,
+		ContactNumber: admin.IsContactVerified,
+		Skipped:       admin.IsSkipVerification,
 	}
-	updated := &Admin{Password: newPassword}
-	_, err = r.AdminDB.UpdateColumns(id, *updated, []string{"Media"})
-	return err
 }
+
 func (r *ModelResource) AdminCreate(user *Admin) error {
 	if user == nil {
 		return errors.New("user cannot be nil")
@@ -299,6 +316,8 @@ func (r *ModelResource) AdminCreate(user *Admin) error {
 		return err
 	}
 	user.Password = hashedPassword
+	now := time.Now()
+	user.PasswordChangedAt = &now
 	return r.AdminDB.Create(user)
 }
 
@@ -312,6 +331,8 @@ func (r *ModelResource) AdminUpdate(user *Admin, preloads []string) error {
 			return err
 		}
 		user.Password = hashedPassword
+		now := time.Now()
+		user.PasswordChangedAt = &now
 	}
 	return r.AdminDB.Update(user, preloads)
 }
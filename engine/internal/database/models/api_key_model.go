@@ -0,0 +1,353 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
+	"github.com/go-playground/validator"
+	"gorm.io/gorm"
+)
+
+const (
+	// ApiKeyAccountType is the account type of the claims of requests
+	// authenticated with an API key.
+	ApiKeyAccountType = "ApiKey"
+	// apiKeyPrefix starts every key so that leaked keys are easy to spot.
+	apiKeyPrefix = "hzk_"
+	// apiKeyDisplayLength is the length of the start of a key kept in clear
+	// to tell keys apart.
+	apiKeyDisplayLength = 12
+)
+
+var (
+	ErrApiKeyNotFound = errors.New("API key not found or revoked")
+	ErrApiKeyCompany  = errors.New("company not found or not yours")
+	// ErrApiKeyRole is returned when the role of a key grants more than its
+	// creator may do.
+	ErrApiKeyRole = errors.New("the role grants permissions you do not have")
+)
+
+// ApiKey authenticates a machine client of a company with the permissions
+// of its role, capped by those of its creator. Only a HashToken hash of the
+// key is stored; the key itself is shown once, when it is created or
+// rotated.
+type ApiKey struct {
+	gorm.Model
+
+	// Fields
+	Name       string     `gorm:"type:varchar(255)" json:"name"`
+	Prefix     string     `gorm:"type:varchar(16)" json:"prefix"`
+	KeyHash    string     `gorm:"type:varchar(255);uniqueIndex" json:"-"`
+	AllowedIPs string     `gorm:"type:text" json:"allowed_ips"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP string     `gorm:"type:varchar(45)" json:"last_used_ip"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+
+	CreatorAccountType string `gorm:"type:varchar(16)" json:"creator_account_type"`
+	CreatorID          uint   `gorm:"type:bigint;unsigned" json:"creator_id"`
+
+	// Relationship 1 to many
+	CompanyID uint     `gorm:"type:bigint;unsigned;index" json:"company_id"`
+	Company   *Company `gorm:"foreignKey:CompanyID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"company"`
+
+	// Relationship 1 to many
+	RoleID uint  `gorm:"type:bigint;unsigned;index" json:"role_id"`
+	Role   *Role `gorm:"foreignKey:RoleID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"role"`
+}
+
+type ApiKeyResource struct {
+	ID        uint   `json:"id"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+
+	Name       string        `json:"name"`
+	Prefix     string        `json:"prefix"`
+	AllowedIPs []string      `json:"allowedIps"`
+	ExpiresAt  *string       `json:"expiresAt"`
+	LastUsedAt *string       `json:"lastUsedAt"`
+	LastUsedIP string        `json:"lastUsedIp"`
+	RevokedAt  *string       `json:"revokedAt"`
+	CompanyID  uint          `json:"companyID"`
+	RoleID     uint          `json:"roleID"`
+	Role       *RoleResource `json:"role"`
+
+	CreatorAccountType string `json:"creatorAccountType"`
+	CreatorID          uint   `json:"creatorID"`
+
+	// Key is only set in the response that creates or rotates the key
+	Key string `json:"key,omitempty"`
+}
+
+type ApiKeyRequest struct {
+	Name       string     `json:"name" validate:"required,max=255"`
+	CompanyID  uint       `json:"companyID" validate:"required"`
+	RoleID     uint       `json:"roleID" validate:"required"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	AllowedIPs []string   `json:"allowedIps,omitempty" validate:"max=50"`
+}
+
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format(time.RFC3339)
+	return &formatted
+}
+
+func (m *ModelResource) ApiKeyToResource(apiKey *ApiKey) *ApiKeyResource {
+	if apiKey == nil {
+		return nil
+	}
+	allowedIPs := []string{}
+	if apiKey.AllowedIPs != "" {
+		allowedIPs = strings.Split(apiKey.AllowedIPs, ",")
+	}
+	return &ApiKeyResource{
+		ID:        apiKey.ID,
+		CreatedAt: apiKey.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: apiKey.UpdatedAt.Format(time.RFC3339),
+
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		AllowedIPs: allowedIPs,
+		ExpiresAt:  formatOptionalTime(apiKey.ExpiresAt),
+		LastUsedAt: formatOptionalTime(apiKey.LastUsedAt),
+		LastUsedIP: apiKey.LastUsedIP,
+		RevokedAt:  formatOptionalTime(apiKey.RevokedAt),
+		CompanyID:  apiKey.CompanyID,
+		RoleID:     apiKey.RoleID,
+		Role:       m.RoleToResource(apiKey.Role),
+
+		CreatorAccountType: apiKey.CreatorAccountType,
+		CreatorID:          apiKey.CreatorID,
+	}
+}
+
+func (m *ModelResource) ApiKeyToResourceList(apiKeys []*ApiKey) []*ApiKeyResource {
+	if apiKeys == nil {
+		return nil
+	}
+	var apiKeyResources []*ApiKeyResource
+	for _, apiKey := range apiKeys {
+		apiKeyResources = append(apiKeyResources, m.ApiKeyToResource(apiKey))
+	}
+	return apiKeyResources
+}
+
+func (m *ModelResource) ValidateApiKeyRequest(req *ApiKeyRequest) error {
+	validate := validator.New()
+	err := validate.Struct(req)
+	if err != nil {
+		return m.helpers.FormatValidationError(err)
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return errors.New("expiresAt must be in the future")
+	}
+	for _, ip := range req.AllowedIPs {
+		if net.ParseIP(ip) == nil {
+			if _, _, err := net.ParseCIDR(ip); err != nil {
+				return fmt.Errorf("invalid IP address or CIDR range: %s", ip)
+			}
+		}
+	}
+	return nil
+}
+
+// Allows reports whether the key may be used from ip. Keys without an
+// allowlist may be used from anywhere.
+func (apiKey *ApiKey) Allows(ip string) bool {
+	if apiKey.AllowedIPs == "" {
+		return true
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, allowed := range strings.Split(apiKey.AllowedIPs, ",") {
+		if _, network, err := net.ParseCIDR(allowed); err == nil {
+			if network.Contains(parsed) {
+				return true
+			}
+		} else if allowedIP := net.ParseIP(allowed); allowedIP != nil && allowedIP.Equal(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+func apiKeyActive(db *gorm.DB) *gorm.DB {
+	return db.Where("revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", time.Now())
+}
+
+// apiKeyGenerate returns a new key along with its hash and displayed prefix.
+func (m *ModelResource) apiKeyGenerate() (key, hash, prefix string, err error) {
+	secret, err := m.cryptoHelpers.GenerateSecureToken(32)
+	if err != nil {
+		return "", "", "", err
+	}
+	key = apiKeyPrefix + secret
+	return key, m.cryptoHelpers.HashToken(key), key[:apiKeyDisplayLength], nil
+}
+
+// ApiKeyCreate creates a key for the company on behalf of its creator and
+// returns it along with the key itself. The role of the key may not grant
+// more than the creator may do, or ErrApiKeyRole is returned.
+func (m *ModelResource) ApiKeyCreate(req *ApiKeyRequest, creatorAccountType string, creatorId uint) (*ApiKey, string, error) {
+	key, hash, prefix, err := m.apiKeyGenerate()
+	if err != nil {
+		return nil, "", err
+	}
+	apiKey := &ApiKey{
+		Name:       req.Name,
+		Prefix:     prefix,
+		KeyHash:    hash,
+		AllowedIPs: strings.Join(req.AllowedIPs, ","),
+		ExpiresAt:  req.ExpiresAt,
+		CompanyID:  req.CompanyID,
+		RoleID:     req.RoleID,
+
+		CreatorAccountType: creatorAccountType,
+		CreatorID:          creatorId,
+	}
+	creatorPermissions, err := m.permissionGetForCreator(creatorAccountType, creatorId)
+	if err != nil {
+		return nil, "", err
+	}
+	err = m.db.Client.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&Role{}, req.RoleID).Error; err != nil {
+			return err
+		}
+		var permissions []*Permission
+		if err := tx.Where("role_id = ?", req.RoleID).Find(&permissions).Error; err != nil {
+			return err
+		}
+		for _, permission := range apiKeyNarrow(m.PermissionToResourceList(permissions)) {
+			if !permissionScopeFor(creatorPermissions, permission.Resource, permission.Action).Includes(PermissionScope(permission.Scope)) {
+				return ErrApiKeyRole
+			}
+		}
+		if err := tx.First(&Company{}, req.CompanyID).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(apiKey).Error; err != nil {
+			return err
+		}
+		return tx.Preload("Role").First(apiKey, apiKey.ID).Error
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return apiKey, key, nil
+}
+
+// ApiKeyList returns the keys within scope, which may be nil, optionally of a
+// single company, newest first.
+func (m *ModelResource) ApiKeyList(scope managers.ScopeFunc, companyId *uint) ([]*ApiKey, error) {
+	query := m.db.Client.Preload("Role")
+	if scope != nil {
+		query = query.Scopes(scope)
+	}
+	if companyId != nil {
+		query = query.Where("company_id = ?", *companyId)
+	}
+	var apiKeys []*ApiKey
+	err := query.Order("created_at DESC").Find(&apiKeys).Error
+	return apiKeys, err
+}
+
+// apiKeyFindActive returns the key with the id within scope unless it was
+// revoked or expired.
+func (m *ModelResource) apiKeyFindActive(tx *gorm.DB, scope managers.ScopeFunc, id uint) (*ApiKey, error) {
+	query := tx.Scopes(apiKeyActive)
+	if scope != nil {
+		query = query.Scopes(scope)
+	}
+	var apiKeys []*ApiKey
+	if err := query.Where("id = ?", id).Limit(1).Find(&apiKeys).Error; err != nil {
+		return nil, err
+	}
+	if len(apiKeys) == 0 {
+		return nil, ErrApiKeyNotFound
+	}
+	return apiKeys[0], nil
+}
+
+// ApiKeyRotate replaces the key with the id by a new one, keeping its
+// settings, and returns it along with the new key. The old key stops working
+// at once.
+func (m *ModelResource) ApiKeyRotate(scope managers.ScopeFunc, id uint) (*ApiKey, string, error) {
+	key, hash, prefix, err := m.apiKeyGenerate()
+	if err != nil {
+		return nil, "", err
+	}
+	var apiKey *ApiKey
+	err = m.db.Client.Transaction(func(tx *gorm.DB) error {
+		found, err := m.apiKeyFindActive(tx, scope, id)
+		if err != nil {
+			return err
+		}
+		apiKey = found
+		if err := tx.Model(apiKey).Updates(map[string]interface{}{
+			"key_hash": hash,
+			"prefix":   prefix,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Preload("Role").First(apiKey, apiKey.ID).Error
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return apiKey, key, nil
+}
+
+// ApiKeyRevoke revokes the key with the id.
+func (m *ModelResource) ApiKeyRevoke(scope managers.ScopeFunc, id uint) (*ApiKey, error) {
+	apiKey, err := m.apiKeyFindActive(m.db.Client, scope, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := m.db.Client.Model(apiKey).Update("revoked_at", now).Error; err != nil {
+		return nil, err
+	}
+	apiKey.RevokedAt = &now
+	return apiKey, nil
+}
+
+// ApiKeyAuthenticate returns the active key matching keyHash, or nil when
+// there is none.
+func (m *ModelResource) ApiKeyAuthenticate(keyHash string) (*ApiKey, error) {
+	var apiKeys []*ApiKey
+	err := m.db.Client.Scopes(apiKeyActive).Where("key_hash = ?", keyHash).Limit(1).Find(&apiKeys).Error
+	if err != nil || len(apiKeys) == 0 {
+		return nil, err
+	}
+	return apiKeys[0], nil
+}
+
+// ApiKeyTouch records a use of the key from ip. It is a raw statement so
+// that usage is not recorded in the audit log; it goes to footsteps instead.
+func (m *ModelResource) ApiKeyTouch(id uint, ip string) error {
+	return m.db.Client.Exec("UPDATE api_keys SET last_used_at = ?, last_used_ip = ? WHERE id = ?", time.Now(), ip, id).Error
+}
+
+// ApiKeyFootstep records a use of the key.
+func (m *ModelResource) ApiKeyFootstep(id uint, activity, description string) error {
+	return m.FootstepDB.Create(&Footstep{
+		AccountType: ApiKeyAccountType,
+		Activity:    activity,
+		Description: description,
+		ApiKeyID:    &id,
+	})
+}
+
+func (m *ModelResource) ApiKeySeeders() error {
+	m.logger.Info("Seeding ApiKey")
+	return nil
+}
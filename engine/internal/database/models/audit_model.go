@@ -0,0 +1,115 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers/filter"
+	"gorm.io/gorm"
+)
+
+// AuditLog records a single create, update or delete of a row, written by the
+// audit callbacks in the transaction of the change.
+type AuditLog struct {
+	gorm.Model
+
+	// Actor, empty for changes made by the system
+	ActorAccountType string `gorm:"type:varchar(11);index" json:"actor_account_type"`
+	ActorID          *uint  `gorm:"index" json:"actor_id"`
+	// ImpersonatorID is the Admin who made the change as the actor
+	ImpersonatorID *uint `gorm:"index" json:"impersonator_id"`
+
+	// Fields
+	EntityType string `gorm:"type:varchar(255);index:idx_audit_entity" json:"entity_type"`
+	EntityID   uint   `gorm:"index:idx_audit_entity" json:"entity_id"`
+	Action     string `gorm:"type:varchar(10);index" json:"action"`
+	Changes    string `gorm:"type:json" json:"changes"`
+
+	// Request
+	RequestID string `gorm:"type:varchar(64);index" json:"request_id"`
+	IP        string `gorm:"type:varchar(45)" json:"ip"`
+}
+
+type AuditLogResource struct {
+	ID        uint   `json:"id"`
+	CreatedAt string `json:"createdAt"`
+
+	ActorAccountType string          `json:"actorAccountType"`
+	ActorID          *uint           `json:"actorID"`
+	ImpersonatorID   *uint           `json:"impersonatorID"`
+	EntityType       string          `json:"entityType"`
+	EntityID         uint            `json:"entityID"`
+	Action           string          `json:"action"`
+	Changes          json.RawMessage `json:"changes"`
+	RequestID        string          `json:"requestID"`
+	IP               string          `json:"ip"`
+}
+
+func (m *ModelResource) AuditLogToResource(auditLog *AuditLog) *AuditLogResource {
+	if auditLog == nil {
+		return nil
+	}
+	changes := json.RawMessage(auditLog.Changes)
+	if !json.Valid(changes) {
+		changes = json.RawMessage("{}")
+	}
+	return &AuditLogResource{
+		ID:        auditLog.ID,
+		CreatedAt: auditLog.CreatedAt.Format(time.RFC3339),
+
+		ActorAccountType: auditLog.ActorAccountType,
+		ActorID:          auditLog.ActorID,
+		ImpersonatorID:   auditLog.ImpersonatorID,
+		EntityType:       auditLog.EntityType,
+		EntityID:         auditLog.EntityID,
+		Action:           auditLog.Action,
+		Changes:          changes,
+		RequestID:        auditLog.RequestID,
+		IP:               auditLog.IP,
+	}
+}
+
+func (m *ModelResource) AuditLogToResourceList(auditLogs []*AuditLog) []*AuditLogResource {
+	if auditLogs == nil {
+		return nil
+	}
+	var auditLogResources []*AuditLogResource
+	for _, auditLog := range auditLogs {
+		auditLogResources = append(auditLogResources, m.AuditLogToResource(auditLog))
+	}
+	return auditLogResources
+}
+
+// AuditRecord implements managers.AuditRecorder.
+func (m *ModelResource) AuditRecord(tx *gorm.DB, entries []managers.AuditEntry) error {
+	auditLogs := make([]*AuditLog, 0, len(entries))
+	for _, entry := range entries {
+		changes, err := json.Marshal(entry.Changes)
+		if err != nil {
+			return err
+		}
+		auditLogs = append(auditLogs, &AuditLog{
+			ActorAccountType: entry.Actor.AccountType,
+			ActorID:          entry.Actor.ID,
+			ImpersonatorID:   entry.Actor.ImpersonatorID,
+			EntityType:       entry.EntityType,
+			EntityID:         entry.EntityID,
+			Action:           entry.Action,
+			Changes:          string(changes),
+			RequestID:        entry.Actor.RequestID,
+			IP:               entry.Actor.IP,
+		})
+	}
+	return tx.Create(&auditLogs).Error
+}
+
+func (m *ModelResource) AuditLogFilter(filters string, pageSize, pageIndex int) (filter.FilterPages[AuditLog], error) {
+	db := m.db.Client
+	return m.AuditLogDB.GetPaginatedResult(db, filters, pageSize, pageIndex)
+}
+
+func (m *ModelResource) AuditLogSeeders() error {
+	m.logger.Info("Seeding AuditLog")
+	return nil
+}
@@ -2,10 +2,10 @@ package models
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers/filter"
 	"github.com/go-playground/validator"
 	"gorm.io/gorm"
@@ -59,15 +59,27 @@ type BranchResource struct {
 }
 
 type BranchRequest struct {
-	Name            string  `json:"name" validate:"required,max=255"`
-	Address         string  `json:"address" validate:"max=500"`
-	Longitude       float64 `json:"longitude" validate:"longitude"`
-	Latitude        float64 `json:"latitude" validate:"latitude"`
-	Email           string  `json:"email" validate:"required,email,max=255"`
-	ContactNumber   string  `json:"contactNumber" validate:"required,max=15"`
-	IsAdminVerified bool    `json:"isAdminVerified"`
-	MediaID         *uint   `json:"mediaID,omitempty"`
-	CompanyID       *uint   `json:"companyID" validate:"required"`
+	Name          string  `json:"name" validate:"required,max=255"`
+	Address       string  `json:"address" validate:"max=500"`
+	Longitude     float64 `json:"longitude" validate:"longitude"`
+	Latitude      float64 `json:"latitude" validate:"latitude"`
+	Email         string  `json:"email" validate:"required,email,max=255"`
+	ContactNumber string  `json:"contactNumber" validate:"required,max=15"`
+	MediaID       *uint   `json:"mediaID,omitempty"`
+	CompanyID     *uint   `json:"companyID" validate:"required"`
+}
+
+// BranchAssignRequest moves an employee or member to another branch.
+type BranchAssignRequest struct {
+	BranchID uint `json:"branchID" validate:"required"`
+}
+
+func (m *ModelResource) ValidateBranchAssignRequest(req *BranchAssignRequest) error {
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		return m.helpers.FormatValidationError(err)
+	}
+	return nil
 }
 
 func (m *ModelResource) BranchToResource(branch *Branch) *BranchResource {
@@ -106,93 +118,72 @@ func (m *ModelResource) BranchToResourceList(branch []*Branch) []*BranchResource
 	return branchResources
 }
 
-// BranchToRecord converts a slice of Branch pointers into CSV records and headers.
-func (m *ModelResource) BranchToRecord(branches []*Branch) ([][]string, []string) {
-	// Convert Branch structs to BranchResource structs
-	resource := m.BranchToResourceList(branches)
-	records := make([][]string, 0, len(resource))
-
-	for _, branch := range resource {
-		id := strconv.Itoa(int(branch.ID))
-		name := sanitizeCSVField(branch.Name)
-		address := sanitizeCSVField(branch.Address)
-		longitude := fmt.Sprintf("%.6f", branch.Longitude)
-		latitude := fmt.Sprintf("%.6f", branch.Latitude)
-		email := sanitizeCSVField(branch.Email)
-		contactNumber := sanitizeCSVField(branch.ContactNumber)
-		isAdminVerified := strconv.FormatBool(branch.IsAdminVerified)
-		createdAt := sanitizeCSVField(branch.CreatedAt)
-		updatedAt := sanitizeCSVField(branch.UpdatedAt)
-
-		// Handle Media
-		mediaURL := "N/A"
-		if branch.Media != nil {
-			mediaURL = sanitizeCSVField(branch.Media.URL)
-		}
+// BranchExportColumns returns the columns of a branch export.
+func (m *ModelResource) BranchExportColumns() []managers.ExportColumn {
+	return []managers.ExportColumn{
+		{Header: "ID", Width: 8},
+		{Header: "Name", Width: 30},
+		{Header: "Address", Width: 40},
+		{Header: "Longitude", Width: 14},
+		{Header: "Latitude", Width: 14},
+		{Header: "Email", Width: 30},
+		{Header: "Contact Number", Width: 18},
+		{Header: "Is Admin Verified", Width: 18},
+		{Header: "Media URL", Width: 40},
+		{Header: "Company Name", Width: 30},
+		{Header: "Employees", Width: 40},
+		{Header: "Members", Width: 40},
+		{Header: "Created At", Width: 20},
+		{Header: "Updated At", Width: 20},
+	}
+}
 
-		// Handle Company
-		companyName := "N/A"
-		if branch.Company != nil {
-			companyName = sanitizeCSVField(branch.Company.Name)
-		}
+// BranchToExportRow converts a branch into a typed export row matching BranchExportColumns.
+func (m *ModelResource) BranchToExportRow(branch *Branch) []interface{} {
+	mediaURL := "N/A"
+	if branch.Media != nil {
+		mediaURL = sanitizeCSVField(branch.Media.URL)
+	}
 
-		// Handle Employees
-		employeeNames := "N/A"
-		if len(branch.Employees) > 0 {
-			empNames := make([]string, 0, len(branch.Employees))
-			for _, emp := range branch.Employees {
-				empNames = append(empNames, sanitizeCSVField(emp.FirstName))
-			}
-			employeeNames = strings.Join(empNames, "; ")
-		}
+	companyName := "N/A"
+	if branch.Company != nil {
+		companyName = sanitizeCSVField(branch.Company.Name)
+	}
 
-		// Handle Members
-		memberNames := "N/A"
-		if len(branch.Members) > 0 {
-			memNames := make([]string, 0, len(branch.Members))
-			for _, mem := range branch.Members {
-				memNames = append(memNames, sanitizeCSVField(mem.FirstName))
-			}
-			memberNames = strings.Join(memNames, "; ")
+	employeeNames := "N/A"
+	if len(branch.Employees) > 0 {
+		empNames := make([]string, 0, len(branch.Employees))
+		for _, emp := range branch.Employees {
+			empNames = append(empNames, sanitizeCSVField(emp.FirstName))
 		}
+		employeeNames = strings.Join(empNames, "; ")
+	}
 
-		record := []string{
-			id,
-			name,
-			address,
-			longitude,
-			latitude,
-			email,
-			contactNumber,
-			isAdminVerified,
-			mediaURL,
-			companyName,
-			employeeNames,
-			memberNames,
-			createdAt,
-			updatedAt,
+	memberNames := "N/A"
+	if len(branch.Members) > 0 {
+		memNames := make([]string, 0, len(branch.Members))
+		for _, mem := range branch.Members {
+			memNames = append(memNames, sanitizeCSVField(mem.FirstName))
 		}
-		records = append(records, record)
+		memberNames = strings.Join(memNames, "; ")
 	}
 
-	headers := []string{
-		"ID",
-		"Name",
-		"Address",
-		"Longitude",
-		"Latitude",
-		"Email",
-		"Contact Number",
-		"Is Admin Verified",
-		"Media URL",
-		"Company Name",
-		"Employees",
-		"Members",
-		"Created At",
-		"Updated At",
+	return []interface{}{
+		branch.ID,
+		sanitizeCSVField(branch.Name),
+		sanitizeCSVField(branch.Address),
+		branch.Longitude,
+		branch.Latitude,
+		sanitizeCSVField(branch.Email),
+		sanitizeCSVField(branch.ContactNumber),
+		branch.IsAdminVerified,
+		mediaURL,
+		companyName,
+		employeeNames,
+		memberNames,
+		branch.CreatedAt,
+		branch.UpdatedAt,
 	}
-
-	return records, headers
 }
 
 func (m *ModelResource) ValidateBranchRequest(req *BranchRequest) error {
@@ -213,6 +204,25 @@ func (m *ModelResource) ValidateBranchRequest(req *BranchRequest) error {
 	return nil
 }
 
+// ValidateBranchRequestForOwner validates the request and makes sure the
+// branch belongs to one of the owner's companies.
+func (m *ModelResource) ValidateBranchRequestForOwner(req *BranchRequest, ownerId uint) error {
+	if err := m.ValidateBranchRequest(req); err != nil {
+		return err
+	}
+	var count int64
+	err := m.db.Client.Model(&Company{}).
+		Where("id = ? AND owner_id = ?", req.CompanyID, ownerId).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("company %d does not belong to the owner", *req.CompanyID)
+	}
+	return nil
+}
+
 func (m *ModelResource) BranchFilterForAdmin(filters string, pageSize, pageIndex int) (filter.FilterPages[Branch], error) {
 	db := m.db.Client
 	return m.BranchDB.GetPaginatedResult(db, filters, pageSize, pageIndex)
@@ -224,21 +234,51 @@ func (m *ModelResource) BranchFilterForAdminRecord(filters string) ([]*Branch, e
 }
 
 func (m *ModelResource) BranchFilterForOwner(filters string, ownerId uint, pageSize int, pageIndex int) (filter.FilterPages[Branch], error) {
-	db := m.db.Client.Where("owner_id = ?", ownerId)
+	db := m.db.Client.Where("company_id IN (SELECT id FROM companies WHERE owner_id = ?)", ownerId)
 	return m.BranchDB.GetPaginatedResult(db, filters, pageSize, pageIndex)
 }
 
 func (m *ModelResource) BranchFilterForOwnerRecord(filters string, ownerId uint) ([]*Branch, error) {
-	db := m.db.Client.Where("owner_id = ?", ownerId)
+	db := m.db.Client.Where("company_id IN (SELECT id FROM companies WHERE owner_id = ?)", ownerId)
 	return m.BranchDB.GetFilteredResults(db, filters)
 }
 
+func (m *ModelResource) BranchStreamAllForAdmin(fn func(batch []*Branch) error) error {
+	db := m.db.Client
+	return m.BranchDB.StreamAll(db, managers.ExportBatchSize, fn)
+}
+
+func (m *ModelResource) BranchStreamAllForOwner(ownerId uint, fn func(batch []*Branch) error) error {
+	db := m.db.Client.Where("company_id IN (SELECT id FROM companies WHERE owner_id = ?)", ownerId)
+	return m.BranchDB.StreamAll(db, managers.ExportBatchSize, fn)
+}
+
+func (m *ModelResource) BranchStreamFilterForAdmin(filters string, fn func(batch []*Branch) error) error {
+	db := m.db.Client
+	return m.BranchDB.StreamFilteredResults(db, filters, managers.ExportBatchSize, fn)
+}
+
+func (m *ModelResource) BranchStreamFilterForOwner(filters string, ownerId uint, fn func(batch []*Branch) error) error {
+	db := m.db.Client.Where("company_id IN (SELECT id FROM companies WHERE owner_id = ?)", ownerId)
+	return m.BranchDB.StreamFilteredResults(db, filters, managers.ExportBatchSize, fn)
+}
+
+func (m *ModelResource) BranchAggregateForAdmin(aggregate string) ([]map[string]interface{}, error) {
+	db := m.db.Client
+	return m.BranchDB.GetAggregatedResults(db, aggregate)
+}
+
+func (m *ModelResource) BranchAggregateForOwner(aggregate string, ownerId uint) ([]map[string]interface{}, error) {
+	db := m.db.Client.Where("company_id IN (SELECT id FROM companies WHERE owner_id = ?)", ownerId)
+	return m.BranchDB.GetAggregatedResults(db, aggregate)
+}
+
 func (m *ModelResource) BranchGetAllForAdmin() ([]*Branch, error) {
 	return m.BranchDB.FindAll()
 }
 
 func (m *ModelResource) BranchGetAllForOwner(id uint) ([]*Branch, error) {
-	db := m.db.Client.Where("owner_id = ?", id)
+	db := m.db.Client.Where("company_id IN (SELECT id FROM companies WHERE owner_id = ?)", id)
 	return m.BranchDB.FindWithQuery(db)
 }
 
@@ -2,10 +2,10 @@ package models
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers/filter"
 	"github.com/go-playground/validator"
 	"gorm.io/gorm"
@@ -31,6 +31,21 @@ type Company struct {
 	Media           *Media `gorm:"foreignKey:MediaID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"media"`
 	IsAdminVerified bool   `gorm:"default:false" json:"is_admin_verified"`
 
+	// PasswordlessAccountTypes lists, comma separated, the account types of the
+	// company that may sign in without a password
+	PasswordlessAccountTypes string `gorm:"type:varchar(64)" json:"passwordless_account_types"`
+
+	// Timezone is the IANA time zone schedules of the company are in
+	Timezone string `gorm:"type:varchar(64);default:'UTC'" json:"timezone"`
+	// RestDays lists, comma separated, the weekdays off (Sun, Mon, ...) of
+	// employees whose shift assignment has none
+	RestDays string `gorm:"type:varchar(32)" json:"rest_days"`
+
+	// GeofencePolicy is what happens to punches farther than GeofenceRadius
+	// meters from the branch of the employee: Off, Flag or Reject
+	GeofencePolicy string `gorm:"type:varchar(16);default:'Off'" json:"geofence_policy"`
+	GeofenceRadius int    `gorm:"default:100" json:"geofence_radius"`
+
 	// Relationship 0 to many
 	Branches []*Branch `gorm:"foreignKey:CompanyID" json:"branches"`
 }
@@ -52,18 +67,37 @@ type CompanyResource struct {
 	Media           *MediaResource    `json:"media"`
 	IsAdminVerified bool              `json:"isAdminVerified"`
 	Branches        []*BranchResource `json:"branches"`
+
+	PasswordlessAccountTypes []string `json:"passwordlessAccountTypes"`
+	Timezone                 string   `json:"timezone"`
+	RestDays                 []string `json:"restDays"`
+	GeofencePolicy           string   `json:"geofencePolicy"`
+	GeofenceRadius           int      `json:"geofenceRadius"`
 }
 
+// CompanyRequest creates or updates a company. Owners own the companies they
+// create; admins verify companies and transfer them through their own routes.
 type CompanyRequest struct {
-	Name            string  `json:"name" validate:"required,max=255"`
-	Description     string  `json:"description,omitempty"`
-	Address         string  `json:"address" validate:"required,max=500"`
-	Longitude       float64 `json:"longitude" validate:"longitude"`
-	Latitude        float64 `json:"latitude" validate:"latitude"`
-	ContactNumber   string  `json:"contactNumber" validate:"required,max=255"`
-	OwnerID         *uint   `json:"ownerID,omitempty"`
-	MediaID         *uint   `json:"mediaID,omitempty"`
-	IsAdminVerified bool    `json:"isAdminVerified"`
+	Name          string  `json:"name" validate:"required,max=255"`
+	Description   string  `json:"description,omitempty"`
+	Address       string  `json:"address" validate:"required,max=500"`
+	Longitude     float64 `json:"longitude" validate:"longitude"`
+	Latitude      float64 `json:"latitude" validate:"latitude"`
+	ContactNumber string  `json:"contactNumber" validate:"required,max=255"`
+	MediaID       *uint   `json:"mediaID,omitempty"`
+}
+
+// CompanyOwnerRequest transfers a company to another owner.
+type CompanyOwnerRequest struct {
+	OwnerID uint `json:"ownerID" validate:"required"`
+}
+
+func (m *ModelResource) ValidateCompanyOwnerRequest(req *CompanyOwnerRequest) error {
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		return m.helpers.FormatValidationError(err)
+	}
+	return nil
 }
 
 func (m *ModelResource) CompanyToResource(company *Company) *CompanyResource {
@@ -88,6 +122,12 @@ func (m *ModelResource) CompanyToResource(company *Company) *CompanyResource {
 		Media:           m.MediaToResource(company.Media),
 		IsAdminVerified: company.IsAdminVerified,
 		Branches:        m.BranchToResourceList(company.Branches),
+
+		PasswordlessAccountTypes: splitList(company.PasswordlessAccountTypes),
+		Timezone:                 company.Timezone,
+		RestDays:                 splitList(company.RestDays),
+		GeofencePolicy:           company.GeofencePolicy,
+		GeofenceRadius:           company.GeofenceRadius,
 	}
 }
 
@@ -105,75 +145,57 @@ func (m *ModelResource) CompanyToResourceList(companies []*Company) []*CompanyRe
 	return companyResources
 }
 
-func (m *ModelResource) CompanyToRecord(company []*Company) ([][]string, []string) {
-	resource := m.CompanyToResourceList(company)
-	records := make([][]string, 0, len(resource))
-
-	for _, company := range resource {
-		id := strconv.Itoa(int(company.ID))
-		name := sanitizeCSVField(company.Name)
-		description := sanitizeCSVField(company.Description)
-		address := sanitizeCSVField(company.Address)
-		longitude := fmt.Sprintf("%.6f", company.Longitude)
-		latitude := fmt.Sprintf("%.6f", company.Latitude)
-		contactNumber := sanitizeCSVField(company.ContactNumber)
-		isAdminVerified := strconv.FormatBool(company.IsAdminVerified)
-		createdAt := sanitizeCSVField(company.CreatedAt)
-		updatedAt := sanitizeCSVField(company.UpdatedAt)
-
-		// Handle Owner
-		ownerName := "N/A"
-		if company.Owner != nil {
-			ownerName = sanitizeCSVField(company.Owner.FirstName)
-		}
+// CompanyExportColumns returns the columns of a company export.
+func (m *ModelResource) CompanyExportColumns() []managers.ExportColumn {
+	return []managers.ExportColumn{
+		{Header: "ID", Width: 8},
+		{Header: "Name", Width: 30},
+		{Header: "Description", Width: 50},
+		{Header: "Address", Width: 40},
+		{Header: "Longitude", Width: 14},
+		{Header: "Latitude", Width: 14},
+		{Header: "Contact Number", Width: 18},
+		{Header: "Owner Name", Width: 25},
+		{Header: "Media URL", Width: 40},
+		{Header: "Is Admin Verified", Width: 18},
+		{Header: "Branches", Width: 50},
+		{Header: "Created At", Width: 20},
+		{Header: "Updated At", Width: 20},
+	}
+}
 
-		// Handle Media
-		mediaURL := "N/A"
-		if company.Media != nil {
-			mediaURL = sanitizeCSVField(company.Media.URL)
-		}
+// CompanyToExportRow converts a company into a typed export row matching CompanyExportColumns.
+func (m *ModelResource) CompanyToExportRow(company *Company) []interface{} {
+	ownerName := "N/A"
+	if company.Owner != nil {
+		ownerName = sanitizeCSVField(company.Owner.FirstName)
+	}
 
-		// Handle Branches
-		branchAddresses := []string{}
-		for _, branch := range company.Branches {
-			branchAddresses = append(branchAddresses, sanitizeCSVField(branch.Address))
-		}
-		branches := strings.Join(branchAddresses, "; ")
-
-		record := []string{
-			id,
-			name,
-			description,
-			address,
-			longitude,
-			latitude,
-			contactNumber,
-			ownerName,
-			mediaURL,
-			isAdminVerified,
-			branches,
-			createdAt,
-			updatedAt,
-		}
-		records = append(records, record)
+	mediaURL := "N/A"
+	if company.Media != nil {
+		mediaURL = sanitizeCSVField(company.Media.URL)
 	}
-	headers := []string{
-		"ID",
-		"Name",
-		"Description",
-		"Address",
-		"Longitude",
-		"Latitude",
-		"Contact Number",
-		"Owner Name",
-		"Media URL",
-		"Is Admin Verified",
-		"Branches",
-		"Created At",
-		"Updated At",
+
+	branchAddresses := []string{}
+	for _, branch := range company.Branches {
+		branchAddresses = append(branchAddresses, sanitizeCSVField(branch.Address))
 	}
 
-	return records, headers
+	return []interface{}{
+		company.ID,
+		sanitizeCSVField(company.Name),
+		sanitizeCSVField(company.Description),
+		sanitizeCSVField(company.Address),
+		company.Longitude,
+		company.Latitude,
+		sanitizeCSVField(company.ContactNumber),
+		ownerName,
+		mediaURL,
+		company.IsAdminVerified,
+		strings.Join(branchAddresses, "; "),
+		company.CreatedAt,
+		company.UpdatedAt,
+	}
 }
 
 func (m *ModelResource) ValidateCompanyRequest(req *CompanyRequest) error {
@@ -207,6 +229,158 @@ func (m *ModelResource) CompanyFilterForAdminRecord(filters string) ([]*Company,
 	return m.CompanyDB.GetFilteredResults(db, filters)
 }
 
+func (m *ModelResource) CompanyStreamAll(fn func(batch []*Company) error) error {
+	db := m.db.Client
+	return m.CompanyDB.StreamAll(db, managers.ExportBatchSize, fn)
+}
+
+func (m *ModelResource) CompanyStreamFilterForAdmin(filters string, fn func(batch []*Company) error) error {
+	db := m.db.Client
+	return m.CompanyDB.StreamFilteredResults(db, filters, managers.ExportBatchSize, fn)
+}
+
+func (m *ModelResource) CompanyAggregateForAdmin(aggregate string) ([]map[string]interface{}, error) {
+	db := m.db.Client
+	return m.CompanyDB.GetAggregatedResults(db, aggregate)
+}
+
+// CompanyPasswordlessRequest sets the account types of a company that may
+// sign in without a password.
+type CompanyPasswordlessRequest struct {
+	AccountTypes []string `json:"accountTypes" validate:"max=3,dive,oneof=Owner Employee Member"`
+}
+
+func (m *ModelResource) ValidateCompanyPasswordlessRequest(req *CompanyPasswordlessRequest) error {
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		return m.helpers.FormatValidationError(err)
+	}
+	return nil
+}
+
+// CompanyUpdatePasswordless sets the passwordless account types of the
+// company with the id within scope, which may be nil.
+func (m *ModelResource) CompanyUpdatePasswordless(scope managers.ScopeFunc, id uint, accountTypes []string) (*Company, error) {
+	query := m.db.Client.Where("id = ?", id)
+	if scope != nil {
+		query = query.Scopes(scope)
+	}
+	var companies []*Company
+	if err := query.Limit(1).Find(&companies).Error; err != nil {
+		return nil, err
+	}
+	if len(companies) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	company := companies[0]
+	if err := m.db.Client.Model(company).Update("passwordless_account_types", strings.Join(accountTypes, ",")).Error; err != nil {
+		return nil, err
+	}
+	return company, nil
+}
+
+// CompanyAttendanceRequest sets the calendar the schedules of a company
+// follow.
+type CompanyAttendanceRequest struct {
+	Timezone string   `json:"timezone" validate:"required,max=64"`
+	RestDays []string `json:"restDays" validate:"max=7"`
+}
+
+func (m *ModelResource) ValidateCompanyAttendanceRequest(req *CompanyAttendanceRequest) error {
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		return m.helpers.FormatValidationError(err)
+	}
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone: %s", req.Timezone)
+	}
+	return validateRestDays(strings.Join(req.RestDays, ","))
+}
+
+// CompanyUpdateAttendance sets the time zone and rest days of the company
+// with the id within scope, which may be nil.
+func (m *ModelResource) CompanyUpdateAttendance(scope managers.ScopeFunc, id uint, req *CompanyAttendanceRequest) (*Company, error) {
+	query := m.db.Client.Where("id = ?", id)
+	if scope != nil {
+		query = query.Scopes(scope)
+	}
+	var companies []*Company
+	if err := query.Limit(1).Find(&companies).Error; err != nil {
+		return nil, err
+	}
+	if len(companies) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	company := companies[0]
+	err := m.db.Client.Model(company).Updates(map[string]interface{}{
+		"timezone":  req.Timezone,
+		"rest_days": strings.Join(req.RestDays, ","),
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	return company, nil
+}
+
+func (m *ModelResource) ValidateCompanyGeofenceRequest(req *CompanyGeofenceRequest) error {
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		return m.helpers.FormatValidationError(err)
+	}
+	return nil
+}
+
+// CompanyUpdateGeofence sets the geofence policy and radius of the company
+// with the id within scope, which may be nil.
+func (m *ModelResource) CompanyUpdateGeofence(scope managers.ScopeFunc, id uint, req *CompanyGeofenceRequest) (*Company, error) {
+	query := m.db.Client.Where("id = ?", id)
+	if scope != nil {
+		query = query.Scopes(scope)
+	}
+	var companies []*Company
+	if err := query.Limit(1).Find(&companies).Error; err != nil {
+		return nil, err
+	}
+	if len(companies) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	company := companies[0]
+	err := m.db.Client.Model(company).Updates(map[string]interface{}{
+		"geofence_policy": req.Policy,
+		"geofence_radius": req.Radius,
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	return company, nil
+}
+
+// PasswordlessAllowed reports whether one of the companies of the user lets
+// their account type sign in without a password. Admins belong to no company
+// and always sign in with their password.
+func (m *ModelResource) PasswordlessAllowed(accountType string, userId uint) (bool, error) {
+	if accountType == "Admin" {
+		return false, nil
+	}
+	tenant, err := m.TenantResolve(accountType, userId)
+	if err != nil || len(tenant.CompanyIDs) == 0 {
+		return false, err
+	}
+	var lists []string
+	err = m.db.Client.Model(&Company{}).Where("id IN ?", tenant.CompanyIDs).Pluck("passwordless_account_types", &lists).Error
+	if err != nil {
+		return false, err
+	}
+	for _, list := range lists {
+		for _, allowed := range splitList(list) {
+			if allowed == accountType {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
 func (m *ModelResource) CompanySeeders() error {
 	m.logger.Info("Seeding Company")
 	return nil
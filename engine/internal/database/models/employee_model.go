@@ -16,22 +16,24 @@ type Employee struct {
 	gorm.Model
 
 	// Fields
-	FirstName          string     `gorm:"type:varchar(255);unsigned" json:"first_name"`
-	LastName           string     `gorm:"type:varchar(255);unsigned" json:"last_name"`
-	MiddleName         string     `gorm:"type:varchar(255)" json:"middle_name"`
-	PermanentAddress   string     `gorm:"type:text" json:"permanent_address"`
-	Description        string     `gorm:"type:text" json:"description"`
-	BirthDate          time.Time  `gorm:"type:date;unsigned" json:"birth_date"`
-	Username           string     `gorm:"type:varchar(255);unique;unsigned" json:"username"`
-	Email              string     `gorm:"type:varchar(255);unique;unsigned" json:"email"`
-	Password           string     `gorm:"type:varchar(255);unsigned" json:"password"`
-	IsEmailVerified    bool       `gorm:"default:false" json:"is_email_verified"`
-	IsContactVerified  bool       `gorm:"default:false" json:"is_contact_verified"`
-	IsSkipVerification bool       `gorm:"default:false" json:"is_skip_verification"`
-	ContactNumber      string     `gorm:"type:varchar(255);unique;unsigned" json:"contact_number"`
-	Status             UserStatus `gorm:"type:varchar(255);default:'Pending'" json:"status"`
-	Longitude          *float64   `gorm:"type:decimal(10,7)" json:"longitude"`
-	Latitude           *float64   `gorm:"type:decimal(10,7)" json:"latitude"`
+	FirstName             string     `gorm:"type:varchar(255);unsigned" json:"first_name"`
+	LastName              string     `gorm:"type:varchar(255);unsigned" json:"last_name"`
+	MiddleName            string     `gorm:"type:varchar(255)" json:"middle_name"`
+	PermanentAddress      string     `gorm:"type:text" json:"permanent_address"`
+	Description           string     `gorm:"type:text" json:"description"`
+	BirthDate             time.Time  `gorm:"type:date;unsigned" json:"birth_date"`
+	Username              string     `gorm:"type:varchar(255);unique;unsigned" json:"username"`
+	Email                 string     `gorm:"type:varchar(255);unique;unsigned" json:"email"`
+	Password              string     `gorm:"type:varchar(255);unsigned" json:"password"`
+	IsEmailVerified       bool       `gorm:"default:false" json:"is_email_verified"`
+	IsContactVerified     bool       `gorm:"default:false" json:"is_contact_verified"`
+	IsSkipVerification    bool       `gorm:"default:false" json:"is_skip_verification"`
+	PasswordChangedAt     *time.Time `json:"password_changed_at"`
+	PasswordResetRequired bool       `gorm:"default:false" json:"password_reset_required"`
+	ContactNumber         string     `gorm:"type:varchar(255);unique;unsigned" json:"contact_number"`
+	Status                UserStatus `gorm:"type:varchar(255);default:'Pending'" json:"status"`
+	Longitude             *float64   `gorm:"type:decimal(10,7)" json:"longitude"`
+	Latitude              *float64   `gorm:"type:decimal(10,7)" json:"latitude"`
 
 	// Relationship 0 to 1
 	MediaID *uint  `gorm:"type:bigint;unsigned" json:"media_id"`
@@ -61,34 +63,60 @@ type EmployeeResource struct {
 	CreatedAt string `json:"createdAt"`
 	UpdatedAt string `json:"updatedAt"`
 
-	FirstName          string               `json:"firstName"`
-	LastName           string               `json:"lastName"`
-	MiddleName         string               `json:"middleName"`
-	PermanentAddress   string               `json:"permanentAddress"`
-	Description        string               `json:"description"`
-	BirthDate          time.Time            `json:"birthDate"`
-	Username           string               `json:"username"`
-	Email              string               `json:"email"`
-	IsEmailVerified    bool                 `json:"isEmailVerified"`
-	IsContactVerified  bool                 `json:"isContactVerified"`
-	IsSkipVerification bool                 `json:"isSkipVerification"`
-	ContactNumber      string               `json:"contactNumber"`
-	Status             UserStatus           `json:"status"`
-	Longitude          *float64             `json:"longitude"`
-	Latitude           *float64             `json:"latitude"`
-	MediaID            *uint                `json:"mediaID"`
-	Media              *MediaResource       `json:"media"`
-	BranchID           *uint                `json:"branchID"`
-	Branch             *BranchResource      `json:"branch"`
-	RoleID             *uint                `json:"roleID"`
-	Role               *RoleResource        `json:"role"`
-	GenderID           *uint                `json:"genderID"`
-	Gender             *GenderResource      `json:"gender"`
-	Timesheets         []*TimesheetResource `json:"timesheets"`
-	Footsteps          []*FootstepResource  `json:"footsteps"`
+	FirstName             string               `json:"firstName"`
+	LastName              string               `json:"lastName"`
+	MiddleName            string               `json:"middleName"`
+	PermanentAddress      string               `json:"permanentAddress"`
+	Description           string               `json:"description"`
+	BirthDate             time.Time            `json:"birthDate"`
+	Username              string               `json:"username"`
+	Email                 string               `json:"email"`
+	IsEmailVerified       bool                 `json:"isEmailVerified"`
+	IsContactVerified     bool                 `json:"isContactVerified"`
+	IsSkipVerification    bool                 `json:"isSkipVerification"`
+	PasswordChangedAt     *string              `json:"passwordChangedAt"`
+	PasswordResetRequired bool                 `json:"passwordResetRequired"`
+	ContactNumber         string               `json:"contactNumber"`
+	Status                UserStatus           `json:"status"`
+	Longitude             *float64             `json:"longitude"`
+	Latitude              *float64             `json:"latitude"`
+	MediaID               *uint                `json:"mediaID"`
+	Media                 *MediaResource       `json:"media"`
+	BranchID              *uint                `json:"branchID"`
+	Branch                *BranchResource      `json:"branch"`
+	RoleID                *uint                `json:"roleID"`
+	Role                  *RoleResource        `json:"role"`
+	GenderID              *uint                `json:"genderID"`
+	Gender                *GenderResource      `json:"gender"`
+	Timesheets            []*TimesheetResource `json:"timesheets"`
+	Footsteps             []*FootstepResource  `json:"footsteps"`
 }
 
+// EmployeeRequest updates an employee, employees included. The branch and
+// role are changed through their own routes so employees cannot change theirs.
 type EmployeeRequest struct {
+	FirstName          string    `json:"firstName" validate:"required,max=255"`
+	LastName           string    `json:"lastName" validate:"required,max=255"`
+	MiddleName         string    `json:"middleName,omitempty" validate:"max=255"`
+	PermanentAddress   string    `json:"permanentAddress,omitempty"`
+	Description        string    `json:"description,omitempty"`
+	BirthDate          time.Time `json:"birthDate" validate:"required"`
+	Username           string    `json:"username" validate:"required,max=255"`
+	Email              string    `json:"email" validate:"required,email,max=255"`
+	IsEmailVerified    bool      `json:"isEmailVerified"`
+	IsContactVerified  bool      `json:"isContactVerified"`
+	IsSkipVerification bool      `json:"isSkipVerification"`
+	ContactNumber      string    `json:"contactNumber" validate:"required,max=15"`
+	Status             string    `json:"status" validate:"required,oneof=Pending Active Inactive"`
+	Longitude          *float64  `json:"longitude" validate:"omitempty,longitude"`
+	Latitude           *float64  `json:"latitude" validate:"omitempty,latitude"`
+	MediaID            *uint     `json:"mediaID,omitempty"`
+	GenderID           *uint     `json:"genderID,omitempty"`
+}
+
+// EmployeeCreateRequest creates or imports an employee into a branch, which
+// must be within the scope of the caller.
+type EmployeeCreateRequest struct {
 	FirstName          string    `json:"firstName" validate:"required,max=255"`
 	LastName           string    `json:"lastName" validate:"required,max=255"`
 	MiddleName         string    `json:"middleName,omitempty" validate:"max=255"`
@@ -106,7 +134,6 @@ type EmployeeRequest struct {
 	Latitude           *float64  `json:"latitude" validate:"omitempty,latitude"`
 	MediaID            *uint     `json:"mediaID,omitempty"`
 	BranchID           *uint     `json:"branchID,omitempty"`
-	RoleID             *uint     `json:"roleID,omitempty"`
 	GenderID           *uint     `json:"genderID,omitempty"`
 }
 
@@ -131,23 +158,25 @@ func (m *ModelResource) EmployeeToResource(employee *Employee) *EmployeeResource
 		Username:         employee.Username,
 		Email:            employee.Email,
 
-		IsEmailVerified:    employee.IsEmailVerified,
-		IsContactVerified:  employee.IsContactVerified,
-		IsSkipVerification: employee.IsSkipVerification,
-		ContactNumber:      employee.ContactNumber,
-		Status:             employee.Status,
-		Longitude:          employee.Longitude,
-		Latitude:           employee.Latitude,
-		MediaID:            employee.MediaID,
-		Media:              m.MediaToResource(employee.Media),
-		BranchID:           employee.BranchID,
-		Branch:             m.BranchToResource(employee.Branch),
-		RoleID:             employee.RoleID,
-		Role:               m.RoleToResource(employee.Role),
-		GenderID:           employee.GenderID,
-		Gender:             m.GenderToResource(employee.Gender),
-		Timesheets:         m.TimesheetToResourceList(employee.Timesheets),
-		Footsteps:          m.FootstepToResourceList(employee.Footsteps),
+		IsEmailVerified:       employee.IsEmailVerified,
+		IsContactVerified:     employee.IsContactVerified,
+		IsSkipVerification:    employee.IsSkipVerification,
+		PasswordChangedAt:     formatOptionalTime(employee.PasswordChangedAt),
+		PasswordResetRequired: employee.PasswordResetRequired,
+		ContactNumber:         employee.ContactNumber,
+		Status:                employee.Status,
+		Longitude:             employee.Longitude,
+		Latitude:              employee.Latitude,
+		MediaID:               employee.MediaID,
+		Media:                 m.MediaToResource(employee.Media),
+		BranchID:              employee.BranchID,
+		Branch:                m.BranchToResource(employee.Branch),
+		RoleID:                employee.RoleID,
+		Role:                  m.RoleToResource(employee.Role),
+		GenderID:              employee.GenderID,
+		Gender:                m.GenderToResource(employee.Gender),
+		Timesheets:            m.TimesheetToResourceList(employee.Timesheets),
+		Footsteps:             m.FootstepToResourceList(employee.Footsteps),
 	}
 }
 
@@ -314,6 +343,14 @@ func (m *ModelResource) EmployeeToRecord(employees []*Employee) ([][]string, []s
 }
 
 func (m *ModelResource) ValidateEmployeeRequest(req *EmployeeRequest) error {
+	return m.validateEmployee(req)
+}
+
+func (m *ModelResource) ValidateEmployeeCreateRequest(req *EmployeeCreateRequest) error {
+	return m.validateEmployee(req)
+}
+
+func (m *ModelResource) validateEmployee(req interface{}) error {
 	validate := validator.New()
 	validate.RegisterValidation("longitude", func(fl validator.FieldLevel) bool {
 		lon := fl.Field().Float()
@@ -359,14 +396,23 @@ func (r *ModelResource) EmployeeFindByEmailUsernameOrContact(input string) (*Emp
 	}
 }
 
-func (r *ModelResource) EmployeeUpdatePassword(id uint, password string) error {
-	newPassword, err := r.cryptoHelpers.HashPassword(password)
-	if err != nil {
-		return err
+func (employee *Employee) GetID() uint              { return employee.ID }
+func (employee *Employee) GetPassword() string      { return employee.Password }
+func (employee *Employee) GetEmail() string         { return employee.Email }
+func (employee *Employee) GetContactNumber() string { return employee.ContactNumber }
+func (employee *Employee) GetStatus() UserStatus    { return employee.Status }
+func (employee *Employee) GetName() string {
+	return fmt.Sprintf("%s %s", employee.FirstName, employee.LastName)
+}
+func (employee *Employee) GetFullName() (string, string, string) {
+	return employee.FirstName, employee.MiddleName, employee.LastName
+}
+func (employee *Employee) GetVerification() AccountVerification {
+	return AccountVerification{
+		Email:         employee.IsEmailVerified,
+		ContactNumber: employee.IsContactVerified,
+		Skipped:       employee.IsSkipVerification,
 	}
-	updated := &Employee{Password: newPassword}
-	_, err = r.EmployeeDB.UpdateColumns(id, *updated, []string{"Media"})
-	return err
 }
 
 func (r *ModelResource) EmployeeCreate(user *Employee) error {
@@ -381,6 +427,8 @@ func (r *ModelResource) EmployeeCreate(user *Employee) error {
 		return err
 	}
 	user.Password = hashedPassword
+	now := time.Now()
+	user.PasswordChangedAt = &now
 	return r.EmployeeDB.Create(user)
 }
 
@@ -394,6 +442,8 @@ func (r *ModelResource) EmployeeUpdate(user *Employee, preloads []string) error
 			return err
 		}
 		user.Password = hashedPassword
+		now := time.Now()
+		user.PasswordChangedAt = &now
 	}
 	return r.EmployeeDB.Update(user, preloads)
 }
@@ -4,6 +4,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers/filter"
 	"github.com/go-playground/validator"
 	"gorm.io/gorm"
@@ -60,43 +61,28 @@ func (m *ModelResource) FeedbackToResourceList(feedbacks []*Feedback) []*Feedbac
 	return feedbackResources
 }
 
-// FeedbackToRecord converts a slice of Feedback pointers into CSV records and headers.
-func (m *ModelResource) FeedbackToRecord(feedbacks []*Feedback) ([][]string, []string) {
-	// Convert Feedback structs to FeedbackResource structs
-	resource := m.FeedbackToResourceList(feedbacks)
-	records := make([][]string, 0, len(resource))
-
-	for _, feedback := range resource {
-		// Basic Fields
-		id := strconv.Itoa(int(feedback.ID))
-		email := sanitizeCSVField(feedback.Email)
-		description := sanitizeCSVField(feedback.Description)
-		feedbackType := sanitizeCSVField(feedback.FeedbackType)
-		createdAt := sanitizeCSVField(feedback.CreatedAt)
-		updatedAt := sanitizeCSVField(feedback.UpdatedAt)
-
-		// Assemble the record
-		record := []string{
-			id,
-			email,
-			description,
-			feedbackType,
-			createdAt,
-			updatedAt,
-		}
-		records = append(records, record)
+// FeedbackExportColumns returns the columns of a feedback export.
+func (m *ModelResource) FeedbackExportColumns() []managers.ExportColumn {
+	return []managers.ExportColumn{
+		{Header: "ID", Width: 8},
+		{Header: "Email", Width: 30},
+		{Header: "Description", Width: 60},
+		{Header: "Feedback Type", Width: 15},
+		{Header: "Created At", Width: 20},
+		{Header: "Updated At", Width: 20},
 	}
+}
 
-	headers := []string{
-		"ID",
-		"Email",
-		"Description",
-		"Feedback Type",
-		"Created At",
-		"Updated At",
+// FeedbackToExportRow converts a feedback into a typed export row matching FeedbackExportColumns.
+func (m *ModelResource) FeedbackToExportRow(feedback *Feedback) []interface{} {
+	return []interface{}{
+		feedback.ID,
+		sanitizeCSVField(feedback.Email),
+		sanitizeCSVField(feedback.Description),
+		sanitizeCSVField(feedback.FeedbackType),
+		feedback.CreatedAt,
+		feedback.UpdatedAt,
 	}
-
-	return records, headers
 }
 
 func (m *ModelResource) FeedbackFilterForAdmin(filters string, pageSize, pageIndex int) (filter.FilterPages[Feedback], error) {
@@ -109,6 +95,21 @@ func (m *ModelResource) FeedbackFilterForAdminRecord(filters string) ([]*Feedbac
 	return m.FeedbackDB.GetFilteredResults(db, filters)
 }
 
+func (m *ModelResource) FeedbackStreamAll(fn func(batch []*Feedback) error) error {
+	db := m.db.Client
+	return m.FeedbackDB.StreamAll(db, managers.ExportBatchSize, fn)
+}
+
+func (m *ModelResource) FeedbackStreamFilterForAdmin(filters string, fn func(batch []*Feedback) error) error {
+	db := m.db.Client
+	return m.FeedbackDB.StreamFilteredResults(db, filters, managers.ExportBatchSize, fn)
+}
+
+func (m *ModelResource) FeedbackAggregateForAdmin(aggregate string) ([]map[string]interface{}, error) {
+	db := m.db.Client
+	return m.FeedbackDB.GetAggregatedResults(db, aggregate)
+}
+
 func (m *ModelResource) ValidateFeedbackRequest(req *FeedbackRequest) error {
 	validate := validator.New()
 	err := validate.Struct(req)
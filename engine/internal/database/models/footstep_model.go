@@ -33,6 +33,10 @@ type Footstep struct {
 	// Relationship 0 to 1
 	MemberID *uint   `gorm:"index" json:"member_id,omitempty"`
 	Member   *Member `gorm:"foreignKey:MemberID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"member,omitempty"`
+
+	// Relationship 0 to 1
+	ApiKeyID *uint   `gorm:"index" json:"api_key_id,omitempty"`
+	ApiKey   *ApiKey `gorm:"foreignKey:ApiKeyID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"api_key,omitempty"`
 }
 
 type FootstepResource struct {
@@ -51,6 +55,7 @@ type FootstepResource struct {
 	Owner       *OwnerResource    `json:"owner"`
 	MemberID    *uint             `json:"memberID"`
 	Member      *MemberResource   `json:"member"`
+	ApiKeyID    *uint             `json:"apiKeyID"`
 }
 
 type FootstepRequest struct {
@@ -84,6 +89,7 @@ func (m *ModelResource) FootstepToResource(footstep *Footstep) *FootstepResource
 		Owner:       m.OwnerToResource(footstep.Owner),
 		MemberID:    footstep.MemberID,
 		Member:      m.MemberToResource(footstep.Member),
+		ApiKeyID:    footstep.ApiKeyID,
 	}
 }
 
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"errors"
+	"math"
+)
+
+// Geofence policies of a company, for punches outside of the radius around
+// the branch of the employee.
+const (
+	GeofenceOff    = "Off"
+	GeofenceFlag   = "Flag"
+	GeofenceReject = "Reject"
+)
+
+var (
+	ErrGeofenceLocationRequired = errors.New("the location of the device is required")
+	ErrGeofenceOutOfRange       = errors.New("the device is too far from the branch")
+)
+
+// Punch is where the device of an employee was when they timed in or out.
+type Punch struct {
+	Latitude  *float64 `json:"latitude,omitempty" validate:"omitempty,latitude"`
+	Longitude *float64 `json:"longitude,omitempty" validate:"omitempty,longitude"`
+	// Accuracy of the location in meters
+	Accuracy *float64 `json:"accuracy,omitempty" validate:"omitempty,min=0"`
+}
+
+// validate checks that the punch has both coordinates or none.
+func (p Punch) validate() error {
+	if (p.Latitude == nil) != (p.Longitude == nil) {
+		return errors.New("latitude and longitude go together")
+	}
+	return nil
+}
+
+// GeofenceResult is a punch checked against the geofence of the branch.
+// Distance is nil when the punch or the branch has no location.
+type GeofenceResult struct {
+	Distance   *float64
+	OutOfRange bool
+}
+
+// CompanyGeofenceRequest sets how far from their branch employees of a
+// company may time in or out.
+type CompanyGeofenceRequest struct {
+	Policy string `json:"policy" validate:"required,oneof=Off Flag Reject"`
+	// Radius around the branch in meters
+	Radius int `json:"radius" validate:"min=10,max=100000"`
+}
+
+// GeofenceCheck checks the punch of the employee against the radius around
+// their branch, following the policy of their company. The accuracy of the
+// punch widens the radius, by at most the radius itself. Out of range punches
+// are flagged, or rejected with ErrGeofenceOutOfRange along with the result.
+// Branches without coordinates are not checked.
+func (m *ModelResource) GeofenceCheck(employeeId uint, punch Punch) (*GeofenceResult, error) {
+	result := &GeofenceResult{}
+	employee, err := m.EmployeeDB.FindByID(employeeId, "Branch")
+	if err != nil {
+		return nil, err
+	}
+	company, err := m.employeeCompany(employee)
+	if err != nil {
+		return nil, err
+	}
+	if company == nil || company.GeofencePolicy == "" || company.GeofencePolicy == GeofenceOff {
+		return result, nil
+	}
+
+	branch := employee.Branch
+	if branch == nil || (branch.Latitude == 0 && branch.Longitude == 0) {
+		return result, nil
+	}
+	if punch.Latitude == nil || punch.Longitude == nil {
+		if company.GeofencePolicy == GeofenceReject {
+			return nil, ErrGeofenceLocationRequired
+		}
+		result.OutOfRange = true
+		return result, nil
+	}
+
+	distance := m.helpers.HaversineDistance(branch.Latitude, branch.Longitude, *punch.Latitude, *punch.Longitude)
+	distance = math.Round(distance*10) / 10
+	result.Distance = &distance
+	radius := float64(company.GeofenceRadius)
+	allowance := 0.0
+	if punch.Accuracy != nil {
+		allowance = math.Min(*punch.Accuracy, radius)
+	}
+	result.OutOfRange = distance > radius+allowance
+	if result.OutOfRange && company.GeofencePolicy == GeofenceReject {
+		return result, ErrGeofenceOutOfRange
+	}
+	return result, nil
+}
@@ -0,0 +1,745 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
+	"github.com/go-playground/validator"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// How often leave types credit the balances of employees.
+const (
+	LeaveAccrualNone    = "None"
+	LeaveAccrualMonthly = "Monthly"
+	LeaveAccrualYearly  = "Yearly"
+)
+
+// Statuses of leave requests.
+const (
+	LeavePending  = "Pending"
+	LeaveApproved = "Approved"
+	LeaveRejected = "Rejected"
+)
+
+// AttendanceOnLeave is the status of working days covered by approved leave.
+const AttendanceOnLeave = "On Leave"
+
+// leaveMaxDays bounds the calendar days of a leave request.
+const leaveMaxDays = 366
+
+var (
+	ErrLeaveOverlap             = errors.New("the leave overlaps another leave request")
+	ErrLeaveNoWorkingDays       = errors.New("the leave covers no working days")
+	ErrLeaveInsufficientBalance = errors.New("the leave balance is insufficient")
+	ErrLeaveReviewed            = errors.New("the leave request has already been reviewed")
+	ErrLeaveAlreadyApproved     = errors.New("the reviewer already approved the leave request")
+)
+
+// LeaveType is a kind of leave of a company, such as vacation or sick leave.
+// Paid leave is taken from the balances of employees, which are credited
+// AccrualDays every accrual period up to MaxBalance, if any. Requests need
+// the approval of ApprovalLevels different reviewers.
+type LeaveType struct {
+	gorm.Model
+
+	// Fields
+	Name           string  `gorm:"type:varchar(255)" json:"name"`
+	Description    string  `gorm:"type:text" json:"description"`
+	Paid           bool    `gorm:"default:false" json:"paid"`
+	AccrualPeriod  string  `gorm:"type:varchar(16);default:'None'" json:"accrual_period"`
+	AccrualDays    float64 `gorm:"default:0" json:"accrual_days"`
+	MaxBalance     float64 `gorm:"default:0" json:"max_balance"`
+	ApprovalLevels int     `gorm:"default:1" json:"approval_levels"`
+
+	// Relationship 1 to many
+	CompanyID uint     `gorm:"type:bigint;unsigned;index" json:"company_id"`
+	Company   *Company `gorm:"foreignKey:CompanyID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"company"`
+}
+
+// LeaveBalance is the days of a paid leave type an employee has left.
+// AccruedAt is the start of the last accrual period credited.
+type LeaveBalance struct {
+	gorm.Model
+
+	// Fields
+	Balance   float64   `gorm:"default:0" json:"balance"`
+	AccruedAt time.Time `gorm:"type:date" json:"accrued_at"`
+
+	// Relationship 1 to many
+	EmployeeID  uint       `gorm:"type:bigint;unsigned;uniqueIndex:idx_leave_balance" json:"employee_id"`
+	Employee    *Employee  `gorm:"foreignKey:EmployeeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"employee"`
+	LeaveTypeID uint       `gorm:"type:bigint;unsigned;uniqueIndex:idx_leave_balance" json:"leave_type_id"`
+	LeaveType   *LeaveType `gorm:"foreignKey:LeaveTypeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"leave_type"`
+}
+
+// LeaveRequest is leave an employee filed from StartDate to EndDate, both
+// calendar dates. Days counts the regular working days it covers.
+type LeaveRequest struct {
+	gorm.Model
+
+	// Fields
+	StartDate      time.Time `gorm:"type:date;index" json:"start_date"`
+	EndDate        time.Time `gorm:"type:date;index" json:"end_date"`
+	Days           float64   `json:"days"`
+	Reason         string    `gorm:"type:varchar(1024)" json:"reason"`
+	Status         string    `gorm:"type:varchar(16);default:'Pending';index" json:"status"`
+	ApprovalLevels int       `gorm:"default:1" json:"approval_levels"`
+	ApprovedLevels int       `gorm:"default:0" json:"approved_levels"`
+
+	// Relationship 1 to many
+	EmployeeID  uint       `gorm:"type:bigint;unsigned;index" json:"employee_id"`
+	Employee    *Employee  `gorm:"foreignKey:EmployeeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"employee"`
+	LeaveTypeID uint       `gorm:"type:bigint;unsigned;index" json:"leave_type_id"`
+	LeaveType   *LeaveType `gorm:"foreignKey:LeaveTypeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"leave_type"`
+
+	// Relationship 0 to many
+	Approvals []*LeaveApproval `gorm:"foreignKey:LeaveRequestID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"approvals"`
+}
+
+// LeaveApproval is the review of a leave request at one approval level.
+type LeaveApproval struct {
+	gorm.Model
+
+	// Fields
+	Level               int    `json:"level"`
+	Approved            bool   `json:"approved"`
+	ReviewerAccountType string `gorm:"type:varchar(16)" json:"reviewer_account_type"`
+	ReviewerID          uint   `gorm:"type:bigint;unsigned" json:"reviewer_id"`
+	Note                string `gorm:"type:varchar(1024)" json:"note"`
+
+	// Relationship 1 to many
+	LeaveRequestID uint `gorm:"type:bigint;unsigned;index" json:"leave_request_id"`
+}
+
+type LeaveTypeResource struct {
+	ID        uint   `json:"id"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+
+	Name           string  `json:"name"`
+	Description    string  `json:"description"`
+	Paid           bool    `json:"paid"`
+	AccrualPeriod  string  `json:"accrualPeriod"`
+	AccrualDays    float64 `json:"accrualDays"`
+	MaxBalance     float64 `json:"maxBalance"`
+	ApprovalLevels int     `json:"approvalLevels"`
+	CompanyID      uint    `json:"companyID"`
+}
+
+type LeaveBalanceResource struct {
+	EmployeeID  uint               `json:"employeeID"`
+	LeaveTypeID uint               `json:"leaveTypeID"`
+	LeaveType   *LeaveTypeResource `json:"leaveType"`
+	Balance     float64            `json:"balance"`
+	// Days of pending requests, not taken from the balance yet
+	Pending   float64 `json:"pending"`
+	Available float64 `json:"available"`
+}
+
+type LeaveApprovalResource struct {
+	ID        uint   `json:"id"`
+	CreatedAt string `json:"createdAt"`
+
+	Level               int    `json:"level"`
+	Approved            bool   `json:"approved"`
+	ReviewerAccountType string `json:"reviewerAccountType"`
+	ReviewerID          uint   `json:"reviewerID"`
+	Note                string `json:"note"`
+}
+
+type LeaveRequestResource struct {
+	ID        uint   `json:"id"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+
+	StartDate      string                   `json:"startDate"`
+	EndDate        string                   `json:"endDate"`
+	Days           float64                  `json:"days"`
+	Reason         string                   `json:"reason"`
+	Status         string                   `json:"status"`
+	ApprovalLevels int                      `json:"approvalLevels"`
+	ApprovedLevels int                      `json:"approvedLevels"`
+	EmployeeID     uint                     `json:"employeeID"`
+	Employee       *EmployeeResource        `json:"employee"`
+	LeaveTypeID    uint                     `json:"leaveTypeID"`
+	LeaveType      *LeaveTypeResource       `json:"leaveType"`
+	Approvals      []*LeaveApprovalResource `json:"approvals"`
+}
+
+type LeaveTypeRequest struct {
+	Name           string  `json:"name" validate:"required,max=255"`
+	Description    string  `json:"description" validate:"max=3000"`
+	Paid           bool    `json:"paid"`
+	AccrualPeriod  string  `json:"accrualPeriod" validate:"required,oneof=None Monthly Yearly"`
+	AccrualDays    float64 `json:"accrualDays" validate:"min=0,max=366"`
+	MaxBalance     float64 `json:"maxBalance" validate:"min=0,max=3660"`
+	ApprovalLevels int     `json:"approvalLevels" validate:"min=1,max=5"`
+	CompanyID      uint    `json:"companyID" validate:"required"`
+}
+
+type LeaveRequestRequest struct {
+	LeaveTypeID uint      `json:"leaveTypeID" validate:"required"`
+	StartDate   time.Time `json:"startDate" validate:"required"`
+	EndDate     time.Time `json:"endDate" validate:"required"`
+	Reason      string    `json:"reason" validate:"required,max=1024"`
+}
+
+// LeaveReviewRequest approves or rejects a leave request at the next level.
+type LeaveReviewRequest struct {
+	Note string `json:"note" validate:"max=1024"`
+}
+
+// LeaveBalanceRequest sets the balance of a paid leave type of an employee.
+type LeaveBalanceRequest struct {
+	EmployeeID  uint    `json:"employeeID" validate:"required"`
+	LeaveTypeID uint    `json:"leaveTypeID" validate:"required"`
+	Balance     float64 `json:"balance" validate:"min=0,max=3660"`
+}
+
+func (m *ModelResource) LeaveTypeToResource(leaveType *LeaveType) *LeaveTypeResource {
+	if leaveType == nil {
+		return nil
+	}
+	return &LeaveTypeResource{
+		ID:        leaveType.ID,
+		CreatedAt: leaveType.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: leaveType.UpdatedAt.Format(time.RFC3339),
+
+		Name:           leaveType.Name,
+		Description:    leaveType.Description,
+		Paid:           leaveType.Paid,
+		AccrualPeriod:  leaveType.AccrualPeriod,
+		AccrualDays:    leaveType.AccrualDays,
+		MaxBalance:     leaveType.MaxBalance,
+		ApprovalLevels: leaveType.ApprovalLevels,
+		CompanyID:      leaveType.CompanyID,
+	}
+}
+
+func (m *ModelResource) LeaveTypeToResourceList(leaveTypes []*LeaveType) []*LeaveTypeResource {
+	if leaveTypes == nil {
+		return nil
+	}
+	var leaveTypeResources []*LeaveTypeResource
+	for _, leaveType := range leaveTypes {
+		leaveTypeResources = append(leaveTypeResources, m.LeaveTypeToResource(leaveType))
+	}
+	return leaveTypeResources
+}
+
+func (m *ModelResource) LeaveApprovalToResource(approval *LeaveApproval) *LeaveApprovalResource {
+	if approval == nil {
+		return nil
+	}
+	return &LeaveApprovalResource{
+		ID:        approval.ID,
+		CreatedAt: approval.CreatedAt.Format(time.RFC3339),
+
+		Level:               approval.Level,
+		Approved:            approval.Approved,
+		ReviewerAccountType: approval.ReviewerAccountType,
+		ReviewerID:          approval.ReviewerID,
+		Note:                approval.Note,
+	}
+}
+
+func (m *ModelResource) LeaveRequestToResource(request *LeaveRequest) *LeaveRequestResource {
+	if request == nil {
+		return nil
+	}
+	resource := &LeaveRequestResource{
+		ID:        request.ID,
+		CreatedAt: request.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: request.UpdatedAt.Format(time.RFC3339),
+
+		StartDate:      formatDate(request.StartDate),
+		EndDate:        formatDate(request.EndDate),
+		Days:           request.Days,
+		Reason:         request.Reason,
+		Status:         request.Status,
+		ApprovalLevels: request.ApprovalLevels,
+		ApprovedLevels: request.ApprovedLevels,
+		EmployeeID:     request.EmployeeID,
+		Employee:       m.EmployeeToResource(request.Employee),
+		LeaveTypeID:    request.LeaveTypeID,
+		LeaveType:      m.LeaveTypeToResource(request.LeaveType),
+	}
+	for _, approval := range request.Approvals {
+		resource.Approvals = append(resource.Approvals, m.LeaveApprovalToResource(approval))
+	}
+	return resource
+}
+
+func (m *ModelResource) LeaveRequestToResourceList(requests []*LeaveRequest) []*LeaveRequestResource {
+	if requests == nil {
+		return nil
+	}
+	var requestResources []*LeaveRequestResource
+	for _, request := range requests {
+		requestResources = append(requestResources, m.LeaveRequestToResource(request))
+	}
+	return requestResources
+}
+
+func (m *ModelResource) ValidateLeaveTypeRequest(req *LeaveTypeRequest) error {
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		return m.helpers.FormatValidationError(err)
+	}
+	if req.AccrualPeriod != LeaveAccrualNone && !req.Paid {
+		return errors.New("only paid leave accrues")
+	}
+	if req.MaxBalance > 0 && req.AccrualDays > req.MaxBalance {
+		return errors.New("accrualDays is more than maxBalance")
+	}
+	return nil
+}
+
+func (m *ModelResource) ValidateLeaveRequestRequest(req *LeaveRequestRequest) error {
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		return m.helpers.FormatValidationError(err)
+	}
+	start, end := calendarDate(req.StartDate), calendarDate(req.EndDate)
+	if end.Before(start) {
+		return errors.New("endDate is before startDate")
+	}
+	if end.Sub(start) >= leaveMaxDays*24*time.Hour {
+		return fmt.Errorf("a leave is at most %d days", leaveMaxDays)
+	}
+	return nil
+}
+
+func (m *ModelResource) ValidateLeaveReviewRequest(req *LeaveReviewRequest) error {
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		return m.helpers.FormatValidationError(err)
+	}
+	return nil
+}
+
+func (m *ModelResource) ValidateLeaveBalanceRequest(req *LeaveBalanceRequest) error {
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		return m.helpers.FormatValidationError(err)
+	}
+	return nil
+}
+
+// accrualPeriodStart returns the start of the accrual period of the calendar
+// date.
+func accrualPeriodStart(period string, date time.Time) time.Time {
+	switch period {
+	case LeaveAccrualMonthly:
+		return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case LeaveAccrualYearly:
+		return time.Date(date.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return calendarDate(date)
+	}
+}
+
+// nextAccrualPeriod returns the start of the accrual period after the one of
+// the calendar date.
+func nextAccrualPeriod(period string, date time.Time) time.Time {
+	start := accrualPeriodStart(period, date)
+	if period == LeaveAccrualYearly {
+		return start.AddDate(1, 0, 0)
+	}
+	return start.AddDate(0, 1, 0)
+}
+
+// accrue credits the leave type to the balance up to its maximum. Balances
+// set above the maximum are kept as they are.
+func (t *LeaveType) accrue(balance float64) float64 {
+	if t.MaxBalance > 0 && balance+t.AccrualDays > t.MaxBalance {
+		return math.Max(balance, t.MaxBalance)
+	}
+	return balance + t.AccrualDays
+}
+
+// leaveBalance returns the balance of the employee for the leave type, as of
+// the calendar date today, crediting the accrual periods that started since
+// it was last credited. New balances are credited the current period.
+func (m *ModelResource) leaveBalance(employeeId uint, leaveType *LeaveType, today time.Time) (*LeaveBalance, error) {
+	today = calendarDate(today)
+	for {
+		var balances []*LeaveBalance
+		err := m.db.Client.Where("employee_id = ? AND leave_type_id = ?", employeeId, leaveType.ID).
+			Limit(1).Find(&balances).Error
+		if err != nil {
+			return nil, err
+		}
+
+		if len(balances) == 0 {
+			balance := &LeaveBalance{
+				EmployeeID:  employeeId,
+				LeaveTypeID: leaveType.ID,
+				AccruedAt:   accrualPeriodStart(leaveType.AccrualPeriod, today),
+			}
+			if leaveType.AccrualPeriod != LeaveAccrualNone {
+				balance.Balance = leaveType.accrue(0)
+			}
+			// Another request may have created it first
+			result := m.db.Client.Clauses(clause.OnConflict{DoNothing: true}).Create(balance)
+			if result.Error != nil {
+				return nil, result.Error
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+			return balance, nil
+		}
+
+		balance := balances[0]
+		if leaveType.AccrualPeriod == LeaveAccrualNone {
+			return balance, nil
+		}
+		credited, accruedAt := balance.Balance, balance.AccruedAt
+		for next := nextAccrualPeriod(leaveType.AccrualPeriod, accruedAt); !next.After(today); next = nextAccrualPeriod(leaveType.AccrualPeriod, next) {
+			credited, accruedAt = leaveType.accrue(credited), next
+		}
+		if accruedAt.Equal(balance.AccruedAt) {
+			return balance, nil
+		}
+		// Credited by another request in the meantime, reload it
+		result := m.db.Client.Model(balance).Where("accrued_at = ?", balance.AccruedAt).
+			Updates(map[string]interface{}{"balance": credited, "accrued_at": accruedAt})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		balance.Balance, balance.AccruedAt = credited, accruedAt
+		return balance, nil
+	}
+}
+
+// pendingLeaveDays returns the days of the pending requests of the employee
+// for the leave type, other than exceptId.
+func (m *ModelResource) pendingLeaveDays(employeeId, leaveTypeId, exceptId uint) (float64, error) {
+	var days float64
+	err := m.db.Client.Model(&LeaveRequest{}).Select("COALESCE(SUM(days), 0)").
+		Where("employee_id = ? AND leave_type_id = ? AND status = ? AND id <> ?", employeeId, leaveTypeId, LeavePending, exceptId).
+		Scan(&days).Error
+	return days, err
+}
+
+// employeeLeaveType returns the leave type with the id of the company of
+// the employee, along with the employee and the company.
+func (m *ModelResource) employeeLeaveType(employeeId, leaveTypeId uint) (*Employee, *Company, *LeaveType, error) {
+	employee, err := m.EmployeeDB.FindByID(employeeId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	company, err := m.employeeCompany(employee)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if company == nil {
+		return nil, nil, nil, gorm.ErrRecordNotFound
+	}
+	var leaveTypes []*LeaveType
+	err = m.db.Client.Where("id = ? AND company_id = ?", leaveTypeId, company.ID).Limit(1).Find(&leaveTypes).Error
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(leaveTypes) == 0 {
+		return nil, nil, nil, gorm.ErrRecordNotFound
+	}
+	return employee, company, leaveTypes[0], nil
+}
+
+// LeaveBalances returns the balance of every paid leave type of the company
+// of the employee.
+func (m *ModelResource) LeaveBalances(employeeId uint) ([]*LeaveBalanceResource, error) {
+	employee, err := m.EmployeeDB.FindByID(employeeId)
+	if err != nil {
+		return nil, err
+	}
+	company, err := m.employeeCompany(employee)
+	if err != nil || company == nil {
+		return []*LeaveBalanceResource{}, err
+	}
+	var leaveTypes []*LeaveType
+	err = m.db.Client.Where("company_id = ? AND paid = ?", company.ID, true).Order("name").Find(&leaveTypes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Now().In(companyLocation(company))
+	resources := []*LeaveBalanceResource{}
+	for _, leaveType := range leaveTypes {
+		balance, err := m.leaveBalance(employee.ID, leaveType, today)
+		if err != nil {
+			return nil, err
+		}
+		pending, err := m.pendingLeaveDays(employee.ID, leaveType.ID, 0)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, &LeaveBalanceResource{
+			EmployeeID:  employee.ID,
+			LeaveTypeID: leaveType.ID,
+			LeaveType:   m.LeaveTypeToResource(leaveType),
+			Balance:     balance.Balance,
+			Pending:     pending,
+			Available:   balance.Balance - pending,
+		})
+	}
+	return resources, nil
+}
+
+// LeaveBalanceSet sets the balance of a paid leave type of the employee,
+// such as when carrying over leave from before.
+func (m *ModelResource) LeaveBalanceSet(req *LeaveBalanceRequest) error {
+	_, company, leaveType, err := m.employeeLeaveType(req.EmployeeID, req.LeaveTypeID)
+	if err != nil {
+		return err
+	}
+	if !leaveType.Paid {
+		return gorm.ErrRecordNotFound
+	}
+	balance, err := m.leaveBalance(req.EmployeeID, leaveType, time.Now().In(companyLocation(company)))
+	if err != nil {
+		return err
+	}
+	return m.db.Client.Model(balance).Update("balance", req.Balance).Error
+}
+
+// leaveDays counts the regular days, neither rest days nor holidays, of the
+// employee of the company from start to end.
+func (m *ModelResource) leaveDays(employee *Employee, company *Company, start, end time.Time) (float64, error) {
+	location := companyLocation(company)
+	days := 0.0
+	for date := calendarDate(start); !date.After(end); date = date.AddDate(0, 0, 1) {
+		local := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, location)
+		schedule, err := m.scheduleOn(employee, company, local)
+		if err != nil {
+			return 0, err
+		}
+		if schedule.DayType == DayTypeRegular {
+			days++
+		}
+	}
+	return days, nil
+}
+
+// payPeriodLockedBetween reports whether a locked pay period of the company
+// overlaps the calendar dates from start to end.
+func (m *ModelResource) payPeriodLockedBetween(company *Company, start, end time.Time) (bool, error) {
+	var count int64
+	err := m.db.Client.Model(&PayPeriod{}).
+		Where("company_id = ? AND locked_at IS NOT NULL AND start_date <= ? AND end_date >= ?", company.ID, end, start).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// LeaveRequestFile files leave of the employee. It may not overlap their
+// other pending or approved leave, nor a locked pay period, and paid leave
+// may not take more than the balance left after their pending requests.
+func (m *ModelResource) LeaveRequestFile(employeeId uint, req *LeaveRequestRequest) (*LeaveRequest, error) {
+	employee, company, leaveType, err := m.employeeLeaveType(employeeId, req.LeaveTypeID)
+	if err != nil {
+		return nil, err
+	}
+	start, end := calendarDate(req.StartDate), calendarDate(req.EndDate)
+
+	var overlapping int64
+	err = m.db.Client.Model(&LeaveRequest{}).
+		Where("employee_id = ? AND status IN ? AND start_date <= ? AND end_date >= ?", employeeId, []string{LeavePending, LeaveApproved}, end, start).
+		Count(&overlapping).Error
+	if err != nil {
+		return nil, err
+	}
+	if overlapping > 0 {
+		return nil, ErrLeaveOverlap
+	}
+	locked, err := m.payPeriodLockedBetween(company, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if locked {
+		return nil, ErrPayPeriodLocked
+	}
+	days, err := m.leaveDays(employee, company, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if days == 0 {
+		return nil, ErrLeaveNoWorkingDays
+	}
+
+	if leaveType.Paid {
+		balance, err := m.leaveBalance(employeeId, leaveType, time.Now().In(companyLocation(company)))
+		if err != nil {
+			return nil, err
+		}
+		pending, err := m.pendingLeaveDays(employeeId, leaveType.ID, 0)
+		if err != nil {
+			return nil, err
+		}
+		if balance.Balance-pending < days {
+			return nil, ErrLeaveInsufficientBalance
+		}
+	}
+
+	request := &LeaveRequest{
+		StartDate:      start,
+		EndDate:        end,
+		Days:           days,
+		Reason:         req.Reason,
+		Status:         LeavePending,
+		ApprovalLevels: leaveType.ApprovalLevels,
+		EmployeeID:     employeeId,
+		LeaveTypeID:    leaveType.ID,
+		LeaveType:      leaveType,
+	}
+	if err := m.db.Client.Create(request).Error; err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// LeaveRequestGet returns the leave request with the id within scope, which
+// may be nil.
+func (m *ModelResource) LeaveRequestGet(scope managers.ScopeFunc, id uint) (*LeaveRequest, error) {
+	query := m.db.Client.Preload("Employee").Preload("LeaveType").
+		Preload("Approvals", func(db *gorm.DB) *gorm.DB { return db.Order("level") }).
+		Where("id = ?", id)
+	if scope != nil {
+		query = query.Scopes(scope)
+	}
+	var requests []*LeaveRequest
+	if err := query.Limit(1).Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return requests[0], nil
+}
+
+// LeaveRequestReview approves or rejects a pending leave request at its next
+// approval level on behalf of the reviewer, who may approve each request
+// once. A rejection at any level rejects the request; the approval of the
+// last level approves it and takes paid leave from the balance.
+func (m *ModelResource) LeaveRequestReview(request *LeaveRequest, approve bool, reviewerAccountType string, reviewerId uint, note string) error {
+	if request.Status != LeavePending {
+		return ErrLeaveReviewed
+	}
+	for _, approval := range request.Approvals {
+		if approval.ReviewerAccountType == reviewerAccountType && approval.ReviewerID == reviewerId {
+			return ErrLeaveAlreadyApproved
+		}
+	}
+
+	final := approve && request.ApprovedLevels+1 >= request.ApprovalLevels
+	var balance *LeaveBalance
+	if final {
+		employee, company, leaveType, err := m.employeeLeaveType(request.EmployeeID, request.LeaveTypeID)
+		if err != nil {
+			return err
+		}
+		locked, err := m.payPeriodLockedBetween(company, request.StartDate, request.EndDate)
+		if err != nil {
+			return err
+		}
+		if locked {
+			return ErrPayPeriodLocked
+		}
+		if leaveType.Paid {
+			if balance, err = m.leaveBalance(employee.ID, leaveType, time.Now().In(companyLocation(company))); err != nil {
+				return err
+			}
+		}
+	}
+
+	return m.db.Client.Transaction(func(tx *gorm.DB) error {
+		approval := &LeaveApproval{
+			Level:               request.ApprovedLevels + 1,
+			Approved:            approve,
+			ReviewerAccountType: reviewerAccountType,
+			ReviewerID:          reviewerId,
+			Note:                note,
+			LeaveRequestID:      request.ID,
+		}
+		if err := tx.Create(approval).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{"status": LeaveRejected}
+		if approve {
+			updates = map[string]interface{}{"approved_levels": approval.Level}
+		}
+		if final {
+			updates["status"] = LeaveApproved
+		}
+		// Reviewers racing on the same level: only the first one counts
+		result := tx.Model(request).Where("status = ? AND approved_levels = ?", LeavePending, request.ApprovedLevels).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrLeaveReviewed
+		}
+
+		if balance != nil {
+			result := tx.Model(balance).Where("balance >= ?", request.Days).
+				Update("balance", gorm.Expr("balance - ?", request.Days))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrLeaveInsufficientBalance
+			}
+		}
+		return nil
+	})
+}
+
+// approvedLeave returns the approved leave of the employee overlapping the
+// calendar dates from from to to.
+func (m *ModelResource) approvedLeave(employeeId uint, from, to time.Time) ([]*LeaveRequest, error) {
+	var requests []*LeaveRequest
+	err := m.db.Client.Preload("LeaveType").
+		Where("employee_id = ? AND status = ? AND start_date <= ? AND end_date >= ?", employeeId, LeaveApproved, to, from).
+		Find(&requests).Error
+	return requests, err
+}
+
+// leaveOn returns the leave among requests covering the calendar date.
+func leaveOn(requests []*LeaveRequest, date time.Time) *LeaveRequest {
+	for _, request := range requests {
+		if !date.Before(calendarDate(request.StartDate)) && !date.After(calendarDate(request.EndDate)) {
+			return request
+		}
+	}
+	return nil
+}
+
+func (m *ModelResource) LeaveTypeSeeders() error {
+	m.logger.Info("Seeding LeaveType")
+	return nil
+}
+
+func (m *ModelResource) LeaveBalanceSeeders() error {
+	m.logger.Info("Seeding LeaveBalance")
+	return nil
+}
+
+func (m *ModelResource) LeaveRequestSeeders() error {
+	m.logger.Info("Seeding LeaveRequest")
+	return nil
+}
+
+func (m *ModelResource) LeaveApprovalSeeders() error {
+	m.logger.Info("Seeding LeaveApproval")
+	return nil
+}
@@ -15,20 +15,22 @@ type Member struct {
 	gorm.Model
 
 	// Fields
-	FirstName          string     `gorm:"type:varchar(255);unsigned" json:"first_name"`
-	LastName           string     `gorm:"type:varchar(255);unsigned" json:"last_name"`
-	MiddleName         string     `gorm:"type:varchar(255)" json:"middle_name"`
-	PermanentAddress   string     `gorm:"type:text" json:"permanent_address"`
-	Description        string     `gorm:"type:text" json:"description"`
-	BirthDate          time.Time  `gorm:"type:date;unsigned" json:"birth_date"`
-	Username           string     `gorm:"type:varchar(255);unique;unsigned" json:"username"`
-	Email              string     `gorm:"type:varchar(255);unique;unsigned" json:"email"`
-	Password           string     `gorm:"type:varchar(255);unsigned" json:"password"`
-	IsEmailVerified    bool       `gorm:"default:false" json:"is_email_verified"`
-	IsContactVerified  bool       `gorm:"default:false" json:"is_contact_verified"`
-	IsSkipVerification bool       `gorm:"default:false" json:"is_skip_verification"`
-	ContactNumber      string     `gorm:"type:varchar(255);unique;unsigned" json:"contact_number"`
-	Status             UserStatus `gorm:"type:varchar(255);default:'Pending'" json:"status"`
+	FirstName             string     `gorm:"type:varchar(255);unsigned" json:"first_name"`
+	LastName              string     `gorm:"type:varchar(255);unsigned" json:"last_name"`
+	MiddleName            string     `gorm:"type:varchar(255)" json:"middle_name"`
+	PermanentAddress      string     `gorm:"type:text" json:"permanent_address"`
+	Description           string     `gorm:"type:text" json:"description"`
+	BirthDate             time.Time  `gorm:"type:date;unsigned" json:"birth_date"`
+	Username              string     `gorm:"type:varchar(255);unique;unsigned" json:"username"`
+	Email                 string     `gorm:"type:varchar(255);unique;unsigned" json:"email"`
+	Password              string     `gorm:"type:varchar(255);unsigned" json:"password"`
+	IsEmailVerified       bool       `gorm:"default:false" json:"is_email_verified"`
+	IsContactVerified     bool       `gorm:"default:false" json:"is_contact_verified"`
+	IsSkipVerification    bool       `gorm:"default:false" json:"is_skip_verification"`
+	PasswordChangedAt     *time.Time `json:"password_changed_at"`
+	PasswordResetRequired bool       `gorm:"default:false" json:"password_reset_required"`
+	ContactNumber         string     `gorm:"type:varchar(255);unique;unsigned" json:"contact_number"`
+	Status                UserStatus `gorm:"type:varchar(255);default:'Pending'" json:"status"`
 
 	// Relationship 0 to 1
 	MediaID *uint  `gorm:"type:bigint;unsigned" json:"media_id"`
@@ -61,29 +63,31 @@ type MemberResource struct {
 	CreatedAt string `json:"createdAt"`
 	UpdatedAt string `json:"updatedAt"`
 
-	FirstName          string          `json:"firstName"`
-	LastName           string          `json:"lastName"`
-	MiddleName         string          `json:"middleName"`
-	PermanentAddress   string          `json:"permanentAddress"`
-	Description        string          `json:"description"`
-	BirthDate          time.Time       `json:"birthDate"`
-	Username           string          `json:"username"`
-	Email              string          `json:"email"`
-	IsEmailVerified    bool            `json:"isEmailVerified"`
-	IsContactVerified  bool            `json:"isContactVerified"`
-	IsSkipVerification bool            `json:"isSkipVerification"`
-	ContactNumber      string          `json:"contactNumber"`
-	Status             UserStatus      `json:"status"`
-	MediaID            *uint           `json:"mediaID"`
-	Media              *MediaResource  `json:"media"`
-	BranchID           *uint           `json:"branchID"`
-	Branch             *BranchResource `json:"branch"`
-	Longitude          *float64        `json:"longitude"`
-	Latitude           *float64        `json:"latitude"`
-	RoleID             *uint           `json:"roleID"`
-	Role               *RoleResource   `json:"role"`
-	GenderID           *uint           `json:"genderID"`
-	Gender             *GenderResource `json:"gender"`
+	FirstName             string          `json:"firstName"`
+	LastName              string          `json:"lastName"`
+	MiddleName            string          `json:"middleName"`
+	PermanentAddress      string          `json:"permanentAddress"`
+	Description           string          `json:"description"`
+	BirthDate             time.Time       `json:"birthDate"`
+	Username              string          `json:"username"`
+	Email                 string          `json:"email"`
+	IsEmailVerified       bool            `json:"isEmailVerified"`
+	IsContactVerified     bool            `json:"isContactVerified"`
+	IsSkipVerification    bool            `json:"isSkipVerification"`
+	PasswordChangedAt     *string         `json:"passwordChangedAt"`
+	PasswordResetRequired bool            `json:"passwordResetRequired"`
+	ContactNumber         string          `json:"contactNumber"`
+	Status                UserStatus      `json:"status"`
+	MediaID               *uint           `json:"mediaID"`
+	Media                 *MediaResource  `json:"media"`
+	BranchID              *uint           `json:"branchID"`
+	Branch                *BranchResource `json:"branch"`
+	Longitude             *float64        `json:"longitude"`
+	Latitude              *float64        `json:"latitude"`
+	RoleID                *uint           `json:"roleID"`
+	Role                  *RoleResource   `json:"role"`
+	GenderID              *uint           `json:"genderID"`
+	Gender                *GenderResource `json:"gender"`
 
 	Footsteps []*FootstepResource `json:"footsteps"`
 }
@@ -100,33 +104,38 @@ func (m *ModelResource) MemberToResource(member *Member) *MemberResource {
 		CreatedAt: member.CreatedAt.Format(time.RFC3339),
 		UpdatedAt: member.UpdatedAt.Format(time.RFC3339),
 
-		FirstName:          member.FirstName,
-		LastName:           member.LastName,
-		MiddleName:         member.MiddleName,
-		PermanentAddress:   member.PermanentAddress,
-		Description:        member.Description,
-		BirthDate:          member.BirthDate,
-		Username:           member.Username,
-		Email:              member.Email,
-		IsEmailVerified:    member.IsEmailVerified,
-		IsContactVerified:  member.IsContactVerified,
-		IsSkipVerification: member.IsSkipVerification,
-		ContactNumber:      member.ContactNumber,
-		Status:             member.Status,
-		MediaID:            member.MediaID,
-		Media:              m.MediaToResource(member.Media),
-		BranchID:           member.BranchID,
-		Branch:             m.BranchToResource(member.Branch),
-		Longitude:          member.Longitude,
-		Latitude:           member.Latitude,
-		RoleID:             member.RoleID,
-		Role:               m.RoleToResource(member.Role),
-		GenderID:           member.GenderID,
-		Gender:             m.GenderToResource(member.Gender),
-		Footsteps:          m.FootstepToResourceList(member.Footsteps),
+		FirstName:             member.FirstName,
+		LastName:              member.LastName,
+		MiddleName:            member.MiddleName,
+		PermanentAddress:      member.PermanentAddress,
+		Description:           member.Description,
+		BirthDate:             member.BirthDate,
+		Username:              member.Username,
+		Email:                 member.Email,
+		IsEmailVerified:       member.IsEmailVerified,
+		IsContactVerified:     member.IsContactVerified,
+		IsSkipVerification:    member.IsSkipVerification,
+		PasswordChangedAt:     formatOptionalTime(member.PasswordChangedAt),
+		PasswordResetRequired: member.PasswordResetRequired,
+		ContactNumber:         member.ContactNumber,
+		Status:                member.Status,
+		MediaID:               member.MediaID,
+		Media:                 m.MediaToResource(member.Media),
+		BranchID:              member.BranchID,
+		Branch:                m.BranchToResource(member.Branch),
+		Longitude:             member.Longitude,
+		Latitude:              member.Latitude,
+		RoleID:                member.RoleID,
+		Role:                  m.RoleToResource(member.Role),
+		GenderID:              member.GenderID,
+		Gender:                m.GenderToResource(member.Gender),
+		Footsteps:             m.FootstepToResourceList(member.Footsteps),
 	}
 }
 
+// MemberRequest updates a member, members included. The branch and role are
+// changed through their own routes so members cannot change theirs, and the
+// password only through the auth flow.
 type MemberRequest struct {
 	FirstName          string    `json:"firstName" validate:"required,max=255"`
 	LastName           string    `json:"lastName" validate:"required,max=255"`
@@ -136,7 +145,29 @@ type MemberRequest struct {
 	BirthDate          time.Time `json:"birthDate" validate:"required"`
 	Username           string    `json:"username" validate:"required,max=255"`
 	Email              string    `json:"email" validate:"required,email,max=255"`
-	Password           string    `json:"password" validate:"required,min=8,max=255"`
+	IsEmailVerified    bool      `json:"isEmailVerified"`
+	IsContactVerified  bool      `json:"isContactVerified"`
+	IsSkipVerification bool      `json:"isSkipVerification"`
+	ContactNumber      string    `json:"contactNumber" validate:"required,max=255"`
+	Status             string    `json:"status" validate:"required,oneof=Pending Active Inactive"`
+	MediaID            *uint     `json:"mediaID,omitempty"`
+	Longitude          *float64  `json:"longitude" validate:"omitempty,longitude"`
+	Latitude           *float64  `json:"latitude" validate:"omitempty,latitude"`
+	GenderID           *uint     `json:"genderID,omitempty"`
+}
+
+// MemberCreateRequest creates or imports a member into a branch, which must be
+// within the scope of the caller. Members set their password through the
+// password reset flow.
+type MemberCreateRequest struct {
+	FirstName          string    `json:"firstName" validate:"required,max=255"`
+	LastName           string    `json:"lastName" validate:"required,max=255"`
+	MiddleName         string    `json:"middleName,omitempty" validate:"max=255"`
+	PermanentAddress   string    `json:"permanentAddress,omitempty"`
+	Description        string    `json:"description,omitempty"`
+	BirthDate          time.Time `json:"birthDate" validate:"required"`
+	Username           string    `json:"username" validate:"required,max=255"`
+	Email              string    `json:"email" validate:"required,email,max=255"`
 	IsEmailVerified    bool      `json:"isEmailVerified"`
 	IsContactVerified  bool      `json:"isContactVerified"`
 	IsSkipVerification bool      `json:"isSkipVerification"`
@@ -146,7 +177,6 @@ type MemberRequest struct {
 	BranchID           *uint     `json:"branchID,omitempty"`
 	Longitude          *float64  `json:"longitude" validate:"omitempty,longitude"`
 	Latitude           *float64  `json:"latitude" validate:"omitempty,latitude"`
-	RoleID             *uint     `json:"roleID,omitempty"`
 	GenderID           *uint     `json:"genderID,omitempty"`
 }
 
@@ -297,6 +327,14 @@ func (m *ModelResource) MemberToRecord(members []*Member) ([][]string, []string)
 }
 
 func (m *ModelResource) ValidateMemberRequest(req *MemberRequest) error {
+	return m.validateMember(req)
+}
+
+func (m *ModelResource) ValidateMemberCreateRequest(req *MemberCreateRequest) error {
+	return m.validateMember(req)
+}
+
+func (m *ModelResource) validateMember(req interface{}) error {
 	validate := validator.New()
 	validate.RegisterValidation("longitude", func(fl validator.FieldLevel) bool {
 		lon := fl.Field().Float()
@@ -341,14 +379,25 @@ func (r *ModelResource) MemberFindByEmailUsernameOrContact(input string) (*Membe
 		return r.MemberGetByUsername(input)
 	}
 }
-func (r *ModelResource) MemberUpdatePassword(id uint, password string) error {
-	newPassword, err := r.cryptoHelpers.HashPassword(password)
-	if err != nil {
-		return err
+
+// Member is an Account, for the auth flows.
+func (member *Member) GetID() uint              { return member.ID }
+func (member *Member) GetPassword() string      { return member.Password }
+func (member *Member) GetEmail() string         { return member.Email }
+func (member *Member) GetContactNumber() string { return member.ContactNumber }
+func (member *Member) GetStatus() UserStatus    { return member.Status }
+func (member *Member) GetName() string {
+	return fmt.Sprintf("%s %s", member.FirstName, member.LastName)
+}
+func (member *Member) GetFullName() (string, string, string) {
+	return member.FirstName, member.MiddleName, member.LastName
+}
+func (member *Member) GetVerification() AccountVerification {
+	return AccountVerification{
+		Email:         member.IsEmailVerified,
+		ContactNumber: member.IsContactVerified,
+		Skipped:       member.IsSkipVerification,
 	}
-	updated := &Member{Password: newPassword}
-	_, err = r.MemberDB.UpdateColumns(id, *updated, []string{"Media"})
-	return err
 }
 
 func (r *ModelResource) MemberCreate(user *Member) error {
@@ -363,6 +412,8 @@ func (r *ModelResource) MemberCreate(user *Member) error {
 		return err
 	}
 	user.Password = hashedPassword
+	now := time.Now()
+	user.PasswordChangedAt = &now
 	return r.MemberDB.Create(user)
 }
 
@@ -376,6 +427,8 @@ func (r *ModelResource) MemberUpdate(user *Member, preloads []string) error {
 			return err
 		}
 		user.Password = hashedPassword
+		now := time.Now()
+		user.PasswordChangedAt = &now
 	}
 	return r.MemberDB.Update(user, preloads)
 }
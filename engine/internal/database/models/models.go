@@ -31,20 +31,31 @@ type ModelResource struct {
 	helpers       *helpers.HelpersFunction
 	cryptoHelpers *helpers.HelpersCryptography
 	Models        []MigrateItem
+	AccountKinds  []*AccountKind
 
-	AdminDB     *managers.Repository[Admin]
-	BranchDB    *managers.Repository[Branch]
-	CompanyDB   *managers.Repository[Company]
-	ContactDB   *managers.Repository[Contact]
-	EmployeeDB  *managers.Repository[Employee]
-	FeedbackDB  *managers.Repository[Feedback]
-	FootstepDB  *managers.Repository[Footstep]
-	GenderDB    *managers.Repository[Gender]
-	MediaDB     *managers.Repository[Media]
-	MemberDB    *managers.Repository[Member]
-	OwnerDB     *managers.Repository[Owner]
-	RoleDB      *managers.Repository[Role]
-	TimesheetDB *managers.Repository[Timesheet]
+	AdminDB               *managers.Repository[Admin]
+	AuditLogDB            *managers.Repository[AuditLog]
+	BranchDB              *managers.Repository[Branch]
+	CompanyDB             *managers.Repository[Company]
+	ContactDB             *managers.Repository[Contact]
+	EmployeeDB            *managers.Repository[Employee]
+	FeedbackDB            *managers.Repository[Feedback]
+	FootstepDB            *managers.Repository[Footstep]
+	GenderDB              *managers.Repository[Gender]
+	HolidayDB             *managers.Repository[Holiday]
+	LeaveRequestDB        *managers.Repository[LeaveRequest]
+	LeaveTypeDB           *managers.Repository[LeaveType]
+	MediaDB               *managers.Repository[Media]
+	MemberDB              *managers.Repository[Member]
+	OwnerDB               *managers.Repository[Owner]
+	PayPeriodDB           *managers.Repository[PayPeriod]
+	PermissionDB          *managers.Repository[Permission]
+	RoleDB                *managers.Repository[Role]
+	ShiftAssignmentDB     *managers.Repository[ShiftAssignment]
+	ShiftDB               *managers.Repository[Shift]
+	TimesheetCorrectionDB *managers.Repository[TimesheetCorrection]
+	TimesheetDB           *managers.Repository[Timesheet]
+	TwoFactorDB           *managers.Repository[TwoFactor]
 }
 
 func NewModelResource(
@@ -64,40 +75,85 @@ func NewModelResource(
 		helpers:       helpers,
 		cryptoHelpers: cryptoHelpers,
 
-		AdminDB:     managers.NewRepository[Admin](db),
-		BranchDB:    managers.NewRepository[Branch](db),
-		CompanyDB:   managers.NewRepository[Company](db),
-		ContactDB:   managers.NewRepository[Contact](db),
-		EmployeeDB:  managers.NewRepository[Employee](db),
-		FeedbackDB:  managers.NewRepository[Feedback](db),
-		FootstepDB:  managers.NewRepository[Footstep](db),
-		GenderDB:    managers.NewRepository[Gender](db),
-		MediaDB:     managers.NewRepository[Media](db),
-		MemberDB:    managers.NewRepository[Member](db),
-		OwnerDB:     managers.NewRepository[Owner](db),
-		RoleDB:      managers.NewRepository[Role](db),
-		TimesheetDB: managers.NewRepository[Timesheet](db),
+		AdminDB:               managers.NewRepository[Admin](db),
+		AuditLogDB:            managers.NewRepository[AuditLog](db),
+		BranchDB:              managers.NewRepository[Branch](db),
+		CompanyDB:             managers.NewRepository[Company](db),
+		ContactDB:             managers.NewRepository[Contact](db),
+		EmployeeDB:            managers.NewRepository[Employee](db),
+		FeedbackDB:            managers.NewRepository[Feedback](db),
+		FootstepDB:            managers.NewRepository[Footstep](db),
+		GenderDB:              managers.NewRepository[Gender](db),
+		HolidayDB:             managers.NewRepository[Holiday](db),
+		LeaveRequestDB:        managers.NewRepository[LeaveRequest](db),
+		LeaveTypeDB:           managers.NewRepository[LeaveType](db),
+		MediaDB:               managers.NewRepository[Media](db),
+		MemberDB:              managers.NewRepository[Member](db),
+		OwnerDB:               managers.NewRepository[Owner](db),
+		PayPeriodDB:           managers.NewRepository[PayPeriod](db),
+		PermissionDB:          managers.NewRepository[Permission](db),
+		RoleDB:                managers.NewRepository[Role](db),
+		ShiftDB:               managers.NewRepository[Shift](db),
+		ShiftAssignmentDB:     managers.NewRepository[ShiftAssignment](db),
+		TimesheetCorrectionDB: managers.NewRepository[TimesheetCorrection](db),
+		TimesheetDB:           managers.NewRepository[Timesheet](db),
+		TwoFactorDB:           managers.NewRepository[TwoFactor](db),
 	}
 
+	modelResource.AccountKinds = modelResource.accountKinds()
+
 	modelResource.Models = []MigrateItem{
+		{Model: &AuditLog{}, Seeder: modelResource.AuditLogSeeders, ModelName: "AuditLog"},
 		{Model: &Admin{}, Seeder: modelResource.AdminSeeders, ModelName: "Admin"},
 		{Model: &Company{}, Seeder: modelResource.CompanySeeders, ModelName: "Company"},
 		{Model: &Branch{}, Seeder: modelResource.BranchSeeders, ModelName: "Branch"},
 		{Model: &Contact{}, Seeder: modelResource.ContactSeeders, ModelName: "Contact"},
 		{Model: &Employee{}, Seeder: modelResource.EmployeeSeeders, ModelName: "Employee"},
 		{Model: &Feedback{}, Seeder: modelResource.FeedbackSeeders, ModelName: "Feedback"},
+		{Model: &ApiKey{}, Seeder: modelResource.ApiKeySeeders, ModelName: "ApiKey"},
 		{Model: &Footstep{}, Seeder: modelResource.FootstepSeeders, ModelName: "Footstep"},
 		{Model: &Gender{}, Seeder: modelResource.GenderSeeders, ModelName: "Gender"},
 		{Model: &Media{}, Seeder: modelResource.MediaSeeders, ModelName: "Media"},
 		{Model: &Member{}, Seeder: modelResource.MemberSeeders, ModelName: "Member"},
 		{Model: &Owner{}, Seeder: modelResource.OwnerSeeders, ModelName: "Owner"},
 		{Model: &Role{}, Seeder: modelResource.RoleSeeders, ModelName: "Role"},
+		{Model: &Permission{}, Seeder: modelResource.PermissionSeeders, ModelName: "Permission"},
+		{Model: &Shift{}, Seeder: modelResource.ShiftSeeders, ModelName: "Shift"},
+		{Model: &ShiftAssignment{}, Seeder: modelResource.ShiftAssignmentSeeders, ModelName: "ShiftAssignment"},
+		{Model: &Holiday{}, Seeder: modelResource.HolidaySeeders, ModelName: "Holiday"},
 		{Model: &Timesheet{}, Seeder: modelResource.TimesheetSeeders, ModelName: "Timesheet"},
+		{Model: &PayPeriod{}, Seeder: modelResource.PayPeriodSeeders, ModelName: "PayPeriod"},
+		{Model: &LeaveType{}, Seeder: modelResource.LeaveTypeSeeders, ModelName: "LeaveType"},
+		{Model: &LeaveBalance{}, Seeder: modelResource.LeaveBalanceSeeders, ModelName: "LeaveBalance"},
+		{Model: &LeaveRequest{}, Seeder: modelResource.LeaveRequestSeeders, ModelName: "LeaveRequest"},
+		{Model: &LeaveApproval{}, Seeder: modelResource.LeaveApprovalSeeders, ModelName: "LeaveApproval"},
+		{Model: &TimesheetCorrection{}, Seeder: modelResource.TimesheetCorrectionSeeders, ModelName: "TimesheetCorrection"},
+		{Model: &TwoFactor{}, Seeder: modelResource.TwoFactorSeeders, ModelName: "TwoFactor"},
+		{Model: &TwoFactorRecoveryCode{}, Seeder: modelResource.TwoFactorRecoveryCodeSeeders, ModelName: "TwoFactorRecoveryCode"},
+		{Model: &Session{}, Seeder: modelResource.SessionSeeders, ModelName: "Session"},
+		{Model: &SessionRefreshToken{}, Seeder: modelResource.SessionRefreshTokenSeeders, ModelName: "SessionRefreshToken"},
+		{Model: &PasswordHistory{}, Seeder: modelResource.PasswordHistorySeeders, ModelName: "PasswordHistory"},
+		{Model: &OIDCIdentity{}, Seeder: modelResource.OIDCIdentitySeeders, ModelName: "OIDCIdentity"},
+	}
+
+	// Sessions are written on every refresh and keep their own history, and
+	// password changes are audited on the account itself
+	if err := managers.RegisterAuditCallbacks(db.Client, modelResource.AuditRecord, "audit_logs", "sessions", "session_refresh_tokens", "password_histories"); err != nil {
+		return nil, err
 	}
 
 	return modelResource, nil
 }
 
+// splitList splits a comma separated column into its values, returning an
+// empty list for an empty column.
+func splitList(list string) []string {
+	if list == "" {
+		return []string{}
+	}
+	return strings.Split(list, ",")
+}
+
 func sanitizeCSVField(field string) string {
 	if strings.HasPrefix(field, "=") || strings.HasPrefix(field, "+") ||
 		strings.HasPrefix(field, "-") || strings.HasPrefix(field, "@") {
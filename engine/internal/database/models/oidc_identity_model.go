@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OIDCIdentity links the subject of an OpenID Connect identity provider to an
+// Owner or Employee. It is created by the first sign-in, which matches the
+// verified email of the identity; later sign-ins follow the link even when
+// the email changes at the provider.
+type OIDCIdentity struct {
+	gorm.Model
+
+	// Fields
+	Issuer       string     `gorm:"type:varchar(255);uniqueIndex:idx_oidc_identity_subject" json:"issuer"`
+	Subject      string     `gorm:"type:varchar(255);uniqueIndex:idx_oidc_identity_subject" json:"subject"`
+	AccountType  string     `gorm:"type:varchar(11);uniqueIndex:idx_oidc_identity_subject;index:idx_oidc_identity_user" json:"account_type"`
+	UserID       uint       `gorm:"index:idx_oidc_identity_user" json:"user_id"`
+	Email        string     `gorm:"type:varchar(255)" json:"email"`
+	LastSignInAt *time.Time `json:"last_sign_in_at"`
+}
+
+// OIDCIdentityGet returns the link of the subject of issuer to an account of
+// accountType, or nil when there is none.
+func (m *ModelResource) OIDCIdentityGet(issuer, subject, accountType string) (*OIDCIdentity, error) {
+	var identities []*OIDCIdentity
+	err := m.db.Client.Where("issuer = ? AND subject = ? AND account_type = ?", issuer, subject, accountType).
+		Limit(1).Find(&identities).Error
+	if err != nil || len(identities) == 0 {
+		return nil, err
+	}
+	return identities[0], nil
+}
+
+// OIDCIdentityLink stores a new link.
+func (m *ModelResource) OIDCIdentityLink(identity *OIDCIdentity) error {
+	return m.db.Client.Create(identity).Error
+}
+
+// OIDCIdentityTouch records a sign-in through the link along with the current
+// email of the identity.
+func (m *ModelResource) OIDCIdentityTouch(identity *OIDCIdentity, email string) error {
+	return m.db.Client.Model(identity).Updates(map[string]interface{}{
+		"email":           email,
+		"last_sign_in_at": time.Now(),
+	}).Error
+}
+
+func (m *ModelResource) OIDCIdentitySeeders() error {
+	m.logger.Info("Seeding OIDCIdentity")
+	return nil
+}
@@ -16,20 +16,22 @@ type Owner struct {
 	gorm.Model
 
 	// Fields
-	FirstName          string     `gorm:"type:varchar(255);unsigned" json:"first_name"`
-	LastName           string     `gorm:"type:varchar(255);unsigned" json:"last_name"`
-	MiddleName         string     `gorm:"type:varchar(255)" json:"middle_name"`
-	PermanentAddress   string     `gorm:"type:text" json:"permanent_address"`
-	Description        string     `gorm:"type:text" json:"description"`
-	BirthDate          time.Time  `gorm:"type:date" json:"birth_date"`
-	Username           string     `gorm:"type:varchar(255);unique;unsigned" json:"username"`
-	Email              string     `gorm:"type:varchar(255);unique;unsigned" json:"email"`
-	Password           string     `gorm:"type:varchar(255);unsigned" json:"password"`
-	ContactNumber      string     `gorm:"type:varchar(15);unique;unsigned" json:"contact_number"`
-	IsEmailVerified    bool       `gorm:"default:false" json:"is_email_verified"`
-	IsContactVerified  bool       `gorm:"default:false" json:"is_contact_verified"`
-	IsSkipVerification bool       `gorm:"default:false" json:"is_skip_verification"`
-	Status             UserStatus `gorm:"type:varchar(11);default:'Pending'" json:"status"`
+	FirstName             string     `gorm:"type:varchar(255);unsigned" json:"first_name"`
+	LastName              string     `gorm:"type:varchar(255);unsigned" json:"last_name"`
+	MiddleName            string     `gorm:"type:varchar(255)" json:"middle_name"`
+	PermanentAddress      string     `gorm:"type:text" json:"permanent_address"`
+	Description           string     `gorm:"type:text" json:"description"`
+	BirthDate             time.Time  `gorm:"type:date" json:"birth_date"`
+	Username              string     `gorm:"type:varchar(255);unique;unsigned" json:"username"`
+	Email                 string     `gorm:"type:varchar(255);unique;unsigned" json:"email"`
+	Password              string     `gorm:"type:varchar(255);unsigned" json:"password"`
+	ContactNumber         string     `gorm:"type:varchar(15);unique;unsigned" json:"contact_number"`
+	IsEmailVerified       bool       `gorm:"default:false" json:"is_email_verified"`
+	IsContactVerified     bool       `gorm:"default:false" json:"is_contact_verified"`
+	IsSkipVerification    bool       `gorm:"default:false" json:"is_skip_verification"`
+	PasswordChangedAt     *time.Time `json:"password_changed_at"`
+	PasswordResetRequired bool       `gorm:"default:false" json:"password_reset_required"`
+	Status                UserStatus `gorm:"type:varchar(11);default:'Pending'" json:"status"`
 
 	// Relationship 0 to 1
 	MediaID *uint  `gorm:"type:bigint;unsigned" json:"media_id"`
@@ -57,28 +59,30 @@ type OwnerResource struct {
 	CreatedAt string `json:"createdAt"`
 	UpdatedAt string `json:"updatedAt"`
 
-	FirstName          string              `json:"firstName"`
-	LastName           string              `json:"lastName"`
-	MiddleName         string              `json:"middleName"`
-	PermanentAddress   string              `json:"permanentAddress"`
-	Description        string              `json:"description"`
-	BirthDate          time.Time           `json:"birthDate"`
-	Username           string              `json:"username"`
-	Email              string              `json:"email"`
-	Password           string              `json:"password"`
-	ContactNumber      string              `json:"contactNumber"`
-	IsEmailVerified    bool                `json:"isEmailVerified"`
-	IsContactVerified  bool                `json:"isContactVerified"`
-	IsSkipVerification bool                `json:"isSkipVerification"`
-	Status             UserStatus          `json:"status"`
-	MediaID            *uint               `json:"mediaID"`
-	Media              *MediaResource      `json:"media"`
-	GenderID           *uint               `json:"genderID"`
-	Gender             *GenderResource     `json:"gender"`
-	RoleID             *uint               `json:"roleID"`
-	Role               *RoleResource       `json:"role"`
-	Footsteps          []*FootstepResource `json:"footsteps"`
-	Companies          []*CompanyResource  `json:"companies"`
+	FirstName             string              `json:"firstName"`
+	LastName              string              `json:"lastName"`
+	MiddleName            string              `json:"middleName"`
+	PermanentAddress      string              `json:"permanentAddress"`
+	Description           string              `json:"description"`
+	BirthDate             time.Time           `json:"birthDate"`
+	Username              string              `json:"username"`
+	Email                 string              `json:"email"`
+	Password              string              `json:"password"`
+	ContactNumber         string              `json:"contactNumber"`
+	IsEmailVerified       bool                `json:"isEmailVerified"`
+	IsContactVerified     bool                `json:"isContactVerified"`
+	IsSkipVerification    bool                `json:"isSkipVerification"`
+	PasswordChangedAt     *string             `json:"passwordChangedAt"`
+	PasswordResetRequired bool                `json:"passwordResetRequired"`
+	Status                UserStatus          `json:"status"`
+	MediaID               *uint               `json:"mediaID"`
+	Media                 *MediaResource      `json:"media"`
+	GenderID              *uint               `json:"genderID"`
+	Gender                *GenderResource     `json:"gender"`
+	RoleID                *uint               `json:"roleID"`
+	Role                  *RoleResource       `json:"role"`
+	Footsteps             []*FootstepResource `json:"footsteps"`
+	Companies             []*CompanyResource  `json:"companies"`
 }
 
 func (m *ModelResource) OwnerToResource(owner *Owner) *OwnerResource {
@@ -101,22 +105,26 @@ func (m *ModelResource) OwnerToResource(owner *Owner) *OwnerResource {
 		Username:         owner.Username,
 		Email:            owner.Email,
 
-		ContactNumber:      owner.ContactNumber,
-		IsEmailVerified:    owner.IsEmailVerified,
-		IsContactVerified:  owner.IsContactVerified,
-		IsSkipVerification: owner.IsSkipVerification,
-		Status:             owner.Status,
-		MediaID:            owner.MediaID,
-		Media:              m.MediaToResource(owner.Media),
-		GenderID:           owner.GenderID,
-		Gender:             m.GenderToResource(owner.Gender),
-		RoleID:             owner.RoleID,
-		Role:               m.RoleToResource(owner.Role),
-		Footsteps:          m.FootstepToResourceList(owner.Footsteps),
-		Companies:          m.CompanyToResourceList(owner.Companies),
+		ContactNumber:         owner.ContactNumber,
+		IsEmailVerified:       owner.IsEmailVerified,
+		IsContactVerified:     owner.IsContactVerified,
+		IsSkipVerification:    owner.IsSkipVerification,
+		PasswordChangedAt:     formatOptionalTime(owner.PasswordChangedAt),
+		PasswordResetRequired: owner.PasswordResetRequired,
+		Status:                owner.Status,
+		MediaID:               owner.MediaID,
+		Media:                 m.MediaToResource(owner.Media),
+		GenderID:              owner.GenderID,
+		Gender:                m.GenderToResource(owner.Gender),
+		RoleID:                owner.RoleID,
+		Role:                  m.RoleToResource(owner.Role),
+		Footsteps:             m.FootstepToResourceList(owner.Footsteps),
+		Companies:             m.CompanyToResourceList(owner.Companies),
 	}
 }
 
+// OwnerRequest updates an owner, owners included. Admins set the role of an
+// owner through its own route.
 type OwnerRequest struct {
 	FirstName        string    `json:"firstName" validate:"required,max=255"`
 	LastName         string    `json:"lastName" validate:"required,max=255"`
@@ -134,7 +142,6 @@ type OwnerRequest struct {
 	Status             string `json:"status" validate:"required,oneof=Pending Active Inactive"`
 	MediaID            *uint  `json:"mediaID,omitempty"`
 	GenderID           *uint  `json:"genderID,omitempty"`
-	RoleID             *uint  `json:"roleID,omitempty"`
 }
 
 func (m *ModelResource) OwnerToResourceList(owners []*Owner) []*OwnerResource {
@@ -312,14 +319,23 @@ func (r *ModelResource) OwnerFindByEmailUsernameOrContact(input string) (*Owner,
 		return r.OwnerGetByUsername(input)
 	}
 }
-func (r *ModelResource) OwnerUpdatePassword(id uint, password string) error {
-	newPassword, err := r.cryptoHelpers.HashPassword(password)
-	if err != nil {
-		return err
+
+// Owner is an Account, for the auth flows.
+func (owner *Owner) GetID() uint              { return owner.ID }
+func (owner *Owner) GetPassword() string      { return owner.Password }
+func (owner *Owner) GetEmail() string         { return owner.Email }
+func (owner *Owner) GetContactNumber() string { return owner.ContactNumber }
+func (owner *Owner) GetStatus() UserStatus    { return owner.Status }
+func (owner *Owner) GetName() string          { return fmt.Sprintf("%s %s", owner.FirstName, owner.LastName) }
+func (owner *Owner) GetFullName() (string, string, string) {
+	return owner.FirstName, owner.MiddleName, owner.LastName
+}
+func (owner *Owner) GetVerification() AccountVerification {
+	return AccountVerification{
+		Email:         owner.IsEmailVerified,
+		ContactNumber: owner.IsContactVerified,
+		Skipped:       owner.IsSkipVerification,
 	}
-	updated := &Owner{Password: newPassword}
-	_, err = r.OwnerDB.UpdateColumns(id, *updated, []string{"Media"})
-	return err
 }
 
 func (r *ModelResource) OwnerCreate(user *Owner) error {
@@ -334,6 +350,8 @@ func (r *ModelResource) OwnerCreate(user *Owner) error {
 		return err
 	}
 	user.Password = hashedPassword
+	now := time.Now()
+	user.PasswordChangedAt = &now
 	return r.OwnerDB.Create(user)
 }
 
@@ -347,6 +365,8 @@ func (r *ModelResource) OwnerUpdate(user *Owner, preloads []string) error {
 			return err
 		}
 		user.Password = hashedPassword
+		now := time.Now()
+		user.PasswordChangedAt = &now
 	}
 	return r.OwnerDB.Update(user, preloads)
 }
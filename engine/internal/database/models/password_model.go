@@ -0,0 +1,149 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrPasswordReused = errors.New("password was used recently, choose another one")
+
+// PasswordHistory keeps the hash of a password an account had, so that it is
+// not chosen again too soon. Only the last cfg.PasswordHistory are kept.
+type PasswordHistory struct {
+	gorm.Model
+
+	// Fields
+	AccountType  string `gorm:"type:varchar(11);index:idx_password_history_user" json:"account_type"`
+	UserID       uint   `gorm:"index:idx_password_history_user" json:"user_id"`
+	PasswordHash string `gorm:"type:varchar(255)" json:"-"`
+}
+
+// PasswordUpdate sets the password of the account, clears its forced reset
+// and records it in the password history.
+func (m *ModelResource) PasswordUpdate(accountType string, userId uint, password string) error {
+	model, err := m.accountModel(accountType)
+	if err != nil {
+		return err
+	}
+	hash, err := m.cryptoHelpers.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	return m.db.Client.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(model).Where("id = ?", userId).Updates(map[string]interface{}{
+			"password":                hash,
+			"password_changed_at":     time.Now(),
+			"password_reset_required": false,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if m.cfg.PasswordHistory <= 0 {
+			return nil
+		}
+		if err := tx.Create(&PasswordHistory{AccountType: accountType, UserID: userId, PasswordHash: hash}).Error; err != nil {
+			return err
+		}
+		var stale []uint
+		err := tx.Model(&PasswordHistory{}).
+			Where("account_type = ? AND user_id = ?", accountType, userId).
+			Order("id DESC").Offset(m.cfg.PasswordHistory).Limit(1000).
+			Pluck("id", &stale).Error
+		if err != nil || len(stale) == 0 {
+			return err
+		}
+		return tx.Unscoped().Delete(&PasswordHistory{}, stale).Error
+	})
+}
+
+// PasswordReused reports whether password is the current password of the
+// account or one of the last cfg.PasswordHistory it had.
+func (m *ModelResource) PasswordReused(accountType string, userId uint, password string) (bool, error) {
+	table, err := m.accountTable(accountType)
+	if err != nil {
+		return false, err
+	}
+	var current []string
+	if err := m.db.Client.Table(table).Where("id = ?", userId).Pluck("password", &current).Error; err != nil {
+		return false, err
+	}
+	if len(current) > 0 && m.cryptoHelpers.VerifyPassword(current[0], password) {
+		return true, nil
+	}
+	if m.cfg.PasswordHistory <= 0 {
+		return false, nil
+	}
+	var hashes []string
+	err = m.db.Client.Model(&PasswordHistory{}).
+		Where("account_type = ? AND user_id = ?", accountType, userId).
+		Order("id DESC").Limit(m.cfg.PasswordHistory).
+		Pluck("password_hash", &hashes).Error
+	if err != nil {
+		return false, err
+	}
+	for _, hash := range hashes {
+		if m.cryptoHelpers.VerifyPassword(hash, password) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// PasswordRequireReset sets or clears the forced password reset of the
+// account.
+func (m *ModelResource) PasswordRequireReset(accountType string, userId uint, required bool) error {
+	model, err := m.accountModel(accountType)
+	if err != nil {
+		return err
+	}
+	result := m.db.Client.Model(model).Where("id = ?", userId).Update("password_reset_required", required)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// PasswordResetRequired reports whether the account has to choose a new
+// password before signing in, either because a reset was forced or because
+// its password is older than cfg.PasswordMaxAge.
+func (m *ModelResource) PasswordResetRequired(accountType string, userId uint) (bool, error) {
+	table, err := m.accountTable(accountType)
+	if err != nil {
+		return false, err
+	}
+	var account struct {
+		PasswordResetRequired bool
+		PasswordChangedAt     *time.Time
+		CreatedAt             time.Time
+	}
+	err = m.db.Client.Table(table).
+		Select("password_reset_required, password_changed_at, created_at").
+		Where("id = ?", userId).Take(&account).Error
+	if err != nil {
+		return false, err
+	}
+	if account.PasswordResetRequired {
+		return true, nil
+	}
+	if m.cfg.PasswordMaxAge <= 0 {
+		return false, nil
+	}
+	changedAt := account.CreatedAt
+	if account.PasswordChangedAt != nil {
+		changedAt = *account.PasswordChangedAt
+	}
+	return time.Since(changedAt) > m.cfg.PasswordMaxAge, nil
+}
+
+func (m *ModelResource) PasswordHistorySeeders() error {
+	m.logger.Info("Seeding PasswordHistory")
+	return nil
+}
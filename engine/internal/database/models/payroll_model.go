@@ -0,0 +1,397 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
+	"github.com/go-playground/validator"
+	"gorm.io/gorm"
+)
+
+// Night differential hours, in the time zone of the company.
+const (
+	nightStartHour = 22
+	nightEndHour   = 6
+)
+
+var ErrPayPeriodLocked = errors.New("the pay period is locked")
+
+// PayPeriod is a cutoff of a company, from StartDate to EndDate, both
+// calendar dates. Once locked, the timesheets of the period can no longer be
+// changed.
+type PayPeriod struct {
+	gorm.Model
+
+	// Fields
+	Name      string     `gorm:"type:varchar(255)" json:"name"`
+	StartDate time.Time  `gorm:"type:date;index" json:"start_date"`
+	EndDate   time.Time  `gorm:"type:date;index" json:"end_date"`
+	LockedAt  *time.Time `json:"locked_at"`
+
+	// Relationship 1 to many
+	CompanyID uint     `gorm:"type:bigint;unsigned;index" json:"company_id"`
+	Company   *Company `gorm:"foreignKey:CompanyID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"company"`
+}
+
+type PayPeriodResource struct {
+	ID        uint   `json:"id"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+
+	Name      string  `json:"name"`
+	StartDate string  `json:"startDate"`
+	EndDate   string  `json:"endDate"`
+	LockedAt  *string `json:"lockedAt"`
+	CompanyID uint    `json:"companyID"`
+}
+
+type PayPeriodRequest struct {
+	Name      string    `json:"name" validate:"required,max=255"`
+	StartDate time.Time `json:"startDate" validate:"required"`
+	EndDate   time.Time `json:"endDate" validate:"required"`
+	CompanyID uint      `json:"companyID" validate:"required"`
+}
+
+// TimesheetSummary is what an employee worked in a pay period. Regular,
+// overtime and holiday minutes split the minutes worked, less breaks; night
+// minutes are those of them worked at night and overlap the others.
+type TimesheetSummary struct {
+	Employee         *Employee
+	DaysPresent      int
+	AbsentDays       int
+	LeaveDays        int
+	PaidLeaveDays    int
+	OpenTimesheets   int
+	ReviewTimesheets int
+	LateMinutes      int
+	UndertimeMinutes int
+	RegularMinutes   int
+	OvertimeMinutes  int
+	NightMinutes     int
+	HolidayMinutes   int
+}
+
+type TimesheetSummaryResource struct {
+	EmployeeID       uint    `json:"employeeID"`
+	EmployeeName     string  `json:"employeeName"`
+	DaysPresent      int     `json:"daysPresent"`
+	AbsentDays       int     `json:"absentDays"`
+	LeaveDays        int     `json:"leaveDays"`
+	PaidLeaveDays    int     `json:"paidLeaveDays"`
+	OpenTimesheets   int     `json:"openTimesheets"`
+	ReviewTimesheets int     `json:"reviewTimesheets"`
+	LateMinutes      int     `json:"lateMinutes"`
+	UndertimeMinutes int     `json:"undertimeMinutes"`
+	RegularHours     float64 `json:"regularHours"`
+	OvertimeHours    float64 `json:"overtimeHours"`
+	NightHours       float64 `json:"nightHours"`
+	HolidayHours     float64 `json:"holidayHours"`
+}
+
+func (m *ModelResource) PayPeriodToResource(period *PayPeriod) *PayPeriodResource {
+	if period == nil {
+		return nil
+	}
+	return &PayPeriodResource{
+		ID:        period.ID,
+		CreatedAt: period.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: period.UpdatedAt.Format(time.RFC3339),
+
+		Name:      period.Name,
+		StartDate: formatDate(period.StartDate),
+		EndDate:   formatDate(period.EndDate),
+		LockedAt:  formatOptionalTime(period.LockedAt),
+		CompanyID: period.CompanyID,
+	}
+}
+
+func (m *ModelResource) PayPeriodToResourceList(periods []*PayPeriod) []*PayPeriodResource {
+	if periods == nil {
+		return nil
+	}
+	var periodResources []*PayPeriodResource
+	for _, period := range periods {
+		periodResources = append(periodResources, m.PayPeriodToResource(period))
+	}
+	return periodResources
+}
+
+func (m *ModelResource) ValidatePayPeriodRequest(req *PayPeriodRequest) error {
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		return m.helpers.FormatValidationError(err)
+	}
+	start, end := calendarDate(req.StartDate), calendarDate(req.EndDate)
+	if end.Before(start) {
+		return errors.New("endDate is before startDate")
+	}
+	if end.Sub(start) >= attendanceMaxDays*24*time.Hour {
+		return fmt.Errorf("a pay period is at most %d days", attendanceMaxDays)
+	}
+	return nil
+}
+
+// PayPeriodGet returns the pay period with the id within scope, which may be
+// nil.
+func (m *ModelResource) PayPeriodGet(scope managers.ScopeFunc, id uint) (*PayPeriod, error) {
+	query := m.db.Client.Where("id = ?", id)
+	if scope != nil {
+		query = query.Scopes(scope)
+	}
+	var periods []*PayPeriod
+	if err := query.Limit(1).Find(&periods).Error; err != nil {
+		return nil, err
+	}
+	if len(periods) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return periods[0], nil
+}
+
+// PayPeriodSetLocked locks or reopens the pay period.
+func (m *ModelResource) PayPeriodSetLocked(period *PayPeriod, locked bool) error {
+	var lockedAt *time.Time
+	if locked {
+		now := time.Now()
+		lockedAt = &now
+	}
+	return m.db.Client.Model(period).Update("locked_at", lockedAt).Error
+}
+
+// TimesheetLocked reports whether a timesheet of the employee dated on the
+// calendar date of t, in the time zone of their company, is in a locked pay
+// period.
+func (m *ModelResource) TimesheetLocked(employeeId uint, t time.Time) (bool, error) {
+	employee, err := m.EmployeeDB.FindByID(employeeId)
+	if err != nil {
+		return false, err
+	}
+	company, err := m.employeeCompany(employee)
+	if err != nil || company == nil {
+		return false, err
+	}
+	date := calendarDate(t.In(companyLocation(company)))
+	var count int64
+	err = m.db.Client.Model(&PayPeriod{}).
+		Where("company_id = ? AND locked_at IS NOT NULL AND start_date <= ? AND end_date >= ?", company.ID, date, date).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// TimesheetEditable returns ErrPayPeriodLocked when the timesheet is in a
+// locked pay period.
+func (m *ModelResource) TimesheetEditable(timesheet *Timesheet) error {
+	date := timesheet.TimeIn
+	if timesheet.ScheduledIn != nil {
+		date = timesheet.ScheduledIn
+	}
+	if date == nil {
+		return nil
+	}
+	locked, err := m.TimesheetLocked(timesheet.EmployeeID, *date)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return ErrPayPeriodLocked
+	}
+	return nil
+}
+
+// TimesheetSummaries returns the summary of every employee of the company
+// of the pay period. Only closed timesheets count toward hours, and not those
+// still flagged for review, like the ones closed automatically.
+func (m *ModelResource) TimesheetSummaries(period *PayPeriod) ([]*TimesheetSummary, error) {
+	company, err := m.CompanyDB.FindByID(period.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	location := companyLocation(company)
+	var employees []*Employee
+	err = m.db.Client.Where("branch_id IN (SELECT id FROM branches WHERE company_id = ?)", company.ID).
+		Order("last_name, first_name, id").Find(&employees).Error
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*TimesheetSummary, 0, len(employees))
+	for _, employee := range employees {
+		days, err := m.attendanceDays(employee, company, period.StartDate, period.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		summary := &TimesheetSummary{Employee: employee}
+		for _, day := range days {
+			switch day.Status {
+			case AttendanceAbsent:
+				summary.AbsentDays++
+			case AttendanceOnLeave:
+				summary.LeaveDays++
+				if day.Leave.LeaveType != nil && day.Leave.LeaveType.Paid {
+					summary.PaidLeaveDays++
+				}
+			}
+			present := false
+			for _, timesheet := range day.Timesheets {
+				if timesheet.TimeOut == nil {
+					summary.OpenTimesheets++
+					continue
+				}
+				if timesheet.NeedsReview {
+					summary.ReviewTimesheets++
+					continue
+				}
+				present = true
+				summary.add(timesheet, location)
+			}
+			if present {
+				summary.DaysPresent++
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// add counts the minutes of a closed timesheet toward the summary.
+func (s *TimesheetSummary) add(timesheet *Timesheet, location *time.Location) {
+	worked := int(timesheet.TimeOut.Sub(*timesheet.TimeIn) / time.Minute)
+	if timesheet.Shift != nil && worked > timesheet.Shift.BreakMinutes {
+		worked -= timesheet.Shift.BreakMinutes
+	}
+	if worked < 0 {
+		worked = 0
+	}
+
+	s.LateMinutes += timesheet.LateMinutes
+	s.UndertimeMinutes += timesheet.UndertimeMinutes
+	s.NightMinutes += nightMinutes(*timesheet.TimeIn, *timesheet.TimeOut, location)
+	switch timesheet.DayType {
+	case DayTypeRegularHoliday, DayTypeSpecialHoliday:
+		s.HolidayMinutes += worked
+	case DayTypeRestDay:
+		s.OvertimeMinutes += worked
+	default:
+		overtime := min(timesheet.OvertimeMinutes, worked)
+		s.OvertimeMinutes += overtime
+		s.RegularMinutes += worked - overtime
+	}
+}
+
+// nightMinutes returns the minutes from in to out between nightStartHour and
+// nightEndHour in location.
+func nightMinutes(in, out time.Time, location *time.Location) int {
+	in, out = in.In(location), out.In(location)
+	total := time.Duration(0)
+	for day := time.Date(in.Year(), in.Month(), in.Day()-1, 0, 0, 0, 0, location); day.Before(out); day = day.AddDate(0, 0, 1) {
+		nightStart := time.Date(day.Year(), day.Month(), day.Day(), nightStartHour, 0, 0, 0, location)
+		nightEnd := time.Date(day.Year(), day.Month(), day.Day()+1, nightEndHour, 0, 0, 0, location)
+		start, end := maxTime(in, nightStart), minTime(out, nightEnd)
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+	return int(total / time.Minute)
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// minutesToHours converts minutes into hours rounded to hundredths.
+func minutesToHours(minutes int) float64 {
+	return math.Round(float64(minutes)/60*100) / 100
+}
+
+func (m *ModelResource) TimesheetSummaryToResource(summary *TimesheetSummary) *TimesheetSummaryResource {
+	if summary == nil {
+		return nil
+	}
+	return &TimesheetSummaryResource{
+		EmployeeID:       summary.Employee.ID,
+		EmployeeName:     fmt.Sprintf("%s %s", summary.Employee.FirstName, summary.Employee.LastName),
+		DaysPresent:      summary.DaysPresent,
+		AbsentDays:       summary.AbsentDays,
+		LeaveDays:        summary.LeaveDays,
+		PaidLeaveDays:    summary.PaidLeaveDays,
+		OpenTimesheets:   summary.OpenTimesheets,
+		ReviewTimesheets: summary.ReviewTimesheets,
+		LateMinutes:      summary.LateMinutes,
+		UndertimeMinutes: summary.UndertimeMinutes,
+		RegularHours:     minutesToHours(summary.RegularMinutes),
+		OvertimeHours:    minutesToHours(summary.OvertimeMinutes),
+		NightHours:       minutesToHours(summary.NightMinutes),
+		HolidayHours:     minutesToHours(summary.HolidayMinutes),
+	}
+}
+
+func (m *ModelResource) TimesheetSummaryToResourceList(summaries []*TimesheetSummary) []*TimesheetSummaryResource {
+	if summaries == nil {
+		return nil
+	}
+	var summaryResources []*TimesheetSummaryResource
+	for _, summary := range summaries {
+		summaryResources = append(summaryResources, m.TimesheetSummaryToResource(summary))
+	}
+	return summaryResources
+}
+
+// TimesheetSummaryExportColumns returns the columns of a pay period export.
+func (m *ModelResource) TimesheetSummaryExportColumns() []managers.ExportColumn {
+	return []managers.ExportColumn{
+		{Header: "Employee ID", Width: 12},
+		{Header: "Employee Name", Width: 30},
+		{Header: "Days Present", Width: 14},
+		{Header: "Absent Days", Width: 14},
+		{Header: "Leave Days", Width: 14},
+		{Header: "Paid Leave Days", Width: 16},
+		{Header: "Open Timesheets", Width: 16},
+		{Header: "Timesheets To Review", Width: 20},
+		{Header: "Late Minutes", Width: 14},
+		{Header: "Undertime Minutes", Width: 18},
+		{Header: "Regular Hours", Width: 14},
+		{Header: "Overtime Hours", Width: 14},
+		{Header: "Night Differential Hours", Width: 24},
+		{Header: "Holiday Hours", Width: 14},
+	}
+}
+
+// TimesheetSummaryToExportRow converts a summary into a typed export row
+// matching TimesheetSummaryExportColumns.
+func (m *ModelResource) TimesheetSummaryToExportRow(summary *TimesheetSummary) []interface{} {
+	resource := m.TimesheetSummaryToResource(summary)
+	return []interface{}{
+		resource.EmployeeID,
+		sanitizeCSVField(resource.EmployeeName),
+		resource.DaysPresent,
+		resource.AbsentDays,
+		resource.LeaveDays,
+		resource.PaidLeaveDays,
+		resource.OpenTimesheets,
+		resource.ReviewTimesheets,
+		resource.LateMinutes,
+		resource.UndertimeMinutes,
+		resource.RegularHours,
+		resource.OvertimeHours,
+		resource.NightHours,
+		resource.HolidayHours,
+	}
+}
+
+func (m *ModelResource) PayPeriodSeeders() error {
+	m.logger.Info("Seeding PayPeriod")
+	return nil
+}
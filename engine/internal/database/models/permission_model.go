@@ -0,0 +1,393 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator"
+	"gorm.io/gorm"
+)
+
+// ErrPermissionDenied is returned when no effective permission grants the action.
+var ErrPermissionDenied = errors.New("permission denied")
+
+type PermissionAction string
+
+const (
+	ActionRead   PermissionAction = "read"
+	ActionCreate PermissionAction = "create"
+	ActionUpdate PermissionAction = "update"
+	ActionDelete PermissionAction = "delete"
+)
+
+// PermissionScope limits which rows a permission applies to. Scopes are
+// ordered: a broader scope includes every narrower one.
+type PermissionScope string
+
+const (
+	ScopeOwn     PermissionScope = "own"
+	ScopeBranch  PermissionScope = "branch"
+	ScopeCompany PermissionScope = "company"
+	ScopeAll     PermissionScope = "all"
+)
+
+var scopeRank = map[PermissionScope]int{
+	ScopeOwn:     1,
+	ScopeBranch:  2,
+	ScopeCompany: 3,
+	ScopeAll:     4,
+}
+
+// Includes reports whether scope s is at least as broad as other.
+func (s PermissionScope) Includes(other PermissionScope) bool {
+	return scopeRank[s] >= scopeRank[other]
+}
+
+// Resources guarded by permissions, one per module.
+const (
+	ResourceAdmin     = "admin"
+	ResourceApiKey    = "api_key"
+	ResourceAudit     = "audit"
+	ResourceBranch    = "branch"
+	ResourceCompany   = "company"
+	ResourceContact   = "contact"
+	ResourceEmployee  = "employee"
+	ResourceFeedback  = "feedback"
+	ResourceFootstep  = "footstep"
+	ResourceGender    = "gender"
+	ResourceHoliday   = "holiday"
+	ResourceLeave     = "leave"
+	ResourceLeaveType = "leave_type"
+	ResourceMedia     = "media"
+	ResourceMember    = "member"
+	ResourceOwner     = "owner"
+	ResourcePayroll   = "payroll"
+	ResourceRole      = "role"
+	ResourceSchedule  = "schedule"
+	ResourceShift     = "shift"
+	ResourceTimesheet = "timesheet"
+	// Supervisors are employees with a role that may update timesheet
+	// corrections of their branch
+	ResourceTimesheetCorrection = "timesheet_correction"
+)
+
+var PermissionResources = []string{
+	ResourceAdmin, ResourceApiKey, ResourceAudit, ResourceBranch, ResourceCompany, ResourceContact,
+	ResourceEmployee, ResourceFeedback, ResourceFootstep, ResourceGender, ResourceHoliday, ResourceLeave,
+	ResourceLeaveType, ResourceMedia, ResourceMember, ResourceOwner, ResourcePayroll, ResourceRole, ResourceSchedule,
+	ResourceShift, ResourceTimesheet, ResourceTimesheetCorrection,
+}
+
+type Permission struct {
+	gorm.Model
+
+	// Fields
+	Resource string `gorm:"type:varchar(255);uniqueIndex:idx_role_resource_action" json:"resource"`
+	Action   string `gorm:"type:varchar(255);uniqueIndex:idx_role_resource_action" json:"action"`
+	Scope    string `gorm:"type:varchar(255);default:'own'" json:"scope"`
+
+	// Relationship 1 to many
+	RoleID uint  `gorm:"type:bigint;unsigned;uniqueIndex:idx_role_resource_action" json:"role_id"`
+	Role   *Role `gorm:"foreignKey:RoleID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"role"`
+}
+
+type PermissionResource struct {
+	Resource string `json:"resource"`
+	Action   string `json:"action"`
+	Scope    string `json:"scope"`
+}
+
+type PermissionRequest struct {
+	Resource string `json:"resource" validate:"required,max=255"`
+	Action   string `json:"action" validate:"required,oneof=read create update delete"`
+	Scope    string `json:"scope" validate:"required,oneof=own branch company all"`
+}
+
+type RolePermissionsRequest struct {
+	Permissions []PermissionRequest `json:"permissions" validate:"dive"`
+}
+
+// defaultPermissions apply to users without a role, per account type. Admins
+// are never checked.
+var defaultPermissions = map[string][]PermissionResource{
+	"Owner": {
+		{ResourceApiKey, "read", "company"}, {ResourceApiKey, "create", "company"},
+		{ResourceApiKey, "update", "company"}, {ResourceApiKey, "delete", "company"},
+		{ResourceBranch, "read", "company"}, {ResourceBranch, "create", "company"},
+		{ResourceBranch, "update", "company"}, {ResourceBranch, "delete", "company"},
+		{ResourceCompany, "read", "own"}, {ResourceCompany, "create", "own"}, {ResourceCompany, "update", "own"},
+		{ResourceEmployee, "read", "company"}, {ResourceEmployee, "create", "company"},
+		{ResourceEmployee, "update", "company"}, {ResourceEmployee, "delete", "company"},
+		{ResourceMember, "read", "company"}, {ResourceMember, "create", "company"},
+		{ResourceMember, "update", "company"}, {ResourceMember, "delete", "company"},
+		{ResourceFootstep, "read", "company"},
+		{ResourceTimesheet, "read", "company"},
+		{ResourceTimesheetCorrection, "read", "company"}, {ResourceTimesheetCorrection, "update", "company"},
+		{ResourceShift, "read", "company"}, {ResourceShift, "create", "company"},
+		{ResourceShift, "update", "company"}, {ResourceShift, "delete", "company"},
+		{ResourceSchedule, "read", "company"}, {ResourceSchedule, "create", "company"},
+		{ResourceSchedule, "update", "company"}, {ResourceSchedule, "delete", "company"},
+		{ResourceHoliday, "read", "company"}, {ResourceHoliday, "create", "company"},
+		{ResourceHoliday, "update", "company"}, {ResourceHoliday, "delete", "company"},
+		{ResourcePayroll, "read", "company"}, {ResourcePayroll, "create", "company"},
+		{ResourcePayroll, "update", "company"}, {ResourcePayroll, "delete", "company"},
+		{ResourceLeaveType, "read", "company"}, {ResourceLeaveType, "create", "company"},
+		{ResourceLeaveType, "update", "company"}, {ResourceLeaveType, "delete", "company"},
+		{ResourceLeave, "read", "company"}, {ResourceLeave, "update", "company"},
+		{ResourceGender, "read", "all"},
+		{ResourceRole, "read", "all"},
+		{ResourceOwner, "read", "own"}, {ResourceOwner, "update", "own"},
+		{ResourceMedia, "read", "own"}, {ResourceMedia, "create", "own"},
+		{ResourceMedia, "update", "own"}, {ResourceMedia, "delete", "own"},
+	},
+	"Employee": {
+		{ResourceBranch, "read", "branch"},
+		{ResourceCompany, "read", "company"},
+		{ResourceEmployee, "read", "branch"}, {ResourceEmployee, "update", "own"},
+		{ResourceMember, "read", "branch"}, {ResourceMember, "create", "branch"}, {ResourceMember, "update", "branch"},
+		{ResourceFootstep, "read", "own"}, {ResourceFootstep, "create", "own"},
+		{ResourceTimesheet, "read", "own"}, {ResourceTimesheet, "create", "own"},
+		{ResourceTimesheetCorrection, "read", "own"}, {ResourceTimesheetCorrection, "create", "own"},
+		{ResourceTimesheetCorrection, "delete", "own"},
+		{ResourceShift, "read", "company"},
+		{ResourceSchedule, "read", "own"},
+		{ResourceHoliday, "read", "company"},
+		{ResourceLeaveType, "read", "company"},
+		{ResourceLeave, "read", "own"}, {ResourceLeave, "create", "own"}, {ResourceLeave, "delete", "own"},
+		{ResourceGender, "read", "all"},
+		{ResourceMedia, "read", "own"}, {ResourceMedia, "create", "own"},
+		{ResourceMedia, "update", "own"}, {ResourceMedia, "delete", "own"},
+	},
+	"Member": {
+		{ResourceBranch, "read", "branch"},
+		{ResourceCompany, "read", "company"},
+		{ResourceMember, "read", "own"}, {ResourceMember, "update", "own"},
+		{ResourceGender, "read", "all"},
+		{ResourceMedia, "read", "own"}, {ResourceMedia, "create", "own"},
+		{ResourceMedia, "update", "own"}, {ResourceMedia, "delete", "own"},
+	},
+}
+
+func (m *ModelResource) PermissionToResource(permission *Permission) *PermissionResource {
+	if permission == nil {
+		return nil
+	}
+	return &PermissionResource{
+		Resource: permission.Resource,
+		Action:   permission.Action,
+		Scope:    permission.Scope,
+	}
+}
+
+func (m *ModelResource) PermissionToResourceList(permissions []*Permission) []*PermissionResource {
+	if permissions == nil {
+		return nil
+	}
+	var permissionResources []*PermissionResource
+	for _, permission := range permissions {
+		permissionResources = append(permissionResources, m.PermissionToResource(permission))
+	}
+	return permissionResources
+}
+
+func (m *ModelResource) ValidateRolePermissionsRequest(req *RolePermissionsRequest) error {
+	validate := validator.New()
+	err := validate.Struct(req)
+	if err != nil {
+		return m.helpers.FormatValidationError(err)
+	}
+	for _, permission := range req.Permissions {
+		if !isPermissionResource(permission.Resource) {
+			return fmt.Errorf("invalid resource: %s", permission.Resource)
+		}
+	}
+	return nil
+}
+
+// PermissionReplaceForRole replaces every permission of the role in a single
+// transaction.
+func (m *ModelResource) PermissionReplaceForRole(roleId uint, req *RolePermissionsRequest) ([]*Permission, error) {
+	permissions := make([]*Permission, 0, len(req.Permissions))
+	for _, permission := range req.Permissions {
+		permissions = append(permissions, &Permission{
+			RoleID:   roleId,
+			Resource: permission.Resource,
+			Action:   permission.Action,
+			Scope:    permission.Scope,
+		})
+	}
+	err := m.db.Client.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&Role{}, roleId).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("role_id = ?", roleId).Delete(&Permission{}).Error; err != nil {
+			return err
+		}
+		if len(permissions) == 0 {
+			return nil
+		}
+		return tx.Create(&permissions).Error
+	})
+	return permissions, err
+}
+
+func (m *ModelResource) PermissionGetForRole(roleId uint) ([]*Permission, error) {
+	var permissions []*Permission
+	err := m.db.Client.Where("role_id = ?", roleId).Order("resource, action").Find(&permissions).Error
+	return permissions, err
+}
+
+// PermissionGetEffective returns the permissions of the user's role, or the
+// account type defaults when the user has no role. Admins get every action on
+// every resource with the "all" scope.
+func (m *ModelResource) PermissionGetEffective(accountType string, userId uint) ([]*PermissionResource, error) {
+	if accountType == "Admin" {
+		permissions := []*PermissionResource{}
+		for _, resource := range PermissionResources {
+			for _, action := range []PermissionAction{ActionRead, ActionCreate, ActionUpdate, ActionDelete} {
+				permissions = append(permissions, &PermissionResource{resource, string(action), string(ScopeAll)})
+			}
+		}
+		return permissions, nil
+	}
+
+	if accountType == ApiKeyAccountType {
+		return m.permissionGetForApiKey(userId)
+	}
+
+	table, ok := map[string]string{
+		"Owner":    "owners",
+		"Employee": "employees",
+		"Member":   "members",
+	}[accountType]
+	if !ok {
+		return nil, fmt.Errorf("unknown account type: %s", accountType)
+	}
+
+	var roleId *uint
+	err := m.db.Client.Table(table).Select("role_id").Where("id = ? AND deleted_at IS NULL", userId).Row().Scan(&roleId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPermissionDenied
+	}
+	if err != nil {
+		return nil, err
+	}
+	if roleId == nil {
+		permissions := []*PermissionResource{}
+		for _, permission := range defaultPermissions[accountType] {
+			permission := permission
+			permissions = append(permissions, &permission)
+		}
+		return permissions, nil
+	}
+
+	permissions, err := m.PermissionGetForRole(*roleId)
+	if err != nil {
+		return nil, err
+	}
+	return m.PermissionToResourceList(permissions), nil
+}
+
+// permissionGetForApiKey returns the permissions of the role of an active
+// API key. Keys never reach beyond their company, so broader scopes are
+// narrowed to it, nor beyond their creator, so each permission is narrowed to
+// the scope the creator still has for it and dropped when there is none.
+func (m *ModelResource) permissionGetForApiKey(apiKeyId uint) ([]*PermissionResource, error) {
+	var apiKeys []*ApiKey
+	err := m.db.Client.Scopes(apiKeyActive).Where("id = ?", apiKeyId).Limit(1).Find(&apiKeys).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(apiKeys) == 0 {
+		return nil, ErrPermissionDenied
+	}
+	creatorPermissions, err := m.permissionGetForCreator(apiKeys[0].CreatorAccountType, apiKeys[0].CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := m.PermissionGetForRole(apiKeys[0].RoleID)
+	if err != nil {
+		return nil, err
+	}
+	resources := []*PermissionResource{}
+	for _, permission := range apiKeyNarrow(m.PermissionToResourceList(permissions)) {
+		limit := permissionScopeFor(creatorPermissions, permission.Resource, permission.Action)
+		if limit == "" {
+			continue
+		}
+		if !limit.Includes(PermissionScope(permission.Scope)) {
+			permission.Scope = string(limit)
+		}
+		resources = append(resources, permission)
+	}
+	return resources, nil
+}
+
+// permissionGetForCreator returns the effective permissions of the creator
+// of an API key. Keys cannot create keys, so a key never caps another.
+func (m *ModelResource) permissionGetForCreator(accountType string, userId uint) ([]*PermissionResource, error) {
+	if accountType == "" || accountType == ApiKeyAccountType {
+		return nil, ErrPermissionDenied
+	}
+	return m.PermissionGetEffective(accountType, userId)
+}
+
+// apiKeyNarrow narrows the "all" scope of permissions to the company of the
+// key.
+func apiKeyNarrow(permissions []*PermissionResource) []*PermissionResource {
+	for _, permission := range permissions {
+		if PermissionScope(permission.Scope) == ScopeAll {
+			permission.Scope = string(ScopeCompany)
+		}
+	}
+	return permissions
+}
+
+// permissionScopeFor returns the broadest scope permissions grant for the
+// action on the resource, or "" when they grant none.
+func permissionScopeFor(permissions []*PermissionResource, resource, action string) PermissionScope {
+	var granted PermissionScope
+	for _, permission := range permissions {
+		if permission.Resource != resource || permission.Action != action {
+			continue
+		}
+		if scope := PermissionScope(permission.Scope); scopeRank[scope] > scopeRank[granted] {
+			granted = scope
+		}
+	}
+	return granted
+}
+
+// PermissionCheck returns the scope granted to the user for the action on the
+// resource, or ErrPermissionDenied.
+func (m *ModelResource) PermissionCheck(accountType string, userId uint, resource string, action PermissionAction) (PermissionScope, error) {
+	permissions, err := m.PermissionGetEffective(accountType, userId)
+	if err != nil {
+		return "", err
+	}
+	granted := permissionScopeFor(permissions, resource, string(action))
+	if granted == "" {
+		return "", ErrPermissionDenied
+	}
+	return granted, nil
+}
+
+func isPermissionResource(resource string) bool {
+	for _, r := range PermissionResources {
+		if r == resource {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *ModelResource) PermissionSeeders() error {
+	m.logger.Info("Seeding Permission")
+	return nil
+}
+
+// permissionLabel formats a permission as resource:action:scope for exports.
+func permissionLabel(permission *PermissionResource) string {
+	return fmt.Sprintf("%s:%s:%s", permission.Resource, permission.Action, permission.Scope)
+}
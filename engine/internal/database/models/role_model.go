@@ -18,20 +18,12 @@ type Role struct {
 	ApiKey      string `gorm:"type:varchar(255);unique;unsigned" json:"api_key"`
 	Color       string `gorm:"type:varchar(255)" json:"color"`
 
-	ReadRole   bool `gorm:"default:false" json:"read_role"`
-	WriteRole  bool `gorm:"default:false" json:"write_role"`
-	UpdateRole bool `gorm:"default:false" json:"update_role"`
-	DeleteRole bool `gorm:"default:false" json:"delete_role"`
+	// RequireTwoFactor makes holders of the role complete two-factor
+	// authentication on every sign-in
+	RequireTwoFactor bool `gorm:"default:false" json:"require_two_factor"`
 
-	ReadErrorDetails   bool `gorm:"default:false" json:"read_error_details"`
-	WriteErrorDetails  bool `gorm:"default:false" json:"write_error_details"`
-	UpdateErrorDetails bool `gorm:"default:false" json:"update_error_details"`
-	DeleteErrorDetails bool `gorm:"default:false" json:"delete_error_details"`
-
-	ReadGender   bool `gorm:"default:false" json:"read_gender"`
-	WriteGender  bool `gorm:"default:false" json:"write_gender"`
-	UpdateGender bool `gorm:"default:false" json:"update_gender"`
-	DeleteGender bool `gorm:"default:false" json:"delete_gender"`
+	// Relationship 0 to many
+	Permissions []*Permission `gorm:"foreignKey:RoleID" json:"permissions"`
 
 	// Relationship 0 to many
 	Admins    []*Admin    `gorm:"foreignKey:RoleID" json:"admins"`
@@ -45,22 +37,12 @@ type RoleResource struct {
 	CreatedAt string `json:"createdAt"`
 	UpdatedAt string `json:"updatedAt"`
 
-	Name               string `json:"name"`
-	Description        string `json:"description"`
-	ApiKey             string `json:"apiKey"`
-	Color              string `json:"color"`
-	ReadRole           bool   `json:"readRole"`
-	WriteRole          bool   `json:"writeRole"`
-	UpdateRole         bool   `json:"updateRole"`
-	DeleteRole         bool   `json:"deleteRole"`
-	ReadErrorDetails   bool   `json:"readErrorDetails"`
-	WriteErrorDetails  bool   `json:"writeErrorDetails"`
-	UpdateErrorDetails bool   `json:"updateErrorDetails"`
-	DeleteErrorDetails bool   `json:"deleteErrorDetails"`
-	ReadGender         bool   `json:"readGender"`
-	WriteGender        bool   `json:"writeGender"`
-	UpdateGender       bool   `json:"updateGender"`
-	DeleteGender       bool   `json:"deleteGender"`
+	Name             string                `json:"name"`
+	Description      string                `json:"description"`
+	ApiKey           string                `json:"apiKey"`
+	Color            string                `json:"color"`
+	RequireTwoFactor bool                  `json:"requireTwoFactor"`
+	Permissions      []*PermissionResource `json:"permissions"`
 
 	Admins    []*AdminResource    `json:"admins"`
 	Owners    []*OwnerResource    `json:"owners"`
@@ -68,23 +50,28 @@ type RoleResource struct {
 	Members   []*MemberResource   `json:"members"`
 }
 
+type RoleTwoFactorRequest struct {
+	Required bool `json:"required"`
+}
+
 type RoleRequest struct {
-	Name               string `json:"name" validate:"required,max=255"`
-	Description        string `json:"description,omitempty" validate:"max=1000"`
-	ApiKey             string `json:"apiKey" validate:"required,max=255"`
-	Color              string `json:"color,omitempty" validate:"max=255"`
-	ReadRole           bool   `json:"readRole"`
-	WriteRole          bool   `json:"writeRole"`
-	UpdateRole         bool   `json:"updateRole"`
-	DeleteRole         bool   `json:"deleteRole"`
-	ReadErrorDetails   bool   `json:"readErrorDetails"`
-	WriteErrorDetails  bool   `json:"writeErrorDetails"`
-	UpdateErrorDetails bool   `json:"updateErrorDetails"`
-	DeleteErrorDetails bool   `json:"deleteErrorDetails"`
-	ReadGender         bool   `json:"readGender"`
-	WriteGender        bool   `json:"writeGender"`
-	UpdateGender       bool   `json:"updateGender"`
-	DeleteGender       bool   `json:"deleteGender"`
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description,omitempty" validate:"max=1000"`
+	ApiKey      string `json:"apiKey" validate:"required,max=255"`
+	Color       string `json:"color,omitempty" validate:"max=255"`
+}
+
+// RoleAssignRequest sets the role of an owner, employee or member.
+type RoleAssignRequest struct {
+	RoleID uint `json:"roleID" validate:"required"`
+}
+
+func (m *ModelResource) ValidateRoleAssignRequest(req *RoleAssignRequest) error {
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		return m.helpers.FormatValidationError(err)
+	}
+	return nil
 }
 
 // RoleToResource implements Models.
@@ -98,26 +85,16 @@ func (m *ModelResource) RoleToResource(role *Role) *RoleResource {
 		CreatedAt: role.CreatedAt.Format(time.RFC3339),
 		UpdatedAt: role.UpdatedAt.Format(time.RFC3339),
 
-		Name:               role.Name,
-		Description:        role.Description,
-		ApiKey:             role.ApiKey,
-		Color:              role.Color,
-		ReadRole:           role.ReadRole,
-		WriteRole:          role.WriteRole,
-		UpdateRole:         role.UpdateRole,
-		DeleteRole:         role.DeleteRole,
-		ReadErrorDetails:   role.ReadErrorDetails,
-		WriteErrorDetails:  role.WriteErrorDetails,
-		UpdateErrorDetails: role.UpdateErrorDetails,
-		DeleteErrorDetails: role.DeleteErrorDetails,
-		ReadGender:         role.ReadGender,
-		WriteGender:        role.WriteGender,
-		UpdateGender:       role.UpdateGender,
-		DeleteGender:       role.DeleteGender,
-		Admins:             m.AdminToResourceList(role.Admins),
-		Owners:             m.OwnerToResourceList(role.Owners),
-		Employees:          m.EmployeeToResourceList(role.Employees),
-		Members:            m.MemberToResourceList(role.Members),
+		Name:             role.Name,
+		Description:      role.Description,
+		ApiKey:           role.ApiKey,
+		Color:            role.Color,
+		RequireTwoFactor: role.RequireTwoFactor,
+		Permissions:      m.PermissionToResourceList(role.Permissions),
+		Admins:           m.AdminToResourceList(role.Admins),
+		Owners:           m.OwnerToResourceList(role.Owners),
+		Employees:        m.EmployeeToResourceList(role.Employees),
+		Members:          m.MemberToResourceList(role.Members),
 	}
 }
 
@@ -145,23 +122,21 @@ func (m *ModelResource) RoleToRecord(roles []*Role) ([][]string, []string) {
 		description := sanitizeCSVField(role.Description)
 		apiKey := sanitizeCSVField(role.ApiKey)
 		color := sanitizeCSVField(role.Color)
-		readRole := strconv.FormatBool(role.ReadRole)
-		writeRole := strconv.FormatBool(role.WriteRole)
-		updateRole := strconv.FormatBool(role.UpdateRole)
-		deleteRole := strconv.FormatBool(role.DeleteRole)
-		readErrorDetails := strconv.FormatBool(role.ReadErrorDetails)
-		writeErrorDetails := strconv.FormatBool(role.WriteErrorDetails)
-		updateErrorDetails := strconv.FormatBool(role.UpdateErrorDetails)
-		deleteErrorDetails := strconv.FormatBool(role.DeleteErrorDetails)
-		readGender := strconv.FormatBool(role.ReadGender)
-		writeGender := strconv.FormatBool(role.WriteGender)
-		updateGender := strconv.FormatBool(role.UpdateGender)
-		deleteGender := strconv.FormatBool(role.DeleteGender)
 		createdAt := sanitizeCSVField(role.CreatedAt)
 		updatedAt := sanitizeCSVField(role.UpdatedAt)
 
 		// Handle Related Entities
 
+		// Permissions
+		permissions := "N/A"
+		if len(role.Permissions) > 0 {
+			permissionLabels := make([]string, 0, len(role.Permissions))
+			for _, permission := range role.Permissions {
+				permissionLabels = append(permissionLabels, sanitizeCSVField(permissionLabel(permission)))
+			}
+			permissions = strings.Join(permissionLabels, "; ")
+		}
+
 		// Admins
 		admins := "N/A"
 		if len(role.Admins) > 0 {
@@ -213,18 +188,7 @@ func (m *ModelResource) RoleToRecord(roles []*Role) ([][]string, []string) {
 			description,
 			apiKey,
 			color,
-			readRole,
-			writeRole,
-			updateRole,
-			deleteRole,
-			readErrorDetails,
-			writeErrorDetails,
-			updateErrorDetails,
-			deleteErrorDetails,
-			readGender,
-			writeGender,
-			updateGender,
-			deleteGender,
+			permissions,
 			createdAt,
 			updatedAt,
 			admins,
@@ -240,18 +204,7 @@ func (m *ModelResource) RoleToRecord(roles []*Role) ([][]string, []string) {
 		"Description",
 		"API Key",
 		"Color",
-		"Read Role",
-		"Write Role",
-		"Update Role",
-		"Delete Role",
-		"Read Error Details",
-		"Write Error Details",
-		"Update Error Details",
-		"Delete Error Details",
-		"Read Gender",
-		"Write Gender",
-		"Update Gender",
-		"Delete Gender",
+		"Permissions",
 		"Created At",
 		"Updated At",
 		"Admins",
@@ -263,6 +216,45 @@ func (m *ModelResource) RoleToRecord(roles []*Role) ([][]string, []string) {
 	return records, headers
 }
 
+// RoleHeldOutsideOwner reports whether the role is assigned to anyone an
+// owner cannot manage: an admin, another owner, or staff outside the owner's
+// companies.
+func (m *ModelResource) RoleHeldOutsideOwner(roleId uint, ownerId uint) (bool, error) {
+	ownerBranches := m.db.Client.Table("branches").Select("branches.id").
+		Joins("JOIN companies ON companies.id = branches.company_id").
+		Where("companies.owner_id = ?", ownerId)
+
+	checks := []*gorm.DB{
+		m.db.Client.Model(&Admin{}).Where("role_id = ?", roleId),
+		m.db.Client.Model(&Owner{}).Where("role_id = ? AND id <> ?", roleId, ownerId),
+		m.db.Client.Model(&Employee{}).Where("role_id = ? AND (branch_id IS NULL OR branch_id NOT IN (?))", roleId, ownerBranches),
+		m.db.Client.Model(&Member{}).Where("role_id = ? AND (branch_id IS NULL OR branch_id NOT IN (?))", roleId, ownerBranches),
+	}
+	for _, check := range checks {
+		var count int64
+		if err := check.Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RoleSetRequireTwoFactor sets whether holders of the role must use
+// two-factor authentication.
+func (m *ModelResource) RoleSetRequireTwoFactor(roleId uint, required bool) (*Role, error) {
+	role, err := m.RoleDB.FindByID(roleId)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.db.Client.Model(role).Update("require_two_factor", required).Error; err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
 func (m *ModelResource) ValidateRoleRequest(req *RoleRequest) error {
 	validate := validator.New()
 	err := validate.Struct(req)
@@ -0,0 +1,717 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
+	"github.com/go-playground/validator"
+	"gorm.io/gorm"
+)
+
+// Day types of a calendar day of a company.
+const (
+	DayTypeRegular        = "Regular"
+	DayTypeRestDay        = "Rest Day"
+	DayTypeRegularHoliday = "Regular Holiday"
+	DayTypeSpecialHoliday = "Special Holiday"
+)
+
+// Attendance statuses of a timesheet or a scheduled day.
+const (
+	AttendanceOnTime      = "On Time"
+	AttendanceLate        = "Late"
+	AttendanceUndertime   = "Undertime"
+	AttendanceOvertime    = "Overtime"
+	AttendanceAbsent      = "Absent"
+	AttendanceUnscheduled = "Unscheduled"
+)
+
+// shiftTimeLayout is the layout of the start and end times of shifts.
+const shiftTimeLayout = "15:04"
+
+// attendanceMaxDays bounds the days of an attendance report.
+const attendanceMaxDays = 62
+
+var weekdays = map[string]time.Weekday{
+	"Sun": time.Sunday, "Mon": time.Monday, "Tue": time.Tuesday, "Wed": time.Wednesday,
+	"Thu": time.Thursday, "Fri": time.Friday, "Sat": time.Saturday,
+}
+
+// Shift is a template of working hours of a company. A shift that ends at or
+// before its start ends the next day.
+type Shift struct {
+	gorm.Model
+
+	// Fields
+	Name      string `gorm:"type:varchar(255)" json:"name"`
+	StartTime string `gorm:"type:varchar(5)" json:"start_time"`
+	EndTime   string `gorm:"type:varchar(5)" json:"end_time"`
+	// BreakMinutes of the shift are unpaid
+	BreakMinutes int `gorm:"default:0" json:"break_minutes"`
+	// Minutes an employee may be late, leave early or stay past the end of
+	// the shift before it counts
+	LateGraceMinutes      int `gorm:"default:0" json:"late_grace_minutes"`
+	UndertimeGraceMinutes int `gorm:"default:0" json:"undertime_grace_minutes"`
+	OvertimeGraceMinutes  int `gorm:"default:0" json:"overtime_grace_minutes"`
+
+	// Relationship 1 to many
+	CompanyID uint     `gorm:"type:bigint;unsigned;index" json:"company_id"`
+	Company   *Company `gorm:"foreignKey:CompanyID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"company"`
+}
+
+type ShiftResource struct {
+	ID        uint   `json:"id"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+
+	Name                  string `json:"name"`
+	StartTime             string `json:"startTime"`
+	EndTime               string `json:"endTime"`
+	BreakMinutes          int    `json:"breakMinutes"`
+	LateGraceMinutes      int    `json:"lateGraceMinutes"`
+	UndertimeGraceMinutes int    `json:"undertimeGraceMinutes"`
+	OvertimeGraceMinutes  int    `json:"overtimeGraceMinutes"`
+	CompanyID             uint   `json:"companyID"`
+}
+
+type ShiftRequest struct {
+	Name                  string `json:"name" validate:"required,max=255"`
+	StartTime             string `json:"startTime" validate:"required,len=5"`
+	EndTime               string `json:"endTime" validate:"required,len=5"`
+	BreakMinutes          int    `json:"breakMinutes" validate:"min=0,max=720"`
+	LateGraceMinutes      int    `json:"lateGraceMinutes" validate:"min=0,max=720"`
+	UndertimeGraceMinutes int    `json:"undertimeGraceMinutes" validate:"min=0,max=720"`
+	OvertimeGraceMinutes  int    `json:"overtimeGraceMinutes" validate:"min=0,max=720"`
+	CompanyID             uint   `json:"companyID" validate:"required"`
+}
+
+// ShiftAssignment puts an employee, or every employee of a branch, on a shift
+// from EffectiveFrom until EffectiveTo, both calendar dates. The assignment of
+// an employee overrides the one of their branch.
+type ShiftAssignment struct {
+	gorm.Model
+
+	// Fields
+	EffectiveFrom time.Time  `gorm:"type:date;index" json:"effective_from"`
+	EffectiveTo   *time.Time `gorm:"type:date" json:"effective_to"`
+	// RestDays lists, comma separated, the weekdays off (Sun, Mon, ...). The
+	// rest days of the company apply when empty
+	RestDays string `gorm:"type:varchar(32)" json:"rest_days"`
+
+	// Relationship 1 to many
+	ShiftID uint   `gorm:"type:bigint;unsigned;index" json:"shift_id"`
+	Shift   *Shift `gorm:"foreignKey:ShiftID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"shift"`
+
+	// Relationship 0 to 1
+	EmployeeID *uint     `gorm:"type:bigint;unsigned;index" json:"employee_id"`
+	Employee   *Employee `gorm:"foreignKey:EmployeeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"employee"`
+
+	// Relationship 0 to 1
+	BranchID *uint   `gorm:"type:bigint;unsigned;index" json:"branch_id"`
+	Branch   *Branch `gorm:"foreignKey:BranchID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"branch"`
+}
+
+type ShiftAssignmentResource struct {
+	ID        uint   `json:"id"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+
+	EffectiveFrom string            `json:"effectiveFrom"`
+	EffectiveTo   *string           `json:"effectiveTo"`
+	RestDays      []string          `json:"restDays"`
+	ShiftID       uint              `json:"shiftID"`
+	Shift         *ShiftResource    `json:"shift"`
+	EmployeeID    *uint             `json:"employeeID"`
+	Employee      *EmployeeResource `json:"employee"`
+	BranchID      *uint             `json:"branchID"`
+	Branch        *BranchResource   `json:"branch"`
+}
+
+type ShiftAssignmentRequest struct {
+	EffectiveFrom time.Time  `json:"effectiveFrom" validate:"required"`
+	EffectiveTo   *time.Time `json:"effectiveTo,omitempty"`
+	RestDays      string     `json:"restDays,omitempty" validate:"max=32"`
+	ShiftID       uint       `json:"shiftID" validate:"required"`
+	EmployeeID    *uint      `json:"employeeID,omitempty" validate:"required_without=BranchID"`
+	BranchID      *uint      `json:"branchID,omitempty"`
+}
+
+// Holiday is a day off of every employee of a company. Working on a holiday
+// counts as overtime.
+type Holiday struct {
+	gorm.Model
+
+	// Fields
+	Date time.Time `gorm:"type:date;index" json:"date"`
+	Name string    `gorm:"type:varchar(255)" json:"name"`
+	// Kind is Regular or Special
+	Kind string `gorm:"type:varchar(16);default:'Regular'" json:"kind"`
+
+	// Relationship 1 to many
+	CompanyID uint     `gorm:"type:bigint;unsigned;index" json:"company_id"`
+	Company   *Company `gorm:"foreignKey:CompanyID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"company"`
+}
+
+type HolidayResource struct {
+	ID        uint   `json:"id"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+
+	Date      string `json:"date"`
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	CompanyID uint   `json:"companyID"`
+}
+
+type HolidayRequest struct {
+	Date      time.Time `json:"date" validate:"required"`
+	Name      string    `json:"name" validate:"required,max=255"`
+	Kind      string    `json:"kind" validate:"required,oneof=Regular Special"`
+	CompanyID uint      `json:"companyID" validate:"required"`
+}
+
+// DaySchedule is what is expected of an employee on a calendar day. Only
+// working days have a shift.
+type DaySchedule struct {
+	Date         time.Time
+	DayType      string
+	Shift        *Shift
+	ScheduledIn  *time.Time
+	ScheduledOut *time.Time
+}
+
+// Working reports whether the employee is expected to work on the day.
+func (s *DaySchedule) Working() bool {
+	return s.ScheduledIn != nil
+}
+
+// Attendance is the attendance of a timesheet against its schedule.
+type Attendance struct {
+	Status           string
+	LateMinutes      int
+	UndertimeMinutes int
+	OvertimeMinutes  int
+}
+
+type AttendanceDayResource struct {
+	Date         string               `json:"date"`
+	DayType      string               `json:"dayType"`
+	ShiftID      *uint                `json:"shiftID"`
+	ScheduledIn  *string              `json:"scheduledIn"`
+	ScheduledOut *string              `json:"scheduledOut"`
+	Status       string               `json:"status"`
+	LeaveID      *uint                `json:"leaveID"`
+	Timesheets   []*TimesheetResource `json:"timesheets"`
+}
+
+func (m *ModelResource) ShiftToResource(shift *Shift) *ShiftResource {
+	if shift == nil {
+		return nil
+	}
+	return &ShiftResource{
+		ID:        shift.ID,
+		CreatedAt: shift.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: shift.UpdatedAt.Format(time.RFC3339),
+
+		Name:                  shift.Name,
+		StartTime:             shift.StartTime,
+		EndTime:               shift.EndTime,
+		BreakMinutes:          shift.BreakMinutes,
+		LateGraceMinutes:      shift.LateGraceMinutes,
+		UndertimeGraceMinutes: shift.UndertimeGraceMinutes,
+		OvertimeGraceMinutes:  shift.OvertimeGraceMinutes,
+		CompanyID:             shift.CompanyID,
+	}
+}
+
+func (m *ModelResource) ShiftToResourceList(shifts []*Shift) []*ShiftResource {
+	if shifts == nil {
+		return nil
+	}
+	var shiftResources []*ShiftResource
+	for _, shift := range shifts {
+		shiftResources = append(shiftResources, m.ShiftToResource(shift))
+	}
+	return shiftResources
+}
+
+func (m *ModelResource) ValidateShiftRequest(req *ShiftRequest) error {
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		return m.helpers.FormatValidationError(err)
+	}
+	for _, clock := range []string{req.StartTime, req.EndTime} {
+		if _, err := time.Parse(shiftTimeLayout, clock); err != nil {
+			return fmt.Errorf("invalid shift time %q, expected HH:MM", clock)
+		}
+	}
+	return nil
+}
+
+func (m *ModelResource) ShiftAssignmentToResource(assignment *ShiftAssignment) *ShiftAssignmentResource {
+	if assignment == nil {
+		return nil
+	}
+	var effectiveTo *string
+	if assignment.EffectiveTo != nil {
+		date := formatDate(*assignment.EffectiveTo)
+		effectiveTo = &date
+	}
+	return &ShiftAssignmentResource{
+		ID:        assignment.ID,
+		CreatedAt: assignment.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: assignment.UpdatedAt.Format(time.RFC3339),
+
+		EffectiveFrom: formatDate(assignment.EffectiveFrom),
+		EffectiveTo:   effectiveTo,
+		RestDays:      splitList(assignment.RestDays),
+		ShiftID:       assignment.ShiftID,
+		Shift:         m.ShiftToResource(assignment.Shift),
+		EmployeeID:    assignment.EmployeeID,
+		Employee:      m.EmployeeToResource(assignment.Employee),
+		BranchID:      assignment.BranchID,
+		Branch:        m.BranchToResource(assignment.Branch),
+	}
+}
+
+func (m *ModelResource) ShiftAssignmentToResourceList(assignments []*ShiftAssignment) []*ShiftAssignmentResource {
+	if assignments == nil {
+		return nil
+	}
+	var assignmentResources []*ShiftAssignmentResource
+	for _, assignment := range assignments {
+		assignmentResources = append(assignmentResources, m.ShiftAssignmentToResource(assignment))
+	}
+	return assignmentResources
+}
+
+func (m *ModelResource) ValidateShiftAssignmentRequest(req *ShiftAssignmentRequest) error {
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		return m.helpers.FormatValidationError(err)
+	}
+	if req.EmployeeID != nil && req.BranchID != nil {
+		return errors.New("assign the shift to an employee or a branch, not both")
+	}
+	if req.EffectiveTo != nil && req.EffectiveTo.Before(req.EffectiveFrom) {
+		return errors.New("effectiveTo is before effectiveFrom")
+	}
+	return validateRestDays(req.RestDays)
+}
+
+func (m *ModelResource) HolidayToResource(holiday *Holiday) *HolidayResource {
+	if holiday == nil {
+		return nil
+	}
+	return &HolidayResource{
+		ID:        holiday.ID,
+		CreatedAt: holiday.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: holiday.UpdatedAt.Format(time.RFC3339),
+
+		Date:      formatDate(holiday.Date),
+		Name:      holiday.Name,
+		Kind:      holiday.Kind,
+		CompanyID: holiday.CompanyID,
+	}
+}
+
+func (m *ModelResource) HolidayToResourceList(holidays []*Holiday) []*HolidayResource {
+	if holidays == nil {
+		return nil
+	}
+	var holidayResources []*HolidayResource
+	for _, holiday := range holidays {
+		holidayResources = append(holidayResources, m.HolidayToResource(holiday))
+	}
+	return holidayResources
+}
+
+func (m *ModelResource) ValidateHolidayRequest(req *HolidayRequest) error {
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		return m.helpers.FormatValidationError(err)
+	}
+	return nil
+}
+
+// validateRestDays checks a comma separated list of weekdays.
+func validateRestDays(restDays string) error {
+	for _, day := range splitList(restDays) {
+		if _, ok := weekdays[day]; !ok {
+			return fmt.Errorf("invalid rest day %q, expected one of Sun, Mon, Tue, Wed, Thu, Fri, Sat", day)
+		}
+	}
+	return nil
+}
+
+// formatDate formats a calendar date, stored as midnight UTC.
+func formatDate(date time.Time) string {
+	return date.UTC().Format(time.DateOnly)
+}
+
+// calendarDate returns the calendar date of t as stored in date columns.
+func calendarDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// companyLocation returns the time zone of the company, UTC when it has none
+// or an unknown one.
+func companyLocation(company *Company) *time.Location {
+	if company == nil || company.Timezone == "" {
+		return time.UTC
+	}
+	location, err := time.LoadLocation(company.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// employeeCompany returns the company of the branch of the employee, nil
+// when the employee has no branch.
+func (m *ModelResource) employeeCompany(employee *Employee) (*Company, error) {
+	if employee.BranchID == nil {
+		return nil, nil
+	}
+	var companies []*Company
+	err := m.db.Client.Where("id = (SELECT company_id FROM branches WHERE id = ?)", *employee.BranchID).Limit(1).Find(&companies).Error
+	if err != nil || len(companies) == 0 {
+		return nil, err
+	}
+	return companies[0], nil
+}
+
+// shiftAssignmentFor returns the shift assignment of the employee in effect
+// on the date, the one of the employee before the one of their branch.
+func (m *ModelResource) shiftAssignmentFor(employee *Employee, date time.Time) (*ShiftAssignment, error) {
+	date = calendarDate(date)
+	query := m.db.Client.Preload("Shift").
+		Where("effective_from <= ? AND (effective_to IS NULL OR effective_to >= ?)", date, date)
+	if employee.BranchID != nil {
+		query = query.Where("(employee_id = ? OR (employee_id IS NULL AND branch_id = ?))", employee.ID, *employee.BranchID)
+	} else {
+		query = query.Where("employee_id = ?", employee.ID)
+	}
+	var assignments []*ShiftAssignment
+	err := query.Order("employee_id IS NULL, effective_from DESC, id DESC").Limit(1).Find(&assignments).Error
+	if err != nil || len(assignments) == 0 {
+		return nil, err
+	}
+	return assignments[0], nil
+}
+
+// holidayOn returns the holiday of the company on the date, if any.
+func (m *ModelResource) holidayOn(company *Company, date time.Time) (*Holiday, error) {
+	if company == nil {
+		return nil, nil
+	}
+	var holidays []*Holiday
+	err := m.db.Client.Where("company_id = ? AND date = ?", company.ID, calendarDate(date)).Limit(1).Find(&holidays).Error
+	if err != nil || len(holidays) == 0 {
+		return nil, err
+	}
+	return holidays[0], nil
+}
+
+// scheduleOn returns the schedule of the employee of the company on the
+// calendar date, in the time zone of the company.
+func (m *ModelResource) scheduleOn(employee *Employee, company *Company, date time.Time) (*DaySchedule, error) {
+	schedule := &DaySchedule{Date: calendarDate(date), DayType: DayTypeRegular}
+	holiday, err := m.holidayOn(company, date)
+	if err != nil {
+		return nil, err
+	}
+	assignment, err := m.shiftAssignmentFor(employee, date)
+	if err != nil {
+		return nil, err
+	}
+
+	restDays := ""
+	if company != nil {
+		restDays = company.RestDays
+	}
+	if assignment != nil && assignment.RestDays != "" {
+		restDays = assignment.RestDays
+	}
+	for _, day := range splitList(restDays) {
+		if weekdays[day] == date.Weekday() {
+			schedule.DayType = DayTypeRestDay
+		}
+	}
+	if holiday != nil {
+		schedule.DayType = DayTypeRegularHoliday
+		if holiday.Kind == "Special" {
+			schedule.DayType = DayTypeSpecialHoliday
+		}
+	}
+
+	if assignment == nil || assignment.Shift == nil || schedule.DayType != DayTypeRegular {
+		return schedule, nil
+	}
+	schedule.Shift = assignment.Shift
+	start, startErr := time.Parse(shiftTimeLayout, assignment.Shift.StartTime)
+	end, endErr := time.Parse(shiftTimeLayout, assignment.Shift.EndTime)
+	if startErr != nil || endErr != nil {
+		return nil, fmt.Errorf("shift %d has an invalid time", assignment.Shift.ID)
+	}
+	location := companyLocation(company)
+	scheduledIn := time.Date(date.Year(), date.Month(), date.Day(), start.Hour(), start.Minute(), 0, 0, location)
+	scheduledOut := time.Date(date.Year(), date.Month(), date.Day(), end.Hour(), end.Minute(), 0, 0, location)
+	if !scheduledOut.After(scheduledIn) {
+		scheduledOut = scheduledOut.AddDate(0, 0, 1)
+	}
+	schedule.ScheduledIn = &scheduledIn
+	schedule.ScheduledOut = &scheduledOut
+	return schedule, nil
+}
+
+// ScheduleForTimeIn returns the schedule a time-in of the employee belongs
+// to: the shift of the previous day while it has not ended, so that
+// overnight shifts are kept together, or else the day of the time-in.
+func (m *ModelResource) ScheduleForTimeIn(employeeId uint, timeIn time.Time) (*DaySchedule, error) {
+	employee, err := m.EmployeeDB.FindByID(employeeId)
+	if err != nil {
+		return nil, err
+	}
+	company, err := m.employeeCompany(employee)
+	if err != nil {
+		return nil, err
+	}
+	local := timeIn.In(companyLocation(company))
+	previous, err := m.scheduleOn(employee, company, local.AddDate(0, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+	if previous.Working() && local.Before(*previous.ScheduledOut) {
+		return previous, nil
+	}
+	return m.scheduleOn(employee, company, local)
+}
+
+// ComputeAttendance returns the attendance of a timesheet that started at
+// timeIn and, once closed, ended at timeOut. Minutes within the grace periods
+// of the shift do not count; past them, every minute counts. Any work on a day
+// off is overtime.
+func ComputeAttendance(schedule *DaySchedule, timeIn time.Time, timeOut *time.Time) Attendance {
+	if !schedule.Working() {
+		if schedule.DayType == DayTypeRegular {
+			return Attendance{Status: AttendanceUnscheduled}
+		}
+		attendance := Attendance{Status: AttendanceOvertime}
+		if timeOut != nil && timeOut.After(timeIn) {
+			attendance.OvertimeMinutes = int(timeOut.Sub(timeIn) / time.Minute)
+		}
+		return attendance
+	}
+
+	shift := schedule.Shift
+	attendance := Attendance{}
+	if late := int(timeIn.Sub(*schedule.ScheduledIn) / time.Minute); late > shift.LateGraceMinutes {
+		attendance.LateMinutes = late
+	}
+	if timeOut != nil {
+		if undertime := int(schedule.ScheduledOut.Sub(*timeOut) / time.Minute); undertime > shift.UndertimeGraceMinutes {
+			attendance.UndertimeMinutes = undertime
+		}
+		if overtime := int(timeOut.Sub(*schedule.ScheduledOut) / time.Minute); overtime > shift.OvertimeGraceMinutes {
+			attendance.OvertimeMinutes = overtime
+		}
+	}
+
+	switch {
+	case attendance.LateMinutes > 0:
+		attendance.Status = AttendanceLate
+	case attendance.UndertimeMinutes > 0:
+		attendance.Status = AttendanceUndertime
+	case attendance.OvertimeMinutes > 0:
+		attendance.Status = AttendanceOvertime
+	default:
+		attendance.Status = AttendanceOnTime
+	}
+	return attendance
+}
+
+// TimesheetApplyAttendance sets the schedule and attendance of the timesheet
+// from its time-in and time-out.
+func (m *ModelResource) TimesheetApplyAttendance(timesheet *Timesheet) error {
+	if timesheet.TimeIn == nil {
+		return nil
+	}
+	schedule, err := m.ScheduleForTimeIn(timesheet.EmployeeID, *timesheet.TimeIn)
+	if err != nil {
+		return err
+	}
+	attendance := ComputeAttendance(schedule, *timesheet.TimeIn, timesheet.TimeOut)
+
+	timesheet.ShiftID = nil
+	if schedule.Shift != nil {
+		timesheet.ShiftID = &schedule.Shift.ID
+	}
+	timesheet.ScheduledIn = schedule.ScheduledIn
+	timesheet.ScheduledOut = schedule.ScheduledOut
+	timesheet.DayType = schedule.DayType
+	timesheet.AttendanceStatus = attendance.Status
+	timesheet.LateMinutes = attendance.LateMinutes
+	timesheet.UndertimeMinutes = attendance.UndertimeMinutes
+	timesheet.OvertimeMinutes = attendance.OvertimeMinutes
+	return nil
+}
+
+// AttendanceDay is the schedule of an employee on a calendar date and the
+// timesheets that belong to it. Status is empty for working days that have
+// not ended yet.
+type AttendanceDay struct {
+	Schedule   *DaySchedule
+	Timesheets []*Timesheet
+	Leave      *LeaveRequest
+	Status     string
+}
+
+func (m *ModelResource) AttendanceDayToResource(day *AttendanceDay) *AttendanceDayResource {
+	if day == nil {
+		return nil
+	}
+	resource := &AttendanceDayResource{
+		Date:         formatDate(day.Schedule.Date),
+		DayType:      day.Schedule.DayType,
+		ScheduledIn:  formatOptionalTime(day.Schedule.ScheduledIn),
+		ScheduledOut: formatOptionalTime(day.Schedule.ScheduledOut),
+		Status:       day.Status,
+		Timesheets:   []*TimesheetResource{},
+	}
+	if day.Schedule.Shift != nil {
+		resource.ShiftID = &day.Schedule.Shift.ID
+	}
+	if day.Leave != nil {
+		resource.LeaveID = &day.Leave.ID
+	}
+	if len(day.Timesheets) > 0 {
+		resource.Timesheets = m.TimesheetToResourceList(day.Timesheets)
+	}
+	return resource
+}
+
+// AttendanceForEmployee returns the attendance of the employee on every
+// calendar date from from to to, in the time zone of their company.
+func (m *ModelResource) AttendanceForEmployee(employeeId uint, from, to time.Time) ([]*AttendanceDayResource, error) {
+	employee, err := m.EmployeeDB.FindByID(employeeId)
+	if err != nil {
+		return nil, err
+	}
+	company, err := m.employeeCompany(employee)
+	if err != nil {
+		return nil, err
+	}
+	days, err := m.attendanceDays(employee, company, from, to)
+	if err != nil {
+		return nil, err
+	}
+	resources := []*AttendanceDayResource{}
+	for _, day := range days {
+		resources = append(resources, m.AttendanceDayToResource(day))
+	}
+	return resources, nil
+}
+
+// attendanceDays returns the attendance of the employee of the company on
+// every calendar date from from to to. A day takes the status of its first
+// timesheet; regular days without one are on leave when covered by approved
+// leave, and working days that ended without one are absent.
+func (m *ModelResource) attendanceDays(employee *Employee, company *Company, from, to time.Time) ([]*AttendanceDay, error) {
+	from, to = calendarDate(from), calendarDate(to)
+	if to.Before(from) {
+		return nil, errors.New("to is before from")
+	}
+	if to.Sub(from) >= attendanceMaxDays*24*time.Hour {
+		return nil, fmt.Errorf("the range is longer than %d days", attendanceMaxDays)
+	}
+	location := companyLocation(company)
+
+	// Timesheets keep the schedule they were computed against, so they are
+	// grouped by the date of their schedule and not of their time-in
+	var timesheets []*Timesheet
+	err := m.db.Client.Preload("Shift").
+		Where("employee_id = ? AND time_in >= ? AND time_in < ?", employee.ID,
+			time.Date(from.Year(), from.Month(), from.Day()-1, 0, 0, 0, 0, location),
+			time.Date(to.Year(), to.Month(), to.Day()+2, 0, 0, 0, 0, location)).
+		Order("time_in").Find(&timesheets).Error
+	if err != nil {
+		return nil, err
+	}
+	leave, err := m.approvedLeave(employee.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	byDate := map[string][]*Timesheet{}
+	for _, timesheet := range timesheets {
+		key := timesheetDate(timesheet, location).Format(time.DateOnly)
+		byDate[key] = append(byDate[key], timesheet)
+	}
+
+	now := time.Now().In(location)
+	days := []*AttendanceDay{}
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		local := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, location)
+		schedule, err := m.scheduleOn(employee, company, local)
+		if err != nil {
+			return nil, err
+		}
+		day := &AttendanceDay{Schedule: schedule, Timesheets: byDate[date.Format(time.DateOnly)]}
+		if schedule.DayType == DayTypeRegular {
+			day.Leave = leaveOn(leave, date)
+		}
+		switch {
+		case len(day.Timesheets) > 0:
+			day.Status = day.Timesheets[0].AttendanceStatus
+		case day.Leave != nil:
+			day.Status = AttendanceOnLeave
+		case !schedule.Working():
+			day.Status = AttendanceUnscheduled
+		case schedule.ScheduledOut.Before(now):
+			day.Status = AttendanceAbsent
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// timesheetDate returns the calendar date the timesheet belongs to, the one
+// of its schedule or else of its time-in, in location.
+func timesheetDate(timesheet *Timesheet, location *time.Location) time.Time {
+	if timesheet.ScheduledIn != nil {
+		return timesheet.ScheduledIn.In(location)
+	}
+	return timesheet.TimeIn.In(location)
+}
+
+// TimesheetEmployeeVisible reports whether the timesheets of the employee are
+// within scope, which may be nil. Timesheet scopes only look at employee_id,
+// so they apply to the employees themselves.
+func (m *ModelResource) TimesheetEmployeeVisible(scope managers.ScopeFunc, employeeId uint) (bool, error) {
+	query := m.db.Client.Table("(SELECT id AS employee_id FROM employees WHERE deleted_at IS NULL) AS visible_employees").
+		Where("employee_id = ?", employeeId)
+	if scope != nil {
+		query = query.Scopes(scope)
+	}
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+func (m *ModelResource) ShiftSeeders() error {
+	m.logger.Info("Seeding Shift")
+	return nil
+}
+
+func (m *ModelResource) ShiftAssignmentSeeders() error {
+	m.logger.Info("Seeding ShiftAssignment")
+	return nil
+}
+
+func (m *ModelResource) HolidaySeeders() error {
+	m.logger.Info("Seeding Holiday")
+	return nil
+}
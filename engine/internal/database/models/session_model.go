@@ -0,0 +1,269 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSessionNotFound    = errors.New("session not found or expired")
+	ErrRefreshTokenReused = errors.New("refresh token was already used, the session has been revoked")
+)
+
+// Session is a signed-in device. Access tokens carry its JTI and are only
+// accepted while the session is neither revoked nor expired; the session is
+// kept alive by rotating its refresh token.
+type Session struct {
+	gorm.Model
+
+	// Fields
+	JTI         string     `gorm:"type:varchar(64);uniqueIndex" json:"-"`
+	AccountType string     `gorm:"type:varchar(11);index:idx_session_user" json:"account_type"`
+	UserID      uint       `gorm:"index:idx_session_user" json:"user_id"`
+	IP          string     `gorm:"type:varchar(45)" json:"ip"`
+	UserAgent   string     `gorm:"type:varchar(512)" json:"user_agent"`
+	LastSeenAt  time.Time  `json:"last_seen_at"`
+	ExpiresAt   time.Time  `gorm:"index" json:"expires_at"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+
+	// Relationship 0 to many
+	RefreshTokens []*SessionRefreshToken `gorm:"foreignKey:SessionID" json:"-"`
+}
+
+// SessionRefreshToken is a refresh token of a session stored as a HashToken
+// hash. Used tokens are kept until they expire so that presenting one again
+// is detected as a stolen token.
+type SessionRefreshToken struct {
+	gorm.Model
+
+	// Fields
+	TokenHash string     `gorm:"type:varchar(255);uniqueIndex" json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+
+	// Relationship 1 to many
+	SessionID uint     `gorm:"index" json:"session_id"`
+	Session   *Session `gorm:"foreignKey:SessionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+type SessionResource struct {
+	ID         uint   `json:"id"`
+	CreatedAt  string `json:"createdAt"`
+	IP         string `json:"ip"`
+	UserAgent  string `json:"userAgent"`
+	LastSeenAt string `json:"lastSeenAt"`
+	ExpiresAt  string `json:"expiresAt"`
+	Current    bool   `json:"current"`
+}
+
+// SessionToResource converts session, flagging it as current when it is the
+// session of the request, identified by currentJTI.
+func (m *ModelResource) SessionToResource(session *Session, currentJTI string) *SessionResource {
+	if session == nil {
+		return nil
+	}
+	return &SessionResource{
+		ID:         session.ID,
+		CreatedAt:  session.CreatedAt.Format(time.RFC3339),
+		IP:         session.IP,
+		UserAgent:  session.UserAgent,
+		LastSeenAt: session.LastSeenAt.Format(time.RFC3339),
+		ExpiresAt:  session.ExpiresAt.Format(time.RFC3339),
+		Current:    session.JTI == currentJTI,
+	}
+}
+
+func (m *ModelResource) SessionToResourceList(sessions []*Session, currentJTI string) []*SessionResource {
+	if sessions == nil {
+		return nil
+	}
+	var sessionResources []*SessionResource
+	for _, session := range sessions {
+		sessionResources = append(sessionResources, m.SessionToResource(session, currentJTI))
+	}
+	return sessionResources
+}
+
+func sessionActive(db *gorm.DB) *gorm.DB {
+	return db.Where("revoked_at IS NULL AND expires_at > ?", time.Now())
+}
+
+// SessionCreate stores session along with its first refresh token.
+func (m *ModelResource) SessionCreate(session *Session, refreshTokenHash string) error {
+	return m.db.Client.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		return tx.Create(&SessionRefreshToken{
+			SessionID: session.ID,
+			TokenHash: refreshTokenHash,
+			ExpiresAt: session.ExpiresAt,
+		}).Error
+	})
+}
+
+// SessionGet returns the active session with the given JTI, or nil when there
+// is none.
+func (m *ModelResource) SessionGet(jti string) (*Session, error) {
+	var sessions []*Session
+	err := m.db.Client.Scopes(sessionActive).Where("jti = ?", jti).Limit(1).Find(&sessions).Error
+	if err != nil || len(sessions) == 0 {
+		return nil, err
+	}
+	return sessions[0], nil
+}
+
+// SessionTouch records that the session was used from ip. It returns
+// ErrSessionNotFound when the session was revoked or expired meanwhile.
+func (m *ModelResource) SessionTouch(jti, ip string) error {
+	result := m.db.Client.Model(&Session{}).Scopes(sessionActive).Where("jti = ?", jti).Updates(map[string]interface{}{
+		"ip":           ip,
+		"last_seen_at": time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// SessionRotate exchanges a refresh token for newRefreshTokenHash and extends
+// the session until expiresAt. Presenting a token that was already exchanged
+// revokes the session and returns ErrRefreshTokenReused along with it.
+func (m *ModelResource) SessionRotate(refreshTokenHash, newRefreshTokenHash string, expiresAt time.Time) (*Session, error) {
+	var refreshTokens []*SessionRefreshToken
+	err := m.db.Client.Preload("Session").Where("token_hash = ?", refreshTokenHash).Limit(1).Find(&refreshTokens).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(refreshTokens) == 0 || refreshTokens[0].Session == nil {
+		return nil, ErrSessionNotFound
+	}
+	refreshToken, session := refreshTokens[0], refreshTokens[0].Session
+	now := time.Now()
+	if session.RevokedAt != nil || !session.ExpiresAt.After(now) || !refreshToken.ExpiresAt.After(now) {
+		return nil, ErrSessionNotFound
+	}
+	if refreshToken.UsedAt != nil {
+		return session, m.sessionRevokeReused(session)
+	}
+
+	err = m.db.Client.Transaction(func(tx *gorm.DB) error {
+		// Only the first request to present the token may exchange it
+		result := tx.Model(&SessionRefreshToken{}).
+			Where("id = ? AND used_at IS NULL", refreshToken.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRefreshTokenReused
+		}
+		if err := tx.Unscoped().Where("session_id = ? AND expires_at <= ?", session.ID, now).Delete(&SessionRefreshToken{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&SessionRefreshToken{
+			SessionID: session.ID,
+			TokenHash: newRefreshTokenHash,
+			ExpiresAt: expiresAt,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(session).Updates(map[string]interface{}{
+			"last_seen_at": now,
+			"expires_at":   expiresAt,
+		}).Error
+	})
+	if errors.Is(err, ErrRefreshTokenReused) {
+		return session, m.sessionRevokeReused(session)
+	}
+	if err != nil {
+		return nil, err
+	}
+	session.LastSeenAt, session.ExpiresAt = now, expiresAt
+	return session, nil
+}
+
+func (m *ModelResource) sessionRevokeReused(session *Session) error {
+	if err := m.db.Client.Model(session).Update("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// SessionByRefreshToken returns the active session holding the unused refresh
+// token, or nil when there is none.
+func (m *ModelResource) SessionByRefreshToken(refreshTokenHash string) (*Session, error) {
+	var sessions []*Session
+	err := m.db.Client.Scopes(sessionActive).
+		Where("id IN (?)", m.db.Client.Model(&SessionRefreshToken{}).Select("session_id").Where("token_hash = ? AND used_at IS NULL", refreshTokenHash)).
+		Limit(1).Find(&sessions).Error
+	if err != nil || len(sessions) == 0 {
+		return nil, err
+	}
+	return sessions[0], nil
+}
+
+// SessionList returns the active sessions of the user, most recently used
+// first.
+func (m *ModelResource) SessionList(accountType string, userId uint) ([]*Session, error) {
+	var sessions []*Session
+	err := m.db.Client.Scopes(sessionActive).
+		Where("account_type = ? AND user_id = ?", accountType, userId).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// SessionRevoke revokes one active session of the user.
+func (m *ModelResource) SessionRevoke(accountType string, userId uint, sessionId uint) (*Session, error) {
+	var sessions []*Session
+	err := m.db.Client.Scopes(sessionActive).
+		Where("id = ? AND account_type = ? AND user_id = ?", sessionId, accountType, userId).
+		Limit(1).Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, ErrSessionNotFound
+	}
+	if err := m.db.Client.Model(sessions[0]).Update("revoked_at", time.Now()).Error; err != nil {
+		return nil, err
+	}
+	return sessions[0], nil
+}
+
+// SessionRevokeAll revokes every active session of the user except the one
+// with exceptJTI, which may be empty, and returns the JTIs it revoked.
+func (m *ModelResource) SessionRevokeAll(accountType string, userId uint, exceptJTI string) ([]string, error) {
+	var jtis []string
+	err := m.db.Client.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&Session{}).Scopes(sessionActive).
+			Where("account_type = ? AND user_id = ? AND jti <> ?", accountType, userId, exceptJTI)
+		if err := query.Pluck("jti", &jtis).Error; err != nil {
+			return err
+		}
+		if len(jtis) == 0 {
+			return nil
+		}
+		return tx.Model(&Session{}).Where("jti IN ?", jtis).Update("revoked_at", time.Now()).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jtis, nil
+}
+
+func (m *ModelResource) SessionSeeders() error {
+	m.logger.Info("Seeding Session")
+	return nil
+}
+
+func (m *ModelResource) SessionRefreshTokenSeeders() error {
+	m.logger.Info("Seeding SessionRefreshToken")
+	return nil
+}
@@ -0,0 +1,227 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
+	"gorm.io/gorm"
+)
+
+// Tenant is the company and branch an authenticated user belongs to. Owners
+// belong to every company they own; employees and members to the company of
+// their branch; API keys to the company they were created for.
+type Tenant struct {
+	AccountType string
+	UserID      uint
+	CompanyIDs  []uint
+	BranchID    *uint
+}
+
+// TenantResolve loads the tenant of the user from their account record.
+func (m *ModelResource) TenantResolve(accountType string, userId uint) (*Tenant, error) {
+	tenant := &Tenant{AccountType: accountType, UserID: userId, CompanyIDs: []uint{}}
+	switch accountType {
+	case "Admin":
+		return tenant, nil
+	case "Owner":
+		err := m.db.Client.Model(&Company{}).Where("owner_id = ?", userId).Pluck("id", &tenant.CompanyIDs).Error
+		return tenant, err
+	case "Employee", "Member":
+		table := "employees"
+		if accountType == "Member" {
+			table = "members"
+		}
+		err := m.db.Client.Table(table).Select("branch_id").Where("id = ? AND deleted_at IS NULL", userId).Row().Scan(&tenant.BranchID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPermissionDenied
+		}
+		if err != nil || tenant.BranchID == nil {
+			return tenant, err
+		}
+		var companyId *uint
+		err = m.db.Client.Model(&Branch{}).Select("company_id").Where("id = ?", *tenant.BranchID).Row().Scan(&companyId)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		if companyId != nil {
+			tenant.CompanyIDs = append(tenant.CompanyIDs, *companyId)
+		}
+		return tenant, nil
+	case ApiKeyAccountType:
+		err := m.db.Client.Model(&ApiKey{}).Where("id = ?", userId).Pluck("company_id", &tenant.CompanyIDs).Error
+		if err == nil && len(tenant.CompanyIDs) == 0 {
+			return nil, ErrPermissionDenied
+		}
+		return tenant, err
+	default:
+		return nil, ErrPermissionDenied
+	}
+}
+
+// TenantBelongs reports whether the user belongs to the company.
+func (m *ModelResource) TenantBelongs(accountType string, userId, companyId uint) (bool, error) {
+	tenant, err := m.TenantResolve(accountType, userId)
+	if err != nil {
+		return false, err
+	}
+	for _, id := range tenant.CompanyIDs {
+		if id == companyId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// TenantScope restricts queries on resource to the rows the tenant can reach
+// with the given permission scope. Resources without tenant columns (genders,
+// roles, media, ...) and the "all" scope are not restricted, so it returns nil.
+func (m *ModelResource) TenantScope(resource string, tenant *Tenant, scope PermissionScope) managers.ScopeFunc {
+	if scope == ScopeAll || tenant.AccountType == "Admin" {
+		return nil
+	}
+
+	// Without a branch, branch scoped users can only reach their own rows
+	if scope == ScopeBranch && tenant.BranchID == nil {
+		scope = ScopeOwn
+	}
+	// An Owner's "own" rows are those of the companies they own
+	if tenant.AccountType == "Owner" && scope == ScopeOwn && resource != ResourceOwner && resource != ResourceFootstep {
+		scope = ScopeCompany
+	}
+
+	switch resource {
+	case ResourceCompany:
+		// Matching on owner_id rather than the companies resolved beforehand
+		// also lets Owners see the company they create within the request
+		if tenant.AccountType == "Owner" {
+			return tenantWhere("owner_id = ?", tenant.UserID)
+		}
+		switch scope {
+		case ScopeOwn:
+			return selfCondition(tenant, "Owner", "owner_id = ?")
+		default:
+			return tenantWhere("id IN ?", tenant.CompanyIDs)
+		}
+
+	case ResourceBranch:
+		switch scope {
+		case ScopeCompany:
+			return tenantWhere("company_id IN ?", tenant.CompanyIDs)
+		default:
+			if tenant.BranchID == nil {
+				return denyAll
+			}
+			return tenantWhere("id = ?", *tenant.BranchID)
+		}
+
+	case ResourceEmployee, ResourceMember:
+		accountType := "Employee"
+		if resource == ResourceMember {
+			accountType = "Member"
+		}
+		switch scope {
+		case ScopeOwn:
+			return selfCondition(tenant, accountType, "id = ?")
+		case ScopeBranch:
+			return tenantWhere("branch_id = ?", *tenant.BranchID)
+		default:
+			return tenantWhere("branch_id IN (SELECT id FROM branches WHERE company_id IN ?)", tenant.CompanyIDs)
+		}
+
+	case ResourceTimesheet, ResourceTimesheetCorrection, ResourceLeave:
+		switch scope {
+		case ScopeOwn:
+			return selfCondition(tenant, "Employee", "employee_id = ?")
+		case ScopeBranch:
+			return tenantWhere("employee_id IN (SELECT id FROM employees WHERE branch_id = ?)", *tenant.BranchID)
+		default:
+			return tenantWhere("employee_id IN (SELECT id FROM employees WHERE branch_id IN (SELECT id FROM branches WHERE company_id IN ?))", tenant.CompanyIDs)
+		}
+
+	case ResourceFootstep:
+		switch scope {
+		case ScopeOwn:
+			switch tenant.AccountType {
+			case "Owner":
+				return tenantWhere("owner_id = ?", tenant.UserID)
+			case "Employee":
+				return tenantWhere("employee_id = ?", tenant.UserID)
+			case "Member":
+				return tenantWhere("member_id = ?", tenant.UserID)
+			}
+			return denyAll
+		case ScopeBranch:
+			return tenantWhere("(employee_id IN (SELECT id FROM employees WHERE branch_id = ?) OR member_id IN (SELECT id FROM members WHERE branch_id = ?))",
+				*tenant.BranchID, *tenant.BranchID)
+		default:
+			return tenantWhere("(employee_id IN (SELECT id FROM employees WHERE branch_id IN (SELECT id FROM branches WHERE company_id IN ?)) OR member_id IN (SELECT id FROM members WHERE branch_id IN (SELECT id FROM branches WHERE company_id IN ?)) OR api_key_id IN (SELECT id FROM api_keys WHERE company_id IN ?))",
+				tenant.CompanyIDs, tenant.CompanyIDs, tenant.CompanyIDs)
+		}
+
+	case ResourceShift, ResourceHoliday, ResourceLeaveType:
+		return tenantWhere("company_id IN ?", tenant.CompanyIDs)
+
+	case ResourceSchedule:
+		// Assignments are of shifts of the company, and of its employees or
+		// branches
+		shifts := tenantWhere("shift_id IN (SELECT id FROM shifts WHERE company_id IN ?)", tenant.CompanyIDs)
+		var assignees managers.ScopeFunc
+		switch scope {
+		case ScopeOwn:
+			assignees = selfCondition(tenant, "Employee", "employee_id = ?")
+		case ScopeBranch:
+			assignees = tenantWhere("(employee_id IN (SELECT id FROM employees WHERE branch_id = ?) OR branch_id = ?)",
+				*tenant.BranchID, *tenant.BranchID)
+		default:
+			assignees = tenantWhere("(employee_id IN (SELECT id FROM employees WHERE branch_id IN (SELECT id FROM branches WHERE company_id IN ?)) OR branch_id IN (SELECT id FROM branches WHERE company_id IN ?))",
+				tenant.CompanyIDs, tenant.CompanyIDs)
+		}
+		return func(db *gorm.DB) *gorm.DB {
+			return db.Scopes(shifts, assignees)
+		}
+
+	case ResourcePayroll:
+		// Pay periods cover every employee of the company
+		if scope != ScopeCompany {
+			return denyAll
+		}
+		return tenantWhere("company_id IN ?", tenant.CompanyIDs)
+
+	case ResourceApiKey:
+		if scope == ScopeOwn && tenant.AccountType != "Owner" {
+			return denyAll
+		}
+		return tenantWhere("company_id IN ?", tenant.CompanyIDs)
+
+	case ResourceOwner:
+		switch scope {
+		case ScopeOwn:
+			return selfCondition(tenant, "Owner", "id = ?")
+		default:
+			return tenantWhere("id IN (SELECT owner_id FROM companies WHERE id IN ?)", tenant.CompanyIDs)
+		}
+
+	default:
+		return nil
+	}
+}
+
+func tenantWhere(query string, args ...interface{}) managers.ScopeFunc {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	}
+}
+
+// selfCondition matches the user's own record, or nothing when the user is not
+// of accountType.
+func selfCondition(tenant *Tenant, accountType string, query string) managers.ScopeFunc {
+	if tenant.AccountType != accountType {
+		return denyAll
+	}
+	return tenantWhere(query, tenant.UserID)
+}
+
+func denyAll(db *gorm.DB) *gorm.DB {
+	return db.Where("1 = 0")
+}
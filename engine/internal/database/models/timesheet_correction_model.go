@@ -0,0 +1,326 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
+	"github.com/go-playground/validator"
+	"gorm.io/gorm"
+)
+
+// Kinds of timesheet corrections.
+const (
+	CorrectionMissingPunch = "Missing Punch"
+	CorrectionWrongTime    = "Wrong Time"
+)
+
+// Statuses of timesheet corrections.
+const (
+	CorrectionPending  = "Pending"
+	CorrectionApproved = "Approved"
+	CorrectionRejected = "Rejected"
+)
+
+var (
+	ErrCorrectionPending  = errors.New("the timesheet already has a pending correction")
+	ErrCorrectionReviewed = errors.New("the correction has already been reviewed")
+	ErrCorrectionTimes    = errors.New("the corrected time out is not after the time in")
+)
+
+// TimesheetCorrection is a request of an employee to fix the time in or time
+// out of one of their timesheets, or to add a timesheet they missed entirely.
+// Once approved the times are applied to the timesheet, and the times it had
+// before are kept here.
+type TimesheetCorrection struct {
+	gorm.Model
+
+	// Fields
+	Type    string     `gorm:"type:varchar(32)" json:"type"`
+	TimeIn  *time.Time `gorm:"type:datetime(3)" json:"time_in"`
+	TimeOut *time.Time `gorm:"type:datetime(3)" json:"time_out"`
+	Reason  string     `gorm:"type:varchar(1024)" json:"reason"`
+	Status  string     `gorm:"type:varchar(16);default:'Pending';index" json:"status"`
+
+	// Times of the timesheet before the correction was applied
+	OriginalTimeIn  *time.Time `gorm:"type:datetime(3)" json:"original_time_in"`
+	OriginalTimeOut *time.Time `gorm:"type:datetime(3)" json:"original_time_out"`
+
+	// Who approved or rejected the correction, and why
+	ReviewerAccountType string     `gorm:"type:varchar(16)" json:"reviewer_account_type"`
+	ReviewerID          *uint      `gorm:"type:bigint;unsigned" json:"reviewer_id"`
+	ReviewedAt          *time.Time `json:"reviewed_at"`
+	ReviewNote          string     `gorm:"type:varchar(1024)" json:"review_note"`
+
+	// Relationship 1 to many
+	EmployeeID uint      `gorm:"type:bigint;unsigned;index" json:"employee_id"`
+	Employee   *Employee `gorm:"foreignKey:EmployeeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"employee"`
+
+	// Relationship 0 to 1, nil until a correction adding a timesheet is
+	// approved
+	TimesheetID *uint      `gorm:"type:bigint;unsigned;index" json:"timesheet_id"`
+	Timesheet   *Timesheet `gorm:"foreignKey:TimesheetID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"timesheet"`
+}
+
+type TimesheetCorrectionResource struct {
+	ID        uint   `json:"id"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+
+	Type            string     `json:"type"`
+	TimeIn          *time.Time `json:"timeIn"`
+	TimeOut         *time.Time `json:"timeOut"`
+	Reason          string     `json:"reason"`
+	Status          string     `json:"status"`
+	OriginalTimeIn  *time.Time `json:"originalTimeIn"`
+	OriginalTimeOut *time.Time `json:"originalTimeOut"`
+
+	ReviewerAccountType string  `json:"reviewerAccountType"`
+	ReviewerID          *uint   `json:"reviewerID"`
+	ReviewedAt          *string `json:"reviewedAt"`
+	ReviewNote          string  `json:"reviewNote"`
+
+	EmployeeID  uint               `json:"employeeID"`
+	Employee    *EmployeeResource  `json:"employee"`
+	TimesheetID *uint              `json:"timesheetID"`
+	Timesheet   *TimesheetResource `json:"timesheet"`
+}
+
+// TimesheetCorrectionRequest files a correction. Without a timesheet it adds
+// one, and needs both times; with one, the times left out are kept.
+type TimesheetCorrectionRequest struct {
+	TimesheetID *uint      `json:"timesheetID"`
+	Type        string     `json:"type" validate:"required"`
+	TimeIn      *time.Time `json:"timeIn"`
+	TimeOut     *time.Time `json:"timeOut"`
+	Reason      string     `json:"reason" validate:"required,max=1024"`
+}
+
+// TimesheetCorrectionReviewRequest approves or rejects a correction.
+type TimesheetCorrectionReviewRequest struct {
+	Note string `json:"note" validate:"max=1024"`
+}
+
+func (m *ModelResource) TimesheetCorrectionToResource(correction *TimesheetCorrection) *TimesheetCorrectionResource {
+	if correction == nil {
+		return nil
+	}
+	return &TimesheetCorrectionResource{
+		ID:        correction.ID,
+		CreatedAt: correction.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: correction.UpdatedAt.Format(time.RFC3339),
+
+		Type:            correction.Type,
+		TimeIn:          correction.TimeIn,
+		TimeOut:         correction.TimeOut,
+		Reason:          correction.Reason,
+		Status:          correction.Status,
+		OriginalTimeIn:  correction.OriginalTimeIn,
+		OriginalTimeOut: correction.OriginalTimeOut,
+
+		ReviewerAccountType: correction.ReviewerAccountType,
+		ReviewerID:          correction.ReviewerID,
+		ReviewedAt:          formatOptionalTime(correction.ReviewedAt),
+		ReviewNote:          correction.ReviewNote,
+
+		EmployeeID:  correction.EmployeeID,
+		Employee:    m.EmployeeToResource(correction.Employee),
+		TimesheetID: correction.TimesheetID,
+		Timesheet:   m.TimesheetToResource(correction.Timesheet),
+	}
+}
+
+func (m *ModelResource) TimesheetCorrectionToResourceList(corrections []*TimesheetCorrection) []*TimesheetCorrectionResource {
+	if corrections == nil {
+		return nil
+	}
+	var correctionResources []*TimesheetCorrectionResource
+	for _, correction := range corrections {
+		correctionResources = append(correctionResources, m.TimesheetCorrectionToResource(correction))
+	}
+	return correctionResources
+}
+
+func (m *ModelResource) ValidateTimesheetCorrectionRequest(req *TimesheetCorrectionRequest) error {
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		return m.helpers.FormatValidationError(err)
+	}
+	if req.Type != CorrectionMissingPunch && req.Type != CorrectionWrongTime {
+		return fmt.Errorf("type must be one of: %s, %s", CorrectionMissingPunch, CorrectionWrongTime)
+	}
+	if req.TimeIn == nil && req.TimeOut == nil {
+		return errors.New("timeIn or timeOut is required")
+	}
+	if req.TimesheetID == nil && (req.TimeIn == nil || req.TimeOut == nil) {
+		return errors.New("timeIn and timeOut are required to add a timesheet")
+	}
+	now := time.Now()
+	if (req.TimeIn != nil && req.TimeIn.After(now)) || (req.TimeOut != nil && req.TimeOut.After(now)) {
+		return errors.New("corrected times cannot be in the future")
+	}
+	return nil
+}
+
+func (m *ModelResource) ValidateTimesheetCorrectionReviewRequest(req *TimesheetCorrectionReviewRequest) error {
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		return m.helpers.FormatValidationError(err)
+	}
+	return nil
+}
+
+// TimesheetCorrectionGet returns the correction with the id within scope,
+// which may be nil.
+func (m *ModelResource) TimesheetCorrectionGet(scope managers.ScopeFunc, id uint) (*TimesheetCorrection, error) {
+	query := m.db.Client.Preload("Employee").Preload("Timesheet").Where("id = ?", id)
+	if scope != nil {
+		query = query.Scopes(scope)
+	}
+	var corrections []*TimesheetCorrection
+	if err := query.Limit(1).Find(&corrections).Error; err != nil {
+		return nil, err
+	}
+	if len(corrections) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return corrections[0], nil
+}
+
+// correctedTimesheet returns the timesheet of the correction with its times
+// corrected, a new one when the correction adds a timesheet. It returns
+// ErrPayPeriodLocked when the timesheet is, or would be, in a locked pay
+// period.
+func (m *ModelResource) correctedTimesheet(correction *TimesheetCorrection) (*Timesheet, error) {
+	timesheet := &Timesheet{EmployeeID: correction.EmployeeID}
+	if correction.TimesheetID != nil {
+		var timesheets []*Timesheet
+		err := m.db.Client.Where("id = ? AND employee_id = ?", *correction.TimesheetID, correction.EmployeeID).
+			Limit(1).Find(&timesheets).Error
+		if err != nil {
+			return nil, err
+		}
+		if len(timesheets) == 0 {
+			return nil, gorm.ErrRecordNotFound
+		}
+		timesheet = timesheets[0]
+		if err := m.TimesheetEditable(timesheet); err != nil {
+			return nil, err
+		}
+	}
+
+	if correction.TimeIn != nil {
+		timesheet.TimeIn = correction.TimeIn
+	}
+	if correction.TimeOut != nil {
+		timesheet.TimeOut = correction.TimeOut
+	}
+	if timesheet.TimeIn != nil && timesheet.TimeOut != nil && !timesheet.TimeOut.After(*timesheet.TimeIn) {
+		return nil, ErrCorrectionTimes
+	}
+	if err := m.TimesheetApplyAttendance(timesheet); err != nil {
+		return nil, err
+	}
+	if err := m.TimesheetEditable(timesheet); err != nil {
+		return nil, err
+	}
+	return timesheet, nil
+}
+
+// TimesheetCorrectionFile files a correction of the employee. The timesheet,
+// if any, must be theirs and without another pending correction.
+func (m *ModelResource) TimesheetCorrectionFile(employeeId uint, req *TimesheetCorrectionRequest) (*TimesheetCorrection, error) {
+	correction := &TimesheetCorrection{
+		Type:        req.Type,
+		TimeIn:      req.TimeIn,
+		TimeOut:     req.TimeOut,
+		Reason:      req.Reason,
+		Status:      CorrectionPending,
+		EmployeeID:  employeeId,
+		TimesheetID: req.TimesheetID,
+	}
+	if _, err := m.correctedTimesheet(correction); err != nil {
+		return nil, err
+	}
+	if correction.TimesheetID != nil {
+		var count int64
+		err := m.db.Client.Model(&TimesheetCorrection{}).
+			Where("timesheet_id = ? AND status = ?", *correction.TimesheetID, CorrectionPending).
+			Count(&count).Error
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, ErrCorrectionPending
+		}
+	}
+	if err := m.db.Client.Create(correction).Error; err != nil {
+		return nil, err
+	}
+	return correction, nil
+}
+
+// TimesheetCorrectionReview approves or rejects a pending correction on
+// behalf of the reviewer. Approving applies it to the timesheet, or adds the
+// timesheet, keeping the times it had and clearing its review flag.
+func (m *ModelResource) TimesheetCorrectionReview(correction *TimesheetCorrection, approve bool, reviewerAccountType string, reviewerId uint, note string) error {
+	if correction.Status != CorrectionPending {
+		return ErrCorrectionReviewed
+	}
+
+	var timesheet *Timesheet
+	if approve {
+		var err error
+		if timesheet, err = m.correctedTimesheet(correction); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	return m.db.Client.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{
+			"status":                CorrectionRejected,
+			"reviewer_account_type": reviewerAccountType,
+			"reviewer_id":           reviewerId,
+			"reviewed_at":           now,
+			"review_note":           note,
+		}
+		if timesheet != nil {
+			updates["status"] = CorrectionApproved
+			if timesheet.ID == 0 {
+				if err := tx.Create(timesheet).Error; err != nil {
+					return err
+				}
+			} else {
+				var original Timesheet
+				if err := tx.Select("time_in", "time_out").First(&original, timesheet.ID).Error; err != nil {
+					return err
+				}
+				updates["original_time_in"] = original.TimeIn
+				updates["original_time_out"] = original.TimeOut
+				timesheet.NeedsReview = false
+				if err := tx.Save(timesheet).Error; err != nil {
+					return err
+				}
+			}
+			updates["timesheet_id"] = timesheet.ID
+		}
+
+		// Reviewers racing on the same correction: only the first one counts
+		result := tx.Model(correction).Where("status = ?", CorrectionPending).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrCorrectionReviewed
+		}
+		correction.Timesheet = timesheet
+		return nil
+	})
+}
+
+func (m *ModelResource) TimesheetCorrectionSeeders() error {
+	m.logger.Info("Seeding TimesheetCorrection")
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers/filter"
 	"github.com/go-playground/validator"
 	"gorm.io/gorm"
@@ -25,6 +26,37 @@ type Timesheet struct {
 	// Relationship 0 to 1
 	MediaOutID *uint  `gorm:"type:bigint;unsigned" json:"media_out_id"`
 	MediaOut   *Media `gorm:"foreignKey:MediaOutID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"media_out"`
+
+	// Relationship 0 to 1, the shift the attendance was computed against
+	ShiftID *uint  `gorm:"type:bigint;unsigned" json:"shift_id"`
+	Shift   *Shift `gorm:"foreignKey:ShiftID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"shift"`
+
+	// Attendance, computed at time-in and time-out
+	ScheduledIn      *time.Time `gorm:"type:datetime(3)" json:"scheduled_in"`
+	ScheduledOut     *time.Time `gorm:"type:datetime(3)" json:"scheduled_out"`
+	DayType          string     `gorm:"type:varchar(32)" json:"day_type"`
+	AttendanceStatus string     `gorm:"type:varchar(32)" json:"attendance_status"`
+	LateMinutes      int        `gorm:"default:0" json:"late_minutes"`
+	UndertimeMinutes int        `gorm:"default:0" json:"undertime_minutes"`
+	OvertimeMinutes  int        `gorm:"default:0" json:"overtime_minutes"`
+
+	// Where the device was at time-in and time-out, and how far from the
+	// branch in meters
+	LatitudeIn    *float64 `gorm:"type:decimal(10,7)" json:"latitude_in"`
+	LongitudeIn   *float64 `gorm:"type:decimal(10,7)" json:"longitude_in"`
+	AccuracyIn    *float64 `json:"accuracy_in"`
+	DistanceIn    *float64 `json:"distance_in"`
+	OutOfRangeIn  bool     `gorm:"default:false" json:"out_of_range_in"`
+	LatitudeOut   *float64 `gorm:"type:decimal(10,7)" json:"latitude_out"`
+	LongitudeOut  *float64 `gorm:"type:decimal(10,7)" json:"longitude_out"`
+	AccuracyOut   *float64 `json:"accuracy_out"`
+	DistanceOut   *float64 `json:"distance_out"`
+	OutOfRangeOut bool     `gorm:"default:false" json:"out_of_range_out"`
+
+	// Timesheets left open too long are closed by the auto-close job and
+	// flagged until a correction of them is approved
+	AutoClosed  bool `gorm:"default:false" json:"auto_closed"`
+	NeedsReview bool `gorm:"default:false;index" json:"needs_review"`
 }
 
 type TimesheetResource struct {
@@ -40,16 +72,42 @@ type TimesheetResource struct {
 	MediaIn    *MediaResource    `json:"mediaIn"`
 	MediaOutID *uint             `json:"mediaOutID"`
 	MediaOut   *MediaResource    `json:"mediaOut"`
+
+	ShiftID          *uint          `json:"shiftID"`
+	Shift            *ShiftResource `json:"shift"`
+	ScheduledIn      *time.Time     `json:"scheduledIn"`
+	ScheduledOut     *time.Time     `json:"scheduledOut"`
+	DayType          string         `json:"dayType"`
+	AttendanceStatus string         `json:"attendanceStatus"`
+	LateMinutes      int            `json:"lateMinutes"`
+	UndertimeMinutes int            `json:"undertimeMinutes"`
+	OvertimeMinutes  int            `json:"overtimeMinutes"`
+
+	LatitudeIn    *float64 `json:"latitudeIn"`
+	LongitudeIn   *float64 `json:"longitudeIn"`
+	AccuracyIn    *float64 `json:"accuracyIn"`
+	DistanceIn    *float64 `json:"distanceIn"`
+	OutOfRangeIn  bool     `json:"outOfRangeIn"`
+	LatitudeOut   *float64 `json:"latitudeOut"`
+	LongitudeOut  *float64 `json:"longitudeOut"`
+	AccuracyOut   *float64 `json:"accuracyOut"`
+	DistanceOut   *float64 `json:"distanceOut"`
+	OutOfRangeOut bool     `json:"outOfRangeOut"`
+
+	AutoClosed  bool `json:"autoClosed"`
+	NeedsReview bool `json:"needsReview"`
 }
 
 type TimeInRequest struct {
 	TimeIn  time.Time    `json:"timeIn" validate:"required"`
 	MediaIn MediaRequest `json:"mediaIn" validate:"required"`
+	Punch
 }
 
 type TimeOutRequest struct {
 	TimeOut  time.Time    `json:"timeOut" validate:"required"`
 	MediaOut MediaRequest `json:"mediaOut" validate:"required"`
+	Punch
 }
 
 func (m *ModelResource) TimesheetToResource(timesheet *Timesheet) *TimesheetResource {
@@ -69,6 +127,30 @@ func (m *ModelResource) TimesheetToResource(timesheet *Timesheet) *TimesheetReso
 		MediaIn:    m.MediaToResource(timesheet.MediaIn),
 		MediaOutID: timesheet.MediaOutID,
 		MediaOut:   m.MediaToResource(timesheet.MediaOut),
+
+		ShiftID:          timesheet.ShiftID,
+		Shift:            m.ShiftToResource(timesheet.Shift),
+		ScheduledIn:      timesheet.ScheduledIn,
+		ScheduledOut:     timesheet.ScheduledOut,
+		DayType:          timesheet.DayType,
+		AttendanceStatus: timesheet.AttendanceStatus,
+		LateMinutes:      timesheet.LateMinutes,
+		UndertimeMinutes: timesheet.UndertimeMinutes,
+		OvertimeMinutes:  timesheet.OvertimeMinutes,
+
+		LatitudeIn:    timesheet.LatitudeIn,
+		LongitudeIn:   timesheet.LongitudeIn,
+		AccuracyIn:    timesheet.AccuracyIn,
+		DistanceIn:    timesheet.DistanceIn,
+		OutOfRangeIn:  timesheet.OutOfRangeIn,
+		LatitudeOut:   timesheet.LatitudeOut,
+		LongitudeOut:  timesheet.LongitudeOut,
+		AccuracyOut:   timesheet.AccuracyOut,
+		DistanceOut:   timesheet.DistanceOut,
+		OutOfRangeOut: timesheet.OutOfRangeOut,
+
+		AutoClosed:  timesheet.AutoClosed,
+		NeedsReview: timesheet.NeedsReview,
 	}
 }
 
@@ -113,6 +195,16 @@ func (m *ModelResource) TimesheetToRecord(timesheets []*Timesheet) ([][]string,
 			mediaOutURL = sanitizeCSVField(timesheet.MediaOut.URL)
 		}
 
+		// Distances from the branch
+		distanceIn := "N/A"
+		if timesheet.DistanceIn != nil {
+			distanceIn = strconv.FormatFloat(*timesheet.DistanceIn, 'f', 1, 64)
+		}
+		distanceOut := "N/A"
+		if timesheet.DistanceOut != nil {
+			distanceOut = strconv.FormatFloat(*timesheet.DistanceOut, 'f', 1, 64)
+		}
+
 		// Assemble the record
 		record := []string{
 			id,
@@ -122,6 +214,17 @@ func (m *ModelResource) TimesheetToRecord(timesheets []*Timesheet) ([][]string,
 			mediaInURL,
 			timeOut,
 			mediaOutURL,
+			distanceIn,
+			strconv.FormatBool(timesheet.OutOfRangeIn),
+			distanceOut,
+			strconv.FormatBool(timesheet.OutOfRangeOut),
+			sanitizeCSVField(timesheet.DayType),
+			sanitizeCSVField(timesheet.AttendanceStatus),
+			strconv.Itoa(timesheet.LateMinutes),
+			strconv.Itoa(timesheet.UndertimeMinutes),
+			strconv.Itoa(timesheet.OvertimeMinutes),
+			strconv.FormatBool(timesheet.AutoClosed),
+			strconv.FormatBool(timesheet.NeedsReview),
 			createdAt,
 			updatedAt,
 		}
@@ -136,6 +239,17 @@ func (m *ModelResource) TimesheetToRecord(timesheets []*Timesheet) ([][]string,
 		"Media In URL",
 		"Time Out",
 		"Media Out URL",
+		"Distance In (m)",
+		"Out Of Range In",
+		"Distance Out (m)",
+		"Out Of Range Out",
+		"Day Type",
+		"Attendance Status",
+		"Late Minutes",
+		"Undertime Minutes",
+		"Overtime Minutes",
+		"Auto Closed",
+		"Needs Review",
 		"Created At",
 		"Updated At",
 	}
@@ -143,13 +257,34 @@ func (m *ModelResource) TimesheetToRecord(timesheets []*Timesheet) ([][]string,
 	return records, headers
 }
 
+// punchClockSkew is how far the clock of the device punching in or out may be
+// from the server clock. Punches are recorded at the server time; a device
+// further off is rejected so that back or forward dated punches are noticed.
+const punchClockSkew = time.Minute * 2
+
+// validatePunchTime rejects the time a device reports for a punch unless it is
+// within punchClockSkew of the server clock.
+func validatePunchTime(field string, at time.Time) error {
+	skew := time.Since(at)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > punchClockSkew {
+		return fmt.Errorf("%s is more than %s off the server clock", field, punchClockSkew)
+	}
+	return nil
+}
+
 func (m *ModelResource) ValidateTimeInRequest(req *TimeInRequest) error {
 	validate := validator.New()
 	err := validate.Struct(req)
 	if err != nil {
 		return m.helpers.FormatValidationError(err)
 	}
-	return nil
+	if err := validatePunchTime("timeIn", req.TimeIn); err != nil {
+		return err
+	}
+	return req.Punch.validate()
 }
 
 func (m *ModelResource) ValidateTimeOutRequest(req *TimeOutRequest) error {
@@ -158,7 +293,10 @@ func (m *ModelResource) ValidateTimeOutRequest(req *TimeOutRequest) error {
 	if err != nil {
 		return m.helpers.FormatValidationError(err)
 	}
-	return nil
+	if err := validatePunchTime("timeOut", req.TimeOut); err != nil {
+		return err
+	}
+	return req.Punch.validate()
 }
 
 func (m *ModelResource) TimesheetToResourceList(timesheets []*Timesheet) []*TimesheetResource {
@@ -191,10 +329,87 @@ func (m *ModelResource) TimesheetCurrentForEmployee(employeeId uint) (*Timesheet
 }
 
 func (m *ModelResource) TimesheetFindallForEmployee(employeeId uint, filters string, pageSize, pageIndex int) (filter.FilterPages[Timesheet], error) {
-	db := m.db.Client.Where("employee_id = ? AND time_out IS NULL", employeeId)
+	db := m.db.Client.Where("employee_id = ?", employeeId)
 	return m.TimesheetDB.GetPaginatedResult(db, filters, pageSize, pageIndex)
 }
 
+// TimesheetNeedsReview returns the timesheets within scope flagged for
+// review, oldest first.
+func (m *ModelResource) TimesheetNeedsReview(scope managers.ScopeFunc) ([]*Timesheet, error) {
+	query := m.db.Client.Preload("Employee").Where("needs_review = ?", true)
+	if scope != nil {
+		query = query.Scopes(scope)
+	}
+	var timesheets []*Timesheet
+	err := query.Order("time_in").Find(&timesheets).Error
+	return timesheets, err
+}
+
+// TimesheetCloseStale closes the timesheets timed in before openedBefore and
+// never timed out, flagging them for review. They are closed at their time in,
+// so that they add no unapproved hours until a correction sets the actual
+// time out. Timesheets in locked pay periods are left open. One that fails
+// to close does not stop the others; it is left for the next run and its
+// error joined to the one returned along with how many were closed.
+func (m *ModelResource) TimesheetCloseStale(openedBefore time.Time) (int, error) {
+	var timesheets []*Timesheet
+	err := m.db.Client.Where("time_out IS NULL AND time_in < ?", openedBefore).Find(&timesheets).Error
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	var errs []error
+	for _, timesheet := range timesheets {
+		ok, err := m.timesheetCloseStale(timesheet)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("timesheet %d: %w", timesheet.ID, err))
+			continue
+		}
+		if ok {
+			closed++
+		}
+	}
+	return closed, errors.Join(errs...)
+}
+
+// timesheetCloseStale closes timesheet for TimesheetCloseStale and reports
+// whether it did.
+func (m *ModelResource) timesheetCloseStale(timesheet *Timesheet) (bool, error) {
+	err := m.TimesheetEditable(timesheet)
+	if errors.Is(err, ErrPayPeriodLocked) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	timeOut := *timesheet.TimeIn
+	timesheet.TimeOut = &timeOut
+	if err := m.TimesheetApplyAttendance(timesheet); err != nil {
+		return false, err
+	}
+
+	// Another instance may have closed it, or the employee timed out
+	result := m.db.Client.Model(timesheet).Where("time_out IS NULL").Updates(map[string]interface{}{
+		"time_out":          timesheet.TimeOut,
+		"shift_id":          timesheet.ShiftID,
+		"scheduled_in":      timesheet.ScheduledIn,
+		"scheduled_out":     timesheet.ScheduledOut,
+		"day_type":          timesheet.DayType,
+		"attendance_status": timesheet.AttendanceStatus,
+		"late_minutes":      timesheet.LateMinutes,
+		"undertime_minutes": timesheet.UndertimeMinutes,
+		"overtime_minutes":  timesheet.OvertimeMinutes,
+		"auto_closed":       true,
+		"needs_review":      true,
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func (m *ModelResource) TimesheetSeeders() error {
 	m.logger.Info("Seeding Timesheet")
 	return nil
@@ -0,0 +1,327 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TwoFactorRecoveryCodeCount is the number of recovery codes issued at once.
+const TwoFactorRecoveryCodeCount = 10
+
+var (
+	ErrTwoFactorNotEnrolled = errors.New("two-factor authentication is not set up")
+	ErrTwoFactorEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorRequired    = errors.New("two-factor authentication is required by your role")
+	ErrTwoFactorInvalidCode = errors.New("invalid two-factor code")
+)
+
+// TwoFactor is the TOTP enrollment of a user of any account type. The secret
+// is encrypted at rest; Enabled turns true once a code from the authenticator
+// app has been confirmed.
+type TwoFactor struct {
+	gorm.Model
+
+	// Fields
+	AccountType string     `gorm:"type:varchar(11);uniqueIndex:idx_two_factor_user" json:"account_type"`
+	UserID      uint       `gorm:"uniqueIndex:idx_two_factor_user" json:"user_id"`
+	Secret      string     `gorm:"type:varchar(255)" json:"-"`
+	Enabled     bool       `gorm:"default:false" json:"enabled"`
+	ConfirmedAt *time.Time `json:"confirmed_at"`
+	// LastUsedStep is the TOTP time step of the last accepted code, so a code
+	// cannot be replayed within its validity window.
+	LastUsedStep int64 `json:"-"`
+
+	// Relationship 0 to many
+	RecoveryCodes []*TwoFactorRecoveryCode `gorm:"foreignKey:TwoFactorID" json:"-"`
+}
+
+// TwoFactorRecoveryCode is a single-use code stored as a HashToken hash.
+type TwoFactorRecoveryCode struct {
+	gorm.Model
+
+	// Fields
+	CodeHash string     `gorm:"type:varchar(255);index" json:"-"`
+	UsedAt   *time.Time `json:"used_at"`
+
+	// Relationship 1 to many
+	TwoFactorID uint       `gorm:"index" json:"two_factor_id"`
+	TwoFactor   *TwoFactor `gorm:"foreignKey:TwoFactorID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+type TwoFactorStatusResource struct {
+	Enabled                bool    `json:"enabled"`
+	Required               bool    `json:"required"`
+	ConfirmedAt            *string `json:"confirmedAt"`
+	RecoveryCodesRemaining int64   `json:"recoveryCodesRemaining"`
+}
+
+// TwoFactorSetupResource is returned once when enrolling, for the user to add
+// the secret to an authenticator app.
+type TwoFactorSetupResource struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+	QRCode          string `json:"qrCode"`
+}
+
+// TwoFactorGet returns the enrollment of the user, or nil when there is none.
+func (m *ModelResource) TwoFactorGet(accountType string, userId uint) (*TwoFactor, error) {
+	var twoFactors []*TwoFactor
+	err := m.db.Client.Where("account_type = ? AND user_id = ?", accountType, userId).Limit(1).Find(&twoFactors).Error
+	if err != nil || len(twoFactors) == 0 {
+		return nil, err
+	}
+	return twoFactors[0], nil
+}
+
+// TwoFactorRoleRequires reports whether the role of the user requires
+// two-factor authentication.
+func (m *ModelResource) TwoFactorRoleRequires(accountType string, userId uint) (bool, error) {
+	table, err := m.accountTable(accountType)
+	if err != nil {
+		return false, err
+	}
+	var required sql.NullBool
+	err = m.db.Client.Table(table).
+		Select("roles.require_two_factor").
+		Joins("JOIN roles ON roles.id = "+table+".role_id AND roles.deleted_at IS NULL").
+		Where(table+".id = ?", userId).
+		Row().Scan(&required)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return required.Valid && required.Bool, nil
+}
+
+// TwoFactorRequired reports whether sign-in needs a second factor, either
+// because the user enabled it or because their role requires it.
+func (m *ModelResource) TwoFactorRequired(accountType string, userId uint) (bool, error) {
+	twoFactor, err := m.TwoFactorGet(accountType, userId)
+	if err != nil {
+		return false, err
+	}
+	if twoFactor != nil && twoFactor.Enabled {
+		return true, nil
+	}
+	return m.TwoFactorRoleRequires(accountType, userId)
+}
+
+// TwoFactorStatus summarizes the enrollment of the user.
+func (m *ModelResource) TwoFactorStatus(accountType string, userId uint) (*TwoFactorStatusResource, error) {
+	status := &TwoFactorStatusResource{}
+	required, err := m.TwoFactorRoleRequires(accountType, userId)
+	if err != nil {
+		return nil, err
+	}
+	status.Required = required
+	twoFactor, err := m.TwoFactorGet(accountType, userId)
+	if err != nil || twoFactor == nil || !twoFactor.Enabled {
+		return status, err
+	}
+	status.Enabled = true
+	if twoFactor.ConfirmedAt != nil {
+		confirmedAt := twoFactor.ConfirmedAt.Format(time.RFC3339)
+		status.ConfirmedAt = &confirmedAt
+	}
+	err = m.db.Client.Model(&TwoFactorRecoveryCode{}).
+		Where("two_factor_id = ? AND used_at IS NULL", twoFactor.ID).
+		Count(&status.RecoveryCodesRemaining).Error
+	return status, err
+}
+
+// TwoFactorSetup starts, or restarts, an enrollment with a new secret. The
+// enrollment stays disabled until TwoFactorConfirm.
+func (m *ModelResource) TwoFactorSetup(accountType string, userId uint) (string, error) {
+	twoFactor, err := m.TwoFactorGet(accountType, userId)
+	if err != nil {
+		return "", err
+	}
+	if twoFactor != nil && twoFactor.Enabled {
+		return "", ErrTwoFactorEnabled
+	}
+	secret, err := m.cryptoHelpers.GenerateTOTPSecret()
+	if err != nil {
+		return "", err
+	}
+	encrypted, err := m.cryptoHelpers.Encrypt(secret)
+	if err != nil {
+		return "", err
+	}
+	if twoFactor == nil {
+		twoFactor = &TwoFactor{AccountType: accountType, UserID: userId}
+	}
+	twoFactor.Secret = encrypted
+	twoFactor.LastUsedStep = 0
+	if err := m.db.Client.Save(twoFactor).Error; err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// TwoFactorConfirm enables a pending enrollment once code matches its secret
+// and returns the plaintext recovery codes, which are never shown again.
+func (m *ModelResource) TwoFactorConfirm(accountType string, userId uint, code string) ([]string, error) {
+	twoFactor, err := m.TwoFactorGet(accountType, userId)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	if twoFactor.Enabled {
+		return nil, ErrTwoFactorEnabled
+	}
+	step, err := m.twoFactorVerifyTOTP(twoFactor, code)
+	if err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err = m.db.Client.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(twoFactor).Updates(map[string]interface{}{
+			"enabled":        true,
+			"confirmed_at":   &now,
+			"last_used_step": step,
+		}).Error; err != nil {
+			return err
+		}
+		codes, err = m.twoFactorReplaceRecoveryCodes(tx, twoFactor.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// TwoFactorVerify checks a TOTP code, or else a recovery code, for the
+// enabled enrollment of the user. Accepted codes cannot be used again.
+func (m *ModelResource) TwoFactorVerify(accountType string, userId uint, code string) error {
+	twoFactor, err := m.TwoFactorGet(accountType, userId)
+	if err != nil {
+		return err
+	}
+	if twoFactor == nil || !twoFactor.Enabled {
+		return ErrTwoFactorNotEnrolled
+	}
+
+	step, err := m.twoFactorVerifyTOTP(twoFactor, code)
+	if err == nil {
+		// Only the first request to present the code advances the step
+		result := m.db.Client.Model(&TwoFactor{}).
+			Where("id = ? AND last_used_step < ?", twoFactor.ID, step).
+			Update("last_used_step", step)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTwoFactorInvalidCode
+		}
+		return nil
+	}
+	if !errors.Is(err, ErrTwoFactorInvalidCode) {
+		return err
+	}
+
+	result := m.db.Client.Model(&TwoFactorRecoveryCode{}).
+		Where("two_factor_id = ? AND code_hash = ? AND used_at IS NULL", twoFactor.ID, m.cryptoHelpers.HashToken(code)).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTwoFactorInvalidCode
+	}
+	return nil
+}
+
+// TwoFactorRegenerateRecoveryCodes replaces every recovery code of the user.
+func (m *ModelResource) TwoFactorRegenerateRecoveryCodes(accountType string, userId uint) ([]string, error) {
+	twoFactor, err := m.TwoFactorGet(accountType, userId)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil || !twoFactor.Enabled {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	var codes []string
+	err = m.db.Client.Transaction(func(tx *gorm.DB) error {
+		codes, err = m.twoFactorReplaceRecoveryCodes(tx, twoFactor.ID)
+		return err
+	})
+	return codes, err
+}
+
+// TwoFactorDisable removes the enrollment of the user, unless their role
+// requires two-factor authentication.
+func (m *ModelResource) TwoFactorDisable(accountType string, userId uint) error {
+	required, err := m.TwoFactorRoleRequires(accountType, userId)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrTwoFactorRequired
+	}
+	twoFactor, err := m.TwoFactorGet(accountType, userId)
+	if err != nil {
+		return err
+	}
+	if twoFactor == nil {
+		return ErrTwoFactorNotEnrolled
+	}
+	return m.db.Client.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("two_factor_id = ?", twoFactor.ID).Delete(&TwoFactorRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(twoFactor).Error
+	})
+}
+
+func (m *ModelResource) twoFactorVerifyTOTP(twoFactor *TwoFactor, code string) (int64, error) {
+	secret, err := m.cryptoHelpers.Decrypt(twoFactor.Secret)
+	if err != nil {
+		return 0, err
+	}
+	step, ok := m.cryptoHelpers.VerifyTOTP(secret, code, time.Now())
+	if !ok || step <= twoFactor.LastUsedStep {
+		return 0, ErrTwoFactorInvalidCode
+	}
+	return step, nil
+}
+
+func (m *ModelResource) twoFactorReplaceRecoveryCodes(tx *gorm.DB, twoFactorId uint) ([]string, error) {
+	codes, err := m.cryptoHelpers.GenerateRecoveryCodes(TwoFactorRecoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Unscoped().Where("two_factor_id = ?", twoFactorId).Delete(&TwoFactorRecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+	recoveryCodes := make([]*TwoFactorRecoveryCode, 0, len(codes))
+	for _, code := range codes {
+		recoveryCodes = append(recoveryCodes, &TwoFactorRecoveryCode{
+			TwoFactorID: twoFactorId,
+			CodeHash:    m.cryptoHelpers.HashToken(code),
+		})
+	}
+	if err := tx.Create(&recoveryCodes).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (m *ModelResource) TwoFactorSeeders() error {
+	m.logger.Info("Seeding TwoFactor")
+	return nil
+}
+
+func (m *ModelResource) TwoFactorRecoveryCodeSeeders() error {
+	m.logger.Info("Seeding TwoFactorRecoveryCode")
+	return nil
+}
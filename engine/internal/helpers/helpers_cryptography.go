@@ -1,11 +1,25 @@
 package helpers
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
 	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/config"
 	"golang.org/x/crypto/bcrypt"
@@ -67,3 +81,147 @@ func (hc *HelpersCryptography) GenerateSecureRandom6DigitNumber() (int, error) {
 
 	return int(n.Int64() + min), nil
 }
+
+// GenerateSecureToken returns size random bytes encoded as URL-safe base64,
+// for opaque tokens such as sign-in challenges.
+func (hc *HelpersCryptography) GenerateSecureToken(size int) (string, error) {
+	token := make([]byte, size)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// TOTP parameters of RFC 6238 as used by authenticator apps.
+const (
+	totpDigits = 6
+	totpPeriod = 30
+	// totpSkew is the number of periods accepted before and after the current
+	// one, to tolerate clock drift.
+	totpSkew = 1
+)
+
+// GenerateTOTPSecret generates a random 160-bit TOTP secret, base32 encoded.
+func (hc *HelpersCryptography) GenerateTOTPSecret() (string, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate TOTP secret: %w", err)
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(secret), nil
+}
+
+// TOTPProvisioningURI returns the otpauth:// URI encoded in enrollment QR
+// codes.
+func (hc *HelpersCryptography) TOTPProvisioningURI(issuer, account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", strconv.Itoa(totpDigits))
+	query.Set("period", strconv.Itoa(totpPeriod))
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// VerifyTOTP checks code against secret at time at and returns the time step
+// it matched, so callers can reject codes that were already used.
+func (hc *HelpersCryptography) VerifyTOTP(secret, code string, at time.Time) (int64, bool) {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(secret))
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+	step := at.Unix() / totpPeriod
+	for offset := int64(-totpSkew); offset <= totpSkew; offset++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step+offset)), []byte(code)) == 1 {
+			return step + offset, true
+		}
+	}
+	return 0, false
+}
+
+// totpCode computes the HOTP value of RFC 4226 for counter.
+func totpCode(key []byte, counter int64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%uint32(math.Pow10(totpDigits)))
+}
+
+// GenerateRecoveryCodes generates count single-use codes formatted as
+// xxxxx-xxxxx.
+func (hc *HelpersCryptography) GenerateRecoveryCodes(count int) ([]string, error) {
+	const alphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+	codes := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		code := make([]byte, 11)
+		for j := range code {
+			if j == 5 {
+				code[j] = '-'
+				continue
+			}
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+			}
+			code[j] = alphabet[n.Int64()]
+		}
+		codes = append(codes, string(code))
+	}
+	return codes, nil
+}
+
+// HashToken hashes a high-entropy token, such as a recovery code, with the
+// token hash key. Unlike HashPassword it is deterministic, so hashes can be
+// looked up directly.
+func (hc *HelpersCryptography) HashToken(token string) string {
+	mac := hmac.New(sha256.New, hc.cfg.TokenHashKey)
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(token))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Encrypt encrypts plaintext with AES-GCM under a key derived from the token
+// hash key.
+func (hc *HelpersCryptography) Encrypt(plaintext string) (string, error) {
+	gcm, err := hc.gcm()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value produced by Encrypt.
+func (hc *HelpersCryptography) Decrypt(ciphertext string) (string, error) {
+	gcm, err := hc.gcm()
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", errors.New("invalid ciphertext")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+func (hc *HelpersCryptography) gcm() (cipher.AEAD, error) {
+	// The encryption key is derived apart from the HashToken key
+	mac := hmac.New(sha256.New, hc.cfg.TokenHashKey)
+	mac.Write([]byte("encryption"))
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -3,6 +3,7 @@ package helpers
 import (
 	"errors"
 	"fmt"
+	"math"
 	"net/mail"
 	"path/filepath"
 	"regexp"
@@ -142,6 +143,20 @@ func (hf *HelpersFunction) FormatValidationError(err error) error {
 	return err
 }
 
+// earthRadiusMeters is the mean radius of the Earth.
+const earthRadiusMeters = 6371008.8
+
+// HaversineDistance returns the great-circle distance in meters between two
+// points given in degrees.
+func (hf *HelpersFunction) HaversineDistance(lat1, lon1, lat2, lon2 float64) float64 {
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
 func (hf *HelpersFunction) GetKeyType(key string) string {
 
 	trimmedKey := strings.TrimSpace(key)
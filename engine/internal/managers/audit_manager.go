@@ -0,0 +1,346 @@
+package managers
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// auditRedacted replaces the values of sensitive columns in audit diffs.
+const auditRedacted = "[REDACTED]"
+
+// auditBeforeKey is the statement instance key holding the rows captured
+// before an update or delete.
+const auditBeforeKey = "audit:before"
+
+// auditIgnoredColumns change on every write and carry no information.
+var auditIgnoredColumns = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+}
+
+// auditSensitiveColumns are recorded as changed without their values.
+var auditSensitiveColumns = []string{"password", "secret", "token", "hash"}
+
+// AuditActor identifies who made a change and from which request. A zero
+// actor means the change was made by the system, e.g. by a seeder.
+type AuditActor struct {
+	AccountType string
+	ID          *uint
+	// ImpersonatorID is the Admin acting as the actor, if any
+	ImpersonatorID *uint
+	RequestID      string
+	IP             string
+}
+
+// AuditChange is the value of a column before and after a change. Old is nil
+// on create and New is nil on delete.
+type AuditChange struct {
+	Old interface{} `json:"old,omitempty"`
+	New interface{} `json:"new,omitempty"`
+}
+
+// AuditEntry is a single audited change to one row.
+type AuditEntry struct {
+	Actor      AuditActor
+	EntityType string
+	EntityID   uint
+	Action     string
+	Changes    map[string]AuditChange
+}
+
+// AuditRecorder persists entries using tx, the connection of the audited
+// statement, so they are committed or rolled back with the change itself.
+type AuditRecorder func(tx *gorm.DB, entries []AuditEntry) error
+
+type auditActorKey struct{}
+
+// WithAuditActor returns a copy of ctx carrying actor. Queries run with that
+// context, e.g. through Repository.WithContext, are attributed to actor.
+func WithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// AuditActorFromContext returns the actor stored in ctx by WithAuditActor.
+func AuditActorFromContext(ctx context.Context) (AuditActor, bool) {
+	if ctx == nil {
+		return AuditActor{}, false
+	}
+	actor, ok := ctx.Value(auditActorKey{}).(AuditActor)
+	return actor, ok
+}
+
+// RegisterAuditCallbacks records every create, update and delete made through
+// db with record. Updates and deletes snapshot the affected rows beforehand so
+// entries hold the diff of the changed columns. Tables listed in ignore, such
+// as the audit table itself, are not audited.
+func RegisterAuditCallbacks(db *gorm.DB, record AuditRecorder, ignore ...string) error {
+	a := &auditor{record: record, ignore: map[string]bool{}}
+	for _, table := range ignore {
+		a.ignore[table] = true
+	}
+
+	// Every callback runs within the transaction of the statement, so entries
+	// are written, or rolled back, together with the change they record
+	const (
+		begin  = "gorm:begin_transaction"
+		commit = "gorm:commit_or_rollback_transaction"
+	)
+	callbacks := db.Callback()
+	if err := callbacks.Create().After("gorm:create").Before(commit).Register("audit:after_create", a.afterCreate); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After(begin).Before("gorm:update").Register("audit:before_update", a.before); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Before(commit).Register("audit:after_update", a.afterUpdate); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After(begin).Before("gorm:delete").Register("audit:before_delete", a.before); err != nil {
+		return err
+	}
+	return callbacks.Delete().After("gorm:delete").Before(commit).Register("audit:after_delete", a.afterDelete)
+}
+
+type auditor struct {
+	record AuditRecorder
+	ignore map[string]bool
+}
+
+func (a *auditor) skip(db *gorm.DB) bool {
+	return db.Error != nil || db.Statement.Schema == nil || db.Statement.Schema.PrioritizedPrimaryField == nil ||
+		a.ignore[db.Statement.Table]
+}
+
+func (a *auditor) afterCreate(db *gorm.DB) {
+	if a.skip(db) || db.Statement.RowsAffected == 0 {
+		return
+	}
+	entries := []AuditEntry{}
+	eachAuditRow(db.Statement.ReflectValue, func(row reflect.Value) {
+		id, values := auditSnapshot(db.Statement.Context, db.Statement.Schema, row)
+		changes := map[string]AuditChange{}
+		for column, value := range values {
+			if value != nil {
+				changes[column] = AuditChange{New: auditValue(column, value)}
+			}
+		}
+		entries = append(entries, a.entry(db, id, AuditActionCreate, changes))
+	})
+	a.save(db, entries)
+}
+
+// before captures the rows matched by an update or delete. Statements without
+// conditions are left alone, GORM rejects them anyway.
+func (a *auditor) before(db *gorm.DB) {
+	if a.skip(db) {
+		return
+	}
+	stmt := db.Statement
+	query := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Model(reflect.New(stmt.Schema.ModelType).Interface())
+	if stmt.Unscoped {
+		query = query.Unscoped()
+	}
+
+	conditions := false
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) > 0 {
+			query = query.Clauses(where)
+			conditions = true
+		}
+	}
+	if ids := auditPrimaryKeys(db, stmt.ReflectValue); len(ids) > 0 {
+		query = query.Where(clause.IN{Column: clause.PrimaryColumn, Values: ids})
+		conditions = true
+	}
+	if !conditions {
+		return
+	}
+
+	rows, err := auditFind(query, stmt.Schema)
+	if err != nil {
+		db.AddError(fmt.Errorf("failed to load rows for audit: %w", err))
+		return
+	}
+	db.InstanceSet(auditBeforeKey, rows)
+}
+
+func (a *auditor) afterUpdate(db *gorm.DB) {
+	if a.skip(db) || db.Statement.RowsAffected == 0 {
+		return
+	}
+	before := auditBefore(db)
+	if len(before) == 0 {
+		return
+	}
+	stmt := db.Statement
+	ids := make([]interface{}, 0, len(before))
+	for id := range before {
+		ids = append(ids, id)
+	}
+	query := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Model(reflect.New(stmt.Schema.ModelType).Interface()).
+		Unscoped().Where(clause.IN{Column: clause.PrimaryColumn, Values: ids})
+	after, err := auditFind(query, stmt.Schema)
+	if err != nil {
+		db.AddError(fmt.Errorf("failed to load rows for audit: %w", err))
+		return
+	}
+
+	entries := []AuditEntry{}
+	for id, old := range before {
+		changes := map[string]AuditChange{}
+		for column, value := range after[id] {
+			if !reflect.DeepEqual(old[column], value) {
+				changes[column] = AuditChange{Old: auditValue(column, old[column]), New: auditValue(column, value)}
+			}
+		}
+		if len(changes) > 0 {
+			entries = append(entries, a.entry(db, id, AuditActionUpdate, changes))
+		}
+	}
+	a.save(db, entries)
+}
+
+func (a *auditor) afterDelete(db *gorm.DB) {
+	if a.skip(db) || db.Statement.RowsAffected == 0 {
+		return
+	}
+	entries := []AuditEntry{}
+	for id, old := range auditBefore(db) {
+		changes := map[string]AuditChange{}
+		for column, value := range old {
+			if value != nil {
+				changes[column] = AuditChange{Old: auditValue(column, value)}
+			}
+		}
+		entries = append(entries, a.entry(db, id, AuditActionDelete, changes))
+	}
+	a.save(db, entries)
+}
+
+func (a *auditor) entry(db *gorm.DB, id uint, action string, changes map[string]AuditChange) AuditEntry {
+	actor, _ := AuditActorFromContext(db.Statement.Context)
+	return AuditEntry{
+		Actor:      actor,
+		EntityType: db.Statement.Table,
+		EntityID:   id,
+		Action:     action,
+		Changes:    changes,
+	}
+}
+
+func (a *auditor) save(db *gorm.DB, entries []AuditEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	tx := db.Session(&gorm.Session{NewDB: true, SkipHooks: true})
+	if err := a.record(tx, entries); err != nil {
+		db.AddError(fmt.Errorf("failed to record audit: %w", err))
+	}
+}
+
+func auditBefore(db *gorm.DB) map[uint]map[string]interface{} {
+	before, ok := db.InstanceGet(auditBeforeKey)
+	if !ok {
+		return nil
+	}
+	rows, _ := before.(map[uint]map[string]interface{})
+	return rows
+}
+
+// auditFind loads the rows of query keyed by primary key.
+func auditFind(query *gorm.DB, s *schema.Schema) (map[uint]map[string]interface{}, error) {
+	rows := reflect.New(reflect.SliceOf(s.ModelType))
+	if err := query.Find(rows.Interface()).Error; err != nil {
+		return nil, err
+	}
+	result := map[uint]map[string]interface{}{}
+	eachAuditRow(rows.Elem(), func(row reflect.Value) {
+		id, values := auditSnapshot(query.Statement.Context, s, row)
+		result[id] = values
+	})
+	return result, nil
+}
+
+// auditSnapshot returns the primary key and the column values of row.
+func auditSnapshot(ctx context.Context, s *schema.Schema, row reflect.Value) (uint, map[string]interface{}) {
+	values := map[string]interface{}{}
+	for _, field := range s.Fields {
+		if field.DBName == "" || auditIgnoredColumns[field.DBName] {
+			continue
+		}
+		value, zero := field.ValueOf(ctx, row)
+		if zero && reflect.ValueOf(value).Kind() == reflect.Ptr {
+			value = nil
+		} else if valuer, ok := value.(driver.Valuer); ok {
+			value, _ = valuer.Value()
+		}
+		values[field.DBName] = value
+	}
+	id, _ := s.PrioritizedPrimaryField.ValueOf(ctx, row)
+	return auditUint(id), values
+}
+
+// auditPrimaryKeys returns the non-zero primary keys of the statement's model.
+func auditPrimaryKeys(db *gorm.DB, value reflect.Value) []interface{} {
+	ids := []interface{}{}
+	field := db.Statement.Schema.PrioritizedPrimaryField
+	eachAuditRow(value, func(row reflect.Value) {
+		if id, zero := field.ValueOf(db.Statement.Context, row); !zero {
+			ids = append(ids, id)
+		}
+	})
+	return ids
+}
+
+// eachAuditRow calls fn for every struct in value, a struct or a slice of
+// structs or pointers to structs.
+func eachAuditRow(value reflect.Value, fn func(row reflect.Value)) {
+	value = reflect.Indirect(value)
+	switch value.Kind() {
+	case reflect.Struct:
+		fn(value)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if row := reflect.Indirect(value.Index(i)); row.Kind() == reflect.Struct {
+				fn(row)
+			}
+		}
+	}
+}
+
+// auditValue hides the value of sensitive columns.
+func auditValue(column string, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	for _, sensitive := range auditSensitiveColumns {
+		if strings.Contains(column, sensitive) {
+			return auditRedacted
+		}
+	}
+	return value
+}
+
+func auditUint(value interface{}) uint {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return uint(v.Uint())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return uint(v.Int())
+	}
+	return 0
+}
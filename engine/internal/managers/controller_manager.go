@@ -10,6 +10,10 @@ import (
 	"gorm.io/gorm"
 )
 
+// TenantScopeKey is the context key holding the ScopeFunc of the current
+// request. When set, the controller only sees rows within that scope.
+const TenantScopeKey = "tenantScope"
+
 type DeleteManyRequest struct {
 	IDs []uint `json:"ids" binding:"required,min=1"`
 }
@@ -38,6 +42,28 @@ func NewController[T any, V any, R any](
 	}
 }
 
+// repo returns the repository bound to the request context and restricted to
+// the tenant scope of the request, if any.
+func (h *Controller[T, V, R]) repo(c *gin.Context) *Repository[T] {
+	repo := h.Repo.WithContext(c.Request.Context())
+	if scope, ok := c.Get(TenantScopeKey); ok {
+		if scopeFunc, ok := scope.(ScopeFunc); ok {
+			return repo.WithScope(scopeFunc)
+		}
+	}
+	return repo
+}
+
+// scoped reports whether the request is restricted to a tenant scope.
+func scoped(c *gin.Context) bool {
+	scope, ok := c.Get(TenantScopeKey)
+	if !ok {
+		return false
+	}
+	_, ok = scope.(ScopeFunc)
+	return ok
+}
+
 // Helper function to extract preload parameters from the request
 func getPreloads(c *gin.Context) []string {
 	preloads := c.QueryArray("preloads")
@@ -45,6 +71,19 @@ func getPreloads(c *gin.Context) []string {
 }
 
 func (h *Controller[T, V, R]) Create(c *gin.Context) {
+	h.CreateWith(nil)(c)
+}
+
+// CreateWith returns a Create handler that lets set fill the fields of the new
+// entity that are kept out of V, such as the owner of a company, before it is
+// stored. set may abort the request by responding and returning an error.
+func (h *Controller[T, V, R]) CreateWith(set func(c *gin.Context, entity *T) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h.create(c, set)
+	}
+}
+
+func (h *Controller[T, V, R]) create(c *gin.Context, set func(c *gin.Context, entity *T) error) {
 
 	var req V
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -62,11 +101,42 @@ func (h *Controller[T, V, R]) Create(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to map create data"})
 		return
 	}
+	if set != nil {
+		if err := set(c, entity); err != nil {
+			return
+		}
+	}
 
 	preloads := getPreloads(c)
 
-	if err := h.Repo.Create(entity, preloads...); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	repo := h.repo(c)
+	if !scoped(c) {
+		if err := repo.Create(entity, preloads...); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, h.Resource(entity))
+		return
+	}
+
+	// Scoped callers may only create rows they can see afterwards
+	err = repo.Transaction(func(tx *Repository[T]) error {
+		if err := tx.Create(entity, preloads...); err != nil {
+			return err
+		}
+		id, err := entityID(entity)
+		if err != nil {
+			return err
+		}
+		_, err = tx.FindByID(id)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Entity is outside of your scope"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 	c.JSON(http.StatusCreated, h.Resource(entity))
@@ -81,7 +151,7 @@ func (h *Controller[T, V, R]) GetByID(c *gin.Context) {
 	}
 
 	preloads := getPreloads(c)
-	entity, err := h.Repo.FindByID(uint(id), preloads...)
+	entity, err := h.repo(c).FindByID(uint(id), preloads...)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
@@ -91,20 +161,18 @@ func (h *Controller[T, V, R]) GetByID(c *gin.Context) {
 
 func (h *Controller[T, V, R]) GetAll(c *gin.Context) {
 	preloads := getPreloads(c)
-	entities, err := h.Repo.FindAll(preloads...)
+	entities, err := h.repo(c).FindAll(preloads...)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	entityPtrs := make([]*T, len(entities))
-	c.JSON(http.StatusOK, h.ResourceList(entityPtrs))
+	c.JSON(http.StatusOK, h.ResourceList(entities))
 }
 
 func (h *Controller[T, V, R]) Update(c *gin.Context) {
 
 	idParam := c.Param("id")
-	id, err := strconv.Atoi(idParam)
-	if err != nil {
+	if _, err := strconv.Atoi(idParam); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
@@ -124,10 +192,29 @@ func (h *Controller[T, V, R]) Update(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to map update data"})
 		return
 	}
+	h.UpdateWith(c, updates)
+}
+
+// UpdateWith updates the entity with the id of the URL within the scope of the
+// request, like Update does with the request body. Handlers use it for fields
+// that are kept out of V, such as the role of a user. Zero fields of updates
+// are left unchanged.
+func (h *Controller[T, V, R]) UpdateWith(c *gin.Context, updates *T) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
 
 	preloads := getPreloads(c)
 
-	updatedEntity, err := h.Repo.UpdateByID(uint(id), updates, preloads...)
+	// The update runs in a transaction so that moving a row out of the caller's
+	// scope fails on reload and is rolled back
+	var updatedEntity *T
+	err = h.repo(c).Transaction(func(tx *Repository[T]) error {
+		updatedEntity, err = tx.UpdateByID(uint(id), updates, preloads...)
+		return err
+	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Entity not found"})
@@ -147,8 +234,12 @@ func (h *Controller[T, V, R]) Delete(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.Repo.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.repo(c).Delete(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Entity not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Entity deleted successfully"})
@@ -178,7 +269,7 @@ func (h *Controller[T, V, R]) DeleteMany(c *gin.Context) {
 		return
 	}
 
-	if err := h.Repo.DeleteMany(req.IDs); err != nil {
+	if err := h.repo(c).DeleteMany(req.IDs); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "One or more entities not found"})
 		} else {
@@ -2,8 +2,11 @@ package managers
 
 import (
 	"encoding/csv"
+	"fmt"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
 )
 
 // CSVManager manages CSV file creation with configurable parameters.
@@ -103,3 +106,122 @@ func (cm *CSVManager) WriteCSV(c *gin.Context) error {
 
 	return nil
 }
+
+// XLSXExporter streams rows into an Excel workbook. Rows are written through
+// excelize's StreamWriter, which spills to a temporary file once large, so
+// memory stays bounded regardless of the number of rows.
+type XLSXExporter struct {
+	ctx         *gin.Context
+	file        *excelize.File
+	stream      *excelize.StreamWriter
+	row         int
+	headerStyle int
+	dateStyle   int
+	numberStyle int
+}
+
+// NewXLSXExporter sets the download headers and returns an XLSX exporter
+// writing to a single "Export" sheet.
+func NewXLSXExporter(c *gin.Context, fileName string) (*XLSXExporter, error) {
+	file := excelize.NewFile()
+	sheet := "Export"
+	if err := file.SetSheetName("Sheet1", sheet); err != nil {
+		return nil, err
+	}
+	headerStyle, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return nil, err
+	}
+	// 22 is the built-in "m/d/yy h:mm" format, 4 is "#,##0.00"
+	dateStyle, err := file.NewStyle(&excelize.Style{NumFmt: 22})
+	if err != nil {
+		return nil, err
+	}
+	numberStyle, err := file.NewStyle(&excelize.Style{NumFmt: 4})
+	if err != nil {
+		return nil, err
+	}
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Disposition", "attachment; filename="+fileName)
+	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+
+	return &XLSXExporter{
+		ctx:         c,
+		file:        file,
+		stream:      stream,
+		row:         1,
+		headerStyle: headerStyle,
+		dateStyle:   dateStyle,
+		numberStyle: numberStyle,
+	}, nil
+}
+
+// WriteHeader sets the column widths, freezes the header row and writes it.
+// Columns without a width are sized from their header.
+func (xe *XLSXExporter) WriteHeader(columns []ExportColumn) error {
+	headers := make([]interface{}, len(columns))
+	for i, column := range columns {
+		width := column.Width
+		if width == 0 {
+			width = float64(len(column.Header) + 4)
+		}
+		if err := xe.stream.SetColWidth(i+1, i+1, width); err != nil {
+			return err
+		}
+		headers[i] = excelize.Cell{StyleID: xe.headerStyle, Value: column.Header}
+	}
+	if err := xe.stream.SetPanes(&excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return err
+	}
+	return xe.writeRow(headers)
+}
+
+func (xe *XLSXExporter) WriteRow(values []interface{}) error {
+	cells := make([]interface{}, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case time.Time:
+			cells[i] = excelize.Cell{StyleID: xe.dateStyle, Value: v}
+		case *time.Time:
+			if v != nil {
+				cells[i] = excelize.Cell{StyleID: xe.dateStyle, Value: *v}
+			}
+		case float32, float64:
+			cells[i] = excelize.Cell{StyleID: xe.numberStyle, Value: v}
+		default:
+			cells[i] = v
+		}
+	}
+	return xe.writeRow(cells)
+}
+
+func (xe *XLSXExporter) writeRow(cells []interface{}) error {
+	cell, err := excelize.CoordinatesToCellName(1, xe.row)
+	if err != nil {
+		return err
+	}
+	if err := xe.stream.SetRow(cell, cells); err != nil {
+		return fmt.Errorf("failed to write row %d: %w", xe.row, err)
+	}
+	xe.row++
+	return nil
+}
+
+// Close flushes the stream and writes the workbook to the response.
+func (xe *XLSXExporter) Close() error {
+	defer xe.file.Close()
+	if err := xe.stream.Flush(); err != nil {
+		return err
+	}
+	return xe.file.Write(xe.ctx.Writer)
+}
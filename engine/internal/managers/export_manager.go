@@ -0,0 +1,147 @@
+package managers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportBatchSize is the number of rows loaded from the database at a time
+// while streaming an export.
+const ExportBatchSize = 500
+
+type ExportFormat string
+
+// ErrUnsupportedExportFormat is returned for a "format" query parameter that
+// names no exporter.
+var ErrUnsupportedExportFormat = errors.New("unsupported export format")
+
+const (
+	ExportCSV  ExportFormat = "csv"
+	ExportXLSX ExportFormat = "xlsx"
+)
+
+// ExportColumn describes a column of an export. Width is in characters and is
+// only used by formats that support it.
+type ExportColumn struct {
+	Header string
+	Width  float64
+}
+
+// Exporter streams tabular data into an HTTP response row by row.
+type Exporter interface {
+	// WriteHeader writes the header row and column settings.
+	WriteHeader(columns []ExportColumn) error
+	// WriteRow writes a single row. Values keep their Go type so typed formats
+	// can emit typed cells (dates, decimals, booleans).
+	WriteRow(values []interface{}) error
+	// Close flushes whatever is still buffered to the response.
+	Close() error
+}
+
+// NewExporter returns the exporter for the format requested by the "format"
+// query parameter, defaulting to CSV. fileName is given without extension.
+func NewExporter(c *gin.Context, fileName string) (Exporter, error) {
+	format := ExportFormat(strings.ToLower(c.DefaultQuery("format", string(ExportCSV))))
+	switch format {
+	case ExportCSV:
+		return NewCSVExporter(c, fileName+".csv"), nil
+	case ExportXLSX:
+		return NewXLSXExporter(c, fileName+".xlsx")
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedExportFormat, format)
+	}
+}
+
+// Export writes the columns and every row produced by stream to the format
+// requested by the client. stream is expected to call its callback once per
+// batch, e.g. Repository.StreamFilteredResults.
+func Export[T any](
+	c *gin.Context,
+	fileName string,
+	columns []ExportColumn,
+	toRow func(entity *T) []interface{},
+	stream func(fn func(batch []*T) error) error,
+) error {
+	exporter, err := NewExporter(c, fileName)
+	if err != nil {
+		return err
+	}
+	if err := exporter.WriteHeader(columns); err != nil {
+		return err
+	}
+	err = stream(func(batch []*T) error {
+		for _, entity := range batch {
+			if err := exporter.WriteRow(toRow(entity)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return exporter.Close()
+}
+
+// CSVExporter streams rows as CSV directly to the response writer.
+type CSVExporter struct {
+	writer *csv.Writer
+}
+
+// NewCSVExporter sets the download headers and returns a CSV exporter.
+func NewCSVExporter(c *gin.Context, fileName string) *CSVExporter {
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Disposition", "attachment; filename="+fileName)
+	c.Header("Content-Type", "text/csv")
+	return &CSVExporter{writer: csv.NewWriter(c.Writer)}
+}
+
+func (ce *CSVExporter) WriteHeader(columns []ExportColumn) error {
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = column.Header
+	}
+	return ce.writer.Write(headers)
+}
+
+func (ce *CSVExporter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = formatCSVValue(value)
+	}
+	return ce.writer.Write(record)
+}
+
+func (ce *CSVExporter) Close() error {
+	ce.writer.Flush()
+	return ce.writer.Error()
+}
+
+// formatCSVValue renders a typed export value as CSV text.
+func formatCSVValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,156 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// BucketAlias is the result key holding the date bucket of each row.
+const BucketAlias = "bucket"
+
+var aliasPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ApplyAggregate selects the requested metrics grouped by the requested fields
+// and optional date bucket. Every field must be a real column of model, and the
+// rows are ordered by the grouping so results are deterministic.
+func ApplyAggregate(db *gorm.DB, request AggregateRequest, model interface{}) (*gorm.DB, error) {
+	modelSchema, err := parseSchema(db, model)
+	if err != nil {
+		return db, err
+	}
+
+	selects := []string{}
+	groups := []string{}
+
+	for _, groupBy := range request.GroupBy {
+		field, err := lookUpColumn(modelSchema, groupBy)
+		if err != nil {
+			return db, err
+		}
+		column := db.Statement.Quote(clause.Column{Table: modelSchema.Table, Name: field.DBName})
+		selects = append(selects, fmt.Sprintf("%s AS %s", column, db.Statement.Quote(field.DBName)))
+		groups = append(groups, column)
+	}
+
+	if request.DateBucket != "" {
+		if request.DateField == "" {
+			return db, errors.New("dateField is required when dateBucket is set")
+		}
+		field, err := lookUpColumn(modelSchema, request.DateField)
+		if err != nil {
+			return db, err
+		}
+		if field.DataType != schema.Time {
+			return db, fmt.Errorf("dateField %s is not a date column", request.DateField)
+		}
+		bucket, err := getDateBucketSyntax(db, DateBucket(strings.ToLower(string(request.DateBucket))))
+		if err != nil {
+			return db, err
+		}
+		expression := bucket(db.Statement.Quote(clause.Column{Table: modelSchema.Table, Name: field.DBName}))
+		selects = append(selects, fmt.Sprintf("%s AS %s", expression, db.Statement.Quote(BucketAlias)))
+		groups = append(groups, expression)
+	}
+
+	metrics := request.Metrics
+	if len(metrics) == 0 {
+		metrics = []AggregateMetric{{Func: AggregateCount}}
+	}
+	for _, metric := range metrics {
+		selectSQL, err := metricSyntax(db, modelSchema, metric)
+		if err != nil {
+			return db, err
+		}
+		selects = append(selects, selectSQL)
+	}
+
+	db = db.Model(model).Select(strings.Join(selects, ", "))
+	for _, group := range groups {
+		db = db.Group(group).Order(group)
+	}
+	return db, nil
+}
+
+// metricSyntax builds the select expression of a metric, e.g. SUM(`hours`) AS `sum_hours`.
+func metricSyntax(db *gorm.DB, modelSchema *schema.Schema, metric AggregateMetric) (string, error) {
+	fn := AggregateFunc(strings.ToLower(string(metric.Func)))
+	switch fn {
+	case AggregateCount, AggregateSum, AggregateAvg, AggregateMin, AggregateMax:
+	default:
+		return "", fmt.Errorf("invalid aggregate function: %s", metric.Func)
+	}
+
+	argument := "*"
+	alias := string(fn)
+	if metric.Field != "" {
+		field, err := lookUpColumn(modelSchema, metric.Field)
+		if err != nil {
+			return "", err
+		}
+		argument = db.Statement.Quote(clause.Column{Table: modelSchema.Table, Name: field.DBName})
+		alias = fmt.Sprintf("%s_%s", fn, field.DBName)
+	} else if fn != AggregateCount {
+		return "", fmt.Errorf("aggregate function %s requires a field", fn)
+	}
+
+	if metric.Alias != "" {
+		if !aliasPattern.MatchString(metric.Alias) {
+			return "", fmt.Errorf("invalid aggregate alias: %s", metric.Alias)
+		}
+		alias = metric.Alias
+	}
+	return fmt.Sprintf("%s(%s) AS %s", strings.ToUpper(string(fn)), argument, db.Statement.Quote(alias)), nil
+}
+
+// lookUpColumn resolves a field name to a column of the model.
+func lookUpColumn(modelSchema *schema.Schema, name string) (*schema.Field, error) {
+	field := modelSchema.LookUpField(sanitizeField(toSnakeCase(name)))
+	if field == nil || field.DBName == "" {
+		return nil, fmt.Errorf("invalid field: %s", name)
+	}
+	return field, nil
+}
+
+func getDateBucketSyntax(db *gorm.DB, bucket DateBucket) (func(string) string, error) {
+	switch bucket {
+	case BucketDay, BucketWeek, BucketMonth:
+	default:
+		return nil, fmt.Errorf("invalid date bucket: %s", bucket)
+	}
+
+	switch db.Dialector.Name() {
+	case "postgres":
+		return func(field string) string {
+			return fmt.Sprintf("DATE_TRUNC('%s', %s)", bucket, field)
+		}, nil
+	case "sqlite":
+		return func(field string) string {
+			switch bucket {
+			case BucketWeek:
+				return fmt.Sprintf("DATE(%s, 'weekday 0', '-6 days')", field)
+			case BucketMonth:
+				return fmt.Sprintf("DATE(%s, 'start of month')", field)
+			default:
+				return fmt.Sprintf("DATE(%s)", field)
+			}
+		}, nil
+	default:
+		// MySQL; weeks start on Monday like DATE_TRUNC
+		return func(field string) string {
+			switch bucket {
+			case BucketWeek:
+				return fmt.Sprintf("DATE(DATE_SUB(%s, INTERVAL WEEKDAY(%s) DAY))", field, field)
+			case BucketMonth:
+				return fmt.Sprintf("DATE_FORMAT(%s, '%%Y-%%m-01')", field)
+			default:
+				return fmt.Sprintf("DATE(%s)", field)
+			}
+		}, nil
+	}
+}
@@ -6,195 +6,250 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
 )
 
-// ApplyFilters applies filters and preloads to a GORM query.
-func ApplyFilters(db *gorm.DB, request PaginatedRequest) *gorm.DB {
+// ApplyFilters applies filters and preloads to a GORM query. Invalid filters
+// fail with an error wrapping ErrInvalidFilter rather than being dropped, so a
+// bad request never widens the result set.
+func ApplyFilters(db *gorm.DB, request PaginatedRequest) (*gorm.DB, error) {
 	if err := validatePaginatedRequest(request); err != nil {
-		return db
+		return db, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
 	}
 
-	if strings.ToLower(request.Logic) == "or" {
-		// Group OR conditions
-		var orConditions *gorm.DB
-		for i, filter := range request.Filters {
-			if i == 0 {
-				orConditions = applyFiltering(db, filter)
-			} else {
-				orConditions = orConditions.Or(applyFiltering(db, filter))
-			}
-		}
-		if orConditions != nil {
-			db = db.Where(orConditions)
-		}
-	} else {
-		for _, filter := range request.Filters {
-			db = applyFiltering(db, filter)
-		}
+	db, conditions, err := applyGroup(db, request.RootGroup())
+	if err != nil {
+		return db, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+	}
+	if conditions != nil {
+		db = db.Where(conditions)
 	}
 	for _, preload := range request.Preloads {
 		db = db.Preload(preload)
 	}
-	return db
+	return db, nil
 }
 
-func applyFiltering(db *gorm.DB, filter Filter) *gorm.DB {
-	value := filter.GetValue()
-	dataType := FilterDataType(filter.GetDataType())
+// ApplySorts orders the query by the requested sorts. Each sort field is
+// sanitized and must match a real column of model. The primary key is always
+// appended as a tie-breaker so pages stay stable between requests.
+func ApplySorts(db *gorm.DB, sorts []SortStruct, model interface{}) (*gorm.DB, error) {
+	columns, err := resolveSortColumns(db, sorts, model)
+	if err != nil {
+		return db, err
+	}
+	for _, column := range columns {
+		if column.nulls != "" {
+			db = db.Order(getNullsOrderSyntax(db, column.column, column.nulls))
+		}
+		db = db.Order(clause.OrderByColumn{Column: column.column, Desc: column.desc})
+	}
+	return db, nil
+}
 
-	if filter.IsMultiple() {
-		values := convertToTypedSlice(value, dataType)
-		convertedValues := convertValues(dataType, values)
+// sortColumn is a sort resolved against the model schema.
+type sortColumn struct {
+	field  *schema.Field
+	column clause.Column
+	desc   bool
+	nulls  SortNulls
+}
 
-		if len(convertedValues) > 0 {
-			fieldName := filter.GetField()
-			queryParts := []string{}
-			args := []interface{}{}
+// resolveSortColumns validates the sorts against the columns of model and
+// appends the primary key when the sorts do not already include it.
+func resolveSortColumns(db *gorm.DB, sorts []SortStruct, model interface{}) ([]sortColumn, error) {
+	modelSchema, err := parseSchema(db, model)
+	if err != nil {
+		return nil, err
+	}
 
-			for _, v := range convertedValues {
-				queryParts = append(queryParts, fmt.Sprintf("%s = ?", fieldName))
-				args = append(args, v)
-			}
-			return db.Where(strings.Join(queryParts, " OR "), args...)
+	columns := make([]sortColumn, 0, len(sorts)+1)
+	sortedByPrimaryKey := false
+	for _, sort := range sorts {
+		field := modelSchema.LookUpField(sanitizeField(sort.GetField()))
+		if field == nil || field.DBName == "" {
+			return nil, fmt.Errorf("invalid sort field: %s", sort.Field)
+		}
+
+		nulls := SortNulls(strings.ToLower(string(sort.Nulls)))
+		switch nulls {
+		case "", NullsFirst, NullsLast:
+		default:
+			return nil, fmt.Errorf("invalid sort nulls option: %s", sort.Nulls)
+		}
+
+		columns = append(columns, sortColumn{
+			field:  field,
+			column: clause.Column{Table: modelSchema.Table, Name: field.DBName},
+			desc:   sort.IsDesc(),
+			nulls:  nulls,
+		})
+		if modelSchema.PrioritizedPrimaryField != nil && field.DBName == modelSchema.PrioritizedPrimaryField.DBName {
+			sortedByPrimaryKey = true
 		}
 	}
 
-	return filtering(db, filter, convertValue(dataType, value))
+	if !sortedByPrimaryKey && modelSchema.PrioritizedPrimaryField != nil {
+		columns = append(columns, sortColumn{
+			field:  modelSchema.PrioritizedPrimaryField,
+			column: clause.Column{Table: modelSchema.Table, Name: modelSchema.PrioritizedPrimaryField.DBName},
+		})
+	}
+	return columns, nil
 }
 
-func filtering(db *gorm.DB, filter Filter, value FilterValue) *gorm.DB {
-	field := sanitizeField(filter.GetField())
-	mode := filter.GetMode()
+// applyGroup compiles a filter group into a single condition that GORM wraps in
+// parentheses when nested. Joins required by dotted fields are added to db, while
+// the returned condition only carries where-clauses. A nil condition means the
+// group produced nothing to filter on.
+func applyGroup(db *gorm.DB, group FilterGroup) (*gorm.DB, *gorm.DB, error) {
+	or := strings.ToLower(group.Logic) == "or"
+
+	var conditions *gorm.DB
+	combine := func(condition *gorm.DB) {
+		if condition == nil {
+			return
+		}
+		if conditions == nil {
+			conditions = db.Session(&gorm.Session{NewDB: true}).Where(condition)
+		} else if or {
+			conditions = conditions.Or(condition)
+		} else {
+			conditions = conditions.Where(condition)
+		}
+	}
+
+	for _, filter := range group.Filters {
+		var condition *gorm.DB
+		var err error
+		if db, condition, err = applyFiltering(db, filter); err != nil {
+			return db, nil, err
+		}
+		combine(condition)
+	}
+	for _, subGroup := range group.Groups {
+		var condition *gorm.DB
+		var err error
+		if db, condition, err = applyGroup(db, subGroup); err != nil {
+			return db, nil, err
+		}
+		combine(condition)
+	}
+	return db, conditions, nil
+}
+
+// applyFiltering builds the condition for a single filter on a fresh session.
+// A filter that cannot be applied as asked is an error, never a no-op.
+func applyFiltering(db *gorm.DB, filter Filter) (*gorm.DB, *gorm.DB, error) {
+	value := filter.GetValue()
 	dataType := FilterDataType(filter.GetDataType())
 
-	if strings.Contains(field, ".") {
+	db, resolved, err := resolveField(db, filter.GetField())
+	if err != nil {
+		return db, nil, fmt.Errorf("invalid filter field %s: %w", filter.GetField(), err)
+	}
+	condition := db.Session(&gorm.Session{NewDB: true})
 
-		parts := strings.Split(field, ".")
-		if len(parts) != 2 {
-			fmt.Printf("Invalid field format: %s\n", field)
-			return db
+	if filter.IsMultiple() {
+		convertedValues, err := convertFilterValues(dataType, convertToTypedSlice(value, dataType))
+		if err != nil {
+			return db, nil, fmt.Errorf("invalid value for filter %s: %w", filter.GetField(), err)
 		}
-		tableAlias := toSnakeCase(parts[0]) // e.g., "Owner" -> "owner"
-		column := toSnakeCase(parts[1])
-		if db.Statement.Table == "" {
-			fmt.Println("Base table not set. Please set the table or model.")
-			return db
+		if len(convertedValues) == 0 {
+			return db, nil, fmt.Errorf("filter %s has an empty list of values", filter.GetField())
 		}
 
-		baseTable := db.Statement.Table
-		foreignKey := fmt.Sprintf("%s_id", tableAlias) // Default convention: "owner_id"
+		queryParts := []string{}
+		args := []interface{}{}
+		for _, v := range convertedValues {
+			queryParts = append(queryParts, fmt.Sprintf("%s = ?", resolved.column))
+			args = append(args, v)
+		}
+		return db, resolved.wrap(condition.Where(strings.Join(queryParts, " OR "), args...)), nil
+	}
 
-		// Build the JOIN clause
-		joinClause := fmt.Sprintf(
-			"LEFT JOIN %s ON %s.id = %s.%s",
-			tableAlias,
-			tableAlias,
-			baseTable,
-			foreignKey,
-		)
-		db = db.Joins(joinClause)
-		field = fmt.Sprintf("%s.%s", tableAlias, column)
+	var converted FilterValue
+	switch filter.GetMode() {
+	case ModeRange, ModeBetween:
+		converted, err = convertRangeValue(dataType, value)
+	case ModeIsEmpty, ModeIsNotEmpty, ModeBooleanTrue, ModeBooleanFalse:
+		converted = value
+	default:
+		converted, err = convertFilterValue(dataType, value)
+	}
+	if err != nil {
+		return db, nil, fmt.Errorf("invalid value for filter %s: %w", filter.GetField(), err)
 	}
 
-	// Handle DataTypeTime as before
-	if dataType == DataTypeTime {
-		castTime := getTimeCastSyntax(db)
-		field = castTime(field)
+	condition, err = filtering(condition, resolved.column, filter, converted)
+	if err != nil {
+		return db, nil, fmt.Errorf("filter %s: %w", filter.GetField(), err)
+	}
+	return db, resolved.wrap(condition), nil
+}
+
+// filtering adds the where-clause of filter on field to db, or fails when the
+// mode does not apply to the data type or the value does not fit it.
+func filtering(db *gorm.DB, field string, filter Filter, value FilterValue) (*gorm.DB, error) {
+	mode := filter.GetMode()
+	dataType := FilterDataType(filter.GetDataType())
 
+	if dataType == DataTypeTime {
+		field = getTimeCastSyntax(db)(field)
 		switch v := value.(type) {
-		case string:
-			parsedTime, err := time.Parse("15:04:05", v)
-			if err != nil {
-				fmt.Printf("Invalid time format for value: %v\n", v)
-				return db
-			}
-			value = parsedTime.Format("15:04:05")
 		case time.Time:
 			value = v.Format("15:04:05")
+		case RangeValue:
+			from, fromOk := v.From.(time.Time)
+			to, toOk := v.To.(time.Time)
+			if !fromOk || !toOk {
+				return db, fmt.Errorf("invalid time range: %v", v)
+			}
+			value = RangeValue{From: from.Format("15:04:05"), To: to.Format("15:04:05")}
 		default:
-			fmt.Printf("Unsupported type for DataTypeTime: %T\n", v)
-			return db
+			return db, fmt.Errorf("invalid time: %v", v)
 		}
 	}
 
 	if dataType == DataTypeDate {
-		var dateValue time.Time
-		switch v := value.(type) {
-		case string:
-			parsedDate, err := time.Parse("2006-01-02", v)
-			if err != nil {
-				fmt.Printf("Invalid date format for value: %v\n", v)
-				return db
+		if rangeVal, ok := value.(RangeValue); ok {
+			fromDate, fromOk := rangeVal.From.(time.Time)
+			toDate, toOk := rangeVal.To.(time.Time)
+			if !fromOk || !toOk || (mode != ModeRange && mode != ModeBetween) {
+				return db, fmt.Errorf("invalid date range: %v", rangeVal)
 			}
-			dateValue = parsedDate
-		case time.Time:
-			dateValue = v
-		default:
-			fmt.Printf("Unsupported type for DataTypeDate: %T\n", v)
-			return db
+			startOfFromDay := time.Date(fromDate.Year(), fromDate.Month(), fromDate.Day(), 0, 0, 0, 0, fromDate.Location())
+			endOfToDay := time.Date(toDate.Year(), toDate.Month(), toDate.Day(), 23, 59, 59, 999999999, toDate.Location())
+			return db.Where(fmt.Sprintf("%s BETWEEN ? AND ?", field), startOfFromDay, endOfToDay), nil
 		}
 
+		dateValue, ok := value.(time.Time)
+		if !ok && mode != ModeIsEmpty && mode != ModeIsNotEmpty {
+			return db, fmt.Errorf("invalid date: %v", value)
+		}
 		startOfDay := time.Date(dateValue.Year(), dateValue.Month(), dateValue.Day(), 0, 0, 0, 0, dateValue.Location())
 		endOfDay := time.Date(dateValue.Year(), dateValue.Month(), dateValue.Day(), 23, 59, 59, 999999999, dateValue.Location())
 
-		if mode == ModeEqual || mode == ModeNotEqual {
-			if mode == ModeEqual {
-				return db.Where(fmt.Sprintf("%s BETWEEN ? AND ?", field), startOfDay, endOfDay)
-			} else {
-				return db.Where(fmt.Sprintf("%s NOT BETWEEN ? AND ?", field), startOfDay, endOfDay)
-			}
-		}
-
 		switch mode {
+		case ModeEqual:
+			return db.Where(fmt.Sprintf("%s BETWEEN ? AND ?", field), startOfDay, endOfDay), nil
+		case ModeNotEqual:
+			return db.Where(fmt.Sprintf("%s NOT BETWEEN ? AND ?", field), startOfDay, endOfDay), nil
 		case ModeGreaterThan, ModeAfter:
-			return db.Where(fmt.Sprintf("%s > ?", field), endOfDay)
+			return db.Where(fmt.Sprintf("%s > ?", field), endOfDay), nil
 		case ModeGreaterEqual:
-			return db.Where(fmt.Sprintf("%s >= ?", field), startOfDay)
+			return db.Where(fmt.Sprintf("%s >= ?", field), startOfDay), nil
 		case ModeLessThan, ModeBefore:
-			return db.Where(fmt.Sprintf("%s < ?", field), startOfDay)
+			return db.Where(fmt.Sprintf("%s < ?", field), startOfDay), nil
 		case ModeLessEqual:
-			return db.Where(fmt.Sprintf("%s <= ?", field), endOfDay)
-		case ModeRange, ModeBetween:
-			rangeVal, ok := value.(RangeValue)
-			if !ok {
-				return db
-			}
-			var fromDate, toDate time.Time
-			switch fv := rangeVal.From.(type) {
-			case string:
-				parsedFrom, err := time.Parse("2006-01-02", fv)
-				if err != nil {
-					fmt.Printf("Invalid date format for range from: %v\n", fv)
-					return db
-				}
-				fromDate = parsedFrom
-			case time.Time:
-				fromDate = fv
-			default:
-				fmt.Printf("Unsupported type for range.From in DataTypeDate: %T\n", fv)
-				return db
-			}
-			switch tv := rangeVal.To.(type) {
-			case string:
-				parsedTo, err := time.Parse("2006-01-02", tv)
-				if err != nil {
-					fmt.Printf("Invalid date format for range to: %v\n", tv)
-					return db
-				}
-				toDate = parsedTo
-			case time.Time:
-				toDate = tv
-			default:
-				fmt.Printf("Unsupported type for range.To in DataTypeDate: %T\n", tv)
-				return db
-			}
-
-			startOfFromDay := time.Date(fromDate.Year(), fromDate.Month(), fromDate.Day(), 0, 0, 0, 0, fromDate.Location())
-			endOfToDay := time.Date(toDate.Year(), toDate.Month(), toDate.Day(), 23, 59, 59, 999999999, toDate.Location())
-
-			return db.Where(fmt.Sprintf("%s BETWEEN ? AND ?", field), startOfFromDay, endOfToDay)
+			return db.Where(fmt.Sprintf("%s <= ?", field), endOfDay), nil
+		case ModeIsEmpty:
+			return db.Where(fmt.Sprintf("%s IS NULL", field)), nil
+		case ModeIsNotEmpty:
+			return db.Where(fmt.Sprintf("%s IS NOT NULL", field)), nil
 		default:
-			return db
+			return db, fmt.Errorf("mode %s does not apply to dates", mode)
 		}
 	}
 
@@ -207,80 +262,80 @@ func filtering(db *gorm.DB, filter Filter, value FilterValue) *gorm.DB {
 
 	switch mode {
 	case ModeEqual:
-		return db.Where(fmt.Sprintf("%s = ?", field), value)
+		return db.Where(fmt.Sprintf("%s = ?", field), value), nil
 	case ModeNotEqual:
-		return db.Where(fmt.Sprintf("%s != ?", field), value)
+		return db.Where(fmt.Sprintf("%s != ?", field), value), nil
 	case ModeContains:
 		if dataType != DataTypeText {
-			return db
+			return db, fmt.Errorf("mode %s only applies to text", mode)
 		}
-		return db.Where(fmt.Sprintf("%s LIKE ?", field), fmt.Sprintf("%%%v%%", value))
+		return db.Where(fmt.Sprintf("%s LIKE ?", field), fmt.Sprintf("%%%v%%", value)), nil
 	case ModeNotContains:
 		if dataType != DataTypeText {
-			return db
+			return db, fmt.Errorf("mode %s only applies to text", mode)
 		}
-		return db.Where(fmt.Sprintf("%s NOT LIKE ?", field), fmt.Sprintf("%%%v%%", value))
+		return db.Where(fmt.Sprintf("%s NOT LIKE ?", field), fmt.Sprintf("%%%v%%", value)), nil
 	case ModeStartsWith:
 		if dataType != DataTypeText {
-			return db
+			return db, fmt.Errorf("mode %s only applies to text", mode)
 		}
-		return db.Where(fmt.Sprintf("%s LIKE ?", field), fmt.Sprintf("%v%%", value))
+		return db.Where(fmt.Sprintf("%s LIKE ?", field), fmt.Sprintf("%v%%", value)), nil
 	case ModeEndsWith:
 		if dataType != DataTypeText {
-			return db
+			return db, fmt.Errorf("mode %s only applies to text", mode)
 		}
-		return db.Where(fmt.Sprintf("%s LIKE ?", field), fmt.Sprintf("%%%v", value))
+		return db.Where(fmt.Sprintf("%s LIKE ?", field), fmt.Sprintf("%%%v", value)), nil
 	case ModeGreaterThan:
 		if !isComparable(dataType) {
-			return db
+			return db, fmt.Errorf("mode %s does not apply to %s", mode, dataType)
 		}
-		return db.Where(fmt.Sprintf("%s > ?", field), value)
+		return db.Where(fmt.Sprintf("%s > ?", field), value), nil
 	case ModeGreaterEqual:
 		if !isComparable(dataType) {
-			return db
+			return db, fmt.Errorf("mode %s does not apply to %s", mode, dataType)
 		}
-		return db.Where(fmt.Sprintf("%s >= ?", field), value)
+		return db.Where(fmt.Sprintf("%s >= ?", field), value), nil
 	case ModeLessThan:
 		if !isComparable(dataType) {
-			return db
+			return db, fmt.Errorf("mode %s does not apply to %s", mode, dataType)
 		}
-		return db.Where(fmt.Sprintf("%s < ?", field), value)
+		return db.Where(fmt.Sprintf("%s < ?", field), value), nil
 	case ModeLessEqual:
 		if !isComparable(dataType) {
-			return db
+			return db, fmt.Errorf("mode %s does not apply to %s", mode, dataType)
 		}
-		return db.Where(fmt.Sprintf("%s <= ?", field), value)
+		return db.Where(fmt.Sprintf("%s <= ?", field), value), nil
 	case ModeIsEmpty:
-		return db.Where(fmt.Sprintf("%s IS NULL OR %s = ?", field, field), "")
+		return db.Where(fmt.Sprintf("%s IS NULL OR %s = ?", field, field), ""), nil
 	case ModeIsNotEmpty:
-		return db.Where(fmt.Sprintf("%s IS unsigned AND %s != ?", field, field), "")
+		return db.Where(fmt.Sprintf("%s IS NOT NULL AND %s != ?", field, field), ""), nil
 	case ModeRange, ModeBetween:
 		rangeVal, ok := value.(RangeValue)
 		if !ok {
-			return db
+			return db, fmt.Errorf("invalid range: %v", value)
 		}
-		return db.Where(fmt.Sprintf("%s BETWEEN ? AND ?", field), rangeVal.From, rangeVal.To)
+		return db.Where(fmt.Sprintf("%s BETWEEN ? AND ?", field), rangeVal.From, rangeVal.To), nil
 	case ModeBefore:
 		if !isComparable(dataType) {
-			return db
+			return db, fmt.Errorf("mode %s does not apply to %s", mode, dataType)
 		}
-		return db.Where(fmt.Sprintf("%s < ?", field), value)
+		return db.Where(fmt.Sprintf("%s < ?", field), value), nil
 	case ModeAfter:
 		if !isComparable(dataType) {
-			return db
+			return db, fmt.Errorf("mode %s does not apply to %s", mode, dataType)
 		}
-		return db.Where(fmt.Sprintf("%s > ?", field), value)
+		return db.Where(fmt.Sprintf("%s > ?", field), value), nil
 	case ModeBooleanTrue:
 		if dataType != DataTypeBoolean {
-			return db
+			return db, fmt.Errorf("mode %s only applies to booleans", mode)
 		}
-		return db.Where(fmt.Sprintf("%s = ?", field), true)
+		return db.Where(fmt.Sprintf("%s = ?", field), true), nil
 	case ModeBooleanFalse:
 		if dataType != DataTypeBoolean {
-			return db
+			return db, fmt.Errorf("mode %s only applies to booleans", mode)
 		}
-		return db.Where(fmt.Sprintf("%s = ?", field), false)
+		return db.Where(fmt.Sprintf("%s = ?", field), false), nil
 	default:
-		return db
+		return db, fmt.Errorf("unsupported filter mode: %s", mode)
 	}
 }
@@ -0,0 +1,188 @@
+package filter
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// cursorPayload is the decoded form of an opaque cursor: the sort key values and
+// ID of the boundary row, and whether to page backward from it.
+type cursorPayload struct {
+	Values   []interface{} `json:"v"`
+	Backward bool          `json:"b,omitempty"`
+}
+
+// CursorQuery holds the keyset columns resolved for a cursor paginated request.
+type CursorQuery struct {
+	columns   []sortColumn
+	backward  bool
+	hasCursor bool
+}
+
+// ApplyCursor orders the query by the request sorts plus the primary key and,
+// when the request carries a cursor, seeks past the row it points at. Backward
+// cursors flip the ordering, so callers must reverse the fetched rows. NULLs
+// sort first in ascending and last in descending order unless a sort asks
+// otherwise, the same on every database, so cursors stay valid across them.
+func ApplyCursor(db *gorm.DB, request PaginatedRequest, model interface{}) (*gorm.DB, *CursorQuery, error) {
+	columns, err := resolveSortColumns(db, request.Sorts, model)
+	if err != nil {
+		return db, nil, err
+	}
+
+	query := &CursorQuery{columns: columns}
+	if request.Cursor != "" {
+		payload, err := decodeCursor(request.Cursor)
+		if err != nil {
+			return db, nil, err
+		}
+		if len(payload.Values) != len(columns) {
+			return db, nil, errors.New("cursor does not match the requested sorts")
+		}
+		query.backward = payload.Backward
+		query.hasCursor = true
+
+		values := make([]interface{}, len(columns))
+		for i, column := range columns {
+			if values[i], err = coerceCursorValue(column.field, payload.Values[i]); err != nil {
+				return db, nil, err
+			}
+		}
+		db = db.Where(query.seekCondition(values))
+	}
+
+	for _, column := range columns {
+		if column.field.PrimaryKey {
+			db = db.Order(clause.OrderByColumn{Column: column.column, Desc: column.desc != query.backward})
+			continue
+		}
+		nulls := NullsLast
+		if query.nullsFirst(column) {
+			nulls = NullsFirst
+		}
+		db = db.Order(getNullsOrderSyntax(db, column.column, nulls))
+		db = db.Order(clause.OrderByColumn{Column: column.column, Desc: column.desc != query.backward})
+	}
+	return db, query, nil
+}
+
+// nullsFirst reports whether NULLs of the column come first in the paging
+// direction.
+func (q *CursorQuery) nullsFirst(column sortColumn) bool {
+	first := column.nulls == NullsFirst || (column.nulls == "" && !column.desc)
+	return first != q.backward
+}
+
+// Backward reports whether the query pages backward from its cursor.
+func (q *CursorQuery) Backward() bool { return q.backward }
+
+// HasCursor reports whether the request started from a cursor rather than the
+// first page.
+func (q *CursorQuery) HasCursor() bool { return q.hasCursor }
+
+// Cursor encodes the keyset values of row as an opaque cursor.
+func (q *CursorQuery) Cursor(row interface{}, backward bool) (string, error) {
+	rowValue := reflect.Indirect(reflect.ValueOf(row))
+	values := make([]interface{}, len(q.columns))
+	for i, column := range q.columns {
+		values[i], _ = column.field.ValueOf(context.Background(), rowValue)
+	}
+
+	data, err := json.Marshal(cursorPayload{Values: values, Backward: backward})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// seekCondition expands the row comparison (k1, k2, id) > (v1, v2, vid) into
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR (k1 = v1 AND k2 = v2 AND id > vid),
+// honoring the direction and NULL placement of each column. A NULL value
+// equals only NULLs, and the rows after it are the non-NULL ones when NULLs
+// come first, and none when they come last.
+func (q *CursorQuery) seekCondition(values []interface{}) clause.Expression {
+	conditions := make([]clause.Expression, 0, len(q.columns))
+	for i, column := range q.columns {
+		after := q.after(column, values[i])
+		if after == nil {
+			continue
+		}
+		exprs := make([]clause.Expression, 0, i+1)
+		for j := 0; j < i; j++ {
+			// clause.Eq renders IS NULL for nil values
+			exprs = append(exprs, clause.Eq{Column: q.columns[j].column, Value: values[j]})
+		}
+		exprs = append(exprs, after)
+		conditions = append(conditions, clause.And(exprs...))
+	}
+	return clause.Or(conditions...)
+}
+
+// after returns the condition of the column values past value in the paging
+// direction, or nil when there are none.
+func (q *CursorQuery) after(column sortColumn, value interface{}) clause.Expression {
+	nullsFirst := q.nullsFirst(column)
+	if value == nil {
+		if nullsFirst {
+			return clause.Neq{Column: column.column, Value: nil}
+		}
+		return nil
+	}
+	var past clause.Expression = clause.Gt{Column: column.column, Value: value}
+	if column.desc != q.backward {
+		past = clause.Lt{Column: column.column, Value: value}
+	}
+	if nullsFirst || column.field.PrimaryKey {
+		return past
+	}
+	return clause.Or(past, clause.Eq{Column: column.column, Value: nil})
+}
+
+func decodeCursor(encoded string) (cursorPayload, error) {
+	var payload cursorPayload
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return payload, fmt.Errorf("failed to decode cursor: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return payload, fmt.Errorf("failed to unmarshal cursor: %w", err)
+	}
+	return payload, nil
+}
+
+// coerceCursorValue converts a decoded cursor value back to the Go type of the
+// column it was read from.
+func coerceCursorValue(field *schema.Field, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case json.Number:
+		switch field.DataType {
+		case schema.Int, schema.Uint:
+			return v.Int64()
+		case schema.Float:
+			return v.Float64()
+		default:
+			return v.String(), nil
+		}
+	case string:
+		if field.DataType == schema.Time {
+			return time.Parse(time.RFC3339Nano, v)
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
 )
 
 // ----------------------
@@ -47,6 +49,74 @@ func convertValue(dataType FilterDataType, value FilterValue) FilterValue {
 	}
 }
 
+// convertFilterValue converts value like convertValue and fails when it does
+// not fit dataType, instead of passing the raw value on to the query.
+func convertFilterValue(dataType FilterDataType, value FilterValue) (FilterValue, error) {
+	converted := convertValue(dataType, value)
+	ok := true
+	switch dataType {
+	case DataTypeNumber:
+		switch converted.(type) {
+		case int, int8, int16, int32, int64:
+		default:
+			ok = false
+		}
+	case DataTypeFloat:
+		switch converted.(type) {
+		case float32, float64:
+		default:
+			ok = false
+		}
+	case DataTypeBoolean:
+		_, ok = converted.(bool)
+	case DataTypeDate, DataTypeDatetime, DataTypeTime:
+		_, ok = converted.(time.Time)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%v is not a valid %s", value, dataType)
+	}
+	return converted, nil
+}
+
+// convertFilterValues converts every value with convertFilterValue.
+func convertFilterValues(dataType FilterDataType, values []FilterValue) ([]FilterValue, error) {
+	converted := make([]FilterValue, len(values))
+	for i, v := range values {
+		c, err := convertFilterValue(dataType, v)
+		if err != nil {
+			return nil, err
+		}
+		converted[i] = c
+	}
+	return converted, nil
+}
+
+// convertRangeValue decodes the value of a range filter, which JSON decodes
+// into a map, and converts both of its bounds.
+func convertRangeValue(dataType FilterDataType, value FilterValue) (RangeValue, error) {
+	var rangeVal RangeValue
+	switch v := value.(type) {
+	case RangeValue:
+		rangeVal = v
+	case map[string]interface{}:
+		rangeVal = RangeValue{From: v["from"], To: v["to"]}
+	default:
+		return rangeVal, fmt.Errorf("%v is not a range", value)
+	}
+	if rangeVal.From == nil || rangeVal.To == nil {
+		return rangeVal, errors.New("a range needs both from and to")
+	}
+	from, err := convertFilterValue(dataType, rangeVal.From)
+	if err != nil {
+		return rangeVal, err
+	}
+	to, err := convertFilterValue(dataType, rangeVal.To)
+	if err != nil {
+		return rangeVal, err
+	}
+	return RangeValue{From: from, To: to}, nil
+}
+
 // convertValues converts a slice of FilterValue to their appropriate types based on FilterDataType.
 func convertValues(dataType FilterDataType, values []FilterValue) []FilterValue {
 	converted := make([]FilterValue, len(values))
@@ -126,6 +196,11 @@ func convertToTime(dataType FilterDataType, value FilterValue) FilterValue {
 		if parsed, err := time.Parse(layout, v); err == nil {
 			return parsed
 		}
+		if dataType == DataTypeDatetime {
+			if parsed, err := time.Parse(time.RFC3339, v); err == nil {
+				return parsed
+			}
+		}
 		log.Printf("Failed to convert value '%v' to time for DataType%s", v, dataType)
 		return v
 	case time.Time:
@@ -145,17 +220,56 @@ func isComparable(dataType FilterDataType) bool {
 	}
 }
 
+// maxGroupDepth bounds how deeply filter groups may be nested.
+const maxGroupDepth = 8
+
+var validModes = map[FilterMode]bool{
+	ModeEqual: true, ModeNotEqual: true, ModeContains: true, ModeNotContains: true,
+	ModeStartsWith: true, ModeEndsWith: true, ModeIsEmpty: true, ModeIsNotEmpty: true,
+	ModeGreaterThan: true, ModeGreaterEqual: true, ModeLessThan: true, ModeLessEqual: true,
+	ModeRange: true, ModeBetween: true, ModeBefore: true, ModeAfter: true,
+	ModeBooleanTrue: true, ModeBooleanFalse: true,
+}
+
+var validDataTypes = map[FilterDataType]bool{
+	DataTypeText: true, DataTypeNumber: true, DataTypeFloat: true, DataTypeBoolean: true,
+	DataTypeDate: true, DataTypeDatetime: true, DataTypeTime: true,
+}
+
 func validatePaginatedRequest(request PaginatedRequest) error {
-	for _, filter := range request.Filters {
+	return validateFilterGroup(request.RootGroup(), 0)
+}
+
+func validateFilterGroup(group FilterGroup, depth int) error {
+	if depth > maxGroupDepth {
+		return fmt.Errorf("filter groups cannot be nested deeper than %d levels", maxGroupDepth)
+	}
+	switch strings.ToLower(group.Logic) {
+	case "", "and", "or":
+	default:
+		return fmt.Errorf("invalid filter logic: %s", group.Logic)
+	}
+	for _, filter := range group.Filters {
 		if filter.GetField() == "" {
 			return errors.New("filter field cannot be empty")
 		}
 		if filter.GetMode() == "" {
 			return errors.New("filter mode cannot be empty")
 		}
+		if !validModes[filter.GetMode()] {
+			return fmt.Errorf("invalid filter mode: %s", filter.GetMode())
+		}
 		if filter.GetDataType() == "" {
 			return errors.New("filter dataType cannot be empty")
 		}
+		if !validDataTypes[FilterDataType(filter.GetDataType())] {
+			return fmt.Errorf("invalid filter dataType: %s", filter.GetDataType())
+		}
+	}
+	for _, subGroup := range group.Groups {
+		if err := validateFilterGroup(subGroup, depth+1); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -191,3 +305,22 @@ func getTimeCastSyntax(db *gorm.DB) func(string) string {
 		}
 	}
 }
+
+// parseSchema returns the parsed GORM schema of the model.
+func parseSchema(db *gorm.DB, model interface{}) (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, fmt.Errorf("failed to parse model schema: %w", err)
+	}
+	return stmt.Schema, nil
+}
+
+// getNullsOrderSyntax returns an ORDER BY column that places NULLs first or last.
+// Sorting on the IS NULL expression works the same on postgres, mysql and sqlite,
+// unlike NULLS FIRST/LAST which mysql does not support.
+func getNullsOrderSyntax(db *gorm.DB, column clause.Column, nulls SortNulls) clause.OrderByColumn {
+	return clause.OrderByColumn{
+		Column: clause.Column{Name: db.Statement.Quote(column) + " IS NULL", Raw: true},
+		Desc:   nulls == NullsFirst,
+	}
+}
@@ -0,0 +1,190 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// resolvedField is a filter field resolved against the schema of the query
+// model. wrap turns a condition on column into a condition on the base query,
+// e.g. an EXISTS subquery for to-many relations.
+type resolvedField struct {
+	column string
+	wrap   func(condition *gorm.DB) *gorm.DB
+}
+
+func identityWrap(condition *gorm.DB) *gorm.DB { return condition }
+
+// resolveField sanitizes the field and resolves it against the schema of the
+// query model. Dotted fields such as Branch.Company.Name walk the model
+// relations: to-one relations are LEFT JOINed once per path and to-many
+// relations are filtered through an EXISTS subquery, so base rows are never
+// duplicated. Without a model only plain columns are supported.
+func resolveField(db *gorm.DB, field string) (*gorm.DB, *resolvedField, error) {
+	field = sanitizeField(field)
+	if db.Statement.Model == nil {
+		if strings.Contains(field, ".") {
+			return db, nil, errors.New("relation fields require the query model to be set")
+		}
+		return db, &resolvedField{column: field, wrap: identityWrap}, nil
+	}
+
+	modelSchema, err := parseSchema(db, db.Statement.Model)
+	if err != nil {
+		return db, nil, err
+	}
+	return resolvePath(db, modelSchema, modelSchema.Table, "", strings.Split(field, "."))
+}
+
+// resolvePath resolves the remaining path parts relative to parentSchema, which
+// is addressed in SQL as parentAlias.
+func resolvePath(db *gorm.DB, parentSchema *schema.Schema, parentAlias, aliasPrefix string, parts []string) (*gorm.DB, *resolvedField, error) {
+	if len(parts) == 1 {
+		field, err := lookUpColumn(parentSchema, parts[0])
+		if err != nil {
+			return db, nil, err
+		}
+		column := db.Statement.Quote(parentAlias + "." + field.DBName)
+		return db, &resolvedField{column: column, wrap: identityWrap}, nil
+	}
+
+	relation := lookUpRelation(parentSchema, parts[0])
+	if relation == nil {
+		return db, nil, fmt.Errorf("invalid relation: %s", parts[0])
+	}
+	alias := aliasPrefix + relation.Name
+
+	switch relation.Type {
+	case schema.BelongsTo, schema.HasOne:
+		on, vars := referenceConditions(db, relation, parentAlias, alias)
+		join := fmt.Sprintf("LEFT JOIN %s %s ON %s",
+			db.Statement.Quote(relation.FieldSchema.Table),
+			db.Statement.Quote(alias),
+			strings.Join(on, " AND "),
+		)
+		db = addJoin(db, join, vars...)
+		return resolvePath(db, relation.FieldSchema, alias, alias+"__", parts[1:])
+
+	case schema.HasMany, schema.Many2Many:
+		subquery := db.Session(&gorm.Session{NewDB: true}).
+			Table(fmt.Sprintf("%s %s", db.Statement.Quote(relation.FieldSchema.Table), db.Statement.Quote(alias))).
+			Select("1")
+		if relation.Type == schema.Many2Many {
+			subquery = applyJoinTable(subquery, relation, parentAlias, alias)
+		} else {
+			on, vars := referenceConditions(db, relation, parentAlias, alias)
+			subquery = subquery.Where(strings.Join(on, " AND "), vars...)
+		}
+
+		subquery, resolved, err := resolvePath(subquery, relation.FieldSchema, alias, alias+"__", parts[1:])
+		if err != nil {
+			return db, nil, err
+		}
+		wrap := func(condition *gorm.DB) *gorm.DB {
+			return db.Session(&gorm.Session{NewDB: true}).
+				Where("EXISTS (?)", subquery.Where(resolved.wrap(condition)))
+		}
+		return db, &resolvedField{column: resolved.column, wrap: wrap}, nil
+
+	default:
+		return db, nil, fmt.Errorf("unsupported relation: %s", parts[0])
+	}
+}
+
+// referenceConditions builds the ON conditions linking alias to parentAlias for
+// belongs-to, has-one and has-many relations.
+func referenceConditions(db *gorm.DB, relation *schema.Relationship, parentAlias, alias string) ([]string, []interface{}) {
+	conditions := []string{}
+	vars := []interface{}{}
+	for _, ref := range relation.References {
+		switch {
+		case ref.PrimaryKey == nil:
+			// Polymorphic type column
+			conditions = append(conditions, fmt.Sprintf("%s = ?", db.Statement.Quote(alias+"."+ref.ForeignKey.DBName)))
+			vars = append(vars, ref.PrimaryValue)
+		case ref.OwnPrimaryKey:
+			conditions = append(conditions, fmt.Sprintf("%s = %s",
+				db.Statement.Quote(alias+"."+ref.ForeignKey.DBName),
+				db.Statement.Quote(parentAlias+"."+ref.PrimaryKey.DBName),
+			))
+		default:
+			conditions = append(conditions, fmt.Sprintf("%s = %s",
+				db.Statement.Quote(alias+"."+ref.PrimaryKey.DBName),
+				db.Statement.Quote(parentAlias+"."+ref.ForeignKey.DBName),
+			))
+		}
+	}
+	if deletedAt := softDeleteColumn(relation.FieldSchema); deletedAt != "" {
+		conditions = append(conditions, fmt.Sprintf("%s IS NULL", db.Statement.Quote(alias+"."+deletedAt)))
+	}
+	return conditions, vars
+}
+
+// applyJoinTable links a many-to-many subquery on alias to parentAlias through
+// the relation join table.
+func applyJoinTable(subquery *gorm.DB, relation *schema.Relationship, parentAlias, alias string) *gorm.DB {
+	joinAlias := alias + "__join"
+	on := []string{}
+	where := []string{}
+	for _, ref := range relation.References {
+		if ref.PrimaryKey == nil {
+			continue
+		}
+		joinColumn := subquery.Statement.Quote(joinAlias + "." + ref.ForeignKey.DBName)
+		if ref.OwnPrimaryKey {
+			where = append(where, fmt.Sprintf("%s = %s", joinColumn, subquery.Statement.Quote(parentAlias+"."+ref.PrimaryKey.DBName)))
+		} else {
+			on = append(on, fmt.Sprintf("%s = %s", joinColumn, subquery.Statement.Quote(alias+"."+ref.PrimaryKey.DBName)))
+		}
+	}
+	if deletedAt := softDeleteColumn(relation.FieldSchema); deletedAt != "" {
+		where = append(where, fmt.Sprintf("%s IS NULL", subquery.Statement.Quote(alias+"."+deletedAt)))
+	}
+
+	subquery = subquery.Joins(fmt.Sprintf("JOIN %s %s ON %s",
+		subquery.Statement.Quote(relation.JoinTable.Table),
+		subquery.Statement.Quote(joinAlias),
+		strings.Join(on, " AND "),
+	))
+	return subquery.Where(strings.Join(where, " AND "))
+}
+
+// addJoin adds the join unless an identical one was already added, so filters
+// touching the same relation share a single join.
+func addJoin(db *gorm.DB, join string, vars ...interface{}) *gorm.DB {
+	for _, existing := range db.Statement.Joins {
+		if existing.Name == join {
+			return db
+		}
+	}
+	return db.Joins(join, vars...)
+}
+
+// lookUpRelation finds a relation by Go field name, case-insensitively or in
+// snake case, e.g. "Company", "company".
+func lookUpRelation(parentSchema *schema.Schema, name string) *schema.Relationship {
+	if relation, ok := parentSchema.Relationships.Relations[name]; ok {
+		return relation
+	}
+	for relationName, relation := range parentSchema.Relationships.Relations {
+		if strings.EqualFold(relationName, name) || toSnakeCase(relationName) == toSnakeCase(name) {
+			return relation
+		}
+	}
+	return nil
+}
+
+// softDeleteColumn returns the gorm.DeletedAt column of the schema, if any.
+func softDeleteColumn(s *schema.Schema) string {
+	for _, field := range s.Fields {
+		if field.FieldType == reflect.TypeOf(gorm.DeletedAt{}) {
+			return field.DBName
+		}
+	}
+	return ""
+}
@@ -1,6 +1,13 @@
 package filter
 
-import "strings"
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidFilter is wrapped by the errors of filter requests the client got
+// wrong, such as unknown fields or modes, as opposed to database failures.
+var ErrInvalidFilter = errors.New("invalid filter")
 
 type FilterMode string
 
@@ -81,27 +88,135 @@ func (f FilterStruct) IsMultiple() bool {
 	}
 }
 
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+)
+
+type SortNulls string
+
+const (
+	NullsFirst SortNulls = "first"
+	NullsLast  SortNulls = "last"
+)
+
+type SortStruct struct {
+	Field     string        `json:"field"`
+	Direction SortDirection `json:"direction"`
+	Nulls     SortNulls     `json:"nulls"`
+}
+
+func (s SortStruct) GetField() string { return toSnakeCase(s.Field) }
+
+func (s SortStruct) IsDesc() bool { return strings.ToLower(string(s.Direction)) == string(SortDesc) }
+
 type Page struct {
 	Page      string `json:"page"`
 	PageIndex int    `json:"pageIndex"`
 }
 
 type FilterPages[T any] struct {
-	Data      []*T   `json:"data"`
-	PageIndex int    `json:"pageIndex"`
-	TotalPage int    `json:"totalPage"`
-	PageSize  int    `json:"pageSize"`
-	TotalSize int    `json:"totalSize"`
-	Pages     []Page `json:"pages"`
+	Data       []*T   `json:"data"`
+	PageIndex  int    `json:"pageIndex"`
+	TotalPage  int    `json:"totalPage"`
+	PageSize   int    `json:"pageSize"`
+	TotalSize  int    `json:"totalSize"`
+	Pages      []Page `json:"pages"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+}
+
+type PaginationMode string
+
+const (
+	PaginationOffset PaginationMode = "offset"
+	PaginationCursor PaginationMode = "cursor"
+)
+
+// FilterGroup is a recursive node of filters and sub-groups combined with its
+// own logic, e.g. (status = verified AND branch = 3) OR (created_at after X).
+type FilterGroup struct {
+	Logic   string         `json:"logic"`
+	Filters []FilterStruct `json:"filters"`
+	Groups  []FilterGroup  `json:"groups"`
 }
 
 type FilterRequest struct {
 	Filters []FilterStruct `json:"filters"`
 	Logic   string         `json:"logic"`
+	Groups  []FilterGroup  `json:"groups"`
 }
 
 type PaginatedRequest struct {
 	Filters  []FilterStruct `json:"filters"`
 	Preloads []string       `json:"preloads"`
 	Logic    string         `json:"logic"`
+	Groups   []FilterGroup  `json:"groups"`
+	Sorts    []SortStruct   `json:"sorts"`
+
+	// Cursor pagination, see ApplyCursor.
+	Pagination PaginationMode `json:"pagination"`
+	Cursor     string         `json:"cursor"`
+	SkipCount  bool           `json:"skipCount"`
+}
+
+// IsCursorMode reports whether the request pages by keyset instead of offset.
+func (r PaginatedRequest) IsCursorMode() bool {
+	return r.Pagination == PaginationCursor || r.Cursor != ""
+}
+
+// RootGroup wraps the top-level filters and groups into a single group so flat
+// payloads and nested payloads are compiled the same way.
+func (r PaginatedRequest) RootGroup() FilterGroup {
+	return FilterGroup{
+		Logic:   r.Logic,
+		Filters: r.Filters,
+		Groups:  r.Groups,
+	}
+}
+
+type AggregateFunc string
+
+const (
+	AggregateCount AggregateFunc = "count"
+	AggregateSum   AggregateFunc = "sum"
+	AggregateAvg   AggregateFunc = "avg"
+	AggregateMin   AggregateFunc = "min"
+	AggregateMax   AggregateFunc = "max"
+)
+
+type DateBucket string
+
+const (
+	BucketDay   DateBucket = "day"
+	BucketWeek  DateBucket = "week"
+	BucketMonth DateBucket = "month"
+)
+
+type AggregateMetric struct {
+	Field string        `json:"field"`
+	Func  AggregateFunc `json:"func"`
+	Alias string        `json:"alias"`
+}
+
+type AggregateRequest struct {
+	Filters    []FilterStruct    `json:"filters"`
+	Logic      string            `json:"logic"`
+	Groups     []FilterGroup     `json:"groups"`
+	GroupBy    []string          `json:"groupBy"`
+	Metrics    []AggregateMetric `json:"metrics"`
+	DateField  string            `json:"dateField"`
+	DateBucket DateBucket        `json:"dateBucket"`
+}
+
+// PaginatedRequest returns the filtering part of the aggregation so it can be
+// compiled by ApplyFilters.
+func (r AggregateRequest) PaginatedRequest() PaginatedRequest {
+	return PaginatedRequest{
+		Filters: r.Filters,
+		Logic:   r.Logic,
+		Groups:  r.Groups,
+	}
 }
@@ -0,0 +1,398 @@
+package managers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+// ImportMaxRows caps the number of data rows accepted in a single import.
+const ImportMaxRows = 5000
+
+// ErrImportCommit wraps database errors raised while creating the valid rows,
+// as opposed to errors in the uploaded file itself.
+var ErrImportCommit = errors.New("failed to import rows")
+
+// ErrImportOutOfScope is returned when a row would be created outside of the
+// tenant scope of the caller.
+var ErrImportOutOfScope = errors.New("row is outside of your scope")
+
+// importDateLayouts are the date formats accepted for time.Time fields.
+var importDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"01/02/2006",
+}
+
+// ImportRowError reports why a spreadsheet row was rejected. Row is the
+// 1-based row number as shown by spreadsheet software, header included.
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// ImportResult summarizes an import. In dry-run mode nothing is written and
+// Created is always zero.
+type ImportResult struct {
+	DryRun  bool             `json:"dryRun"`
+	Total   int              `json:"total"`
+	Valid   int              `json:"valid"`
+	Created int              `json:"created"`
+	Errors  []ImportRowError `json:"errors"`
+}
+
+// T = Model
+// V = Request
+type Importer[T any, V any] struct {
+	Repo     *Repository[T]
+	Validate func(request *V) error
+	// ToEntity maps a validated request to the model. Defaults to copying
+	// fields with the same name, like Controller does on create.
+	ToEntity func(request *V) (*T, error)
+	// Aliases maps extra spreadsheet headers to request field names, e.g.
+	// "E-mail" => "Email". Headers otherwise match the field name or its json
+	// tag, ignoring case, spaces, dashes and underscores.
+	Aliases map[string]string
+
+	// scoped is set when Repo is restricted to the tenant scope of the caller
+	scoped bool
+}
+
+func NewImporter[T any, V any](
+	repo *Repository[T],
+	Validate func(request *V) error,
+	ToEntity func(request *V) (*T, error),
+) *Importer[T, V] {
+	return &Importer[T, V]{
+		Repo:     repo,
+		Validate: Validate,
+		ToEntity: ToEntity,
+	}
+}
+
+// Import reads the uploaded "file" form field (.csv or .xlsx), validates every
+// row and, unless the "dryRun" query parameter is true, creates the valid rows
+// in a single transaction. Invalid rows are reported and skipped; if any valid
+// row fails to insert, or falls outside of the tenant scope of the caller,
+// nothing is created.
+func (im *Importer[T, V]) Import(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dryRun", "false"))
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no file is received"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unable to open the file"})
+		return
+	}
+	defer file.Close()
+
+	rows, err := ReadImportRows(file, fileHeader.Filename)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Rows are created with the request context so they are attributed to the
+	// caller in the audit log, and within their tenant scope
+	importer := *im
+	importer.Repo = im.Repo.WithContext(c.Request.Context())
+	if scope, ok := c.Get(TenantScopeKey); ok {
+		if scopeFunc, ok := scope.(ScopeFunc); ok {
+			importer.Repo = importer.Repo.WithScope(scopeFunc)
+			importer.scoped = true
+		}
+	}
+	result, err := importer.Run(rows, dryRun)
+	if errors.Is(err, ErrImportOutOfScope) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, ErrImportCommit) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !dryRun && result.Created > 0 {
+		c.JSON(http.StatusCreated, result)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// Run imports rows, the first of which must be the header row.
+func (im *Importer[T, V]) Run(rows [][]string, dryRun bool) (*ImportResult, error) {
+	if len(rows) == 0 {
+		return nil, errors.New("the file is empty")
+	}
+	if len(rows)-1 > ImportMaxRows {
+		return nil, fmt.Errorf("the file exceeds the maximum of %d rows", ImportMaxRows)
+	}
+	fields, err := im.mapHeaders(rows[0])
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{DryRun: dryRun, Errors: []ImportRowError{}}
+	entities := []*T{}
+	entityRows := []int{}
+	for i, row := range rows[1:] {
+		rowNumber := i + 2
+		if isBlankRow(row) {
+			continue
+		}
+		result.Total++
+
+		entity, err := im.parseRow(fields, row)
+		if err != nil {
+			result.Errors = append(result.Errors, ImportRowError{Row: rowNumber, Error: err.Error()})
+			continue
+		}
+		entities = append(entities, entity)
+		entityRows = append(entityRows, rowNumber)
+	}
+	result.Valid = len(entities)
+
+	if dryRun || len(entities) == 0 {
+		return result, nil
+	}
+	err = im.Repo.Transaction(func(tx *Repository[T]) error {
+		for i, entity := range entities {
+			if err := tx.Create(entity); err != nil {
+				return fmt.Errorf("%w: row %d: %v", ErrImportCommit, entityRows[i], err)
+			}
+			if !im.scoped {
+				continue
+			}
+			// Scoped callers may only create rows they can see afterwards
+			id, err := entityID(entity)
+			if err != nil {
+				return fmt.Errorf("%w: row %d: %v", ErrImportCommit, entityRows[i], err)
+			}
+			if _, err := tx.FindByID(id); errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: row %d", ErrImportOutOfScope, entityRows[i])
+			} else if err != nil {
+				return fmt.Errorf("%w: row %d: %v", ErrImportCommit, entityRows[i], err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Created = len(entities)
+	return result, nil
+}
+
+// parseRow converts a row into a validated request and maps it to the model.
+func (im *Importer[T, V]) parseRow(fields []int, row []string) (*T, error) {
+	var req V
+	reqValue := reflect.ValueOf(&req).Elem()
+	for i, fieldIndex := range fields {
+		if fieldIndex < 0 || i >= len(row) {
+			continue
+		}
+		field := reqValue.Type().Field(fieldIndex)
+		if err := setImportValue(reqValue.Field(fieldIndex), strings.TrimSpace(row[i])); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", field.Name, err)
+		}
+	}
+	if err := im.Validate(&req); err != nil {
+		return nil, err
+	}
+	if im.ToEntity != nil {
+		return im.ToEntity(&req)
+	}
+	return copyToEntity[T](&req), nil
+}
+
+// mapHeaders returns, for every column, the index of the request field it
+// maps to, or -1 for ignored columns.
+func (im *Importer[T, V]) mapHeaders(headers []string) ([]int, error) {
+	reqType := reflect.TypeOf((*V)(nil)).Elem()
+	lookup := map[string]int{}
+	for i := 0; i < reqType.NumField(); i++ {
+		field := reqType.Field(i)
+		lookup[normalizeHeader(field.Name)] = i
+		if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag != "" && tag != "-" {
+			lookup[normalizeHeader(tag)] = i
+		}
+	}
+	for alias, name := range im.Aliases {
+		if index, ok := lookup[normalizeHeader(name)]; ok {
+			lookup[normalizeHeader(alias)] = index
+		}
+	}
+
+	fields := make([]int, len(headers))
+	seen := map[int]bool{}
+	matched := 0
+	for i, header := range headers {
+		index, ok := lookup[normalizeHeader(header)]
+		if !ok {
+			fields[i] = -1
+			continue
+		}
+		if seen[index] {
+			return nil, fmt.Errorf("duplicate column: %s", header)
+		}
+		seen[index] = true
+		fields[i] = index
+		matched++
+	}
+	if matched == 0 {
+		return nil, errors.New("no column of the header row matches the import fields")
+	}
+	return fields, nil
+}
+
+// ReadImportRows reads every row of a CSV file, or of the first sheet of an
+// XLSX file, picking the format from the file extension.
+func ReadImportRows(reader io.Reader, fileName string) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		csvReader := csv.NewReader(reader)
+		csvReader.FieldsPerRecord = -1
+		csvReader.TrimLeadingSpace = true
+		rows, err := csvReader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv: %w", err)
+		}
+		if len(rows) > 0 && len(rows[0]) > 0 {
+			rows[0][0] = strings.TrimPrefix(rows[0][0], "\ufeff")
+		}
+		return rows, nil
+	case ".xlsx":
+		file, err := excelize.OpenReader(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read xlsx: %w", err)
+		}
+		defer file.Close()
+		sheets := file.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, errors.New("the workbook has no sheets")
+		}
+		rows, err := file.GetRows(sheets[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read xlsx: %w", err)
+		}
+		return rows, nil
+	default:
+		return nil, fmt.Errorf("unsupported import file type: %s", filepath.Ext(fileName))
+	}
+}
+
+// setImportValue parses a cell into a request field. Empty cells leave the
+// field at its zero value so required validators still catch them.
+func setImportValue(field reflect.Value, value string) error {
+	if value == "" {
+		return nil
+	}
+	if field.Kind() == reflect.Ptr {
+		ptr := reflect.New(field.Type().Elem())
+		if err := setImportValue(ptr.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(ptr)
+		return nil
+	}
+	if field.Type() == reflect.TypeOf(time.Time{}) {
+		for _, layout := range importDateLayouts {
+			if parsed, err := time.Parse(layout, value); err == nil {
+				field.Set(reflect.ValueOf(parsed))
+				return nil
+			}
+		}
+		return fmt.Errorf("unrecognized date %q", value)
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		parsed, err := parseImportBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected an integer, got %q", value)
+		}
+		field.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected a positive integer, got %q", value)
+		}
+		field.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected a number, got %q", value)
+		}
+		field.SetFloat(parsed)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
+
+func parseImportBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "1", "true", "yes", "y":
+		return true, nil
+	case "0", "false", "no", "n":
+		return false, nil
+	default:
+		return false, fmt.Errorf("expected yes or no, got %q", value)
+	}
+}
+
+// copyToEntity copies the request fields onto the model fields of the same
+// name, converting named types such as string to UserStatus.
+func copyToEntity[T any](request interface{}) *T {
+	var entity T
+	vValue := reflect.ValueOf(request).Elem()
+	tValue := reflect.ValueOf(&entity).Elem()
+	for i := 0; i < vValue.NumField(); i++ {
+		tField := tValue.FieldByName(vValue.Type().Field(i).Name)
+		vField := vValue.Field(i)
+		if tField.IsValid() && tField.CanSet() && vField.Type().ConvertibleTo(tField.Type()) {
+			tField.Set(vField.Convert(tField.Type()))
+		}
+	}
+	return &entity
+}
+
+func normalizeHeader(header string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "", "\ufeff", "").Replace(strings.TrimSpace(header)))
+}
+
+func isBlankRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -2,6 +2,7 @@
 package managers
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
@@ -18,6 +19,10 @@ type Repository[T any] struct {
 	DB *providers.DatabaseService
 }
 
+// ScopeFunc restricts a query to the rows the caller may access, e.g. the
+// rows of their company.
+type ScopeFunc func(db *gorm.DB) *gorm.DB
+
 // NewRepository initializes a new Repository.
 func NewRepository[T any](db *providers.DatabaseService) *Repository[T] {
 	return &Repository[T]{DB: db}
@@ -34,19 +39,11 @@ func (r *Repository[T]) Create(entity *T, preloads ...string) error {
 		return nil
 	}
 
-	// Use reflection to retrieve the primary key (assumed to be "ID" of type uint)
-	v := reflect.ValueOf(entity).Elem()
-	idField := v.FieldByName("ID")
-	if !idField.IsValid() {
-		return fmt.Errorf("entity does not have an 'ID' field for preloading")
-	}
-
-	if idField.Kind() != reflect.Uint && idField.Kind() != reflect.Uint32 && idField.Kind() != reflect.Uint64 {
-		return fmt.Errorf("entity 'ID' field is not of type uint")
+	id, err := entityID(entity)
+	if err != nil {
+		return err
 	}
 
-	id := idField.Uint()
-
 	// Apply preloads using the existing applyPreloads method
 	query := r.applyPreloads(r.DB.Client, preloads)
 
@@ -58,6 +55,45 @@ func (r *Repository[T]) Create(entity *T, preloads ...string) error {
 	return nil
 }
 
+// entityID retrieves the primary key of entity, assumed to be "ID" of type uint.
+func entityID[T any](entity *T) (uint, error) {
+	v := reflect.ValueOf(entity).Elem()
+	idField := v.FieldByName("ID")
+	if !idField.IsValid() {
+		return 0, fmt.Errorf("entity does not have an 'ID' field")
+	}
+
+	if idField.Kind() != reflect.Uint && idField.Kind() != reflect.Uint32 && idField.Kind() != reflect.Uint64 {
+		return 0, fmt.Errorf("entity 'ID' field is not of type uint")
+	}
+
+	return uint(idField.Uint()), nil
+}
+
+// WithScope returns a repository whose every read, update and delete is
+// restricted by scope. Rows outside the scope behave as if they did not exist.
+func (r *Repository[T]) WithScope(scope ScopeFunc) *Repository[T] {
+	if scope == nil {
+		return r
+	}
+	client := scope(r.DB.Client.Session(&gorm.Session{NewDB: true})).Session(&gorm.Session{})
+	return &Repository[T]{DB: &providers.DatabaseService{Client: client}}
+}
+
+// WithContext returns a repository whose queries run with ctx, which carries
+// the audit actor of the request.
+func (r *Repository[T]) WithContext(ctx context.Context) *Repository[T] {
+	return &Repository[T]{DB: &providers.DatabaseService{Client: r.DB.Client.WithContext(ctx)}}
+}
+
+// Transaction runs fn with a repository bound to a single database
+// transaction, committing when fn returns nil and rolling back otherwise.
+func (r *Repository[T]) Transaction(fn func(tx *Repository[T]) error) error {
+	return r.DB.Client.Transaction(func(tx *gorm.DB) error {
+		return fn(&Repository[T]{DB: &providers.DatabaseService{Client: tx}})
+	})
+}
+
 // FindByID retrieves an entity by its ID with optional preloads.
 func (r *Repository[T]) FindByID(id uint, preloads ...string) (*T, error) {
 	var entity T
@@ -149,8 +185,12 @@ func (r *Repository[T]) UpdateByID(id uint, updates *T, preloads ...string) (*T,
 
 // Delete removes an entity by its ID.
 func (r *Repository[T]) Delete(id uint) error {
-	if err := r.DB.Client.Delete(new(T), id).Error; err != nil {
-		return fmt.Errorf("failed to delete entity with ID %d: %w", id, err)
+	result := r.DB.Client.Delete(new(T), id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete entity with ID %d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("entity with ID %d not found: %w", id, gorm.ErrRecordNotFound)
 	}
 	return nil
 }
@@ -168,7 +208,7 @@ func (r *Repository[T]) DeleteMany(ids []uint) error {
 
 	// Optionally, you can check how many records were deleted
 	if result.RowsAffected != int64(len(ids)) {
-		return fmt.Errorf("expected to delete %d entities, but deleted %d", len(ids), result.RowsAffected)
+		return fmt.Errorf("expected to delete %d entities, but deleted %d: %w", len(ids), result.RowsAffected, gorm.ErrRecordNotFound)
 	}
 
 	return nil
@@ -209,7 +249,10 @@ func (r *Repository[T]) prepareFilteredDB(db *gorm.DB, req string) (filter.Pagin
 	if db != nil {
 		clientDB = db
 	}
-	filteredDB := filter.ApplyFilters(clientDB, filterReq)
+	filteredDB, err := filter.ApplyFilters(clientDB.Model(new(T)), filterReq)
+	if err != nil {
+		return filterReq, nil, err
+	}
 	filteredDB = filteredDB.Session(&gorm.Session{
 		Logger: clientDB.Logger.LogMode(logger.Info),
 	})
@@ -224,11 +267,15 @@ func (r *Repository[T]) prepareFilteredDB(db *gorm.DB, req string) (filter.Pagin
 
 func (r *Repository[T]) GetFilteredResults(db *gorm.DB, req string) ([]*T, error) {
 	var results []*T
-	_, filteredDB, err := r.prepareFilteredDB(db, req)
+	filterReq, filteredDB, err := r.prepareFilteredDB(db, req)
 	if err != nil {
 		return nil, err
 	}
-	if err := filteredDB.Find(&results).Error; err != nil {
+	sortedDB, err := filter.ApplySorts(filteredDB, filterReq.Sorts, new(T))
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply sorts: %w", err)
+	}
+	if err := sortedDB.Find(&results).Error; err != nil {
 		return nil, fmt.Errorf("failed to retrieve filtered results: %w", err)
 	}
 	return results, nil
@@ -237,10 +284,13 @@ func (r *Repository[T]) GetFilteredResults(db *gorm.DB, req string) ([]*T, error
 func (r *Repository[T]) GetPaginatedResult(db *gorm.DB, req string, pageSize, pageIndex int) (filter.FilterPages[T], error) {
 	var results []*T
 	var totalSize int64
-	_, filteredDB, err := r.prepareFilteredDB(db, req)
+	filterReq, filteredDB, err := r.prepareFilteredDB(db, req)
 	if err != nil {
 		return filter.FilterPages[T]{}, err
 	}
+	if filterReq.IsCursorMode() {
+		return r.getCursorPaginatedResult(filteredDB, filterReq, pageSize)
+	}
 	if err := filteredDB.Model(new(T)).Count(&totalSize).Error; err != nil {
 		return filter.FilterPages[T]{}, err
 	}
@@ -248,7 +298,11 @@ func (r *Repository[T]) GetPaginatedResult(db *gorm.DB, req string, pageSize, pa
 	if totalPages == 0 {
 		totalPages = 1
 	}
-	paginatedDB := filteredDB.Offset((pageIndex - 1) * pageSize).Limit(pageSize)
+	sortedDB, err := filter.ApplySorts(filteredDB, filterReq.Sorts, new(T))
+	if err != nil {
+		return filter.FilterPages[T]{}, fmt.Errorf("failed to apply sorts: %w", err)
+	}
+	paginatedDB := sortedDB.Offset((pageIndex - 1) * pageSize).Limit(pageSize)
 	if err := paginatedDB.Find(&results).Error; err != nil {
 		return filter.FilterPages[T]{}, fmt.Errorf("failed to retrieve paginated results: %w", err)
 	}
@@ -269,6 +323,137 @@ func (r *Repository[T]) GetPaginatedResult(db *gorm.DB, req string, pageSize, pa
 	}, nil
 }
 
+// StreamFilteredResults walks every row matching the filter request in keyset
+// batches, honoring its sorts and preloads, so exports never hold the whole
+// result set in memory.
+func (r *Repository[T]) StreamFilteredResults(db *gorm.DB, req string, batchSize int, fn func(batch []*T) error) error {
+	filterReq, filteredDB, err := r.prepareFilteredDB(db, req)
+	if err != nil {
+		return err
+	}
+	return r.streamBatches(filteredDB, filterReq, batchSize, fn)
+}
+
+// StreamAll walks every row of the query in keyset batches ordered by ID.
+func (r *Repository[T]) StreamAll(db *gorm.DB, batchSize int, fn func(batch []*T) error, preloads ...string) error {
+	clientDB := r.DB.Client
+	if db != nil {
+		clientDB = db
+	}
+	query := r.applyPreloads(clientDB.Model(new(T)), preloads).Session(&gorm.Session{})
+	return r.streamBatches(query, filter.PaginatedRequest{}, batchSize, fn)
+}
+
+func (r *Repository[T]) streamBatches(query *gorm.DB, filterReq filter.PaginatedRequest, batchSize int, fn func(batch []*T) error) error {
+	filterReq.Cursor = ""
+	for {
+		var batch []*T
+		cursorDB, cursorQuery, err := filter.ApplyCursor(query, filterReq, new(T))
+		if err != nil {
+			return fmt.Errorf("failed to apply cursor: %w", err)
+		}
+		if err := cursorDB.Limit(batchSize).Find(&batch).Error; err != nil {
+			return fmt.Errorf("failed to retrieve batch: %w", err)
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		if filterReq.Cursor, err = cursorQuery.Cursor(batch[len(batch)-1], false); err != nil {
+			return err
+		}
+	}
+}
+
+// GetAggregatedResults runs the base64 encoded aggregation request and returns
+// one row per group keyed by group field, "bucket" and metric alias.
+func (r *Repository[T]) GetAggregatedResults(db *gorm.DB, req string) ([]map[string]interface{}, error) {
+	var aggregateReq filter.AggregateRequest
+	if err := filter.DecodeBase64JSON(req, &aggregateReq); err != nil {
+		return nil, fmt.Errorf("failed to decode aggregate request: %w", err)
+	}
+
+	clientDB := r.DB.Client
+	if db != nil {
+		clientDB = db
+	}
+	filteredDB, err := filter.ApplyFilters(clientDB.Model(new(T)), aggregateReq.PaginatedRequest())
+	if err != nil {
+		return nil, err
+	}
+	aggregateDB, err := filter.ApplyAggregate(filteredDB, aggregateReq, new(T))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build aggregate query: %w", err)
+	}
+
+	results := []map[string]interface{}{}
+	if err := aggregateDB.Find(&results).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve aggregated results: %w", err)
+	}
+	return results, nil
+}
+
+// getCursorPaginatedResult pages by keyset instead of OFFSET. One extra row is
+// fetched to know whether another page exists in the paging direction, and the
+// total count is skipped when the request asks for it.
+func (r *Repository[T]) getCursorPaginatedResult(filteredDB *gorm.DB, filterReq filter.PaginatedRequest, pageSize int) (filter.FilterPages[T], error) {
+	var results []*T
+	var totalSize int64
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if !filterReq.SkipCount {
+		if err := filteredDB.Model(new(T)).Count(&totalSize).Error; err != nil {
+			return filter.FilterPages[T]{}, err
+		}
+	}
+
+	cursorDB, cursorQuery, err := filter.ApplyCursor(filteredDB, filterReq, new(T))
+	if err != nil {
+		return filter.FilterPages[T]{}, fmt.Errorf("failed to apply cursor: %w", err)
+	}
+	if err := cursorDB.Limit(pageSize + 1).Find(&results).Error; err != nil {
+		return filter.FilterPages[T]{}, fmt.Errorf("failed to retrieve paginated results: %w", err)
+	}
+	hasMore := len(results) > pageSize
+	if hasMore {
+		results = results[:pageSize]
+	}
+	if cursorQuery.Backward() {
+		for i, j := 0, len(results)-1; i < j; i, j = i+1, j-1 {
+			results[i], results[j] = results[j], results[i]
+		}
+	}
+
+	pages := filter.FilterPages[T]{
+		Data:     results,
+		PageSize: pageSize,
+	}
+	if !filterReq.SkipCount {
+		pages.TotalSize = int(totalSize)
+		pages.TotalPage = int(math.Ceil(float64(totalSize) / float64(pageSize)))
+	}
+	if len(results) == 0 {
+		return pages, nil
+	}
+	if hasMore || cursorQuery.Backward() {
+		if pages.NextCursor, err = cursorQuery.Cursor(results[len(results)-1], false); err != nil {
+			return filter.FilterPages[T]{}, err
+		}
+	}
+	if cursorQuery.HasCursor() && (hasMore || !cursorQuery.Backward()) {
+		if pages.PrevCursor, err = cursorQuery.Cursor(results[0], true); err != nil {
+			return filter.FilterPages[T]{}, err
+		}
+	}
+	return pages, nil
+}
+
 func (r *Repository[T]) FindWithQuery(query *gorm.DB) ([]*T, error) {
 	var entities []*T
 
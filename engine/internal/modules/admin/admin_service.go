@@ -4,18 +4,21 @@ import (
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/server/middleware"
 )
 
 type AdminService struct {
 	controller *managers.Controller[models.Admin, models.AdminRequest, models.AdminResource]
 	db         *providers.DatabaseService
 	engine     *providers.EngineService
+	middle     *middleware.Middleware
 	models     *models.ModelResource
 }
 
 func NewAdminService(
 	db *providers.DatabaseService,
 	engine *providers.EngineService,
+	middle *middleware.Middleware,
 	models *models.ModelResource,
 ) *AdminService {
 	controller := managers.NewController(
@@ -29,6 +32,7 @@ func NewAdminService(
 		controller: controller,
 		db:         db,
 		engine:     engine,
+		middle:     middle,
 		models:     models,
 	}
 }
@@ -36,10 +40,11 @@ func NewAdminService(
 func (as *AdminService) RegisterRoutes() {
 	routes := as.engine.Client.Group("/api/v1//admin")
 	{
-		routes.POST("/", as.controller.Create)
-		routes.GET("/", as.controller.GetAll)
-		routes.GET("/:id", as.controller.GetByID)
-		routes.PUT("/:id", as.controller.Update)
-		routes.DELETE("/:id", as.controller.Delete)
+		routes.Use(as.middle.AuthMiddleware())
+		routes.POST("/", as.middle.Permission(models.ResourceAdmin, models.ActionCreate), as.controller.Create)
+		routes.GET("/", as.middle.Permission(models.ResourceAdmin, models.ActionRead), as.controller.GetAll)
+		routes.GET("/:id", as.middle.Permission(models.ResourceAdmin, models.ActionRead), as.controller.GetByID)
+		routes.PUT("/:id", as.middle.Permission(models.ResourceAdmin, models.ActionUpdate), as.controller.Update)
+		routes.DELETE("/:id", as.middle.Permission(models.ResourceAdmin, models.ActionDelete), as.controller.Delete)
 	}
 }
@@ -0,0 +1,10 @@
+package api_key
+
+import "go.uber.org/fx"
+
+var Module = fx.Module(
+	"api-key-module",
+	fx.Provide(
+		NewApiKeyService,
+	),
+)
@@ -0,0 +1,213 @@
+package api_key
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/server/middleware"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type ApiKeyService struct {
+	engine *providers.EngineService
+	middle *middleware.Middleware
+	logger *providers.LoggerService
+	models *models.ModelResource
+}
+
+func NewApiKeyService(
+	engine *providers.EngineService,
+	middle *middleware.Middleware,
+	logger *providers.LoggerService,
+	models *models.ModelResource,
+) *ApiKeyService {
+	return &ApiKeyService{
+		engine: engine,
+		middle: middle,
+		logger: logger,
+		models: models,
+	}
+}
+
+// getUserClaims returns the claims of a signed-in user. API keys cannot
+// manage API keys, so that a leaked key cannot mint new ones.
+func (as *ApiKeyService) getUserClaims(ctx *gin.Context) (*providers.UserClaims, bool) {
+	claims, exists := ctx.Get("claims")
+	userClaims, ok := claims.(*providers.UserClaims)
+	if !exists || !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
+		return nil, false
+	}
+	if userClaims.AccountType == models.ApiKeyAccountType {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: API keys cannot manage API keys"})
+		return nil, false
+	}
+	return userClaims, true
+}
+
+// tenantScope returns the scope set by the Permission middleware, if any.
+func tenantScope(ctx *gin.Context) managers.ScopeFunc {
+	scope, _ := ctx.Get(managers.TenantScopeKey)
+	scopeFunc, _ := scope.(managers.ScopeFunc)
+	return scopeFunc
+}
+
+func (as *ApiKeyService) footstep(apiKey *models.ApiKey, activity string, claims *providers.UserClaims) {
+	description := fmt.Sprintf("%s (%s) of company %d by %s %d", apiKey.Name, apiKey.Prefix, apiKey.CompanyID, claims.AccountType, claims.ID)
+	if err := as.models.ApiKeyFootstep(apiKey.ID, activity, description); err != nil {
+		as.logger.Error("Failed to record API key footstep", zap.Error(err))
+	}
+}
+
+func (as *ApiKeyService) List(ctx *gin.Context) {
+	if _, ok := as.getUserClaims(ctx); !ok {
+		return
+	}
+	var companyId *uint
+	if raw := ctx.Query("companyId"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
+			return
+		}
+		value := uint(id)
+		companyId = &value
+	}
+	apiKeys, err := as.models.ApiKeyList(tenantScope(ctx), companyId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	resources := as.models.ApiKeyToResourceList(apiKeys)
+	if resources == nil {
+		resources = []*models.ApiKeyResource{}
+	}
+	ctx.JSON(http.StatusOK, resources)
+}
+
+// Create creates a key for one of the caller's companies, with a role that
+// grants no more than the caller may do. The response is the only one that
+// carries the key.
+func (as *ApiKeyService) Create(ctx *gin.Context) {
+	claims, ok := as.getUserClaims(ctx)
+	if !ok {
+		return
+	}
+	var req models.ApiKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := as.models.ValidateApiKeyRequest(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tenant, err := as.models.TenantResolve(claims.AccountType, claims.ID)
+	if err != nil {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: insufficient permissions"})
+		return
+	}
+	if claims.AccountType != "Admin" && !containsID(tenant.CompanyIDs, req.CompanyID) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": models.ErrApiKeyCompany.Error()})
+		return
+	}
+
+	apiKey, key, err := as.models.ApiKeyCreate(&req, claims.AccountType, claims.ID)
+	if errors.Is(err, models.ErrApiKeyRole) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, models.ErrPermissionDenied) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: insufficient permissions"})
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Company or role not found"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	as.footstep(apiKey, "API Key Created", claims)
+	resource := as.models.ApiKeyToResource(apiKey)
+	resource.Key = key
+	ctx.JSON(http.StatusCreated, resource)
+}
+
+// Rotate replaces a key by a new one with the same settings. The response is
+// the only one that carries the new key.
+func (as *ApiKeyService) Rotate(ctx *gin.Context) {
+	claims, ok := as.getUserClaims(ctx)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	apiKey, key, err := as.models.ApiKeyRotate(tenantScope(ctx), uint(id))
+	if errors.Is(err, models.ErrApiKeyNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	as.footstep(apiKey, "API Key Rotated", claims)
+	resource := as.models.ApiKeyToResource(apiKey)
+	resource.Key = key
+	ctx.JSON(http.StatusOK, resource)
+}
+
+func (as *ApiKeyService) Revoke(ctx *gin.Context) {
+	claims, ok := as.getUserClaims(ctx)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	apiKey, err := as.models.ApiKeyRevoke(tenantScope(ctx), uint(id))
+	if errors.Is(err, models.ErrApiKeyNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	as.footstep(apiKey, "API Key Revoked", claims)
+	ctx.JSON(http.StatusOK, as.models.ApiKeyToResource(apiKey))
+}
+
+func containsID(ids []uint, id uint) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (as *ApiKeyService) RegisterRoutes() {
+	routes := as.engine.Client.Group("/api/v1/api-key")
+	routes.Use(as.middle.AuthMiddleware(), as.middle.BlockImpersonation())
+	{
+		routes.GET("", as.middle.Permission(models.ResourceApiKey, models.ActionRead), as.List)
+		routes.POST("", as.middle.Permission(models.ResourceApiKey, models.ActionCreate), as.Create)
+		routes.POST("/:id/rotate", as.middle.Permission(models.ResourceApiKey, models.ActionUpdate), as.Rotate)
+		routes.DELETE("/:id", as.middle.Permission(models.ResourceApiKey, models.ActionDelete), as.Revoke)
+	}
+}
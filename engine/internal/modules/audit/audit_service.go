@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditService struct {
+	middle        *middleware.Middleware
+	engine        *providers.EngineService
+	modelResource *models.ModelResource
+}
+
+func NewAuditService(
+	middle *middleware.Middleware,
+	engine *providers.EngineService,
+	modelResource *models.ModelResource,
+) *AuditService {
+	return &AuditService{
+		middle:        middle,
+		engine:        engine,
+		modelResource: modelResource,
+	}
+}
+
+func (as *AuditService) SearchFilter(ctx *gin.Context) {
+	filterParam := ctx.Query("filter")
+	if filterParam == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "filter parameter is required"})
+		return
+	}
+	pageIndex, err := strconv.Atoi(ctx.Query("pageIndex"))
+	if err != nil || pageIndex < 1 {
+		pageIndex = 1
+	}
+	pageSize, err := strconv.Atoi(ctx.Query("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10
+	}
+	auditLogs, err := as.modelResource.AuditLogFilter(filterParam, pageSize, pageIndex)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	data := as.modelResource.AuditLogToResourceList(auditLogs.Data)
+	if data == nil {
+		data = []*models.AuditLogResource{}
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       data,
+		"pageIndex":  auditLogs.PageIndex,
+		"totalPage":  auditLogs.TotalPage,
+		"pageSize":   auditLogs.PageSize,
+		"totalSize":  auditLogs.TotalSize,
+		"pages":      auditLogs.Pages,
+		"nextCursor": auditLogs.NextCursor,
+		"prevCursor": auditLogs.PrevCursor,
+	})
+}
+
+func (as *AuditService) GetByID(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	auditLog, err := as.modelResource.AuditLogDB.FindByID(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Audit log not found"})
+		return
+	}
+	ctx.JSON(http.StatusOK, as.modelResource.AuditLogToResource(auditLog))
+}
+
+func (as *AuditService) RegisterRoutes() {
+	routes := as.engine.Client.Group("/api/v1/audit")
+	routes.Use(as.middle.AuthMiddleware())
+	{
+		routes.GET("", as.middle.Permission(models.ResourceAudit, models.ActionRead), as.SearchFilter)
+		routes.GET("/:id", as.middle.Permission(models.ResourceAudit, models.ActionRead), as.GetByID)
+	}
+}
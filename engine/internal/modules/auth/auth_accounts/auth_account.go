@@ -13,18 +13,21 @@ import (
 	"go.uber.org/zap"
 )
 
-const SignedInExpiration = time.Hour * 12
-
 type AuthAccount struct {
 	cfg         *config.AppConfig
 	engine      *providers.EngineService
 	middle      *middleware.Middleware
 	otpProvider *providers.OTPService
+	lockout     *providers.LockoutService
+
+	passwordPolicy *providers.PasswordPolicyService
+	oidc           *providers.OIDCService
 
 	smsProvider   *providers.SMSService
 	emailProvider *providers.EmailService
 
 	tokenProvider *providers.TokenService
+	cache         *providers.CacheService
 	logger        *providers.LoggerService
 	modelResource *models.ModelResource
 
@@ -37,8 +40,12 @@ func NewAuthAccount(
 	engine *providers.EngineService,
 	middle *middleware.Middleware,
 	otpProvider *providers.OTPService,
+	lockout *providers.LockoutService,
+	passwordPolicy *providers.PasswordPolicyService,
+	oidc *providers.OIDCService,
 
 	tokenProvider *providers.TokenService,
+	cache *providers.CacheService,
 	smsProvider *providers.SMSService,
 	emailProvider *providers.EmailService,
 
@@ -50,36 +57,39 @@ func NewAuthAccount(
 
 ) *AuthAccount {
 	return &AuthAccount{
-		cfg:           cfg,
-		engine:        engine,
-		middle:        middle,
-		otpProvider:   otpProvider,
-		tokenProvider: tokenProvider,
-		smsProvider:   smsProvider,
-		emailProvider: emailProvider,
-		modelResource: modelResource,
-		helpers:       helpers,
-		cryptoHelpers: cryptoHelpers,
+		cfg:            cfg,
+		engine:         engine,
+		middle:         middle,
+		otpProvider:    otpProvider,
+		lockout:        lockout,
+		passwordPolicy: passwordPolicy,
+		oidc:           oidc,
+		tokenProvider:  tokenProvider,
+		cache:          cache,
+		smsProvider:    smsProvider,
+		emailProvider:  emailProvider,
+		logger:         logger,
+		modelResource:  modelResource,
+		helpers:        helpers,
+		cryptoHelpers:  cryptoHelpers,
 	}
 }
 
 func (at *AuthAccount) GenerateUserToken(id uint, accountType string, expiration time.Duration) (*string, error) {
-	validTypes := []string{"Member", "Employee", "Admin", "Owner"}
-	isValid := false
-	for _, validType := range validTypes {
-		if accountType == validType {
-			isValid = true
-			break
-		}
-	}
+	return at.generateToken(id, accountType, "", expiration)
+}
 
-	if !isValid {
-		return nil, errors.New("invalid account type")
+// generateToken signs a token for the user, bound to the session with the
+// given JTI when it is not empty.
+func (at *AuthAccount) generateToken(id uint, accountType, jti string, expiration time.Duration) (*string, error) {
+	if _, err := at.modelResource.AccountKind(accountType); err != nil {
+		return nil, err
 	}
 	claims := &providers.UserClaims{
 		ID:          id,
 		AccountType: accountType,
 	}
+	claims.Id = jti
 	token, err := at.tokenProvider.GenerateToken(claims, expiration)
 	if err != nil {
 		at.logger.Error("Failed to generate admin token", zap.Error(err))
@@ -88,879 +98,233 @@ func (at *AuthAccount) GenerateUserToken(id uint, accountType string, expiration
 	return &token, nil
 }
 
-func (ap *AuthAccount) Create(user interface{}, accountType string) (uint, error) {
-	if user == nil {
+// Create stores a new account of accountType, which has to be built by the
+// New of its kind.
+func (ap *AuthAccount) Create(account models.Account, accountType string) (uint, error) {
+	if account == nil {
 		return 0, errors.New("user cannot be nil")
 	}
+	kind, err := ap.modelResource.AccountKind(accountType)
+	if err != nil {
+		return 0, err
+	}
+	if err := kind.Create(account); err != nil {
+		return 0, err
+	}
+	return account.GetID(), nil
+}
 
-	switch accountType {
-	case "Employee":
-		employee, ok := user.(*models.Employee)
-		if !ok {
-			return 0, errors.New("invalid user type for Employee")
-		}
-		if err := ap.modelResource.EmployeeCreate(employee); err != nil {
-			return 0, err
-		}
-		return employee.ID, nil
-
-	case "Member":
-		member, ok := user.(*models.Member)
-		if !ok {
-			return 0, errors.New("invalid user type for Member")
-		}
-		if err := ap.modelResource.MemberCreate(member); err != nil {
-			return 0, err
-		}
-		return member.ID, nil
-
-	case "Owner":
-		owner, ok := user.(*models.Owner)
-		if !ok {
-			return 0, errors.New("invalid user type for Owner")
-		}
-		if err := ap.modelResource.OwnerCreate(owner); err != nil {
-			return 0, err
-		}
-		return owner.ID, nil
-
-	case "Admin":
-		admin, ok := user.(*models.Admin)
-		if !ok {
-			return 0, errors.New("invalid user type for Admin")
-		}
-		if err := ap.modelResource.AdminCreate(admin); err != nil {
-			return 0, err
-		}
-		return admin.ID, nil
-
-	default:
-		return 0, errors.New("unsupported account type")
+// account returns the account of accountType with the given ID, without
+// associations.
+func (ap *AuthAccount) account(accountType string, id uint) (models.Account, error) {
+	kind, err := ap.modelResource.AccountKind(accountType)
+	if err != nil {
+		return nil, err
 	}
+	return kind.FindByID(id)
 }
 
 func (ap *AuthAccount) GetByID(accountType string, id uint) (interface{}, error) {
-	switch accountType {
-	case "Admin":
-		preloads := []string{"Media", "Role", "Gender"}
-		admin, err := ap.modelResource.AdminDB.FindByID(id, preloads...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to find Admin with ID %d: %w", id, err)
-		}
-		return ap.modelResource.AdminToResource(admin), nil
-
-	case "Owner":
-		preloads := []string{"Media", "Companies", "Gender"}
-		owner, err := ap.modelResource.OwnerDB.FindByID(id, preloads...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to find Owner with ID %d: %w", id, err)
-		}
-		return ap.modelResource.OwnerToResource(owner), nil
-
-	case "Employee":
-		preloads := []string{"Media", "Branch", "Role", "Gender"}
-		employee, err := ap.modelResource.EmployeeDB.FindByID(id, preloads...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to find Employee with ID %d: %w", id, err)
-		}
-		return ap.modelResource.EmployeeToResource(employee), nil
-
-	case "Member":
-		preloads := []string{"Media", "Branch", "Role", "Gender"}
-		member, err := ap.modelResource.MemberDB.FindByID(id, preloads...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to find Member with ID %d: %w", id, err)
-		}
-		return ap.modelResource.MemberToResource(member), nil
-
-	default:
-		return nil, errors.New("invalid account type")
+	kind, err := ap.modelResource.AccountKind(accountType)
+	if err != nil {
+		return nil, err
 	}
+	account, err := kind.FindByID(id, kind.Preloads...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s with ID %d: %w", accountType, id, err)
+	}
+	return kind.ToResource(account), nil
 }
 
 func (ap *AuthAccount) GetByIDForPassword(accountType string, id uint) (string, error) {
-	switch accountType {
-	case "Admin":
-		admin, err := ap.modelResource.AdminDB.FindByID(id)
-		return admin.Password, err
-	case "Owner":
-		owner, err := ap.modelResource.OwnerDB.FindByID(id)
-		return owner.Password, err
-	case "Employee":
-		employee, err := ap.modelResource.EmployeeDB.FindByID(id)
-		return employee.Password, err
-	case "Member":
-		member, err := ap.modelResource.MemberDB.FindByID(id)
-		return member.Password, err
-	default:
-		return "", errors.New("invalid account type")
+	account, err := ap.account(accountType, id)
+	if err != nil {
+		return "", err
 	}
+	return account.GetPassword(), nil
 }
 
 func (ap *AuthAccount) GetByIDForEmail(accountType string, id uint) (string, error) {
-	switch accountType {
-	case "Admin":
-		admin, err := ap.modelResource.AdminDB.FindByID(id)
-		return admin.Email, err
-	case "Owner":
-		owner, err := ap.modelResource.OwnerDB.FindByID(id)
-		return owner.Email, err
-	case "Employee":
-		employee, err := ap.modelResource.EmployeeDB.FindByID(id)
-		return employee.Email, err
-	case "Member":
-		member, err := ap.modelResource.MemberDB.FindByID(id)
-		return member.Email, err
-	default:
-		return "", errors.New("invalid account type")
+	account, err := ap.account(accountType, id)
+	if err != nil {
+		return "", err
 	}
+	return account.GetEmail(), nil
 }
 
 func (ap *AuthAccount) GetByIDForContact(accountType string, id uint) (string, error) {
-	switch accountType {
-	case "Admin":
-		admin, err := ap.modelResource.AdminDB.FindByID(id)
-		return admin.ContactNumber, err
-	case "Owner":
-		owner, err := ap.modelResource.OwnerDB.FindByID(id)
-		return owner.ContactNumber, err
-	case "Employee":
-		employee, err := ap.modelResource.EmployeeDB.FindByID(id)
-		return employee.ContactNumber, err
-	case "Member":
-		member, err := ap.modelResource.MemberDB.FindByID(id)
-		return member.ContactNumber, err
-	default:
-		return "", errors.New("invalid account type")
+	account, err := ap.account(accountType, id)
+	if err != nil {
+		return "", err
 	}
+	return account.GetContactNumber(), nil
 }
 
 func (ap *AuthAccount) GetByIDForName(accountType string, id uint) (string, error) {
-	switch accountType {
-	case "Admin":
-		admin, err := ap.modelResource.AdminDB.FindByID(id)
-		return admin.ContactNumber, err
-	case "Owner":
-		owner, err := ap.modelResource.OwnerDB.FindByID(id)
-		return owner.ContactNumber, err
-	case "Employee":
-		employee, err := ap.modelResource.EmployeeDB.FindByID(id)
-		return employee.ContactNumber, err
-	case "Member":
-		member, err := ap.modelResource.MemberDB.FindByID(id)
-		return member.ContactNumber, err
-	default:
-		return "", errors.New("invalid account type")
-	}
-}
-
-func (ap *AuthAccount) Update(user interface{}, accountType string, preloads []string) (interface{}, error) {
-	if user == nil {
-		return nil, errors.New("user cannot be nil")
-	}
-	switch accountType {
-	case "Employee":
-		employee, ok := user.(*models.Employee)
-		if !ok {
-			return nil, errors.New("invalid user type for Employee")
-		}
-		if err := ap.modelResource.EmployeeUpdate(employee, preloads); err != nil {
-			return nil, err
-		}
-		return ap.modelResource.EmployeeToResource(employee), nil
-
-	case "Member":
-		member, ok := user.(*models.Member)
-		if !ok {
-			return nil, errors.New("invalid user type for Member")
-		}
-		if err := ap.modelResource.MemberUpdate(member, preloads); err != nil {
-			return nil, err
-		}
-		return ap.modelResource.MemberToResource(member), nil
-	case "Owner":
-		owner, ok := user.(*models.Owner)
-		if !ok {
-			return nil, errors.New("invalid user type for Owner")
-		}
-		if err := ap.modelResource.OwnerUpdate(owner, preloads); err != nil {
-			return nil, err
-		}
-		return ap.modelResource.OwnerToResource(owner), nil
-
-	case "Admin":
-		admin, ok := user.(*models.Admin)
-		if !ok {
-			return nil, errors.New("invalid user type for Admin")
-		}
-		if err := ap.modelResource.AdminUpdate(admin, preloads); err != nil {
-			return nil, err
-		}
-		return ap.modelResource.AdminToResource(admin), nil
-	default:
-		return nil, errors.New("unsupported account type")
+	account, err := ap.account(accountType, id)
+	if err != nil {
+		return "", err
 	}
+	return account.GetName(), nil
 }
 
-func (ap *AuthAccount) Delete(accountType string, id uint) error {
-	switch accountType {
-	case "Admin":
-		return ap.modelResource.AdminDB.Delete(id)
-	case "Owner":
-		return ap.modelResource.OwnerDB.Delete(id)
-	case "Employee":
-		return ap.modelResource.EmployeeDB.Delete(id)
-	case "Member":
-		return ap.modelResource.MemberDB.Delete(id)
-	default:
-		return errors.New("invalid account type")
+// findByKey returns the account of accountType with the email, username or
+// contact number key.
+func (ap *AuthAccount) findByKey(accountType, key string) (models.Account, error) {
+	kind, err := ap.modelResource.AccountKind(accountType)
+	if err != nil {
+		return nil, err
 	}
+	return kind.FindByKey(key)
 }
 
 func (ap *AuthAccount) FindByEmailUsernameOrContact(accountType, input string) (interface{}, error) {
-	switch accountType {
-	case "Admin":
-		user, err := ap.modelResource.AdminFindByEmailUsernameOrContact(input)
-		if err != nil {
-			return nil, err
-		}
-		return ap.modelResource.AdminToResource(user), nil
-	case "Owner":
-		user, err := ap.modelResource.OwnerFindByEmailUsernameOrContact(input)
-		if err != nil {
-			return nil, err
-		}
-		return ap.modelResource.OwnerToResource(user), nil
-	case "Employee":
-		user, err := ap.modelResource.EmployeeFindByEmailUsernameOrContact(input)
-		if err != nil {
-			return nil, err
-		}
-		return ap.modelResource.EmployeeToResource(user), nil
-	case "Member":
-		user, err := ap.modelResource.MemberFindByEmailUsernameOrContact(input)
-		if err != nil {
-			return nil, err
-		}
-		return ap.modelResource.MemberToResource(user), nil
-	default:
-		return nil, errors.New("invalid account type")
+	kind, err := ap.modelResource.AccountKind(accountType)
+	if err != nil {
+		return nil, err
+	}
+	account, err := kind.FindByKey(input)
+	if err != nil {
+		return nil, err
 	}
+	return kind.ToResource(account), nil
 }
 
 func (ap *AuthAccount) FindByEmailUsernameOrContactForPassword(accountType, input string) (uint, string, error) {
-	switch accountType {
-	case "Admin":
-		user, err := ap.modelResource.AdminFindByEmailUsernameOrContact(input)
-		if err != nil {
-			return 0, "", err
-		}
-		return user.ID, user.Password, nil
-	case "Owner":
-		user, err := ap.modelResource.OwnerFindByEmailUsernameOrContact(input)
-		if err != nil {
-			return 0, "", err
-		}
-		return user.ID, user.Password, nil
-	case "Employee":
-		user, err := ap.modelResource.EmployeeFindByEmailUsernameOrContact(input)
-		if err != nil {
-			return 0, "", err
-		}
-		return user.ID, user.Password, nil
-	case "Member":
-		user, err := ap.modelResource.MemberFindByEmailUsernameOrContact(input)
-		if err != nil {
-			return 0, "", err
-		}
-		return user.ID, user.Password, nil
-	default:
-		return 0, "", errors.New("invalid account type")
+	account, err := ap.findByKey(accountType, input)
+	if err != nil {
+		return 0, "", err
 	}
+	return account.GetID(), account.GetPassword(), nil
 }
 
 func (ap *AuthAccount) FindByEmailUsernameOrContactForID(accountType, input string) (uint, error) {
-	switch accountType {
-	case "Admin":
-		user, err := ap.modelResource.AdminFindByEmailUsernameOrContact(input)
-		if err != nil {
-			return 0, err
-		}
-		return user.ID, nil
-	case "Owner":
-		user, err := ap.modelResource.OwnerFindByEmailUsernameOrContact(input)
-		if err != nil {
-			return 0, err
-		}
-		return user.ID, nil
-	case "Employee":
-		user, err := ap.modelResource.EmployeeFindByEmailUsernameOrContact(input)
-		if err != nil {
-			return 0, err
-		}
-		return user.ID, nil
-	case "Member":
-		user, err := ap.modelResource.MemberFindByEmailUsernameOrContact(input)
-		if err != nil {
-			return 0, err
-		}
-		return user.ID, nil
-	default:
-		return 0, errors.New("invalid account type")
+	account, err := ap.findByKey(accountType, input)
+	if err != nil {
+		return 0, err
 	}
+	return account.GetID(), nil
 }
 
 func (ap *AuthAccount) FindByEmailUsernameOrContactForName(accountType, input string) (string, error) {
-	switch accountType {
-	case "Admin":
-		user, err := ap.modelResource.AdminFindByEmailUsernameOrContact(input)
-		if err != nil {
-			return "", err
-		}
-		return fmt.Sprintf("%s %s", user.FirstName, user.LastName), nil
-	case "Owner":
-		user, err := ap.modelResource.OwnerFindByEmailUsernameOrContact(input)
-		if err != nil {
-			return "", err
-		}
-		return fmt.Sprintf("%s %s", user.FirstName, user.LastName), nil
-	case "Employee":
-		user, err := ap.modelResource.EmployeeFindByEmailUsernameOrContact(input)
-		if err != nil {
-			return "", err
-		}
-		return fmt.Sprintf("%s %s", user.FirstName, user.LastName), nil
-	case "Member":
-		user, err := ap.modelResource.MemberFindByEmailUsernameOrContact(input)
-		if err != nil {
-			return "", err
-		}
-		return fmt.Sprintf("%s %s", user.FirstName, user.LastName), nil
-	default:
-		return "", errors.New("invalid account type")
+	account, err := ap.findByKey(accountType, input)
+	if err != nil {
+		return "", err
 	}
+	return account.GetName(), nil
 }
 
+// UpdatePassword sets a new password, which also lifts any sign-in lockout of
+// the account. The password has to meet the password policy and differ from
+// the recent passwords of the account.
 func (ap *AuthAccount) UpdatePassword(accountType string, userID uint, password string) error {
-	switch accountType {
-	case "Admin":
-		return ap.modelResource.AdminUpdatePassword(userID, password)
-	case "Owner":
-		return ap.modelResource.OwnerUpdatePassword(userID, password)
-	case "Employee":
-		return ap.modelResource.EmployeeUpdatePassword(userID, password)
-	case "Member":
-		return ap.modelResource.MemberUpdatePassword(userID, password)
-	default:
-		return fmt.Errorf("invalid account type")
+	if err := ap.passwordPolicy.Validate(password); err != nil {
+		return err
 	}
+	reused, err := ap.modelResource.PasswordReused(accountType, userID, password)
+	if err != nil {
+		return err
+	}
+	if reused {
+		return models.ErrPasswordReused
+	}
+	if err := ap.modelResource.PasswordUpdate(accountType, userID, password); err != nil {
+		return err
+	}
+	ap.signInSucceeded(accountType, userID)
+	return nil
+}
+
+// updateAccount sets the columns of updates on the account and returns it as
+// a resource.
+func (ap *AuthAccount) updateAccount(accountType string, userID uint, updates map[string]interface{}) (interface{}, error) {
+	kind, err := ap.modelResource.AccountKind(accountType)
+	if err != nil {
+		return nil, err
+	}
+	account, err := ap.modelResource.AccountUpdate(accountType, userID, updates)
+	if err != nil {
+		return nil, err
+	}
+	return kind.ToResource(account), nil
 }
 
 func (ap *AuthAccount) UpdateVerification(accountType string, userID uint, verificationType string, value bool) (interface{}, error) {
-	var preloads []string
-	var err error
-	var resource interface{}
-
-	switch accountType {
-	case "Admin":
-		preloads = []string{"Media", "Role", "Gender"}
-		admin := &models.Admin{}
-		switch verificationType {
-		case "email":
-			admin.IsEmailVerified = value
-		case "contact":
-			admin.IsContactVerified = value
-		case "skip":
-			admin.IsSkipVerification = value
-		default:
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		result, err := ap.modelResource.AdminDB.UpdateColumns(userID, *admin, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.AdminToResource(result)
-
-	case "Owner":
-		preloads = []string{"Media", "Companies", "Gender"}
-		owner := &models.Owner{}
-		switch verificationType {
-		case "email":
-			owner.IsEmailVerified = value
-		case "contact":
-			owner.IsContactVerified = value
-		case "skip":
-			owner.IsSkipVerification = value
-		default:
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		result, err := ap.modelResource.OwnerDB.UpdateColumns(userID, *owner, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.OwnerToResource(result)
-
-	case "Employee":
-		preloads = []string{"Media", "Branch", "Role", "Gender"}
-		employee := &models.Employee{}
-		switch verificationType {
-		case "email":
-			employee.IsEmailVerified = value
-		case "contact":
-			employee.IsContactVerified = value
-		case "skip":
-			employee.IsSkipVerification = value
-		default:
-			return nil, fmt.Errorf("invalid verification type")
-		}
-
-		result, err := ap.modelResource.EmployeeDB.UpdateColumns(userID, *employee, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.EmployeeToResource(result)
-
-	case "Member":
-		preloads = []string{"Media", "Branch", "Role", "Gender"}
-		member := &models.Member{}
-		switch verificationType {
-		case "email":
-			member.IsEmailVerified = value
-		case "contact":
-			member.IsContactVerified = value
-		case "skip":
-			member.IsSkipVerification = value
-		default:
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		result, err := ap.modelResource.MemberDB.UpdateColumns(userID, *member, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.MemberToResource(result)
-
-	default:
-		return nil, fmt.Errorf("invalid account type")
-	}
-	return resource, err
+	columns := map[string]string{
+		"email":   "is_email_verified",
+		"contact": "is_contact_verified",
+		"skip":    "is_skip_verification",
+	}
+	column, ok := columns[verificationType]
+	if !ok {
+		return nil, fmt.Errorf("invalid verification type")
+	}
+	return ap.updateAccount(accountType, userID, map[string]interface{}{column: value})
 }
 
 func (ap *AuthAccount) UpdateProfilePicture(accountType string, userID uint, mediaID *uint) (interface{}, error) {
-	var preloads []string
-	var err error
-	var resource interface{}
-
-	switch accountType {
-	case "Admin":
-		preloads = []string{"Media", "Role", "Gender"}
-		admin := &models.Admin{MediaID: mediaID}
-		result, err := ap.modelResource.AdminDB.UpdateColumns(userID, *admin, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.AdminToResource(result)
-
-	case "Owner":
-		preloads = []string{"Media", "Companies", "Gender"}
-		owner := &models.Owner{MediaID: mediaID}
-		result, err := ap.modelResource.OwnerDB.UpdateColumns(userID, *owner, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.OwnerToResource(result)
-
-	case "Employee":
-		preloads = []string{"Media", "Branch", "Role", "Gender"}
-		employee := &models.Employee{MediaID: mediaID}
-		result, err := ap.modelResource.EmployeeDB.UpdateColumns(userID, *employee, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.EmployeeToResource(result)
-
-	case "Member":
-		preloads = []string{"Media", "Branch", "Role", "Gender"}
-		member := &models.Member{MediaID: mediaID}
-
-		result, err := ap.modelResource.MemberDB.UpdateColumns(userID, *member, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.MemberToResource(result)
-
-	default:
-		return nil, fmt.Errorf("invalid account type")
-	}
-	return resource, err
+	return ap.updateAccount(accountType, userID, map[string]interface{}{"media_id": mediaID})
 }
 
 func (ap *AuthAccount) UpdateProfileAccountSettings(accountType string, userID uint, birthDate time.Time, firstName, middleName, lastName, description, permanentAddress string) (interface{}, error) {
-	var preloads []string
-	var err error
-	var resource interface{}
-
-	switch accountType {
-	case "Admin":
-		preloads = []string{"Media", "Role", "Gender"}
-		admin := &models.Admin{
-			BirthDate:        birthDate,
-			MiddleName:       middleName,
-			FirstName:        firstName,
-			LastName:         lastName,
-			Description:      description,
-			PermanentAddress: permanentAddress,
-		}
-		result, err := ap.modelResource.AdminDB.UpdateColumns(userID, *admin, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.AdminToResource(result)
-
-	case "Owner":
-		preloads = []string{"Media", "Companies", "Gender"}
-		owner := &models.Owner{
-			BirthDate:        birthDate,
-			MiddleName:       middleName,
-			FirstName:        firstName,
-			LastName:         lastName,
-			Description:      description,
-			PermanentAddress: permanentAddress,
-		}
-		result, err := ap.modelResource.OwnerDB.UpdateColumns(userID, *owner, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.OwnerToResource(result)
-
-	case "Employee":
-		preloads = []string{"Media", "Branch", "Role", "Gender"}
-		employee := &models.Employee{
-			BirthDate:        birthDate,
-			MiddleName:       middleName,
-			FirstName:        firstName,
-			LastName:         lastName,
-			Description:      description,
-			PermanentAddress: permanentAddress,
-		}
-		result, err := ap.modelResource.EmployeeDB.UpdateColumns(userID, *employee, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.EmployeeToResource(result)
-
-	case "Member":
-		preloads = []string{"Media", "Branch", "Role", "Gender"}
-		member := &models.Member{
-			BirthDate:        birthDate,
-			MiddleName:       middleName,
-			FirstName:        firstName,
-			LastName:         lastName,
-			Description:      description,
-			PermanentAddress: permanentAddress,
-		}
-
-		result, err := ap.modelResource.MemberDB.UpdateColumns(userID, *member, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.MemberToResource(result)
-
-	default:
-		return nil, fmt.Errorf("invalid account type")
-	}
-	return resource, err
+	return ap.updateAccount(accountType, userID, map[string]interface{}{
+		"birth_date":        birthDate,
+		"first_name":        firstName,
+		"middle_name":       middleName,
+		"last_name":         lastName,
+		"description":       description,
+		"permanent_address": permanentAddress,
+	})
 }
 
+// UpdateProfileChangeContactNumber sets a new contact number, which has to be
+// verified again.
 func (ap *AuthAccount) UpdateProfileChangeContactNumber(accountType string, userID uint, contactNumber string) (interface{}, error) {
-	var preloads []string
-	var err error
-	var resource interface{}
-
-	switch accountType {
-	case "Admin":
-		preloads = []string{"Media", "Role", "Gender"}
-		admin := &models.Admin{
-			ContactNumber:     contactNumber,
-			IsContactVerified: false,
-		}
-		result, err := ap.modelResource.AdminDB.UpdateColumns(userID, *admin, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.AdminToResource(result)
-
-	case "Owner":
-		preloads = []string{"Media", "Companies", "Gender"}
-		owner := &models.Owner{
-			ContactNumber:     contactNumber,
-			IsContactVerified: false,
-		}
-		result, err := ap.modelResource.OwnerDB.UpdateColumns(userID, *owner, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.OwnerToResource(result)
-
-	case "Employee":
-		preloads = []string{"Media", "Branch", "Role", "Gender"}
-		employee := &models.Employee{
-			ContactNumber:     contactNumber,
-			IsContactVerified: false,
-		}
-		result, err := ap.modelResource.EmployeeDB.UpdateColumns(userID, *employee, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.EmployeeToResource(result)
-
-	case "Member":
-		preloads = []string{"Media", "Branch", "Role", "Gender"}
-		member := &models.Member{
-			ContactNumber:     contactNumber,
-			IsContactVerified: false,
-		}
-
-		result, err := ap.modelResource.MemberDB.UpdateColumns(userID, *member, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.MemberToResource(result)
-
-	default:
-		return nil, fmt.Errorf("invalid account type")
-	}
-	return resource, err
+	return ap.updateAccount(accountType, userID, map[string]interface{}{
+		"contact_number":      contactNumber,
+		"is_contact_verified": false,
+	})
 }
 
+// UpdateProfileChangeEmail sets a new email, which has to be verified again.
 func (ap *AuthAccount) UpdateProfileChangeEmail(accountType string, userID uint, email string) (interface{}, error) {
-	var preloads []string
-	var err error
-	var resource interface{}
-
-	switch accountType {
-	case "Admin":
-		preloads = []string{"Media", "Role", "Gender"}
-		admin := &models.Admin{
-			ContactNumber:   email,
-			IsEmailVerified: false,
-		}
-		result, err := ap.modelResource.AdminDB.UpdateColumns(userID, *admin, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.AdminToResource(result)
-
-	case "Owner":
-		preloads = []string{"Media", "Companies", "Gender"}
-		owner := &models.Owner{
-			ContactNumber:   email,
-			IsEmailVerified: false,
-		}
-		result, err := ap.modelResource.OwnerDB.UpdateColumns(userID, *owner, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.OwnerToResource(result)
-
-	case "Employee":
-		preloads = []string{"Media", "Branch", "Role", "Gender"}
-		employee := &models.Employee{
-			ContactNumber:   email,
-			IsEmailVerified: false,
-		}
-		result, err := ap.modelResource.EmployeeDB.UpdateColumns(userID, *employee, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.EmployeeToResource(result)
-
-	case "Member":
-		preloads = []string{"Media", "Branch", "Role", "Gender"}
-		member := &models.Member{
-			ContactNumber:   email,
-			IsEmailVerified: false,
-		}
-
-		result, err := ap.modelResource.MemberDB.UpdateColumns(userID, *member, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.MemberToResource(result)
-
-	default:
-		return nil, fmt.Errorf("invalid account type")
-	}
-	return resource, err
+	return ap.updateAccount(accountType, userID, map[string]interface{}{
+		"email":             email,
+		"is_email_verified": false,
+	})
 }
 
 func (ap *AuthAccount) UpdateProfileChangeUsername(accountType string, userID uint, username string) (interface{}, error) {
-	var preloads []string
-	var err error
-	var resource interface{}
-
-	switch accountType {
-	case "Admin":
-		preloads = []string{"Media", "Role", "Gender"}
-		admin := &models.Admin{
-			Username: username,
-		}
-		result, err := ap.modelResource.AdminDB.UpdateColumns(userID, *admin, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.AdminToResource(result)
-
-	case "Owner":
-		preloads = []string{"Media", "Companies", "Gender"}
-		owner := &models.Owner{
-			Username: username,
-		}
-		result, err := ap.modelResource.OwnerDB.UpdateColumns(userID, *owner, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.OwnerToResource(result)
-
-	case "Employee":
-		preloads = []string{"Media", "Branch", "Role", "Gender"}
-		employee := &models.Employee{
-			Username: username,
-		}
-		result, err := ap.modelResource.EmployeeDB.UpdateColumns(userID, *employee, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.EmployeeToResource(result)
-
-	case "Member":
-		preloads = []string{"Media", "Branch", "Role", "Gender"}
-		member := &models.Member{
-			Username: username,
-		}
-
-		result, err := ap.modelResource.MemberDB.UpdateColumns(userID, *member, preloads)
-		if err != nil {
-			return nil, fmt.Errorf("invalid verification type")
-		}
-		resource = ap.modelResource.MemberToResource(result)
-
-	default:
-		return nil, fmt.Errorf("invalid account type")
-	}
-	return resource, err
+	return ap.updateAccount(accountType, userID, map[string]interface{}{"username": username})
 }
 
 func (ap *AuthAccount) VerifyPassword(accountType string, userID uint, password string) bool {
-	switch accountType {
-	case "Admin":
-		user, err := ap.modelResource.AdminDB.FindByID(userID)
-		if err != nil {
-			return false
-		}
-		if !ap.cryptoHelpers.VerifyPassword(user.Password, password) {
-			return false
-		}
-		return true
-	case "Owner":
-		user, err := ap.modelResource.OwnerDB.FindByID(userID)
-		if err != nil {
-			return false
-		}
-		if !ap.cryptoHelpers.VerifyPassword(user.Password, password) {
-			return false
-		}
-		return true
-
-	case "Employee":
-		user, err := ap.modelResource.EmployeeDB.FindByID(userID)
-		if err != nil {
-			return false
-		}
-		if !ap.cryptoHelpers.VerifyPassword(user.Password, password) {
-			return false
-		}
-		return true
-
-	case "Member":
-		user, err := ap.modelResource.MemberDB.FindByID(userID)
-		if err != nil {
-			return false
-		}
-		if !ap.cryptoHelpers.VerifyPassword(user.Password, password) {
-			return false
-		}
-		return true
-
-	default:
+	account, err := ap.account(accountType, userID)
+	if err != nil {
 		return false
 	}
+	return ap.cryptoHelpers.VerifyPassword(account.GetPassword(), password)
 }
 
 func (ap *AuthAccount) AccountFootstep(accountType string, userID uint, action, description string) (*models.Footstep, error) {
-	type UserDetail struct {
-		FirstName  string
-		MiddleName string
-		LastName   string
-	}
-
-	var userDetail *UserDetail
-
-	fetchUserDetail := func(accountType string, userID uint) (*UserDetail, error) {
-		switch accountType {
-		case "Admin":
-			admin, err := ap.modelResource.AdminDB.FindByID(userID)
-			if err != nil {
-				return nil, fmt.Errorf("failed to find Admin with ID %d: %w", userID, err)
-			}
-			return &UserDetail{admin.FirstName, admin.MiddleName, admin.LastName}, nil
-		case "Owner":
-			owner, err := ap.modelResource.OwnerDB.FindByID(userID)
-			if err != nil {
-				return nil, fmt.Errorf("failed to find Owner with ID %d: %w", userID, err)
-			}
-			return &UserDetail{owner.FirstName, owner.MiddleName, owner.LastName}, nil
-		case "Employee":
-			employee, err := ap.modelResource.EmployeeDB.FindByID(userID)
-			if err != nil {
-				return nil, fmt.Errorf("failed to find Employee with ID %d: %w", userID, err)
-			}
-			return &UserDetail{employee.FirstName, employee.MiddleName, employee.LastName}, nil
-		case "Member":
-			member, err := ap.modelResource.MemberDB.FindByID(userID)
-			if err != nil {
-				return nil, fmt.Errorf("failed to find Member with ID %d: %w", userID, err)
-			}
-			return &UserDetail{member.FirstName, member.MiddleName, member.LastName}, nil
-		default:
-			return nil, fmt.Errorf("invalid account type")
-		}
-	}
-
-	var err error
-	userDetail, err = fetchUserDetail(accountType, userID)
+	kind, err := ap.modelResource.AccountKind(accountType)
 	if err != nil {
 		return nil, err
 	}
+	account, err := kind.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s with ID %d: %w", accountType, userID, err)
+	}
 
-	description = fmt.Sprintf("%s (Name: %s %s %s)", description, userDetail.FirstName, userDetail.MiddleName, userDetail.LastName)
+	firstName, middleName, lastName := account.GetFullName()
+	description = fmt.Sprintf("%s (Name: %s %s %s)", description, firstName, middleName, lastName)
 
 	model := &models.Footstep{
 		AccountType: accountType,
 		Description: description,
 		Activity:    action,
 	}
-	switch accountType {
-	case "Admin":
-		model.AdminID = &userID
-	case "Owner":
-		model.OwnerID = &userID
-	case "Employee":
-		model.EmployeeID = &userID
-	case "Member":
-		model.MemberID = &userID
-	default:
-		return nil, fmt.Errorf("invalid account type")
-	}
+	kind.Footstep(model, userID)
 	if err := ap.modelResource.FootstepDB.Create(model); err != nil {
 		return nil, fmt.Errorf("failed to create Footstep: %w", err)
 	}
@@ -0,0 +1,353 @@
+package auth_accounts
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/gin-gonic/gin"
+)
+
+// accountKind returns the kind of accountType, or responds with 400 when
+// there is no such account type.
+func (ac *AuthAccount) accountKind(ctx *gin.Context, accountType string) (*models.AccountKind, bool) {
+	kind, err := ac.modelResource.AccountKind(accountType)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Account type doesn't exist"})
+		return nil, false
+	}
+	return kind, true
+}
+
+// signInHook runs the BeforeSignIn hook of the account type once a sign-in
+// is verified. It responds with 403 and returns false when the hook refuses
+// the sign-in.
+func (ac *AuthAccount) signInHook(ctx *gin.Context, action, accountType string, userID uint) bool {
+	kind, ok := ac.accountKind(ctx, accountType)
+	if !ok {
+		return false
+	}
+	if kind.Hooks.BeforeSignIn == nil {
+		return true
+	}
+	account, err := kind.FindByID(userID)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("%s: User not found: %v", action, err)})
+		return false
+	}
+	if err := kind.Hooks.BeforeSignIn(account); err != nil {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("%s: %v", action, err)})
+		return false
+	}
+	return true
+}
+
+// SignUp creates a pending account of accountType from profile, sends the
+// codes to verify its email and contact number, and starts its session.
+func (ac *AuthAccount) SignUp(ctx *gin.Context, accountType string, profile models.AccountProfile, emailTemplate, contactTemplate string) {
+	kind, ok := ac.accountKind(ctx, accountType)
+	if !ok {
+		return
+	}
+	account := kind.New(profile)
+	if kind.Hooks.BeforeSignUp != nil {
+		if err := kind.Hooks.BeforeSignUp(account); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("SignUp: %v", err)})
+			return
+		}
+	}
+	id, err := ac.Create(account, accountType)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err})
+		return
+	}
+
+	emailReq := providers.EmailRequest{
+		To:      account.GetEmail(),
+		Subject: "ECOOP: Email Verification",
+		Body:    emailTemplate,
+	}
+	if err := ac.otpProvider.SendEmailOTP(accountType, id, emailReq); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err})
+		return
+	}
+	contactReq := providers.SMSRequest{
+		To:   account.GetContactNumber(),
+		Body: contactTemplate,
+		Vars: &map[string]string{
+			"name": account.GetName(),
+		},
+	}
+	if err := ac.otpProvider.SendContactNumberOTP(accountType, id, contactReq); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err})
+		return
+	}
+
+	if err := ac.StartSession(ctx, accountType, id); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "SignUp: Session creation error"})
+		return
+	}
+	ctx.JSON(http.StatusCreated, kind.ToResource(account))
+}
+
+// SignIn signs in the account of accountType with the email, username or
+// contact number key and its password.
+func (ac *AuthAccount) SignIn(ctx *gin.Context, accountType, key, password string) {
+	kind, ok := ac.accountKind(ctx, accountType)
+	if !ok {
+		return
+	}
+	account, err := kind.FindByKey(key)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("SignIn: User not found: %v", err)})
+		return
+	}
+	userID := account.GetID()
+	if !ac.signInAllowed(ctx, "SignIn", accountType, userID) {
+		return
+	}
+	if !ac.cryptoHelpers.VerifyPassword(account.GetPassword(), password) {
+		ac.signInFailed(ctx, "SignIn", accountType, userID, "SignIn: Invalid credentials.")
+		return
+	}
+	ac.passwordBreachedCheck(accountType, userID, password)
+	if !ac.signInHook(ctx, "SignIn", accountType, userID) {
+		return
+	}
+	if ac.TwoFactorChallenge(ctx, accountType, userID) {
+		return
+	}
+	ac.signInSucceeded(accountType, userID)
+	if ac.PasswordResetChallenge(ctx, "SignIn", accountType, userID, nil) {
+		return
+	}
+	if err := ac.StartSession(ctx, accountType, userID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "SignIn: Session creation error"})
+		return
+	}
+	user, err := ac.GetByID(accountType, userID)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("SignIn: User not found: %v", err)})
+		return
+	}
+	ctx.JSON(http.StatusOK, user)
+}
+
+// ForgotPassword sends a password reset link to the email or contact number
+// key of an account of accountType.
+func (ac *AuthAccount) ForgotPassword(ctx *gin.Context, accountType, key, emailTemplate, contactTemplate string) {
+	kind, ok := ac.accountKind(ctx, accountType)
+	if !ok {
+		return
+	}
+	account, err := kind.FindByKey(key)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("ForgotPassword: User not found: %v", err)})
+		return
+	}
+
+	token, err := ac.PasswordResetToken(accountType, account.GetID())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err})
+		return
+	}
+
+	resetLink := fmt.Sprintf("%s/auth/password-reset/%s", ac.cfg.AppClientUrl, *token)
+	vars := &map[string]string{
+		"name":      account.GetName(),
+		"eventLink": resetLink,
+	}
+
+	switch keyType := ac.helpers.GetKeyType(key); keyType {
+	case "contact":
+		contactReq := providers.SMSRequest{
+			To:   key,
+			Body: contactTemplate,
+			Vars: vars,
+		}
+		if err := ac.smsProvider.SendSMS(contactReq); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("ForgotPassword: SMS sending error %v", err)})
+			return
+		}
+	case "email":
+		emailReq := providers.EmailRequest{
+			To:      key,
+			Subject: "ECOOP: Change Password Request",
+			Body:    emailTemplate,
+			Vars:    vars,
+		}
+		if err := ac.emailProvider.SendEmail(emailReq); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("ForgotPassword: Email sending error: %v", err)})
+			return
+		}
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("ForgotPassword: Invalid key type: %s", keyType)})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "If the account exists, password reset instructions have been sent."})
+}
+
+func (ac *AuthAccount) ChangePassword(ctx *gin.Context, accountType string, id uint, password string) {
+	if _, ok := ac.accountKind(ctx, accountType); !ok {
+		return
+	}
+	if err := ac.UpdatePassword(accountType, id, password); err != nil {
+		if ac.passwordError(ctx, "ChangePassword", err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("ChangePassword: Password update error: %v", err)})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Password changed successfully."})
+}
+
+// respondUpdate responds with the account updated by an UpdateX helper.
+func (ac *AuthAccount) respondUpdate(ctx *gin.Context, action string, resource interface{}, err error) {
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("%s: User update error: %v", action, err)})
+		return
+	}
+	ctx.JSON(http.StatusOK, resource)
+}
+
+func (ac *AuthAccount) SkipVerification(ctx *gin.Context, accountType string, id uint) {
+	if _, ok := ac.accountKind(ctx, accountType); !ok {
+		return
+	}
+	resource, err := ac.UpdateVerification(accountType, id, "skip", true)
+	ac.respondUpdate(ctx, "SkipVerification", resource, err)
+}
+
+func (ac *AuthAccount) SendEmailVerification(ctx *gin.Context, accountType string, id uint, emailTemplate string) {
+	if _, ok := ac.accountKind(ctx, accountType); !ok {
+		return
+	}
+	email, err := ac.GetByIDForEmail(accountType, id)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("SendEmailVerification: User not found: %v", err)})
+		return
+	}
+	emailReq := providers.EmailRequest{
+		To:      email,
+		Subject: "ECOOP: Email Verification",
+		Body:    emailTemplate,
+	}
+	if err := ac.otpProvider.SendEmailOTP(accountType, id, emailReq); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Email verification sent successfully. Please check your inbox or spam folder."})
+}
+
+// VerifyEmail marks the email of the account verified, once the caller
+// validated the code sent to it.
+func (ac *AuthAccount) VerifyEmail(ctx *gin.Context, accountType string, id uint) {
+	if _, ok := ac.accountKind(ctx, accountType); !ok {
+		return
+	}
+	resource, err := ac.UpdateVerification(accountType, id, "email", true)
+	ac.respondUpdate(ctx, "VerifyEmail", resource, err)
+}
+
+func (ac *AuthAccount) SendContactNumberVerification(ctx *gin.Context, accountType string, id uint, contactTemplate string) {
+	if _, ok := ac.accountKind(ctx, accountType); !ok {
+		return
+	}
+	account, err := ac.account(accountType, id)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("SendContactNumberVerification: User not found: %v", err)})
+		return
+	}
+	contactReq := providers.SMSRequest{
+		To:   account.GetContactNumber(),
+		Body: contactTemplate,
+		Vars: &map[string]string{
+			"name": account.GetName(),
+		},
+	}
+	if err := ac.otpProvider.SendContactNumberOTP(accountType, id, contactReq); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("SendContactNumberVerification: SMS sending error: %v", err)})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Contact number verification OTP sent successfully."})
+}
+
+// VerifyContactNumber marks the contact number of the account verified, once
+// the caller validated the code sent to it.
+func (ac *AuthAccount) VerifyContactNumber(ctx *gin.Context, accountType string, id uint) {
+	if _, ok := ac.accountKind(ctx, accountType); !ok {
+		return
+	}
+	resource, err := ac.UpdateVerification(accountType, id, "contact", true)
+	ac.respondUpdate(ctx, "VerifyContactNumber", resource, err)
+}
+
+func (ac *AuthAccount) ProfilePicture(ctx *gin.Context, accountType string, id uint, mediaId uint) {
+	if _, ok := ac.accountKind(ctx, accountType); !ok {
+		return
+	}
+	resource, err := ac.UpdateProfilePicture(accountType, id, &mediaId)
+	ac.respondUpdate(ctx, "ProfilePicture", resource, err)
+}
+
+func (ac *AuthAccount) ProfileAccountSetting(ctx *gin.Context, accountType string, id uint, birthDate time.Time, firstName, middleName, lastName, description, permanentAddress string) {
+	if _, ok := ac.accountKind(ctx, accountType); !ok {
+		return
+	}
+	resource, err := ac.UpdateProfileAccountSettings(accountType, id, birthDate, firstName, middleName, lastName, description, permanentAddress)
+	ac.respondUpdate(ctx, "ProfileAccountSetting", resource, err)
+}
+
+// profilePasswordConfirmed responds and returns false unless password is the
+// current password of the account, which profile changes ask for.
+func (ac *AuthAccount) profilePasswordConfirmed(ctx *gin.Context, accountType string, id uint, password string) bool {
+	if _, ok := ac.accountKind(ctx, accountType); !ok {
+		return false
+	}
+	if !ac.VerifyPassword(accountType, id, password) {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Wrong password"})
+		return false
+	}
+	return true
+}
+
+func (ac *AuthAccount) ProfileChangeEmail(ctx *gin.Context, accountType string, id uint, password, email string) {
+	if !ac.profilePasswordConfirmed(ctx, accountType, id, password) {
+		return
+	}
+	resource, err := ac.UpdateProfileChangeEmail(accountType, id, email)
+	ac.respondUpdate(ctx, "ProfileChangeEmail", resource, err)
+}
+
+func (ac *AuthAccount) ProfileChangeContactNumber(ctx *gin.Context, accountType string, id uint, password, contactNumber string) {
+	if !ac.profilePasswordConfirmed(ctx, accountType, id, password) {
+		return
+	}
+	resource, err := ac.UpdateProfileChangeContactNumber(accountType, id, contactNumber)
+	ac.respondUpdate(ctx, "ProfileChangeContactNumber", resource, err)
+}
+
+func (ac *AuthAccount) ProfileChangeUsername(ctx *gin.Context, accountType string, id uint, password, username string) {
+	if !ac.profilePasswordConfirmed(ctx, accountType, id, password) {
+		return
+	}
+	resource, err := ac.UpdateProfileChangeUsername(accountType, id, username)
+	ac.respondUpdate(ctx, "ProfileChangeUsername", resource, err)
+}
+
+func (ac *AuthAccount) ProfileChangePassword(ctx *gin.Context, accountType string, id uint, oldPassword, newPassword string) {
+	if !ac.profilePasswordConfirmed(ctx, accountType, id, oldPassword) {
+		return
+	}
+	err := ac.UpdatePassword(accountType, id, newPassword)
+	if ac.passwordError(ctx, "ProfileChangePassword", err) {
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("ProfileChangePassword: User update error: %v", err)})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Password changed successfully."})
+}
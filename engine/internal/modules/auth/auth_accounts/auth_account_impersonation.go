@@ -0,0 +1,135 @@
+package auth_accounts
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ImpersonationExpiration is the lifetime of an impersonation. It is not
+// refreshed; the Admin starts a new one when it runs out.
+const ImpersonationExpiration = time.Minute * 30
+
+// Impersonate signs the Admin in as the user for ImpersonationExpiration. The
+// impersonation replaces the access token cookie only, so the session of the
+// Admin resumes with a refresh once it is stopped. It is not a session of the
+// user and does not show in their session list.
+func (ac *AuthAccount) Impersonate(ctx *gin.Context, adminID uint, accountType string, userID uint, reason string) {
+	user, err := ac.GetByID(accountType, userID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Impersonate: User not found: %v", err)})
+		return
+	}
+	claims := &providers.UserClaims{
+		ID:           userID,
+		AccountType:  accountType,
+		Impersonator: &adminID,
+	}
+	claims.Id = uuid.NewString()
+	token, err := ac.tokenProvider.GenerateToken(claims, ImpersonationExpiration)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Impersonate: Token generation error"})
+		return
+	}
+	if err := ac.tokenProvider.ActivateSession(claims.Id, ImpersonationExpiration); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Impersonate: Session creation error"})
+		return
+	}
+	ac.tokenProvider.SetAccessTokenCookie(ctx, token)
+
+	if reason == "" {
+		reason = "no reason given"
+	}
+	ac.impersonationFootsteps(claims, "Impersonation Started", reason)
+	ctx.JSON(http.StatusOK, gin.H{
+		"user":      user,
+		"expiresAt": time.Unix(claims.ExpiresAt, 0).Format(time.RFC3339),
+	})
+}
+
+// StopImpersonation ends the impersonation of the request. The client then
+// refreshes to resume the session of the Admin.
+func (ac *AuthAccount) StopImpersonation(ctx *gin.Context, claims *providers.UserClaims) {
+	if claims.Impersonator == nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "StopImpersonation: Not impersonating"})
+		return
+	}
+	ac.endImpersonation(claims, "stopped by the Admin")
+	ac.tokenProvider.ClearTokenCookie(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Impersonation stopped."})
+}
+
+// impersonationClaims returns the claims of the access token cookie when it
+// belongs to an impersonation that is still running.
+func (ac *AuthAccount) impersonationClaims(ctx *gin.Context) *providers.UserClaims {
+	token, err := ctx.Cookie(ac.cfg.AppTokenName)
+	if err != nil || token == "" {
+		return nil
+	}
+	claims, err := ac.tokenProvider.VerifyToken(token)
+	if err != nil || claims.Impersonator == nil || !ac.tokenProvider.SessionActive(claims.Id) {
+		return nil
+	}
+	return claims
+}
+
+func (ac *AuthAccount) endImpersonation(claims *providers.UserClaims, reason string) {
+	if err := ac.tokenProvider.RevokeSession(claims.Id); err != nil {
+		ac.logger.Error("Failed to revoke impersonation", zap.Error(err))
+	}
+	ac.impersonationFootsteps(claims, "Impersonation Stopped", reason)
+}
+
+// impersonationFootsteps records the activity on both the Admin and the
+// impersonated user.
+func (ac *AuthAccount) impersonationFootsteps(claims *providers.UserClaims, activity, reason string) {
+	adminDescription := fmt.Sprintf("%s %d impersonated: %s", claims.AccountType, claims.ID, reason)
+	if _, err := ac.AccountFootstep("Admin", *claims.Impersonator, activity, adminDescription); err != nil {
+		ac.logger.Error("Failed to record impersonation footstep", zap.Error(err))
+	}
+	userDescription := fmt.Sprintf("Impersonated by Admin %d: %s", *claims.Impersonator, reason)
+	if _, err := ac.AccountFootstep(claims.AccountType, claims.ID, activity, userDescription); err != nil {
+		ac.logger.Error("Failed to record impersonation footstep", zap.Error(err))
+	}
+}
+
+// CurrentUser responds with the user of claims. During an impersonation the
+// response also names the Admin and when the impersonation ends.
+func (ac *AuthAccount) CurrentUser(ctx *gin.Context, claims *providers.UserClaims) {
+	user, err := ac.GetByID(claims.AccountType, claims.ID)
+	if err != nil {
+		ac.tokenProvider.ClearTokenCookie(ctx)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User not found."})
+		return
+	}
+	if claims.Impersonator == nil {
+		ctx.JSON(http.StatusOK, user)
+		return
+	}
+
+	// Add the impersonation to the fields of the user resource
+	raw, err := json.Marshal(user)
+	var response gin.H
+	if err == nil {
+		err = json.Unmarshal(raw, &response)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "CurrentUser: User encoding error"})
+		return
+	}
+	impersonation := gin.H{
+		"adminID":   *claims.Impersonator,
+		"expiresAt": time.Unix(claims.ExpiresAt, 0).Format(time.RFC3339),
+	}
+	if admin, err := ac.modelResource.AdminDB.FindByID(*claims.Impersonator); err == nil {
+		impersonation["adminName"] = fmt.Sprintf("%s %s", admin.FirstName, admin.LastName)
+	}
+	response["impersonation"] = impersonation
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,124 @@
+package auth_accounts
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// accountLockedEmail tells the owner of an account that it was locked and
+// when it unlocks.
+const accountLockedEmail = `<p>Hello,</p>
+<p>{{.reason}} of your {{.app}} account was locked after too many failed attempts.
+It unlocks automatically at {{.unlockAt}}.</p>
+<p>If this was not you, reset your password. Resetting it also unlocks sign-in.</p>`
+
+func lockoutSubject(accountType string, userID uint) string {
+	return fmt.Sprintf("%s:%d", accountType, userID)
+}
+
+// lockoutError responds with 429 and a Retry-After header when err is a
+// *providers.LockoutError and returns true.
+func (ac *AuthAccount) lockoutError(ctx *gin.Context, action string, err error) bool {
+	var lockErr *providers.LockoutError
+	if !errors.As(err, &lockErr) {
+		return false
+	}
+	ctx.Header("Retry-After", strconv.Itoa(int(lockErr.RetryAfter.Round(time.Second).Seconds())))
+	ctx.JSON(http.StatusTooManyRequests, gin.H{
+		"error":      fmt.Sprintf("%s: %v", action, lockErr),
+		"retryAfter": int(lockErr.RetryAfter.Round(time.Second).Seconds()),
+	})
+	return true
+}
+
+// signInAllowed responds with 429 and returns false while the account is
+// locked out of signing in or has to wait before its next attempt.
+func (ac *AuthAccount) signInAllowed(ctx *gin.Context, action, accountType string, userID uint) bool {
+	err := ac.lockout.Check(providers.SignInLockout, lockoutSubject(accountType, userID))
+	if ac.lockoutError(ctx, action, err) {
+		return false
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("%s: Lockout lookup error", action)})
+		return false
+	}
+	return true
+}
+
+// signInFailed counts a wrong password or two-factor code and responds with
+// message, or with 429 when the attempt locked the account.
+func (ac *AuthAccount) signInFailed(ctx *gin.Context, action, accountType string, userID uint, message string) {
+	lockErr, err := ac.lockout.Fail(providers.SignInLockout, lockoutSubject(accountType, userID))
+	if err != nil {
+		ac.logger.Error("Failed to record failed sign-in", zap.Error(err))
+	}
+	if lockErr != nil && lockErr.Locked {
+		ac.accountLocked(accountType, userID, "Sign-in", lockErr)
+		ac.lockoutError(ctx, action, lockErr)
+		return
+	}
+	ctx.JSON(http.StatusUnauthorized, gin.H{"error": message})
+}
+
+// signInSucceeded clears the failed sign-ins of the account.
+func (ac *AuthAccount) signInSucceeded(accountType string, userID uint) {
+	ac.lockout.Reset(providers.SignInLockout, lockoutSubject(accountType, userID))
+}
+
+// ValidateOTP checks the OTP sent to the user through mediumType and responds
+// on its behalf unless it is valid.
+func (ac *AuthAccount) ValidateOTP(ctx *gin.Context, action, accountType string, userID uint, otp, mediumType string) bool {
+	isValid, err := ac.otpProvider.ValidateOTP(accountType, userID, otp, mediumType)
+	var lockErr *providers.LockoutError
+	if errors.As(err, &lockErr) {
+		if lockErr.Locked {
+			ac.accountLocked(accountType, userID, fmt.Sprintf("Verification by %s", mediumType), lockErr)
+		}
+		ac.lockoutError(ctx, action, lockErr)
+		return false
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("%s: OTP validation error: %v", action, err)})
+		return false
+	}
+	if !isValid {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("%s: Invalid or expired OTP", action)})
+		return false
+	}
+	return true
+}
+
+// accountLocked records a footstep of the lockout for admins to review and
+// tells the user by email when the account unlocks.
+func (ac *AuthAccount) accountLocked(accountType string, userID uint, reason string, lockErr *providers.LockoutError) {
+	unlockAt := time.Now().Add(lockErr.RetryAfter)
+	description := fmt.Sprintf("%s locked until %s after too many failed attempts", reason, unlockAt.Format(time.RFC3339))
+	if _, err := ac.AccountFootstep(accountType, userID, "Account Locked", description); err != nil {
+		ac.logger.Error("Failed to record account lockout", zap.Error(err))
+	}
+
+	email, err := ac.GetByIDForEmail(accountType, userID)
+	if err != nil || email == "" {
+		return
+	}
+	err = ac.emailProvider.SendEmail(providers.EmailRequest{
+		To:      email,
+		Subject: fmt.Sprintf("%s: account locked", ac.cfg.AppName),
+		Body:    accountLockedEmail,
+		Vars: &map[string]string{
+			"reason":   reason,
+			"app":      ac.cfg.AppName,
+			"unlockAt": unlockAt.Format("Jan 2, 2006 3:04 PM MST"),
+		},
+	})
+	if err != nil {
+		ac.logger.Error("Failed to send account lockout email", zap.Error(err))
+	}
+}
@@ -0,0 +1,148 @@
+package auth_accounts
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// oidcBindingPath limits the sign-in binding cookie to the OIDC routes.
+const oidcBindingPath = "/api/v1/auth/oidc"
+
+// oidcBindingName is the cookie holding the secret that binds a sign-in at an
+// identity provider to the browser that started it.
+func (ac *AuthAccount) oidcBindingName() string {
+	return ac.cfg.AppTokenName + "_oidc"
+}
+
+// setOIDCBinding sets the binding cookie to value for maxAge seconds, or
+// clears it when maxAge is negative.
+func (ac *AuthAccount) setOIDCBinding(ctx *gin.Context, value string, maxAge int) {
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     ac.oidcBindingName(),
+		Value:    value,
+		Path:     oidcBindingPath,
+		HttpOnly: true,
+		Secure:   true,
+		MaxAge:   maxAge,
+		SameSite: http.SameSiteNoneMode,
+	})
+}
+
+// OIDCProviders responds with the identity providers users can sign in with,
+// without their client secrets.
+func (ac *AuthAccount) OIDCProviders(ctx *gin.Context) {
+	providerList := []gin.H{}
+	for _, provider := range ac.oidc.Providers() {
+		providerList = append(providerList, gin.H{
+			"name":         provider.Name,
+			"companyId":    provider.CompanyID,
+			"accountTypes": provider.AccountTypes,
+		})
+	}
+	ctx.JSON(http.StatusOK, providerList)
+}
+
+// OIDCAuthorize starts a sign-in of accountType at the identity provider and
+// responds with the URL to send the user to. The provider sends them back to
+// the client with a code and the state, which the client posts to OIDCSignIn
+// from the same browser.
+func (ac *AuthAccount) OIDCAuthorize(ctx *gin.Context, providerName, accountType string) {
+	authorizationURL, binding, err := ac.oidc.AuthorizationURL(ctx.Request.Context(), providerName, accountType)
+	if errors.Is(err, providers.ErrOIDCProviderNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("OIDCAuthorize: %v", err)})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("OIDCAuthorize: %v", err)})
+		return
+	}
+	ac.setOIDCBinding(ctx, binding, int(providers.OIDCStateExpiration.Seconds()))
+	ctx.JSON(http.StatusOK, gin.H{"url": authorizationURL})
+}
+
+// OIDCSignIn completes a sign-in at an identity provider and signs in the
+// Owner or Employee the identity is linked to.
+func (ac *AuthAccount) OIDCSignIn(ctx *gin.Context, state, code string) {
+	binding, err := ctx.Cookie(ac.oidcBindingName())
+	if err != nil || binding == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("OIDCSignIn: %v", providers.ErrOIDCState)})
+		return
+	}
+	claims, err := ac.oidc.Exchange(ctx.Request.Context(), state, binding, code)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("OIDCSignIn: %v", err)})
+		return
+	}
+	ac.setOIDCBinding(ctx, "", -1)
+	userID, linked, err := ac.oidcUser(claims)
+	if err != nil {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("OIDCSignIn: %v", err)})
+		return
+	}
+	if !ac.signInAllowed(ctx, "OIDCSignIn", claims.AccountType, userID) {
+		return
+	}
+	if linked {
+		description := fmt.Sprintf("Linked to %s identity %s", claims.Provider.Name, claims.Email)
+		if _, err := ac.AccountFootstep(claims.AccountType, userID, "OIDC Account Linked", description); err != nil {
+			ac.logger.Error("Failed to record OIDC link footstep", zap.Error(err))
+		}
+	}
+	ac.completeSignIn(ctx, "OIDCSignIn", claims.AccountType, userID, "OIDC Sign In", fmt.Sprintf("Signed in with %s", claims.Provider.Name))
+}
+
+// oidcUser returns the account the identity of claims is linked to. The first
+// sign-in links the account with the verified email of the identity, and
+// reports it. The account has to belong to the company of the provider.
+func (ac *AuthAccount) oidcUser(claims *providers.OIDCClaims) (uint, bool, error) {
+	identity, err := ac.modelResource.OIDCIdentityGet(claims.Issuer, claims.Subject, claims.AccountType)
+	if err != nil {
+		return 0, false, err
+	}
+
+	linked := false
+	if identity == nil {
+		if claims.Email == "" || !claims.EmailVerified {
+			return 0, false, errors.New("the identity provider did not verify an email")
+		}
+		userID, err := ac.FindByEmailUsernameOrContactForID(claims.AccountType, claims.Email)
+		if err != nil {
+			return 0, false, fmt.Errorf("no %s account with the email %s", claims.AccountType, claims.Email)
+		}
+		now := time.Now()
+		identity = &models.OIDCIdentity{
+			Issuer:       claims.Issuer,
+			Subject:      claims.Subject,
+			AccountType:  claims.AccountType,
+			UserID:       userID,
+			Email:        claims.Email,
+			LastSignInAt: &now,
+		}
+		linked = true
+	}
+
+	belongs, err := ac.modelResource.TenantBelongs(identity.AccountType, identity.UserID, claims.Provider.CompanyID)
+	if err != nil {
+		return 0, false, err
+	}
+	if !belongs {
+		return 0, false, fmt.Errorf("the account does not belong to the company of %s", claims.Provider.Name)
+	}
+
+	if linked {
+		err = ac.modelResource.OIDCIdentityLink(identity)
+	} else {
+		err = ac.modelResource.OIDCIdentityTouch(identity, claims.Email)
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return identity.UserID, linked, nil
+}
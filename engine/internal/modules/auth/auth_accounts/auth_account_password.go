@@ -0,0 +1,195 @@
+package auth_accounts
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// PasswordResetExpiration bounds the time a user who has to reset their
+	// password at sign-in, or who forgot it, has to choose a new one.
+	PasswordResetExpiration = time.Minute * 10
+	// PasswordResetPurpose is the purpose of password reset tokens.
+	PasswordResetPurpose = "password_reset"
+)
+
+// ErrPasswordResetToken is returned for a password reset token that is not
+// one, was used already or expired.
+var ErrPasswordResetToken = errors.New("invalid or expired password reset link")
+
+func passwordResetKey(tokenHash string) string {
+	return "password_reset:" + tokenHash
+}
+
+// ValidatePasswordPolicy checks a new password against the password policy.
+func (ac *AuthAccount) ValidatePasswordPolicy(password string) error {
+	return ac.passwordPolicy.Validate(password)
+}
+
+// passwordError responds with 400 and returns true when err rejects a new
+// password, rather than failing to store it.
+func (ac *AuthAccount) passwordError(ctx *gin.Context, action string, err error) bool {
+	var policyErr *providers.PasswordPolicyError
+	if !errors.As(err, &policyErr) && !errors.Is(err, providers.ErrPasswordBreached) && !errors.Is(err, models.ErrPasswordReused) {
+		return false
+	}
+	ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s: %v", action, err)})
+	return true
+}
+
+// passwordBreachedCheck forces a reset of a password that was accepted before
+// it appeared in the breached password list. It is called once the password
+// of a sign-in is verified.
+func (ac *AuthAccount) passwordBreachedCheck(accountType string, userID uint, password string) {
+	breached, err := ac.passwordPolicy.Breached(password)
+	if err != nil {
+		ac.logger.Error("Failed to check breached password list", zap.Error(err))
+		return
+	}
+	if !breached {
+		return
+	}
+	if err := ac.modelResource.PasswordRequireReset(accountType, userID, true); err != nil {
+		ac.logger.Error("Failed to require password reset", zap.Error(err))
+		return
+	}
+	if _, err := ac.AccountFootstep(accountType, userID, "Password Reset Required", "Password found in the breached password list"); err != nil {
+		ac.logger.Error("Failed to record password reset footstep", zap.Error(err))
+	}
+}
+
+// PasswordResetToken issues a token that lets the user choose a new password
+// once within PasswordResetExpiration. The token is recorded in the cache,
+// which is what makes it usable.
+func (ac *AuthAccount) PasswordResetToken(accountType string, userID uint) (*string, error) {
+	if _, err := ac.modelResource.AccountKind(accountType); err != nil {
+		return nil, err
+	}
+	claims := &providers.UserClaims{
+		ID:          userID,
+		AccountType: accountType,
+		Purpose:     PasswordResetPurpose,
+	}
+	token, err := ac.tokenProvider.GenerateToken(claims, PasswordResetExpiration)
+	if err != nil {
+		return nil, err
+	}
+	subject := fmt.Sprintf("%s:%d", accountType, userID)
+	if err := ac.cache.Set(passwordResetKey(ac.cryptoHelpers.HashToken(token)), subject, PasswordResetExpiration); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// PasswordResetVerify returns the claims of a password reset token that was
+// issued by PasswordResetToken and not used yet. Session and impersonation
+// tokens are never password reset tokens.
+func (ac *AuthAccount) PasswordResetVerify(token string) (*providers.UserClaims, error) {
+	claims, err := ac.tokenProvider.VerifyToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != PasswordResetPurpose || claims.Id != "" || claims.Impersonator != nil {
+		return nil, ErrPasswordResetToken
+	}
+	subject, err := ac.cache.Get(passwordResetKey(ac.cryptoHelpers.HashToken(token)))
+	if errors.Is(err, providers.ErrCacheMiss) {
+		return nil, ErrPasswordResetToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if subject != fmt.Sprintf("%s:%d", claims.AccountType, claims.ID) {
+		return nil, ErrPasswordResetToken
+	}
+	return claims, nil
+}
+
+// PasswordReset sets the password of the user a password reset token was
+// issued for, using the token up. A password the policy rejects leaves the
+// token usable for another try until it expires.
+func (ac *AuthAccount) PasswordReset(ctx *gin.Context, token, password string) {
+	claims, err := ac.PasswordResetVerify(token)
+	if err != nil {
+		ac.tokenProvider.ClearTokenCookie(ctx)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("ChangePassword: Token verification error: %v", err)})
+		return
+	}
+	// Only the request that deletes the token may use it
+	key := passwordResetKey(ac.cryptoHelpers.HashToken(token))
+	deleted, err := ac.cache.Client.Del(key).Result()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "ChangePassword: Cache error"})
+		return
+	}
+	if deleted != 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("ChangePassword: Token verification error: %v", ErrPasswordResetToken)})
+		return
+	}
+	ac.ChangePassword(ctx, claims.AccountType, claims.ID, password)
+	if ctx.Writer.Status() == http.StatusOK {
+		return
+	}
+	if remaining := time.Until(time.Unix(claims.ExpiresAt, 0)); remaining > 0 {
+		subject := fmt.Sprintf("%s:%d", claims.AccountType, claims.ID)
+		if err := ac.cache.Set(key, subject, remaining); err != nil {
+			ac.logger.Error("Failed to restore password reset token", zap.Error(err))
+		}
+	}
+}
+
+// PasswordResetChallenge is called once a sign-in is verified. When the user
+// has to choose a new password first it responds with a reset token for the
+// change-password endpoint instead of a session, along with the fields of
+// extra, and returns true; otherwise it returns false and the caller issues
+// the session as usual.
+func (ac *AuthAccount) PasswordResetChallenge(ctx *gin.Context, action, accountType string, userID uint, extra gin.H) bool {
+	required, err := ac.modelResource.PasswordResetRequired(accountType, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("%s: Password lookup error", action)})
+		return true
+	}
+	if !required {
+		return false
+	}
+	token, err := ac.PasswordResetToken(accountType, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("%s: Token generation error", action)})
+		return true
+	}
+	response := gin.H{
+		"error":                 fmt.Sprintf("%s: Password reset required", action),
+		"passwordResetRequired": true,
+		"resetId":               *token,
+	}
+	for key, value := range extra {
+		response[key] = value
+	}
+	ctx.JSON(http.StatusForbidden, response)
+	return true
+}
+
+// RequirePasswordReset forces the user to choose a new password at their
+// next sign-in.
+func (ac *AuthAccount) RequirePasswordReset(ctx *gin.Context, accountType string, userID uint, adminID uint) {
+	if _, ok := ac.accountKind(ctx, accountType); !ok {
+		return
+	}
+	err := ac.modelResource.PasswordRequireReset(accountType, userID, true)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("RequirePasswordReset: User not found: %v", err)})
+		return
+	}
+	description := fmt.Sprintf("Password reset required by Admin %d", adminID)
+	if _, err := ac.AccountFootstep(accountType, userID, "Password Reset Required", description); err != nil {
+		ac.logger.Error("Failed to record password reset footstep", zap.Error(err))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "The user has to reset their password at their next sign-in."})
+}
@@ -0,0 +1,210 @@
+package auth_accounts
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	// MagicLinkExpiration is the lifetime of a sign-in link.
+	MagicLinkExpiration = time.Minute * 10
+	// passwordlessResendInterval is the time a user waits before asking for
+	// another code or link.
+	passwordlessResendInterval = time.Minute
+	// passwordlessSentMessage answers every sign-in request, whether the
+	// account exists or not.
+	passwordlessSentMessage = "If the account exists, sign-in instructions have been sent."
+)
+
+func magicLinkKey(tokenHash string) string {
+	return "magic_link:" + tokenHash
+}
+
+func passwordlessSentKey(accountType string, userID uint) string {
+	return fmt.Sprintf("passwordless_sent:%s:%d", accountType, userID)
+}
+
+// passwordlessAllowed responds with 403 and returns false unless a company of
+// the user lets its accountType sign in without a password.
+func (ac *AuthAccount) passwordlessAllowed(ctx *gin.Context, action, accountType string, userID uint) bool {
+	allowed, err := ac.modelResource.PasswordlessAllowed(accountType, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("%s: Company lookup error", action)})
+		return false
+	}
+	if !allowed {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("%s: Passwordless sign-in is not enabled for this account", action)})
+		return false
+	}
+	return true
+}
+
+// passwordlessMedium returns the OTP medium of a sign-in with key: a contact
+// number signs in by SMS, an email or username by email.
+func (ac *AuthAccount) passwordlessMedium(key string) string {
+	if ac.helpers.GetKeyType(key) == "contact" {
+		return providers.OTPMediumSignInSMS
+	}
+	return providers.OTPMediumSignInEmail
+}
+
+// PasswordlessRequest sends the user of key a sign-in code, or a single-use
+// sign-in link when method is "link", by SMS when key is a contact number and
+// by email otherwise. The templates receive the name of the user, and the
+// code as "otp" or the link as "eventLink".
+func (ac *AuthAccount) PasswordlessRequest(ctx *gin.Context, accountType, key, method, emailTemplate, contactTemplate string) {
+	userID, err := ac.FindByEmailUsernameOrContactForID(accountType, key)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Unknown keys get the answer of a sent sign-in, so that the request
+		// does not reveal which accounts exist
+		ctx.JSON(http.StatusOK, gin.H{"message": passwordlessSentMessage})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("PasswordlessRequest: %v", err)})
+		return
+	}
+	if !ac.signInAllowed(ctx, "PasswordlessRequest", accountType, userID) {
+		return
+	}
+	if !ac.passwordlessAllowed(ctx, "PasswordlessRequest", accountType, userID) {
+		return
+	}
+	first, err := ac.cache.Client.SetNX(passwordlessSentKey(accountType, userID), 1, passwordlessResendInterval).Result()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "PasswordlessRequest: Cache error"})
+		return
+	}
+	if !first {
+		ctx.Header("Retry-After", strconv.Itoa(int(passwordlessResendInterval.Seconds())))
+		ctx.JSON(http.StatusTooManyRequests, gin.H{"error": "PasswordlessRequest: Wait before requesting another sign-in"})
+		return
+	}
+
+	name, err := ac.FindByEmailUsernameOrContactForName(accountType, key)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("PasswordlessRequest: User not found: %v", err)})
+		return
+	}
+	medium := ac.passwordlessMedium(key)
+	vars := map[string]string{"name": name}
+	var to string
+	if medium == providers.OTPMediumSignInSMS {
+		to, err = ac.GetByIDForContact(accountType, userID)
+	} else {
+		to, err = ac.GetByIDForEmail(accountType, userID)
+	}
+	if err != nil || to == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "PasswordlessRequest: No address to send the sign-in to"})
+		return
+	}
+
+	if method == "link" {
+		token, err := ac.cryptoHelpers.GenerateSecureToken(32)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "PasswordlessRequest: Token generation error"})
+			return
+		}
+		subject := fmt.Sprintf("%s:%d", accountType, userID)
+		if err := ac.cache.Set(magicLinkKey(ac.cryptoHelpers.HashToken(token)), subject, MagicLinkExpiration); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "PasswordlessRequest: Cache error"})
+			return
+		}
+		vars["eventLink"] = fmt.Sprintf("%s/auth/magic-link/%s", ac.cfg.AppClientUrl, token)
+		if medium == providers.OTPMediumSignInSMS {
+			err = ac.smsProvider.SendSMS(providers.SMSRequest{To: to, Body: contactTemplate, Vars: &vars})
+		} else {
+			err = ac.emailProvider.SendEmail(providers.EmailRequest{
+				To:      to,
+				Subject: "ECOOP: Sign-in Link",
+				Body:    emailTemplate,
+				Vars:    &vars,
+			})
+		}
+	} else if medium == providers.OTPMediumSignInSMS {
+		err = ac.otpProvider.SendContactNumberOTPFor(accountType, userID, medium, providers.SMSRequest{
+			To:   to,
+			Body: contactTemplate,
+			Vars: &vars,
+		})
+	} else {
+		err = ac.otpProvider.SendEmailOTPFor(accountType, userID, medium, providers.EmailRequest{
+			To:      to,
+			Subject: "ECOOP: Sign-in Code",
+			Body:    emailTemplate,
+			Vars:    &vars,
+		})
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("PasswordlessRequest: Sending error: %v", err)})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": passwordlessSentMessage})
+}
+
+// PasswordlessSignIn signs the user of key in with the code sent by
+// PasswordlessRequest.
+func (ac *AuthAccount) PasswordlessSignIn(ctx *gin.Context, accountType, key, code string) {
+	userID, err := ac.FindByEmailUsernameOrContactForID(accountType, key)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("PasswordlessSignIn: User not found: %v", err)})
+		return
+	}
+	if !ac.signInAllowed(ctx, "PasswordlessSignIn", accountType, userID) {
+		return
+	}
+	if !ac.passwordlessAllowed(ctx, "PasswordlessSignIn", accountType, userID) {
+		return
+	}
+	if !ac.ValidateOTP(ctx, "PasswordlessSignIn", accountType, userID, code, ac.passwordlessMedium(key)) {
+		return
+	}
+	ac.completeSignIn(ctx, "PasswordlessSignIn", accountType, userID, "Passwordless Sign In", "Signed in with a code")
+}
+
+// MagicLinkSignIn signs in the user a link sent by PasswordlessRequest was
+// made for. The link is consumed by its first use.
+func (ac *AuthAccount) MagicLinkSignIn(ctx *gin.Context, token string) {
+	key := magicLinkKey(ac.cryptoHelpers.HashToken(token))
+	subject, err := ac.cache.Get(key)
+	if errors.Is(err, providers.ErrCacheMiss) {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "MagicLinkSignIn: Invalid or expired link"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "MagicLinkSignIn: Cache error"})
+		return
+	}
+	// Only the request that deletes the link may use it
+	deleted, err := ac.cache.Client.Del(key).Result()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "MagicLinkSignIn: Cache error"})
+		return
+	}
+	if deleted != 1 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "MagicLinkSignIn: Invalid or expired link"})
+		return
+	}
+
+	accountType, id, found := strings.Cut(subject, ":")
+	userID, err := strconv.ParseUint(id, 10, 64)
+	if !found || err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "MagicLinkSignIn: Invalid or expired link"})
+		return
+	}
+	if !ac.signInAllowed(ctx, "MagicLinkSignIn", accountType, uint(userID)) {
+		return
+	}
+	if !ac.passwordlessAllowed(ctx, "MagicLinkSignIn", accountType, uint(userID)) {
+		return
+	}
+	ac.completeSignIn(ctx, "MagicLinkSignIn", accountType, uint(userID), "Passwordless Sign In", "Signed in with a link")
+}
@@ -0,0 +1,240 @@
+package auth_accounts
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// AccessTokenExpiration is the lifetime of the access token cookie; the
+	// client refreshes it with the refresh token.
+	AccessTokenExpiration = time.Minute * 15
+	// RefreshTokenExpiration is how long a session survives without being
+	// refreshed.
+	RefreshTokenExpiration = time.Hour * 24 * 7
+	// sessionUserAgentLength is the size of the user agent column.
+	sessionUserAgentLength = 512
+)
+
+// StartSession signs the user in on the device of the request: it records a
+// new session and sets its access and refresh token cookies.
+func (ac *AuthAccount) StartSession(ctx *gin.Context, accountType string, userID uint) error {
+	refreshToken, err := ac.cryptoHelpers.GenerateSecureToken(32)
+	if err != nil {
+		return err
+	}
+	userAgent := ctx.Request.UserAgent()
+	if len(userAgent) > sessionUserAgentLength {
+		userAgent = userAgent[:sessionUserAgentLength]
+	}
+	ip := ctx.ClientIP()
+	if actor, ok := managers.AuditActorFromContext(ctx.Request.Context()); ok && actor.IP != "" {
+		ip = actor.IP
+	}
+	now := time.Now()
+	session := &models.Session{
+		JTI:         uuid.NewString(),
+		AccountType: accountType,
+		UserID:      userID,
+		IP:          ip,
+		UserAgent:   userAgent,
+		LastSeenAt:  now,
+		ExpiresAt:   now.Add(RefreshTokenExpiration),
+	}
+	if err := ac.modelResource.SessionCreate(session, ac.cryptoHelpers.HashToken(refreshToken)); err != nil {
+		return err
+	}
+	return ac.issueSessionTokens(ctx, session, refreshToken)
+}
+
+// issueSessionTokens activates session and sets a new access token for it
+// along with refreshToken.
+func (ac *AuthAccount) issueSessionTokens(ctx *gin.Context, session *models.Session, refreshToken string) error {
+	token, err := ac.generateToken(session.UserID, session.AccountType, session.JTI, AccessTokenExpiration)
+	if err != nil {
+		return err
+	}
+	if err := ac.tokenProvider.ActivateSession(session.JTI, time.Until(session.ExpiresAt)); err != nil {
+		return err
+	}
+	ac.tokenProvider.SetTokenCookies(ctx, *token, refreshToken, time.Until(session.ExpiresAt))
+	return nil
+}
+
+// completeSignIn finishes a sign-in verified without a password, like a
+// password sign-in does: it runs the sign-in hook of the account type, asks
+// for the second factor when needed, then starts the session, records
+// activity as a footstep and responds with the user. There is no password to
+// reset, so the password reset challenge is left to password sign-ins.
+func (ac *AuthAccount) completeSignIn(ctx *gin.Context, action, accountType string, userID uint, activity, description string) {
+	if !ac.signInHook(ctx, action, accountType, userID) {
+		return
+	}
+	if ac.TwoFactorChallenge(ctx, accountType, userID) {
+		return
+	}
+	ac.signInSucceeded(accountType, userID)
+	if err := ac.StartSession(ctx, accountType, userID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("%s: Session creation error", action)})
+		return
+	}
+	user, err := ac.GetByID(accountType, userID)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("%s: User not found: %v", action, err)})
+		return
+	}
+	if _, err := ac.AccountFootstep(accountType, userID, activity, description); err != nil {
+		ac.logger.Error("Failed to record sign-in footstep", zap.Error(err))
+	}
+	ctx.JSON(http.StatusOK, user)
+}
+
+// RefreshSession exchanges the refresh token cookie for a new access token
+// and a new refresh token. A refresh token presented twice means it leaked,
+// so the whole session is revoked. During an impersonation the refresh token
+// belongs to the Admin, so it is left alone until the impersonation ends.
+func (ac *AuthAccount) RefreshSession(ctx *gin.Context) {
+	if claims := ac.impersonationClaims(ctx); claims != nil {
+		ctx.JSON(http.StatusOK, gin.H{"expiresIn": int(time.Until(time.Unix(claims.ExpiresAt, 0)).Seconds())})
+		return
+	}
+	refreshToken, err := ctx.Cookie(ac.tokenProvider.RefreshTokenName())
+	if err != nil || refreshToken == "" {
+		ac.tokenProvider.ClearSessionCookies(ctx)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh: Refresh token not found"})
+		return
+	}
+	newRefreshToken, err := ac.cryptoHelpers.GenerateSecureToken(32)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Refresh: Token generation error"})
+		return
+	}
+	session, err := ac.modelResource.SessionRotate(
+		ac.cryptoHelpers.HashToken(refreshToken),
+		ac.cryptoHelpers.HashToken(newRefreshToken),
+		time.Now().Add(RefreshTokenExpiration),
+	)
+	if errors.Is(err, models.ErrRefreshTokenReused) {
+		if err := ac.tokenProvider.RevokeSession(session.JTI); err != nil {
+			ac.logger.Error("Failed to revoke reused session", zap.Error(err))
+		}
+		ac.AccountFootstep(session.AccountType, session.UserID, "Session Revoked", "A refresh token was reused, the session was revoked")
+		ac.tokenProvider.ClearSessionCookies(ctx)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("Refresh: %v", err)})
+		return
+	}
+	if errors.Is(err, models.ErrSessionNotFound) {
+		ac.tokenProvider.ClearSessionCookies(ctx)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("Refresh: %v", err)})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Refresh: Session update error"})
+		return
+	}
+	if err := ac.issueSessionTokens(ctx, session, newRefreshToken); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Refresh: Token generation error"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"expiresIn": int(AccessTokenExpiration.Seconds())})
+}
+
+// EndSession revokes the session of the request, found from its refresh
+// token or else its access token, and clears the cookies. Signing out during
+// an impersonation also ends it.
+func (ac *AuthAccount) EndSession(ctx *gin.Context) {
+	defer ac.tokenProvider.ClearSessionCookies(ctx)
+
+	if claims := ac.impersonationClaims(ctx); claims != nil {
+		ac.endImpersonation(claims, "signed out")
+	}
+
+	var session *models.Session
+	if refreshToken, err := ctx.Cookie(ac.tokenProvider.RefreshTokenName()); err == nil && refreshToken != "" {
+		session, _ = ac.modelResource.SessionByRefreshToken(ac.cryptoHelpers.HashToken(refreshToken))
+	}
+	if session == nil {
+		if token, err := ctx.Cookie(ac.cfg.AppTokenName); err == nil {
+			if claims, err := ac.tokenProvider.VerifyToken(token); err == nil && claims.Id != "" {
+				session, _ = ac.modelResource.SessionGet(claims.Id)
+			}
+		}
+	}
+	if session == nil {
+		return
+	}
+	if _, err := ac.modelResource.SessionRevoke(session.AccountType, session.UserID, session.ID); err != nil {
+		ac.logger.Error("Failed to revoke session on sign out", zap.Error(err))
+		return
+	}
+	if err := ac.tokenProvider.RevokeSession(session.JTI); err != nil {
+		ac.logger.Error("Failed to remove signed out session from cache", zap.Error(err))
+	}
+}
+
+func (ac *AuthAccount) SessionList(ctx *gin.Context, accountType string, userID uint, currentJTI string) {
+	sessions, err := ac.modelResource.SessionList(accountType, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "SessionList: Session lookup error"})
+		return
+	}
+	ctx.JSON(http.StatusOK, ac.modelResource.SessionToResourceList(sessions, currentJTI))
+}
+
+// SessionRevoke signs the user out of one of their sessions.
+func (ac *AuthAccount) SessionRevoke(ctx *gin.Context, accountType string, userID uint, currentJTI string, sessionID uint) {
+	session, err := ac.modelResource.SessionRevoke(accountType, userID, sessionID)
+	if errors.Is(err, models.ErrSessionNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("SessionRevoke: %v", err)})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "SessionRevoke: Session update error"})
+		return
+	}
+	if session.JTI == currentJTI {
+		ac.tokenProvider.ClearSessionCookies(ctx)
+	}
+	if err := ac.tokenProvider.RevokeSession(session.JTI); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "SessionRevoke: Session cache error"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Session signed out."})
+}
+
+// SessionRevokeAll signs the user out of all their sessions, or of all but the
+// current one when keepCurrent is set.
+func (ac *AuthAccount) SessionRevokeAll(ctx *gin.Context, accountType string, userID uint, currentJTI string, keepCurrent bool) {
+	except := ""
+	if keepCurrent {
+		except = currentJTI
+	}
+	jtis, err := ac.modelResource.SessionRevokeAll(accountType, userID, except)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "SessionRevokeAll: Session update error"})
+		return
+	}
+	var cacheErr error
+	for _, jti := range jtis {
+		if err := ac.tokenProvider.RevokeSession(jti); err != nil {
+			cacheErr = err
+		}
+	}
+	if !keepCurrent {
+		ac.tokenProvider.ClearSessionCookies(ctx)
+	}
+	ac.AccountFootstep(accountType, userID, "Sessions Revoked", fmt.Sprintf("Signed out of %d sessions", len(jtis)))
+	if cacheErr != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "SessionRevokeAll: Session cache error"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Sessions signed out.", "count": len(jtis)})
+}
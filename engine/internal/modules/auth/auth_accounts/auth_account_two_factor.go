@@ -0,0 +1,265 @@
+package auth_accounts
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
+	"github.com/gin-gonic/gin"
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	// TwoFactorChallengeExpiration bounds the time between the password step
+	// and the two-factor step of a sign-in.
+	TwoFactorChallengeExpiration = time.Minute * 5
+	// twoFactorMaxAttempts is the number of codes accepted per challenge.
+	twoFactorMaxAttempts = 5
+)
+
+func twoFactorChallengeKey(challenge string) string {
+	return "two_factor_challenge:" + challenge
+}
+
+func twoFactorAttemptsKey(challenge string) string {
+	return "two_factor_attempts:" + challenge
+}
+
+// TwoFactorChallenge is called once the password of a sign-in is verified.
+// When the user needs a second factor it responds with a challenge instead of
+// a session and returns true; otherwise it returns false and the caller
+// issues the session as usual.
+func (ac *AuthAccount) TwoFactorChallenge(ctx *gin.Context, accountType string, userID uint) bool {
+	required, err := ac.modelResource.TwoFactorRequired(accountType, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "SignIn: Two-factor lookup error"})
+		return true
+	}
+	if !required {
+		return false
+	}
+	twoFactor, err := ac.modelResource.TwoFactorGet(accountType, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "SignIn: Two-factor lookup error"})
+		return true
+	}
+
+	challenge, err := ac.cryptoHelpers.GenerateSecureToken(32)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "SignIn: Challenge generation error"})
+		return true
+	}
+	value := fmt.Sprintf("%s:%d", accountType, userID)
+	if err := ac.cache.Set(twoFactorChallengeKey(challenge), value, TwoFactorChallengeExpiration); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "SignIn: Challenge generation error"})
+		return true
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"twoFactorRequired": true,
+		"setupRequired":     twoFactor == nil || !twoFactor.Enabled,
+		"challenge":         challenge,
+	})
+	return true
+}
+
+// twoFactorChallengeUser resolves a challenge to its user, counting the
+// attempt and discarding the challenge after too many.
+func (ac *AuthAccount) twoFactorChallengeUser(challenge string) (string, uint, error) {
+	value, err := ac.cache.Get(twoFactorChallengeKey(challenge))
+	if err != nil {
+		return "", 0, errors.New("challenge expired or not found")
+	}
+	attempts, err := ac.cache.IncrementWithExpiration(twoFactorAttemptsKey(challenge), TwoFactorChallengeExpiration)
+	if err != nil {
+		return "", 0, err
+	}
+	if attempts > twoFactorMaxAttempts {
+		ac.twoFactorDiscardChallenge(challenge)
+		return "", 0, errors.New("too many attempts, sign in again")
+	}
+	accountType, rawID, found := strings.Cut(value, ":")
+	id, err := strconv.ParseUint(rawID, 10, 64)
+	if !found || err != nil {
+		return "", 0, errors.New("invalid challenge")
+	}
+	return accountType, uint(id), nil
+}
+
+func (ac *AuthAccount) twoFactorDiscardChallenge(challenge string) {
+	_ = ac.cache.Delete(twoFactorChallengeKey(challenge))
+	_ = ac.cache.Delete(twoFactorAttemptsKey(challenge))
+}
+
+// TwoFactorSignIn completes a sign-in started by TwoFactorChallenge. For users
+// who had to enroll during sign-in, the code confirms the enrollment and the
+// response also carries their recovery codes.
+func (ac *AuthAccount) TwoFactorSignIn(ctx *gin.Context, challenge, code string) {
+	accountType, userID, err := ac.twoFactorChallengeUser(challenge)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("TwoFactorSignIn: %v", err)})
+		return
+	}
+	if !ac.signInAllowed(ctx, "TwoFactorSignIn", accountType, userID) {
+		return
+	}
+	twoFactor, err := ac.modelResource.TwoFactorGet(accountType, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "TwoFactorSignIn: Two-factor lookup error"})
+		return
+	}
+
+	var recoveryCodes []string
+	if twoFactor != nil && !twoFactor.Enabled {
+		recoveryCodes, err = ac.modelResource.TwoFactorConfirm(accountType, userID, code)
+	} else {
+		err = ac.modelResource.TwoFactorVerify(accountType, userID, code)
+	}
+	if errors.Is(err, models.ErrTwoFactorInvalidCode) {
+		ac.signInFailed(ctx, "TwoFactorSignIn", accountType, userID, fmt.Sprintf("TwoFactorSignIn: %v", err))
+		return
+	}
+	if errors.Is(err, models.ErrTwoFactorNotEnrolled) {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("TwoFactorSignIn: %v", err)})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "TwoFactorSignIn: Verification error"})
+		return
+	}
+	ac.twoFactorDiscardChallenge(challenge)
+	ac.signInSucceeded(accountType, userID)
+	var extra gin.H
+	if recoveryCodes != nil {
+		extra = gin.H{"recoveryCodes": recoveryCodes}
+	}
+	if ac.PasswordResetChallenge(ctx, "TwoFactorSignIn", accountType, userID, extra) {
+		return
+	}
+
+	if err := ac.StartSession(ctx, accountType, userID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "TwoFactorSignIn: Session creation error"})
+		return
+	}
+	user, err := ac.GetByID(accountType, userID)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("TwoFactorSignIn: User not found: %v", err)})
+		return
+	}
+	if recoveryCodes != nil {
+		ctx.JSON(http.StatusCreated, gin.H{"user": user, "recoveryCodes": recoveryCodes})
+		return
+	}
+	ctx.JSON(http.StatusOK, user)
+}
+
+// TwoFactorChallengeSetup lets users whose role requires two-factor
+// authentication enroll in the middle of a sign-in.
+func (ac *AuthAccount) TwoFactorChallengeSetup(ctx *gin.Context, challenge string) {
+	accountType, userID, err := ac.twoFactorChallengeUser(challenge)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("TwoFactorSetup: %v", err)})
+		return
+	}
+	ac.TwoFactorSetup(ctx, accountType, userID)
+}
+
+// TwoFactorSetup generates a new secret and responds with its provisioning
+// URI and QR code. The enrollment is confirmed by the first valid code.
+func (ac *AuthAccount) TwoFactorSetup(ctx *gin.Context, accountType string, userID uint) {
+	secret, err := ac.modelResource.TwoFactorSetup(accountType, userID)
+	if errors.Is(err, models.ErrTwoFactorEnabled) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "TwoFactorSetup: Secret generation error"})
+		return
+	}
+	email, err := ac.GetByIDForEmail(accountType, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "TwoFactorSetup: User not found"})
+		return
+	}
+	uri := ac.cryptoHelpers.TOTPProvisioningURI(ac.cfg.AppName, email, secret)
+	png, err := qrcode.Encode(uri, qrcode.Medium, 256)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "TwoFactorSetup: QR code generation error"})
+		return
+	}
+	ctx.JSON(http.StatusOK, models.TwoFactorSetupResource{
+		Secret:          secret,
+		ProvisioningURI: uri,
+		QRCode:          "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	})
+}
+
+func (ac *AuthAccount) TwoFactorStatus(ctx *gin.Context, accountType string, userID uint) {
+	status, err := ac.modelResource.TwoFactorStatus(accountType, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "TwoFactorStatus: Two-factor lookup error"})
+		return
+	}
+	ctx.JSON(http.StatusOK, status)
+}
+
+func (ac *AuthAccount) TwoFactorConfirm(ctx *gin.Context, accountType string, userID uint, code string) {
+	recoveryCodes, err := ac.modelResource.TwoFactorConfirm(accountType, userID, code)
+	if !ac.twoFactorError(ctx, "TwoFactorConfirm", err) {
+		return
+	}
+	ac.AccountFootstep(accountType, userID, "Two-Factor Enabled", "Two-factor authentication was enabled")
+	ctx.JSON(http.StatusOK, gin.H{"recoveryCodes": recoveryCodes})
+}
+
+// TwoFactorDisable turns two-factor authentication off after checking both
+// the password and a current code.
+func (ac *AuthAccount) TwoFactorDisable(ctx *gin.Context, accountType string, userID uint, password, code string) {
+	if !ac.VerifyPassword(accountType, userID, password) {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "TwoFactorDisable: Invalid credentials."})
+		return
+	}
+	if !ac.twoFactorError(ctx, "TwoFactorDisable", ac.modelResource.TwoFactorVerify(accountType, userID, code)) {
+		return
+	}
+	if !ac.twoFactorError(ctx, "TwoFactorDisable", ac.modelResource.TwoFactorDisable(accountType, userID)) {
+		return
+	}
+	ac.AccountFootstep(accountType, userID, "Two-Factor Disabled", "Two-factor authentication was disabled")
+	ctx.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled."})
+}
+
+func (ac *AuthAccount) TwoFactorRecoveryCodes(ctx *gin.Context, accountType string, userID uint, code string) {
+	if !ac.twoFactorError(ctx, "TwoFactorRecoveryCodes", ac.modelResource.TwoFactorVerify(accountType, userID, code)) {
+		return
+	}
+	recoveryCodes, err := ac.modelResource.TwoFactorRegenerateRecoveryCodes(accountType, userID)
+	if !ac.twoFactorError(ctx, "TwoFactorRecoveryCodes", err) {
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"recoveryCodes": recoveryCodes})
+}
+
+// twoFactorError responds to err, if any, and reports whether the request can
+// go on.
+func (ac *AuthAccount) twoFactorError(ctx *gin.Context, action string, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, models.ErrTwoFactorInvalidCode):
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("%s: %v", action, err)})
+	case errors.Is(err, models.ErrTwoFactorNotEnrolled):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s: %v", action, err)})
+	case errors.Is(err, models.ErrTwoFactorEnabled):
+		ctx.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("%s: %v", action, err)})
+	case errors.Is(err, models.ErrTwoFactorRequired):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("%s: %v", action, err)})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("%s: Internal error", action)})
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Impersonate lets an Admin act as another user to see what they see. It
+// runs behind AuthMiddlewareAdminOnly.
+func (as AuthService) Impersonate(ctx *gin.Context) {
+	var req ImpersonateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Impersonate: JSON binding error: %v", err)})
+		return
+	}
+	if err := as.authProvider.ValidateImpersonate(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Impersonate: Validation error: %v", err)})
+		return
+	}
+	claims, err := as.getUserClaims(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
+		return
+	}
+	as.authAccount.Impersonate(ctx, claims.ID, req.AccountType, req.ID, req.Reason)
+}
+
+func (as AuthService) StopImpersonation(ctx *gin.Context) {
+	claims, err := as.getUserClaims(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
+		return
+	}
+	as.authAccount.StopImpersonation(ctx, claims)
+}
@@ -0,0 +1,14 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKS publishes the public keys of the access tokens so other services can
+// verify them. Keys are rotated, so clients should not cache them for long.
+func (as AuthService) JWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, as.tokenProvider.JWKS())
+}
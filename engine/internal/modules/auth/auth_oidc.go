@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (as AuthService) OIDCProviders(ctx *gin.Context) {
+	as.authAccount.OIDCProviders(ctx)
+}
+
+// OIDCAuthorize starts a sign-in at the identity provider of a company.
+func (as AuthService) OIDCAuthorize(ctx *gin.Context) {
+	var req OIDCAuthorizeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("OIDCAuthorize: JSON binding error: %v", err)})
+		return
+	}
+	if err := as.authProvider.ValidateOIDCAuthorize(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("OIDCAuthorize: Validation error: %v", err)})
+		return
+	}
+	as.authAccount.OIDCAuthorize(ctx, req.Provider, req.AccountType)
+}
+
+// OIDCSignIn exchanges the code the identity provider redirected the client
+// with for a session.
+func (as AuthService) OIDCSignIn(ctx *gin.Context) {
+	var req OIDCSignInRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("OIDCSignIn: JSON binding error: %v", err)})
+		return
+	}
+	if err := as.authProvider.ValidateOIDCSignIn(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("OIDCSignIn: Validation error: %v", err)})
+		return
+	}
+	as.authAccount.OIDCSignIn(ctx, req.State, req.Code)
+}
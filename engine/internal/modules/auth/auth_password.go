@@ -0,0 +1,24 @@
+package auth
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePasswordReset lets admins force a user to choose a new password at
+// their next sign-in.
+func (as AuthService) RequirePasswordReset(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "RequirePasswordReset: Invalid user ID"})
+		return
+	}
+	claims, err := as.getUserClaims(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
+		return
+	}
+	as.authAccount.RequirePasswordReset(ctx, ctx.Param("accountType"), uint(id), claims.ID)
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Passwordless sends a sign-in code or link to users whose company lets them
+// sign in without a password.
+func (as AuthService) Passwordless(ctx *gin.Context) {
+	var req PasswordlessRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Passwordless: JSON binding error: %v", err)})
+		return
+	}
+	if err := as.authProvider.ValidatePasswordless(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Passwordless: Validation error: %v", err)})
+		return
+	}
+	as.authAccount.PasswordlessRequest(ctx, req.AccountType, req.Key, req.Method, req.EmailTemplate, req.ContactTemplate)
+}
+
+func (as AuthService) PasswordlessSignIn(ctx *gin.Context) {
+	var req PasswordlessSignInRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("PasswordlessSignIn: JSON binding error: %v", err)})
+		return
+	}
+	if err := as.authProvider.ValidatePasswordlessSignIn(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("PasswordlessSignIn: Validation error: %v", err)})
+		return
+	}
+	as.authAccount.PasswordlessSignIn(ctx, req.AccountType, req.Key, req.Code)
+}
+
+// MagicLinkSignIn exchanges the token of a sign-in link for a session. The
+// link opens the client, which posts the token, so that mail scanners
+// following the link do not consume it.
+func (as AuthService) MagicLinkSignIn(ctx *gin.Context) {
+	var req MagicLinkSignInRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("MagicLinkSignIn: JSON binding error: %v", err)})
+		return
+	}
+	if err := as.authProvider.ValidateMagicLinkSignIn(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("MagicLinkSignIn: Validation error: %v", err)})
+		return
+	}
+	as.authAccount.MagicLinkSignIn(ctx, req.Token)
+}
@@ -104,6 +104,58 @@ type ChangePasswordSettingRequest struct {
 	ConfirmPassword string `json:"confirm_password" validate:"required,min=8,max=255"`
 }
 
+type TwoFactorSignInRequest struct {
+	Challenge string `json:"challenge" validate:"required,max=255"`
+	Code      string `json:"code" validate:"required,min=6,max=32"`
+}
+
+type TwoFactorChallengeRequest struct {
+	Challenge string `json:"challenge" validate:"required,max=255"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required,min=6,max=32"`
+}
+
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" validate:"required,min=8,max=255"`
+	Code     string `json:"code" validate:"required,min=6,max=32"`
+}
+
+type ImpersonateRequest struct {
+	AccountType string `json:"accountType" validate:"required,oneof=Owner Employee Member"`
+	ID          uint   `json:"id" validate:"required"`
+	Reason      string `json:"reason" validate:"max=255"`
+}
+
+type PasswordlessRequest struct {
+	Key             string `json:"key" validate:"required,max=255"`
+	AccountType     string `json:"accountType" validate:"required,oneof=Owner Employee Member"`
+	Method          string `json:"method" validate:"required,oneof=code link"`
+	EmailTemplate   string `json:"emailTemplate"`
+	ContactTemplate string `json:"contactTemplate"`
+}
+
+type PasswordlessSignInRequest struct {
+	Key         string `json:"key" validate:"required,max=255"`
+	AccountType string `json:"accountType" validate:"required,oneof=Owner Employee Member"`
+	Code        string `json:"code" validate:"required,min=6,max=32"`
+}
+
+type MagicLinkSignInRequest struct {
+	Token string `json:"token" validate:"required,max=255"`
+}
+
+type OIDCAuthorizeRequest struct {
+	Provider    string `json:"provider" validate:"required,max=255"`
+	AccountType string `json:"accountType" validate:"required,oneof=Owner Employee"`
+}
+
+type OIDCSignInRequest struct {
+	State string `json:"state" validate:"required,max=255"`
+	Code  string `json:"code" validate:"required,max=2048"`
+}
+
 func NewAuthProvider(
 	cfg *config.AppConfig,
 	cryptoHelpers *helpers.HelpersCryptography,
@@ -117,14 +169,8 @@ func NewAuthProvider(
 }
 
 func (ap *AuthProvider) AccountTypeValidator(fl validator.FieldLevel) bool {
-	accountType := fl.Field().String()
-	validTypes := []string{"Member", "Employee", "Admin", "Owner"}
-	for _, validType := range validTypes {
-		if accountType == validType {
-			return true
-		}
-	}
-	return false
+	_, err := ap.modelResource.AccountKind(fl.Field().String())
+	return err == nil
 }
 
 func (ap *AuthProvider) ValidateSignUp(r SignUpRequest) error {
@@ -271,3 +317,59 @@ func (ap *AuthProvider) ValidateChangePasswordSetting(r ChangePasswordSettingReq
 	}
 	return nil
 }
+
+func (ap *AuthProvider) ValidateTwoFactorSignIn(r TwoFactorSignInRequest) error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (ap *AuthProvider) ValidateTwoFactorChallenge(r TwoFactorChallengeRequest) error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (ap *AuthProvider) ValidateTwoFactorCode(r TwoFactorCodeRequest) error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (ap *AuthProvider) ValidateTwoFactorDisable(r TwoFactorDisableRequest) error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (ap *AuthProvider) ValidateImpersonate(r ImpersonateRequest) error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (ap *AuthProvider) ValidatePasswordless(r PasswordlessRequest) error {
+	validate := validator.New()
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+	if r.EmailTemplate == "" && r.ContactTemplate == "" {
+		return fmt.Errorf("either emailTemplate or contactTemplate must be provided")
+	}
+	return nil
+}
+
+func (ap *AuthProvider) ValidatePasswordlessSignIn(r PasswordlessSignInRequest) error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (ap *AuthProvider) ValidateMagicLinkSignIn(r MagicLinkSignInRequest) error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (ap *AuthProvider) ValidateOIDCAuthorize(r OIDCAuthorizeRequest) error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (ap *AuthProvider) ValidateOIDCSignIn(r OIDCSignInRequest) error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
@@ -68,83 +68,23 @@ func (as AuthService) SignUp(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err})
 		return
 	}
+	if err := as.authAccount.ValidatePasswordPolicy(req.Password); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("SignUp: %v", err)})
+		return
+	}
 
-	switch req.AccountType {
-	case "Member":
-		user := &models.Member{
-			FirstName:         req.FirstName,
-			LastName:          req.LastName,
-			MiddleName:        req.MiddleName,
-			PermanentAddress:  req.PermanentAddress,
-			Description:       "",
-			BirthDate:         req.BirthDate,
-			Username:          req.Username,
-			Email:             req.Email,
-			Password:          req.Password,
-			IsEmailVerified:   false,
-			IsContactVerified: false,
-			ContactNumber:     req.ContactNumber,
-			MediaID:           nil,
-			Status:            "Pending",
-		}
-		as.authAccount.MemberSignUp(ctx, user, req.EmailTemplate, req.ContactTemplate)
-	case "Admin":
-		user := &models.Admin{
-			FirstName:         req.FirstName,
-			LastName:          req.LastName,
-			MiddleName:        req.MiddleName,
-			PermanentAddress:  req.PermanentAddress,
-			Description:       "",
-			BirthDate:         req.BirthDate,
-			Username:          req.Username,
-			Email:             req.Email,
-			Password:          req.Password,
-			IsEmailVerified:   false,
-			IsContactVerified: false,
-			ContactNumber:     req.ContactNumber,
-			MediaID:           nil,
-			Status:            "Pending",
-		}
-		as.authAccount.AdminSignUp(ctx, user, req.EmailTemplate, req.ContactTemplate)
-	case "Owner":
-		user := &models.Owner{
-			FirstName:         req.FirstName,
-			LastName:          req.LastName,
-			MiddleName:        req.MiddleName,
-			PermanentAddress:  req.PermanentAddress,
-			Description:       "",
-			BirthDate:         req.BirthDate,
-			Username:          req.Username,
-			Email:             req.Email,
-			Password:          req.Password,
-			IsEmailVerified:   false,
-			IsContactVerified: false,
-			ContactNumber:     req.ContactNumber,
-			MediaID:           nil,
-			Status:            "Pending",
-		}
-		as.authAccount.OwnerSignUp(ctx, user, req.EmailTemplate, req.ContactTemplate)
-	case "Employee":
-		user := &models.Employee{
-			FirstName:         req.FirstName,
-			LastName:          req.LastName,
-			MiddleName:        req.MiddleName,
-			PermanentAddress:  req.PermanentAddress,
-			Description:       "",
-			BirthDate:         req.BirthDate,
-			Username:          req.Username,
-			Email:             req.Email,
-			Password:          req.Password,
-			IsEmailVerified:   false,
-			IsContactVerified: false,
-			ContactNumber:     req.ContactNumber,
-			MediaID:           nil,
-			Status:            "Pending",
-		}
-		as.authAccount.EmployeeSignUp(ctx, user, req.EmailTemplate, req.ContactTemplate)
-	default:
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Account type doesn't exist"})
+	profile := models.AccountProfile{
+		FirstName:        req.FirstName,
+		LastName:         req.LastName,
+		MiddleName:       req.MiddleName,
+		PermanentAddress: req.PermanentAddress,
+		BirthDate:        req.BirthDate,
+		Username:         req.Username,
+		Email:            req.Email,
+		Password:         req.Password,
+		ContactNumber:    req.ContactNumber,
 	}
+	as.authAccount.SignUp(ctx, req.AccountType, profile, req.EmailTemplate, req.ContactTemplate)
 }
 
 func (as AuthService) SignIn(ctx *gin.Context) {
@@ -157,19 +97,7 @@ func (as AuthService) SignIn(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err})
 		return
 	}
-	switch req.AccountType {
-	case "Member":
-		as.authAccount.MemberSignIn(ctx, req.Key, req.Password)
-	case "Admin":
-		as.authAccount.AdminSignIn(ctx, req.Key, req.Password)
-	case "Owner":
-		as.authAccount.OwnerSignIn(ctx, req.Key, req.Password)
-	case "Employee":
-		as.authAccount.EmployeeSignIn(ctx, req.Key, req.Password)
-	default:
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Account type doesn't exist"})
-	}
-
+	as.authAccount.SignIn(ctx, req.AccountType, req.Key, req.Password)
 }
 
 func (as AuthService) ForgotPassword(ctx *gin.Context) {
@@ -182,18 +110,7 @@ func (as AuthService) ForgotPassword(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err})
 		return
 	}
-	switch req.AccountType {
-	case "Member":
-		as.authAccount.MemberForgotPassword(ctx, req.Key, req.EmailTemplate, req.ContactTemplate)
-	case "Admin":
-		as.authAccount.AdminForgotPassword(ctx, req.Key, req.EmailTemplate, req.ContactTemplate)
-	case "Owner":
-		as.authAccount.OwnerForgotPassword(ctx, req.Key, req.EmailTemplate, req.ContactTemplate)
-	case "Employee":
-		as.authAccount.EmployeeForgotPassword(ctx, req.Key, req.EmailTemplate, req.ContactTemplate)
-	default:
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Account type doesn't exist"})
-	}
+	as.authAccount.ForgotPassword(ctx, req.AccountType, req.Key, req.EmailTemplate, req.ContactTemplate)
 }
 
 func (as AuthService) ChangePassword(ctx *gin.Context) {
@@ -207,29 +124,11 @@ func (as AuthService) ChangePassword(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("ChangePassword: Validation error: %v", err)})
 		return
 	}
-	claims, err := as.tokenProvider.VerifyToken(req.ResetID)
-	if err != nil {
-		as.tokenProvider.ClearTokenCookie(ctx)
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("ChangePassword: Token verification error: %v", err)})
-		return
-	}
-	switch claims.AccountType {
-	case "Member":
-		as.authAccount.MemberChangePassword(ctx, claims.ID, req.NewPassword)
-	case "Admin":
-		as.authAccount.AdminChangePassword(ctx, claims.ID, req.NewPassword)
-	case "Owner":
-		as.authAccount.OwnerChangePassword(ctx, claims.ID, req.NewPassword)
-	case "Employee":
-		as.authAccount.EmployeeChangePassword(ctx, claims.ID, req.NewPassword)
-	default:
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Account type doesn't exist"})
-	}
-	as.tokenProvider.DeleteToken(req.ResetID)
+	as.authAccount.PasswordReset(ctx, req.ResetID, req.NewPassword)
 }
 
 func (as AuthService) VerifyResetLink(ctx *gin.Context) {
-	_, err := as.tokenProvider.VerifyToken(ctx.Param("id"))
+	_, err := as.authAccount.PasswordResetVerify(ctx.Param("id"))
 	if err != nil {
 		as.tokenProvider.ClearTokenCookie(ctx)
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("VerifyResetLink: Token verification error: %v", err)})
@@ -239,7 +138,7 @@ func (as AuthService) VerifyResetLink(ctx *gin.Context) {
 }
 
 func (as AuthService) SignOut(ctx *gin.Context) {
-	as.tokenProvider.ClearTokenCookie(ctx)
+	as.authAccount.EndSession(ctx)
 	ctx.JSON(http.StatusOK, gin.H{"message": "Successfully signed out"})
 }
 
@@ -250,13 +149,7 @@ func (as AuthService) CurrentUser(ctx *gin.Context) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
 		return
 	}
-	user, err := as.authAccount.GetByID(userClaims.AccountType, userClaims.ID)
-	if err != nil {
-		as.tokenProvider.ClearTokenCookie(ctx)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User not found."})
-		return
-	}
-	ctx.JSON(http.StatusOK, user)
+	as.authAccount.CurrentUser(ctx, userClaims)
 }
 
 func (as AuthService) NewPassword(ctx *gin.Context) {
@@ -274,19 +167,7 @@ func (as AuthService) NewPassword(ctx *gin.Context) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
 		return
 	}
-	switch claims.AccountType {
-	case "Member":
-		as.authAccount.MemberChangePassword(ctx, claims.ID, req.NewPassword)
-	case "Admin":
-		as.authAccount.AdminChangePassword(ctx, claims.ID, req.NewPassword)
-	case "Owner":
-		as.authAccount.OwnerChangePassword(ctx, claims.ID, req.NewPassword)
-	case "Employee":
-		as.authAccount.EmployeeChangePassword(ctx, claims.ID, req.NewPassword)
-	default:
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Account type doesn't exist"})
-	}
-
+	as.authAccount.ChangePassword(ctx, claims.AccountType, claims.ID, req.NewPassword)
 }
 
 func (as AuthService) SkipVerification(ctx *gin.Context) {
@@ -295,18 +176,7 @@ func (as AuthService) SkipVerification(ctx *gin.Context) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
 		return
 	}
-	switch claims.AccountType {
-	case "Member":
-		as.authAccount.MemberSkipVerification(ctx, claims.ID)
-	case "Admin":
-		as.authAccount.AdminSkipVerification(ctx, claims.ID)
-	case "Owner":
-		as.authAccount.OwnerSkipVerification(ctx, claims.ID)
-	case "Employee":
-		as.authAccount.EmployeeSkipVerification(ctx, claims.ID)
-	default:
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Account type doesn't exist"})
-	}
+	as.authAccount.SkipVerification(ctx, claims.AccountType, claims.ID)
 }
 
 func (as AuthService) SendEmailVerification(ctx *gin.Context) {
@@ -324,18 +194,7 @@ func (as AuthService) SendEmailVerification(ctx *gin.Context) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
 		return
 	}
-	switch claims.AccountType {
-	case "Member":
-		as.authAccount.MemberSendEmailVerification(ctx, claims.ID, req.EmailTemplate)
-	case "Admin":
-		as.authAccount.AdminSendEmailVerification(ctx, claims.ID, req.EmailTemplate)
-	case "Owner":
-		as.authAccount.OwnerSendEmailVerification(ctx, claims.ID, req.EmailTemplate)
-	case "Employee":
-		as.authAccount.EmployeeSendEmailVerification(ctx, claims.ID, req.EmailTemplate)
-	default:
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Account type doesn't exist"})
-	}
+	as.authAccount.SendEmailVerification(ctx, claims.AccountType, claims.ID, req.EmailTemplate)
 }
 
 func (as AuthService) VerifyEmail(ctx *gin.Context) {
@@ -354,28 +213,11 @@ func (as AuthService) VerifyEmail(ctx *gin.Context) {
 		return
 	}
 
-	isValid, err := as.otpProvider.ValidateOTP(claims.AccountType, claims.ID, req.Otp, "email")
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("VerifyEmail: OTP validation error: %v", err)})
-		return
-	}
-	if !isValid {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "VerifyEmail: Invalid or expired OTP"})
+	if !as.authAccount.ValidateOTP(ctx, "VerifyEmail", claims.AccountType, claims.ID, req.Otp, "email") {
 		return
 	}
 
-	switch claims.AccountType {
-	case "Member":
-		as.authAccount.MemberVerifyEmail(ctx, claims.ID)
-	case "Admin":
-		as.authAccount.AdminVerifyEmail(ctx, claims.ID)
-	case "Owner":
-		as.authAccount.OwnerVerifyEmail(ctx, claims.ID)
-	case "Employee":
-		as.authAccount.EmployeeVerifyEmail(ctx, claims.ID)
-	default:
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Account type doesn't exist"})
-	}
+	as.authAccount.VerifyEmail(ctx, claims.AccountType, claims.ID)
 }
 
 func (as AuthService) SendContactNumberVerification(ctx *gin.Context) {
@@ -393,18 +235,7 @@ func (as AuthService) SendContactNumberVerification(ctx *gin.Context) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
 		return
 	}
-	switch claims.AccountType {
-	case "Member":
-		as.authAccount.MemberSendContactNumberVerification(ctx, claims.ID, req.ContactTemplate)
-	case "Admin":
-		as.authAccount.AdminSendContactNumberVerification(ctx, claims.ID, req.ContactTemplate)
-	case "Owner":
-		as.authAccount.OwnerSendContactNumberVerification(ctx, claims.ID, req.ContactTemplate)
-	case "Employee":
-		as.authAccount.EmployeeSendContactNumberVerification(ctx, claims.ID, req.ContactTemplate)
-	default:
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Account type doesn't exist"})
-	}
+	as.authAccount.SendContactNumberVerification(ctx, claims.AccountType, claims.ID, req.ContactTemplate)
 }
 
 func (as AuthService) VerifyContactNumber(ctx *gin.Context) {
@@ -424,28 +255,11 @@ func (as AuthService) VerifyContactNumber(ctx *gin.Context) {
 		return
 	}
 
-	isValid, err := as.otpProvider.ValidateOTP(claims.AccountType, claims.ID, req.Otp, "sms")
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("VerifyContactNumber: OTP validation error: %v", err)})
-		return
-	}
-	if !isValid {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "VerifyContactNumber: Invalid or expired OTP"})
+	if !as.authAccount.ValidateOTP(ctx, "VerifyContactNumber", claims.AccountType, claims.ID, req.Otp, "sms") {
 		return
 	}
 
-	switch claims.AccountType {
-	case "Member":
-		as.authAccount.MemberVerifyContactNumber(ctx, claims.ID)
-	case "Admin":
-		as.authAccount.AdminVerifyContactNumber(ctx, claims.ID)
-	case "Owner":
-		as.authAccount.OwnerVerifyContactNumber(ctx, claims.ID)
-	case "Employee":
-		as.authAccount.EmployeeVerifyContactNumber(ctx, claims.ID)
-	default:
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Account type doesn't exist"})
-	}
+	as.authAccount.VerifyContactNumber(ctx, claims.AccountType, claims.ID)
 }
 
 func (as AuthService) ProfilePicture(ctx *gin.Context) {
@@ -465,19 +279,7 @@ func (as AuthService) ProfilePicture(ctx *gin.Context) {
 		return
 	}
 
-	switch claims.AccountType {
-	case "Member":
-		as.authAccount.MemberProfilePicture(ctx, claims.ID, *req.ID)
-	case "Admin":
-		as.authAccount.AdminProfilePicture(ctx, claims.ID, *req.ID)
-	case "Owner":
-		as.authAccount.OwnerProfilePicture(ctx, claims.ID, *req.ID)
-	case "Employee":
-		as.authAccount.EmployeeProfilePicture(ctx, claims.ID, *req.ID)
-	default:
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Account type doesn't exist"})
-	}
-
+	as.authAccount.ProfilePicture(ctx, claims.AccountType, claims.ID, *req.ID)
 }
 func (as AuthService) ProfileAccountSetting(ctx *gin.Context) {
 	var req *AccountSettingRequest
@@ -496,18 +298,7 @@ func (as AuthService) ProfileAccountSetting(ctx *gin.Context) {
 		return
 	}
 
-	switch claims.AccountType {
-	case "Member":
-		as.authAccount.MemberProfileAccountSetting(ctx, claims.ID, req.BirthDate, req.FirstName, req.MiddleName, req.LastName, req.Description, req.PermanentAddress)
-	case "Admin":
-		as.authAccount.AdminProfileAccountSetting(ctx, claims.ID, req.BirthDate, req.FirstName, req.MiddleName, req.LastName, req.Description, req.PermanentAddress)
-	case "Owner":
-		as.authAccount.OwnerProfileAccountSetting(ctx, claims.ID, req.BirthDate, req.FirstName, req.MiddleName, req.LastName, req.Description, req.PermanentAddress)
-	case "Employee":
-		as.authAccount.EmployeeProfileAccountSetting(ctx, claims.ID, req.BirthDate, req.FirstName, req.MiddleName, req.LastName, req.Description, req.PermanentAddress)
-	default:
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Account type doesn't exist"})
-	}
+	as.authAccount.ProfileAccountSetting(ctx, claims.AccountType, claims.ID, req.BirthDate, req.FirstName, req.MiddleName, req.LastName, req.Description, req.PermanentAddress)
 }
 func (as AuthService) ProfileChangeEmail(ctx *gin.Context) {
 	var req *ChangeEmailRequest
@@ -526,18 +317,7 @@ func (as AuthService) ProfileChangeEmail(ctx *gin.Context) {
 		return
 	}
 
-	switch claims.AccountType {
-	case "Member":
-		as.authAccount.MemberProfileChangeEmail(ctx, claims.ID, req.Password, req.Email)
-	case "Admin":
-		as.authAccount.AdminProfileChangeEmail(ctx, claims.ID, req.Password, req.Email)
-	case "Owner":
-		as.authAccount.OwnerProfileChangeEmail(ctx, claims.ID, req.Password, req.Email)
-	case "Employee":
-		as.authAccount.EmployeeProfileChangeEmail(ctx, claims.ID, req.Password, req.Email)
-	default:
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Account type doesn't exist"})
-	}
+	as.authAccount.ProfileChangeEmail(ctx, claims.AccountType, claims.ID, req.Password, req.Email)
 }
 func (as AuthService) ProfileChangeContactNumber(ctx *gin.Context) {
 	var req *ChangeContactNumberRequest
@@ -556,18 +336,7 @@ func (as AuthService) ProfileChangeContactNumber(ctx *gin.Context) {
 		return
 	}
 
-	switch claims.AccountType {
-	case "Member":
-		as.authAccount.MemberProfileChangeContactNumber(ctx, claims.ID, req.Password, req.ContactNumber)
-	case "Admin":
-		as.authAccount.AdminProfileChangeContactNumber(ctx, claims.ID, req.Password, req.ContactNumber)
-	case "Owner":
-		as.authAccount.OwnerProfileChangeContactNumber(ctx, claims.ID, req.Password, req.ContactNumber)
-	case "Employee":
-		as.authAccount.EmployeeProfileChangeContactNumber(ctx, claims.ID, req.Password, req.ContactNumber)
-	default:
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Account type doesn't exist"})
-	}
+	as.authAccount.ProfileChangeContactNumber(ctx, claims.AccountType, claims.ID, req.Password, req.ContactNumber)
 }
 func (as AuthService) ProfileChangeUsername(ctx *gin.Context) {
 	var req *ChangeUsernameRequest
@@ -586,18 +355,7 @@ func (as AuthService) ProfileChangeUsername(ctx *gin.Context) {
 		return
 	}
 
-	switch claims.AccountType {
-	case "Member":
-		as.authAccount.MemberProfileChangeUsername(ctx, claims.ID, req.Password, req.Username)
-	case "Admin":
-		as.authAccount.AdminProfileChangeUsername(ctx, claims.ID, req.Password, req.Username)
-	case "Owner":
-		as.authAccount.OwnerProfileChangeUsername(ctx, claims.ID, req.Password, req.Username)
-	case "Employee":
-		as.authAccount.EmployeeProfileChangeUsername(ctx, claims.ID, req.Password, req.Username)
-	default:
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Account type doesn't exist"})
-	}
+	as.authAccount.ProfileChangeUsername(ctx, claims.AccountType, claims.ID, req.Password, req.Username)
 }
 func (as AuthService) ProfileChangePassword(ctx *gin.Context) {
 	var req *ChangePasswordSettingRequest
@@ -615,36 +373,37 @@ func (as AuthService) ProfileChangePassword(ctx *gin.Context) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
 		return
 	}
-	switch claims.AccountType {
-	case "Member":
-		as.authAccount.MemberProfileChangePassword(ctx, claims.ID, req.OldPassword, req.NewPassword)
-	case "Admin":
-		as.authAccount.AdminProfileChangePassword(ctx, claims.ID, req.OldPassword, req.NewPassword)
-	case "Owner":
-		as.authAccount.OwnerProfileChangePassword(ctx, claims.ID, req.OldPassword, req.NewPassword)
-	case "Employee":
-		as.authAccount.EmployeeProfileChangePassword(ctx, claims.ID, req.OldPassword, req.NewPassword)
-	default:
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Account type doesn't exist"})
-	}
+	as.authAccount.ProfileChangePassword(ctx, claims.AccountType, claims.ID, req.OldPassword, req.NewPassword)
 }
 
 func (as *AuthService) RegisterRoutes() {
+	as.engine.Client.GET("/.well-known/jwks.json", as.JWKS)
+
 	authRoutes := as.engine.Client.Group("/api/v1/auth")
 	{
 		// Public Auth Endpoints
 		authRoutes.POST("/signup", as.SignUp)
 		authRoutes.POST("/signin", as.SignIn)
+		authRoutes.POST("/signin/two-factor", as.TwoFactorSignIn)
+		authRoutes.POST("/signin/two-factor/setup", as.TwoFactorChallengeSetup)
 		authRoutes.POST("/forgot-password", as.ForgotPassword)
+		authRoutes.POST("/passwordless", as.Passwordless)
+		authRoutes.POST("/passwordless/signin", as.PasswordlessSignIn)
+		authRoutes.POST("/passwordless/link", as.MagicLinkSignIn)
+		authRoutes.GET("/oidc/providers", as.OIDCProviders)
+		authRoutes.POST("/oidc/authorize", as.OIDCAuthorize)
+		authRoutes.POST("/oidc/callback", as.OIDCSignIn)
 		authRoutes.POST("/change-password", as.ChangePassword)
 		authRoutes.GET("/verify-reset-link/:id", as.VerifyResetLink)
+		authRoutes.POST("/refresh", as.Refresh)
 		authRoutes.POST("/signout", as.SignOut)
 
 		// Protected Auth Endpoints (Require Authentication)
 		authRoutes.Use(as.middle.AuthMiddleware())
 		{
 			authRoutes.GET("/current-user", as.CurrentUser)
-			authRoutes.POST("/new-password", as.NewPassword)
+			authRoutes.POST("/new-password", as.middle.BlockImpersonation(), as.NewPassword)
+			authRoutes.POST("/impersonate/stop", as.StopImpersonation)
 			authRoutes.POST("/skip-verification", as.SkipVerification)
 			authRoutes.POST("/send-email-verification", as.SendEmailVerification)
 			authRoutes.POST("/verify-email", as.VerifyEmail)
@@ -653,16 +412,34 @@ func (as *AuthService) RegisterRoutes() {
 		}
 	}
 
+	// Account Routes Group (Admin only)
+	accountRoutes := as.engine.Client.Group("/api/v1/auth/accounts")
+	accountRoutes.Use(as.middle.AuthMiddlewareAdminOnly())
+	{
+		accountRoutes.PUT("/:accountType/:id/require-password-reset", as.RequirePasswordReset)
+		accountRoutes.POST("/impersonate", as.Impersonate)
+	}
+
 	// Profile Routes Group (Protected)
 	profileRoutes := as.engine.Client.Group("/api/v1/profile")
 	profileRoutes.Use(as.middle.AuthMiddleware())
 	{
 		profileRoutes.POST("/profile-picture", as.ProfilePicture)
 		profileRoutes.POST("/account-setting", as.ProfileAccountSetting)
-		profileRoutes.POST("/change-email", as.ProfileChangeEmail)
-		profileRoutes.POST("/change-contact-number", as.ProfileChangeContactNumber)
-		profileRoutes.POST("/change-username", as.ProfileChangeUsername)
-		profileRoutes.POST("/change-password", as.ChangePassword)
+		profileRoutes.POST("/change-email", as.middle.BlockImpersonation(), as.ProfileChangeEmail)
+		profileRoutes.POST("/change-contact-number", as.middle.BlockImpersonation(), as.ProfileChangeContactNumber)
+		profileRoutes.POST("/change-username", as.middle.BlockImpersonation(), as.ProfileChangeUsername)
+		profileRoutes.POST("/change-password", as.middle.BlockImpersonation(), as.ChangePassword)
+
+		profileRoutes.GET("/two-factor", as.TwoFactorStatus)
+		profileRoutes.POST("/two-factor/setup", as.middle.BlockImpersonation(), as.TwoFactorSetup)
+		profileRoutes.POST("/two-factor/confirm", as.middle.BlockImpersonation(), as.TwoFactorConfirm)
+		profileRoutes.POST("/two-factor/disable", as.middle.BlockImpersonation(), as.TwoFactorDisable)
+		profileRoutes.POST("/two-factor/recovery-codes", as.middle.BlockImpersonation(), as.TwoFactorRecoveryCodes)
+
+		profileRoutes.GET("/sessions", as.SessionList)
+		profileRoutes.DELETE("/sessions/:id", as.SessionRevoke)
+		profileRoutes.DELETE("/sessions", as.SessionRevokeAll)
 	}
 
 }
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (as AuthService) Refresh(ctx *gin.Context) {
+	as.authAccount.RefreshSession(ctx)
+}
+
+func (as AuthService) SessionList(ctx *gin.Context) {
+	claims, err := as.getUserClaims(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
+		return
+	}
+	as.authAccount.SessionList(ctx, claims.AccountType, claims.ID, claims.Id)
+}
+
+func (as AuthService) SessionRevoke(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "SessionRevoke: Invalid session ID"})
+		return
+	}
+	claims, err := as.getUserClaims(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
+		return
+	}
+	as.authAccount.SessionRevoke(ctx, claims.AccountType, claims.ID, claims.Id, uint(id))
+}
+
+// SessionRevokeAll signs out of every session, keeping the current one when
+// the keepCurrent query parameter is true.
+func (as AuthService) SessionRevokeAll(ctx *gin.Context) {
+	keepCurrent, _ := strconv.ParseBool(ctx.Query("keepCurrent"))
+	claims, err := as.getUserClaims(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
+		return
+	}
+	as.authAccount.SessionRevokeAll(ctx, claims.AccountType, claims.ID, claims.Id, keepCurrent)
+}
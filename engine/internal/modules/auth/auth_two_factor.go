@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (as AuthService) TwoFactorSignIn(ctx *gin.Context) {
+	var req TwoFactorSignInRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("TwoFactorSignIn: JSON binding error: %v", err)})
+		return
+	}
+	if err := as.authProvider.ValidateTwoFactorSignIn(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("TwoFactorSignIn: Validation error: %v", err)})
+		return
+	}
+	as.authAccount.TwoFactorSignIn(ctx, req.Challenge, req.Code)
+}
+
+func (as AuthService) TwoFactorChallengeSetup(ctx *gin.Context) {
+	var req TwoFactorChallengeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("TwoFactorSetup: JSON binding error: %v", err)})
+		return
+	}
+	if err := as.authProvider.ValidateTwoFactorChallenge(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("TwoFactorSetup: Validation error: %v", err)})
+		return
+	}
+	as.authAccount.TwoFactorChallengeSetup(ctx, req.Challenge)
+}
+
+func (as AuthService) TwoFactorStatus(ctx *gin.Context) {
+	claims, err := as.getUserClaims(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
+		return
+	}
+	as.authAccount.TwoFactorStatus(ctx, claims.AccountType, claims.ID)
+}
+
+func (as AuthService) TwoFactorSetup(ctx *gin.Context) {
+	claims, err := as.getUserClaims(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
+		return
+	}
+	as.authAccount.TwoFactorSetup(ctx, claims.AccountType, claims.ID)
+}
+
+func (as AuthService) TwoFactorConfirm(ctx *gin.Context) {
+	var req TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("TwoFactorConfirm: JSON binding error: %v", err)})
+		return
+	}
+	if err := as.authProvider.ValidateTwoFactorCode(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("TwoFactorConfirm: Validation error: %v", err)})
+		return
+	}
+	claims, err := as.getUserClaims(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
+		return
+	}
+	as.authAccount.TwoFactorConfirm(ctx, claims.AccountType, claims.ID, req.Code)
+}
+
+func (as AuthService) TwoFactorDisable(ctx *gin.Context) {
+	var req TwoFactorDisableRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("TwoFactorDisable: JSON binding error: %v", err)})
+		return
+	}
+	if err := as.authProvider.ValidateTwoFactorDisable(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("TwoFactorDisable: Validation error: %v", err)})
+		return
+	}
+	claims, err := as.getUserClaims(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
+		return
+	}
+	as.authAccount.TwoFactorDisable(ctx, claims.AccountType, claims.ID, req.Password, req.Code)
+}
+
+func (as AuthService) TwoFactorRecoveryCodes(ctx *gin.Context) {
+	var req TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("TwoFactorRecoveryCodes: JSON binding error: %v", err)})
+		return
+	}
+	if err := as.authProvider.ValidateTwoFactorCode(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("TwoFactorRecoveryCodes: Validation error: %v", err)})
+		return
+	}
+	claims, err := as.getUserClaims(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
+		return
+	}
+	as.authAccount.TwoFactorRecoveryCodes(ctx, claims.AccountType, claims.ID, req.Code)
+}
@@ -13,7 +13,6 @@ import (
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
 	"github.com/Lands-Horizon-Corp/horizon-corp/server/middleware"
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
 type BranchService struct {
@@ -80,9 +79,9 @@ func (bs *BranchService) GetAll(ctx *gin.Context) {
 	var branches []*models.Branch
 	switch userClaims.AccountType {
 	case "Owner":
-		branches, err = bs.modelResource.BranchGetAllForAdmin()
-	case "Admin":
 		branches, err = bs.modelResource.BranchGetAllForOwner(userClaims.ID)
+	case "Admin":
+		branches, err = bs.modelResource.BranchGetAllForAdmin()
 	default:
 		ctx.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions."})
 		return
@@ -127,6 +126,10 @@ func (bs *BranchService) SearchFilter(ctx *gin.Context) {
 		ctx.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions."})
 		return
 	}
+	if errors.Is(err, filter.ErrInvalidFilter) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Branches not found."})
 		return
@@ -138,17 +141,48 @@ func (bs *BranchService) SearchFilter(ctx *gin.Context) {
 	}
 
 	response := gin.H{
-		"data":      data,
-		"pageIndex": branches.PageIndex,
-		"totalPage": branches.TotalPage,
-		"pageSize":  branches.PageSize,
-		"totalSize": branches.TotalSize,
-		"pages":     branches.Pages,
+		"data":       data,
+		"pageIndex":  branches.PageIndex,
+		"totalPage":  branches.TotalPage,
+		"pageSize":   branches.PageSize,
+		"totalSize":  branches.TotalSize,
+		"pages":      branches.Pages,
+		"nextCursor": branches.NextCursor,
+		"prevCursor": branches.PrevCursor,
 	}
 
 	ctx.JSON(http.StatusOK, response)
 }
 
+func (bs *BranchService) Aggregate(ctx *gin.Context) {
+	filterParam := ctx.Query("filter")
+	if filterParam == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "filter parameter is required"})
+		return
+	}
+	userClaims, err := bs.getUserClaims(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
+		return
+	}
+
+	var results []map[string]interface{}
+	switch userClaims.AccountType {
+	case "Owner":
+		results, err = bs.modelResource.BranchAggregateForOwner(filterParam, userClaims.ID)
+	case "Admin":
+		results, err = bs.modelResource.BranchAggregateForAdmin(filterParam)
+	default:
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions."})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": results})
+}
+
 func (as *BranchService) Verify(ctx *gin.Context) {
 	userClaims, err := as.getUserClaims(ctx)
 	if err != nil {
@@ -162,26 +196,7 @@ func (as *BranchService) Verify(ctx *gin.Context) {
 		return
 	}
 
-	idParam := ctx.Param("id")
-	id, err := strconv.Atoi(idParam)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
-		return
-	}
-	preloads := ctx.QueryArray("preloads")
-	branch := &models.Branch{
-		IsAdminVerified: true,
-	}
-	result, err := as.modelResource.BranchDB.UpdateColumns(uint(id), *branch, preloads)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "Entity not found"})
-		} else {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
-		return
-	}
-	ctx.JSON(http.StatusOK, as.modelResource.BranchToResource(result))
+	as.controller.UpdateWith(ctx, &models.Branch{IsAdminVerified: true})
 }
 
 func (bs *BranchService) ExportAll(ctx *gin.Context) {
@@ -190,27 +205,25 @@ func (bs *BranchService) ExportAll(ctx *gin.Context) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
 		return
 	}
-	var branches []*models.Branch
+	var stream func(fn func(batch []*models.Branch) error) error
 	switch userClaims.AccountType {
 	case "Owner":
-		branches, err = bs.modelResource.BranchGetAllForAdmin()
+		stream = func(fn func(batch []*models.Branch) error) error {
+			return bs.modelResource.BranchStreamAllForOwner(userClaims.ID, fn)
+		}
 	case "Admin":
-		branches, err = bs.modelResource.BranchGetAllForOwner(userClaims.ID)
+		stream = bs.modelResource.BranchStreamAllForAdmin
 	default:
 		ctx.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions."})
 		return
 	}
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Branches not found."})
-		return
-	}
-	record, headers := bs.modelResource.BranchToRecord(branches)
-	csvManager := managers.NewCSVManager()
-	csvManager.SetFileName("branch-export-all.csv")
-	csvManager.SetHeaders(headers)
-	csvManager.AddRecords(record)
-	if err := csvManager.WriteCSV(ctx); err != nil {
-		ctx.String(http.StatusInternalServerError, "Failed to generate CSV: %v", err)
+	columns := bs.modelResource.BranchExportColumns()
+	if err := managers.Export(ctx, "branch-export-all", columns, bs.modelResource.BranchToExportRow, stream); err != nil {
+		if errors.Is(err, managers.ErrUnsupportedExportFormat) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.String(http.StatusInternalServerError, "Failed to generate export: %v", err)
 		return
 	}
 }
@@ -226,27 +239,27 @@ func (bs *BranchService) ExportAllFiltered(ctx *gin.Context) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
 		return
 	}
-	var branches []*models.Branch
+	var stream func(fn func(batch []*models.Branch) error) error
 	switch userClaims.AccountType {
 	case "Owner":
-		branches, err = bs.modelResource.BranchFilterForOwnerRecord(filterParam, userClaims.ID)
+		stream = func(fn func(batch []*models.Branch) error) error {
+			return bs.modelResource.BranchStreamFilterForOwner(filterParam, userClaims.ID, fn)
+		}
 	case "Admin":
-		branches, err = bs.modelResource.BranchFilterForAdminRecord(filterParam)
+		stream = func(fn func(batch []*models.Branch) error) error {
+			return bs.modelResource.BranchStreamFilterForAdmin(filterParam, fn)
+		}
 	default:
 		ctx.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions."})
 		return
 	}
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Branches not found."})
-		return
-	}
-	record, headers := bs.modelResource.BranchToRecord(branches)
-	csvManager := managers.NewCSVManager()
-	csvManager.SetFileName("branches-export-all-filtered.csv")
-	csvManager.SetHeaders(headers)
-	csvManager.AddRecords(record)
-	if err := csvManager.WriteCSV(ctx); err != nil {
-		ctx.String(http.StatusInternalServerError, "Failed to generate CSV: %v", err)
+	columns := bs.modelResource.BranchExportColumns()
+	if err := managers.Export(ctx, "branches-export-all-filtered", columns, bs.modelResource.BranchToExportRow, stream); err != nil {
+		if errors.Is(err, filter.ErrInvalidFilter) || errors.Is(err, managers.ErrUnsupportedExportFormat) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.String(http.StatusInternalServerError, "Failed to generate export: %v", err)
 		return
 	}
 }
@@ -256,22 +269,53 @@ func (bs *BranchService) ExportSelected(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	branch, err := bs.modelResource.BranchDB.GetAllByIDs(ids)
+	repo := bs.modelResource.BranchDB
+	if scope, ok := ctx.Get(managers.TenantScopeKey); ok {
+		if scopeFunc, ok := scope.(managers.ScopeFunc); ok {
+			repo = repo.WithScope(scopeFunc)
+		}
+	}
+	branch, err := repo.GetAllByIDs(ids)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve branches."})
 		return
 	}
-	record, headers := bs.modelResource.BranchToRecord(branch)
-	csvManager := managers.NewCSVManager()
-	csvManager.SetFileName("branch-export-all-filtered.csv")
-	csvManager.SetHeaders(headers)
-	csvManager.AddRecords(record)
-	if err := csvManager.WriteCSV(ctx); err != nil {
-		ctx.String(http.StatusInternalServerError, "Failed to generate CSV: %v", err)
+	stream := func(fn func(batch []*models.Branch) error) error { return fn(branch) }
+	columns := bs.modelResource.BranchExportColumns()
+	if err := managers.Export(ctx, "branch-export-all-filtered", columns, bs.modelResource.BranchToExportRow, stream); err != nil {
+		if errors.Is(err, managers.ErrUnsupportedExportFormat) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.String(http.StatusInternalServerError, "Failed to generate export: %v", err)
 		return
 	}
 }
 
+// Import creates branches from an uploaded CSV or XLSX file. Owners can only
+// import branches into their own companies.
+func (bs *BranchService) Import(ctx *gin.Context) {
+	userClaims, err := bs.getUserClaims(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
+		return
+	}
+	var validate func(req *models.BranchRequest) error
+	switch userClaims.AccountType {
+	case "Owner":
+		validate = func(req *models.BranchRequest) error {
+			return bs.modelResource.ValidateBranchRequestForOwner(req, userClaims.ID)
+		}
+	case "Admin":
+		validate = bs.modelResource.ValidateBranchRequest
+	default:
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions."})
+		return
+	}
+	importer := managers.NewImporter(bs.modelResource.BranchDB, validate, nil)
+	importer.Import(ctx)
+}
+
 func (as *BranchService) ProfilePicture(ctx *gin.Context) {
 	var req *models.MediaRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -288,26 +332,8 @@ func (as *BranchService) ProfilePicture(ctx *gin.Context) {
 		return
 	}
 
-	idParam := ctx.Param("id")
-	id, err := strconv.Atoi(idParam)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
-		return
-	}
-
 	if claims.AccountType == "Admin" || claims.AccountType == "Owner" {
-		preloads := ctx.QueryArray("preloads")
-		branch := &models.Branch{MediaID: req.ID}
-		result, err := as.modelResource.BranchDB.UpdateColumns(uint(id), *branch, preloads)
-		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				ctx.JSON(http.StatusNotFound, gin.H{"error": "Entity not found"})
-			} else {
-				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			}
-			return
-		}
-		ctx.JSON(http.StatusOK, as.modelResource.BranchToResource(result))
+		as.controller.UpdateWith(ctx, &models.Branch{MediaID: req.ID})
 	} else {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Account type doesn't exist"})
 	}
@@ -317,19 +343,21 @@ func (bs *BranchService) RegisterRoutes() {
 	routes := bs.engine.Client.Group("/api/v1/branch")
 	routes.Use(bs.middle.AuthMiddleware())
 	{
-		routes.GET("/search", bs.SearchFilter)
-		routes.POST("/", bs.controller.Create)
-		routes.GET("/", bs.GetAll)
-		routes.GET("/:id", bs.controller.GetByID)
-		routes.PUT("/:id", bs.controller.Update)
-		routes.DELETE("/:id", bs.controller.Delete)
-		routes.DELETE("/bulk-delete", bs.controller.DeleteMany)
-		routes.POST("/verify/:id", bs.Verify)
-		routes.POST("/profile-picture/:id", bs.ProfilePicture)
+		routes.GET("/search", bs.middle.Permission(models.ResourceBranch, models.ActionRead), bs.SearchFilter)
+		routes.GET("/aggregate", bs.middle.Permission(models.ResourceBranch, models.ActionRead), bs.Aggregate)
+		routes.POST("/", bs.middle.Permission(models.ResourceBranch, models.ActionCreate), bs.controller.Create)
+		routes.GET("/", bs.middle.Permission(models.ResourceBranch, models.ActionRead), bs.GetAll)
+		routes.GET("/:id", bs.middle.Permission(models.ResourceBranch, models.ActionRead), bs.controller.GetByID)
+		routes.PUT("/:id", bs.middle.Permission(models.ResourceBranch, models.ActionUpdate), bs.controller.Update)
+		routes.DELETE("/:id", bs.middle.Permission(models.ResourceBranch, models.ActionDelete), bs.controller.Delete)
+		routes.DELETE("/bulk-delete", bs.middle.Permission(models.ResourceBranch, models.ActionDelete), bs.controller.DeleteMany)
+		routes.POST("/verify/:id", bs.middle.Permission(models.ResourceBranch, models.ActionUpdate), bs.Verify)
+		routes.POST("/profile-picture/:id", bs.middle.Permission(models.ResourceBranch, models.ActionUpdate), bs.ProfilePicture)
+		routes.POST("/import", bs.middle.Permission(models.ResourceBranch, models.ActionCreate), bs.Import)
 
 		// Export routes
-		routes.GET("/export", bs.ExportAll)
-		routes.GET("/export-search", bs.ExportAllFiltered)
-		routes.GET("/export-selected", bs.ExportSelected)
+		routes.GET("/export", bs.middle.Permission(models.ResourceBranch, models.ActionRead), bs.ExportAll)
+		routes.GET("/export-search", bs.middle.Permission(models.ResourceBranch, models.ActionRead), bs.ExportAllFiltered)
+		routes.GET("/export-selected", bs.middle.Permission(models.ResourceBranch, models.ActionRead), bs.ExportSelected)
 	}
 }
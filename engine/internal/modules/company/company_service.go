@@ -9,6 +9,7 @@ import (
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/helpers"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers/filter"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
 	"github.com/Lands-Horizon-Corp/horizon-corp/server/middleware"
 	"github.com/gin-gonic/gin"
@@ -69,6 +70,42 @@ func (ts *CompanyService) getUserClaims(ctx *gin.Context) (*providers.UserClaims
 	return userClaims, nil
 }
 
+// setOwner makes the Owner creating a company its owner. Admins set the owner
+// of the companies they create through PUT /:id/owner.
+func (as *CompanyService) setOwner(ctx *gin.Context, company *models.Company) error {
+	userClaims, err := as.getUserClaims(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
+		return err
+	}
+	if userClaims.AccountType == "Owner" {
+		company.OwnerID = &userClaims.ID
+	}
+	return nil
+}
+
+// Owner transfers the company to another owner.
+func (as *CompanyService) Owner(ctx *gin.Context) {
+	var req models.CompanyOwnerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := as.modelResource.ValidateCompanyOwnerRequest(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"validation_error": err.Error()})
+		return
+	}
+	if _, err := as.modelResource.OwnerDB.FindByID(req.OwnerID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Owner not found"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	as.controller.UpdateWith(ctx, &models.Company{OwnerID: &req.OwnerID})
+}
+
 func (as *CompanyService) SearchFilter(ctx *gin.Context) {
 	filterParam := ctx.Query("filter")
 	if filterParam == "" {
@@ -88,6 +125,10 @@ func (as *CompanyService) SearchFilter(ctx *gin.Context) {
 	}
 
 	companies, err := as.modelResource.CompanyFilterForAdmin(filterParam, pageSize, pageIndex)
+	if errors.Is(err, filter.ErrInvalidFilter) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Companies not found."})
 		return
@@ -95,25 +136,43 @@ func (as *CompanyService) SearchFilter(ctx *gin.Context) {
 	data := as.modelResource.CompanyToResourceList(companies.Data)
 	if data == nil {
 		ctx.JSON(http.StatusOK, gin.H{
-			"data":      []interface{}{},
-			"pageIndex": companies.PageIndex,
-			"totalPage": companies.TotalPage,
-			"pageSize":  companies.PageSize,
-			"totalSize": companies.TotalSize,
-			"pages":     companies.Pages,
+			"data":       []interface{}{},
+			"pageIndex":  companies.PageIndex,
+			"totalPage":  companies.TotalPage,
+			"pageSize":   companies.PageSize,
+			"totalSize":  companies.TotalSize,
+			"pages":      companies.Pages,
+			"nextCursor": companies.NextCursor,
+			"prevCursor": companies.PrevCursor,
 		})
 		return
 	}
 	ctx.JSON(http.StatusOK, gin.H{
-		"data":      data,
-		"pageIndex": companies.PageIndex,
-		"totalPage": companies.TotalPage,
-		"pageSize":  companies.PageSize,
-		"totalSize": companies.TotalSize,
-		"pages":     companies.Pages,
+		"data":       data,
+		"pageIndex":  companies.PageIndex,
+		"totalPage":  companies.TotalPage,
+		"pageSize":   companies.PageSize,
+		"totalSize":  companies.TotalSize,
+		"pages":      companies.Pages,
+		"nextCursor": companies.NextCursor,
+		"prevCursor": companies.PrevCursor,
 	})
 }
 
+func (as *CompanyService) Aggregate(ctx *gin.Context) {
+	filterParam := ctx.Query("filter")
+	if filterParam == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "filter parameter is required"})
+		return
+	}
+	results, err := as.modelResource.CompanyAggregateForAdmin(filterParam)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": results})
+}
+
 func (as *CompanyService) Verify(ctx *gin.Context) {
 
 	userClaims, err := as.getUserClaims(ctx)
@@ -127,26 +186,38 @@ func (as *CompanyService) Verify(ctx *gin.Context) {
 		return
 	}
 
-	idParam := ctx.Param("id")
-	id, err := strconv.Atoi(idParam)
+	as.controller.UpdateWith(ctx, &models.Company{IsAdminVerified: true})
+}
+
+// Passwordless sets which account types of the company may sign in without a
+// password.
+func (as *CompanyService) Passwordless(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	preloads := ctx.QueryArray("preloads")
-	company := &models.Company{
-		IsAdminVerified: true,
+	var req models.CompanyPasswordlessRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := as.modelResource.ValidateCompanyPasswordlessRequest(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	scope, _ := ctx.Get(managers.TenantScopeKey)
+	scopeFunc, _ := scope.(managers.ScopeFunc)
+	company, err := as.modelResource.CompanyUpdatePasswordless(scopeFunc, uint(id), req.AccountTypes)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Entity not found"})
+		return
 	}
-	result, err := as.modelResource.CompanyDB.UpdateColumns(uint(id), *company, preloads)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "Entity not found"})
-		} else {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	ctx.JSON(http.StatusOK, as.modelResource.CompanyToResource(result))
+	ctx.JSON(http.StatusOK, as.modelResource.CompanyToResource(company))
 }
 
 func (as *CompanyService) ExportAll(ctx *gin.Context) {
@@ -160,18 +231,14 @@ func (as *CompanyService) ExportAll(ctx *gin.Context) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
 		return
 	}
-	company, err := as.modelResource.CompanyDB.FindAll()
-	if err != nil {
-		ctx.String(http.StatusInternalServerError, "Failed to export CSV: %v", err)
-		return
-	}
-	record, headers := as.modelResource.CompanyToRecord(company)
-	csvManager := managers.NewCSVManager()
-	csvManager.SetFileName("company-export-all.csv")
-	csvManager.SetHeaders(headers)
-	csvManager.AddRecords(record)
-	if err := csvManager.WriteCSV(ctx); err != nil {
-		ctx.String(http.StatusInternalServerError, "Failed to generate CSV: %v", err)
+	stream := as.modelResource.CompanyStreamAll
+	columns := as.modelResource.CompanyExportColumns()
+	if err := managers.Export(ctx, "company-export-all", columns, as.modelResource.CompanyToExportRow, stream); err != nil {
+		if errors.Is(err, managers.ErrUnsupportedExportFormat) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.String(http.StatusInternalServerError, "Failed to generate export: %v", err)
 		return
 	}
 }
@@ -192,18 +259,16 @@ func (as *CompanyService) ExportAllFiltered(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "filter parameter is required"})
 		return
 	}
-	company, err := as.modelResource.CompanyFilterForAdminRecord(filterParam)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Companies not found."})
-		return
+	stream := func(fn func(batch []*models.Company) error) error {
+		return as.modelResource.CompanyStreamFilterForAdmin(filterParam, fn)
 	}
-	record, headers := as.modelResource.CompanyToRecord(company)
-	csvManager := managers.NewCSVManager()
-	csvManager.SetFileName("company-export-all-filtered.csv")
-	csvManager.SetHeaders(headers)
-	csvManager.AddRecords(record)
-	if err := csvManager.WriteCSV(ctx); err != nil {
-		ctx.String(http.StatusInternalServerError, "Failed to generate CSV: %v", err)
+	columns := as.modelResource.CompanyExportColumns()
+	if err := managers.Export(ctx, "company-export-all-filtered", columns, as.modelResource.CompanyToExportRow, stream); err != nil {
+		if errors.Is(err, filter.ErrInvalidFilter) || errors.Is(err, managers.ErrUnsupportedExportFormat) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.String(http.StatusInternalServerError, "Failed to generate export: %v", err)
 		return
 	}
 }
@@ -229,13 +294,14 @@ func (as *CompanyService) ExportSelected(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve companies."})
 		return
 	}
-	record, headers := as.modelResource.CompanyToRecord(company)
-	csvManager := managers.NewCSVManager()
-	csvManager.SetFileName("company-export-all-filtered.csv")
-	csvManager.SetHeaders(headers)
-	csvManager.AddRecords(record)
-	if err := csvManager.WriteCSV(ctx); err != nil {
-		ctx.String(http.StatusInternalServerError, "Failed to generate CSV: %v", err)
+	stream := func(fn func(batch []*models.Company) error) error { return fn(company) }
+	columns := as.modelResource.CompanyExportColumns()
+	if err := managers.Export(ctx, "company-export-all-filtered", columns, as.modelResource.CompanyToExportRow, stream); err != nil {
+		if errors.Is(err, managers.ErrUnsupportedExportFormat) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.String(http.StatusInternalServerError, "Failed to generate export: %v", err)
 		return
 	}
 }
@@ -256,48 +322,97 @@ func (as *CompanyService) ProfilePicture(ctx *gin.Context) {
 		return
 	}
 
-	idParam := ctx.Param("id")
-	id, err := strconv.Atoi(idParam)
+	if claims.AccountType == "Admin" || claims.AccountType == "Owner" {
+		as.controller.UpdateWith(ctx, &models.Company{MediaID: req.ID})
+	} else {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Account type doesn't exist"})
+	}
+}
+
+// Attendance sets the time zone and rest days the schedules of the company
+// follow.
+func (as *CompanyService) Attendance(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
+	var req models.CompanyAttendanceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := as.modelResource.ValidateCompanyAttendanceRequest(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	scope, _ := ctx.Get(managers.TenantScopeKey)
+	scopeFunc, _ := scope.(managers.ScopeFunc)
+	company, err := as.modelResource.CompanyUpdateAttendance(scopeFunc, uint(id), &req)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Entity not found"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, as.modelResource.CompanyToResource(company))
+}
 
-	if claims.AccountType == "Admin" || claims.AccountType == "Owner" {
-		preloads := ctx.QueryArray("preloads")
-		company := &models.Company{MediaID: req.ID}
-		result, err := as.modelResource.CompanyDB.UpdateColumns(uint(id), *company, preloads)
-		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				ctx.JSON(http.StatusNotFound, gin.H{"error": "Entity not found"})
-			} else {
-				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			}
-			return
-		}
-		ctx.JSON(http.StatusOK, as.modelResource.CompanyToResource(result))
-	} else {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Account type doesn't exist"})
+// Geofence sets how far from their branch employees of the company may time
+// in or out.
+func (as *CompanyService) Geofence(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var req models.CompanyGeofenceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := as.modelResource.ValidateCompanyGeofenceRequest(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	scope, _ := ctx.Get(managers.TenantScopeKey)
+	scopeFunc, _ := scope.(managers.ScopeFunc)
+	company, err := as.modelResource.CompanyUpdateGeofence(scopeFunc, uint(id), &req)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Entity not found"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
+	ctx.JSON(http.StatusOK, as.modelResource.CompanyToResource(company))
 }
 
 func (as *CompanyService) RegisterRoutes() {
 	routes := as.engine.Client.Group("/api/v1/company")
 	routes.Use(as.middle.AuthMiddleware())
 	{
-		routes.POST("/", as.controller.Create)
-		routes.GET("/:id", as.controller.GetByID)
-		routes.PUT("/:id", as.controller.Update)
+		routes.POST("/", as.middle.Permission(models.ResourceCompany, models.ActionCreate), as.controller.CreateWith(as.setOwner))
+		routes.GET("/:id", as.middle.Permission(models.ResourceCompany, models.ActionRead), as.controller.GetByID)
+		routes.PUT("/:id", as.middle.Permission(models.ResourceCompany, models.ActionUpdate), as.controller.Update)
 
-		routes.POST("/profile-picture/:id", as.ProfilePicture)
+		routes.POST("/profile-picture/:id", as.middle.Permission(models.ResourceCompany, models.ActionUpdate), as.ProfilePicture)
+		routes.PUT("/:id/passwordless", as.middle.Permission(models.ResourceCompany, models.ActionUpdate), as.Passwordless)
+		routes.PUT("/:id/attendance", as.middle.Permission(models.ResourceCompany, models.ActionUpdate), as.Attendance)
+		routes.PUT("/:id/geofence", as.middle.Permission(models.ResourceCompany, models.ActionUpdate), as.Geofence)
 
-		routes.POST("/verify/:id", as.middle.AuthMiddlewareAdminOnly(), as.Verify)
-		routes.GET("", as.middle.AuthMiddlewareAdminOnly(), as.SearchFilter)
-		routes.DELETE("/bulk-delete", as.middle.AuthMiddlewareAdminOnly(), as.controller.DeleteMany)
-		routes.DELETE("/:id", as.middle.AuthMiddlewareAdminOnly(), as.controller.Delete)
+		routes.PUT("/:id/owner", as.middle.AuthMiddlewareAdminOnly(), as.middle.Permission(models.ResourceCompany, models.ActionUpdate), as.Owner)
+		routes.POST("/verify/:id", as.middle.AuthMiddlewareAdminOnly(), as.middle.Permission(models.ResourceCompany, models.ActionUpdate), as.Verify)
+		routes.GET("", as.middle.AuthMiddlewareAdminOnly(), as.middle.Permission(models.ResourceCompany, models.ActionRead), as.SearchFilter)
+		routes.GET("/aggregate", as.middle.AuthMiddlewareAdminOnly(), as.middle.Permission(models.ResourceCompany, models.ActionRead), as.Aggregate)
+		routes.DELETE("/bulk-delete", as.middle.AuthMiddlewareAdminOnly(), as.middle.Permission(models.ResourceCompany, models.ActionDelete), as.controller.DeleteMany)
+		routes.DELETE("/:id", as.middle.AuthMiddlewareAdminOnly(), as.middle.Permission(models.ResourceCompany, models.ActionDelete), as.controller.Delete)
 		// Export routes
-		routes.GET("/export", as.ExportAll)
-		routes.GET("/export-search", as.ExportAllFiltered)
-		routes.GET("/export-selected", as.ExportSelected)
+		routes.GET("/export", as.middle.Permission(models.ResourceCompany, models.ActionRead), as.ExportAll)
+		routes.GET("/export-search", as.middle.Permission(models.ResourceCompany, models.ActionRead), as.ExportAllFiltered)
+		routes.GET("/export-selected", as.middle.Permission(models.ResourceCompany, models.ActionRead), as.ExportSelected)
 	}
 }
@@ -4,18 +4,21 @@ import (
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/server/middleware"
 )
 
 type ContactService struct {
 	controller *managers.Controller[models.Contact, models.ContactRequest, models.ContactResource]
 	db         *providers.DatabaseService
 	engine     *providers.EngineService
+	middle     *middleware.Middleware
 	models     *models.ModelResource
 }
 
 func NewContactService(
 	db *providers.DatabaseService,
 	engine *providers.EngineService,
+	middle *middleware.Middleware,
 	models *models.ModelResource,
 ) *ContactService {
 	controller := managers.NewController(
@@ -29,6 +32,7 @@ func NewContactService(
 		controller: controller,
 		db:         db,
 		engine:     engine,
+		middle:     middle,
 		models:     models,
 	}
 }
@@ -36,10 +40,13 @@ func NewContactService(
 func (as *ContactService) RegisterRoutes() {
 	routes := as.engine.Client.Group("/api/v1/contact")
 	{
+		// Public
 		routes.POST("/", as.controller.Create)
-		routes.GET("/", as.controller.GetAll)
-		routes.GET("/:id", as.controller.GetByID)
-		routes.PUT("/:id", as.controller.Update)
-		routes.DELETE("/:id", as.controller.Delete)
+
+		routes.Use(as.middle.AuthMiddleware())
+		routes.GET("/", as.middle.Permission(models.ResourceContact, models.ActionRead), as.controller.GetAll)
+		routes.GET("/:id", as.middle.Permission(models.ResourceContact, models.ActionRead), as.controller.GetByID)
+		routes.PUT("/:id", as.middle.Permission(models.ResourceContact, models.ActionUpdate), as.controller.Update)
+		routes.DELETE("/:id", as.middle.Permission(models.ResourceContact, models.ActionDelete), as.controller.Delete)
 	}
 }
@@ -1,21 +1,31 @@
 package employee
 
 import (
+	"errors"
+	"net/http"
+
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/server/middleware"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type EmployeeService struct {
 	controller *managers.Controller[models.Employee, models.EmployeeRequest, models.EmployeeResource]
+	creator    *managers.Controller[models.Employee, models.EmployeeCreateRequest, models.EmployeeResource]
+	importer   *managers.Importer[models.Employee, models.EmployeeCreateRequest]
 	db         *providers.DatabaseService
 	engine     *providers.EngineService
+	middle     *middleware.Middleware
 	models     *models.ModelResource
 }
 
 func NewEmployeeService(
 	db *providers.DatabaseService,
 	engine *providers.EngineService,
+	middle *middleware.Middleware,
 	models *models.ModelResource,
 ) *EmployeeService {
 	controller := managers.NewController(
@@ -24,22 +34,79 @@ func NewEmployeeService(
 		models.EmployeeToResource,
 		models.EmployeeToResourceList,
 	)
+	creator := managers.NewController(
+		models.EmployeeDB,
+		models.ValidateEmployeeCreateRequest,
+		models.EmployeeToResource,
+		models.EmployeeToResourceList,
+	)
+	importer := managers.NewImporter(
+		models.EmployeeDB,
+		models.ValidateEmployeeCreateRequest,
+		nil,
+	)
 
 	return &EmployeeService{
 		controller: controller,
+		creator:    creator,
+		importer:   importer,
 		db:         db,
 		engine:     engine,
+		middle:     middle,
 		models:     models,
 	}
 }
 
+// Branch moves the employee to another branch. Placing an employee in a
+// branch requires the permission to create employees, and the move is rolled
+// back when the branch is outside of the caller's scope.
+func (as *EmployeeService) Branch(ctx *gin.Context) {
+	var req models.BranchAssignRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := as.models.ValidateBranchAssignRequest(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"validation_error": err.Error()})
+		return
+	}
+	as.controller.UpdateWith(ctx, &models.Employee{BranchID: &req.BranchID})
+}
+
+// Role sets the role of the employee. Roles are shared by every company, so
+// only admins may assign them.
+func (as *EmployeeService) Role(ctx *gin.Context) {
+	var req models.RoleAssignRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := as.models.ValidateRoleAssignRequest(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"validation_error": err.Error()})
+		return
+	}
+	if _, err := as.models.RoleDB.FindByID(req.RoleID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Role not found"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	as.controller.UpdateWith(ctx, &models.Employee{RoleID: &req.RoleID})
+}
+
 func (as *EmployeeService) RegisterRoutes() {
 	routes := as.engine.Client.Group("/api/v1/employee")
 	{
-		routes.POST("/", as.controller.Create)
-		routes.GET("/", as.controller.GetAll)
-		routes.GET("/:id", as.controller.GetByID)
-		routes.PUT("/:id", as.controller.Update)
-		routes.DELETE("/:id", as.controller.Delete)
+		routes.Use(as.middle.AuthMiddleware())
+		routes.POST("/", as.middle.Permission(models.ResourceEmployee, models.ActionCreate), as.creator.Create)
+		routes.GET("/", as.middle.Permission(models.ResourceEmployee, models.ActionRead), as.controller.GetAll)
+		routes.GET("/:id", as.middle.Permission(models.ResourceEmployee, models.ActionRead), as.controller.GetByID)
+		routes.PUT("/:id", as.middle.BlockImpersonation(), as.middle.Permission(models.ResourceEmployee, models.ActionUpdate), as.controller.Update)
+		routes.PUT("/:id/branch", as.middle.Permission(models.ResourceEmployee, models.ActionCreate), as.Branch)
+		routes.PUT("/:id/role", as.middle.AuthMiddlewareAdminOnly(), as.middle.Permission(models.ResourceEmployee, models.ActionUpdate), as.Role)
+		routes.DELETE("/:id", as.middle.Permission(models.ResourceEmployee, models.ActionDelete), as.controller.Delete)
+		routes.POST("/import", as.middle.Permission(models.ResourceEmployee, models.ActionCreate), as.importer.Import)
 	}
 }
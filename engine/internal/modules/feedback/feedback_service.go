@@ -1,6 +1,7 @@
 package feedback
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -8,6 +9,7 @@ import (
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/helpers"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers/filter"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
 	"github.com/Lands-Horizon-Corp/horizon-corp/server/middleware"
 	"github.com/gin-gonic/gin"
@@ -84,6 +86,10 @@ func (fs *FeedbackService) SearchFilter(ctx *gin.Context) {
 		pageSize = 10
 	}
 	feedbacks, err := fs.modelResource.FeedbackFilterForAdmin(filterParam, pageIndex, pageSize)
+	if errors.Is(err, filter.ErrInvalidFilter) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Feedbacks not found."})
 		return
@@ -91,37 +97,52 @@ func (fs *FeedbackService) SearchFilter(ctx *gin.Context) {
 	data := fs.modelResource.FeedbackToResourceList(feedbacks.Data)
 	if data == nil {
 		ctx.JSON(http.StatusOK, gin.H{
-			"data":      []interface{}{},
-			"pageIndex": feedbacks.PageIndex,
-			"totalPage": feedbacks.TotalPage,
-			"pageSize":  feedbacks.PageSize,
-			"totalSize": feedbacks.TotalSize,
-			"pages":     feedbacks.Pages,
+			"data":       []interface{}{},
+			"pageIndex":  feedbacks.PageIndex,
+			"totalPage":  feedbacks.TotalPage,
+			"pageSize":   feedbacks.PageSize,
+			"totalSize":  feedbacks.TotalSize,
+			"pages":      feedbacks.Pages,
+			"nextCursor": feedbacks.NextCursor,
+			"prevCursor": feedbacks.PrevCursor,
 		})
 		return
 	}
 	ctx.JSON(http.StatusOK, gin.H{
-		"data":      data,
-		"pageIndex": feedbacks.PageIndex,
-		"totalPage": feedbacks.TotalPage,
-		"pageSize":  feedbacks.PageSize,
-		"totalSize": feedbacks.TotalSize,
-		"pages":     feedbacks.Pages,
+		"data":       data,
+		"pageIndex":  feedbacks.PageIndex,
+		"totalPage":  feedbacks.TotalPage,
+		"pageSize":   feedbacks.PageSize,
+		"totalSize":  feedbacks.TotalSize,
+		"pages":      feedbacks.Pages,
+		"nextCursor": feedbacks.NextCursor,
+		"prevCursor": feedbacks.PrevCursor,
 	})
 }
-func (fs *FeedbackService) ExportAll(ctx *gin.Context) {
-	feedback, err := fs.modelResource.FeedbackDB.FindAll()
+
+// # Admin only
+func (fs *FeedbackService) Aggregate(ctx *gin.Context) {
+	filterParam := ctx.Query("filter")
+	if filterParam == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "filter parameter is required"})
+		return
+	}
+	results, err := fs.modelResource.FeedbackAggregateForAdmin(filterParam)
 	if err != nil {
-		ctx.String(http.StatusInternalServerError, "Failed to export CSV: %v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	record, headers := fs.modelResource.FeedbackToRecord(feedback)
-	csvManager := managers.NewCSVManager()
-	csvManager.SetFileName("feedback-export-all.csv")
-	csvManager.SetHeaders(headers)
-	csvManager.AddRecords(record)
-	if err := csvManager.WriteCSV(ctx); err != nil {
-		ctx.String(http.StatusInternalServerError, "Failed to generate CSV: %v", err)
+	ctx.JSON(http.StatusOK, gin.H{"data": results})
+}
+
+func (fs *FeedbackService) ExportAll(ctx *gin.Context) {
+	columns := fs.modelResource.FeedbackExportColumns()
+	if err := managers.Export(ctx, "feedback-export-all", columns, fs.modelResource.FeedbackToExportRow, fs.modelResource.FeedbackStreamAll); err != nil {
+		if errors.Is(err, managers.ErrUnsupportedExportFormat) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.String(http.StatusInternalServerError, "Failed to generate export: %v", err)
 		return
 	}
 }
@@ -132,18 +153,16 @@ func (fs *FeedbackService) ExportAllFiltered(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "filter parameter is required"})
 		return
 	}
-	feedback, err := fs.modelResource.FeedbackFilterForAdminRecord(filterParam)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Companies not found."})
-		return
+	stream := func(fn func(batch []*models.Feedback) error) error {
+		return fs.modelResource.FeedbackStreamFilterForAdmin(filterParam, fn)
 	}
-	record, headers := fs.modelResource.FeedbackToRecord(feedback)
-	csvManager := managers.NewCSVManager()
-	csvManager.SetFileName("feedback-export-all-filtered.csv")
-	csvManager.SetHeaders(headers)
-	csvManager.AddRecords(record)
-	if err := csvManager.WriteCSV(ctx); err != nil {
-		ctx.String(http.StatusInternalServerError, "Failed to generate CSV: %v", err)
+	columns := fs.modelResource.FeedbackExportColumns()
+	if err := managers.Export(ctx, "feedback-export-all-filtered", columns, fs.modelResource.FeedbackToExportRow, stream); err != nil {
+		if errors.Is(err, filter.ErrInvalidFilter) || errors.Is(err, managers.ErrUnsupportedExportFormat) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.String(http.StatusInternalServerError, "Failed to generate export: %v", err)
 		return
 	}
 }
@@ -158,13 +177,14 @@ func (fs *FeedbackService) ExportSelected(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve companies."})
 		return
 	}
-	record, headers := fs.modelResource.FeedbackToRecord(feedbacks)
-	csvManager := managers.NewCSVManager()
-	csvManager.SetFileName("feedback-export-all-filtered.csv")
-	csvManager.SetHeaders(headers)
-	csvManager.AddRecords(record)
-	if err := csvManager.WriteCSV(ctx); err != nil {
-		ctx.String(http.StatusInternalServerError, "Failed to generate CSV: %v", err)
+	stream := func(fn func(batch []*models.Feedback) error) error { return fn(feedbacks) }
+	columns := fs.modelResource.FeedbackExportColumns()
+	if err := managers.Export(ctx, "feedback-export-all-filtered", columns, fs.modelResource.FeedbackToExportRow, stream); err != nil {
+		if errors.Is(err, managers.ErrUnsupportedExportFormat) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.String(http.StatusInternalServerError, "Failed to generate export: %v", err)
 		return
 	}
 }
@@ -176,15 +196,17 @@ func (as *FeedbackService) RegisterRoutes() {
 		routes.POST("/", as.controller.Create)
 
 		// Admin only
-		routes.GET("/search", as.middle.AuthMiddlewareAdminOnly(), as.SearchFilter)
-		routes.GET("/", as.middle.AuthMiddlewareAdminOnly(), as.controller.GetAll)
-		routes.GET("/:id", as.middle.AuthMiddlewareAdminOnly(), as.controller.GetByID)
-		routes.DELETE("/:id", as.middle.AuthMiddlewareAdminOnly(), as.controller.Delete)
-		routes.DELETE("/bulk-delete", as.middle.AuthMiddlewareAdminOnly(), as.controller.DeleteMany)
+		routes.Use(as.middle.AuthMiddlewareAdminOnly())
+		routes.GET("/search", as.middle.Permission(models.ResourceFeedback, models.ActionRead), as.SearchFilter)
+		routes.GET("/aggregate", as.middle.Permission(models.ResourceFeedback, models.ActionRead), as.Aggregate)
+		routes.GET("/", as.middle.Permission(models.ResourceFeedback, models.ActionRead), as.controller.GetAll)
+		routes.GET("/:id", as.middle.Permission(models.ResourceFeedback, models.ActionRead), as.controller.GetByID)
+		routes.DELETE("/:id", as.middle.Permission(models.ResourceFeedback, models.ActionDelete), as.controller.Delete)
+		routes.DELETE("/bulk-delete", as.middle.Permission(models.ResourceFeedback, models.ActionDelete), as.controller.DeleteMany)
 
 		// Export routes
-		routes.GET("/export", as.middle.AuthMiddlewareAdminOnly(), as.ExportAll)
-		routes.GET("/export-search", as.middle.AuthMiddlewareAdminOnly(), as.ExportAllFiltered)
-		routes.GET("/export-selected", as.middle.AuthMiddlewareAdminOnly(), as.ExportSelected)
+		routes.GET("/export", as.middle.Permission(models.ResourceFeedback, models.ActionRead), as.ExportAll)
+		routes.GET("/export-search", as.middle.Permission(models.ResourceFeedback, models.ActionRead), as.ExportAllFiltered)
+		routes.GET("/export-selected", as.middle.Permission(models.ResourceFeedback, models.ActionRead), as.ExportSelected)
 	}
 }
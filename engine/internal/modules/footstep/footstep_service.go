@@ -4,18 +4,21 @@ import (
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/server/middleware"
 )
 
 type FootstepService struct {
 	controller *managers.Controller[models.Footstep, models.FootstepRequest, models.FootstepResource]
 	db         *providers.DatabaseService
 	engine     *providers.EngineService
+	middle     *middleware.Middleware
 	models     *models.ModelResource
 }
 
 func NewFootstepService(
 	db *providers.DatabaseService,
 	engine *providers.EngineService,
+	middle *middleware.Middleware,
 	models *models.ModelResource,
 ) *FootstepService {
 	controller := managers.NewController(
@@ -29,6 +32,7 @@ func NewFootstepService(
 		controller: controller,
 		db:         db,
 		engine:     engine,
+		middle:     middle,
 		models:     models,
 	}
 }
@@ -36,10 +40,11 @@ func NewFootstepService(
 func (as *FootstepService) RegisterRoutes() {
 	routes := as.engine.Client.Group("/api/v1/footstep")
 	{
-		routes.POST("/", as.controller.Create)
-		routes.GET("/", as.controller.GetAll)
-		routes.GET("/:id", as.controller.GetByID)
-		routes.PUT("/:id", as.controller.Update)
-		routes.DELETE("/:id", as.controller.Delete)
+		routes.Use(as.middle.AuthMiddleware())
+		routes.POST("/", as.middle.Permission(models.ResourceFootstep, models.ActionCreate), as.controller.Create)
+		routes.GET("/", as.middle.Permission(models.ResourceFootstep, models.ActionRead), as.controller.GetAll)
+		routes.GET("/:id", as.middle.Permission(models.ResourceFootstep, models.ActionRead), as.controller.GetByID)
+		routes.PUT("/:id", as.middle.Permission(models.ResourceFootstep, models.ActionUpdate), as.controller.Update)
+		routes.DELETE("/:id", as.middle.Permission(models.ResourceFootstep, models.ActionDelete), as.controller.Delete)
 	}
 }
@@ -0,0 +1,10 @@
+package leave
+
+import "go.uber.org/fx"
+
+var Module = fx.Module(
+	"leave-module",
+	fx.Provide(
+		NewLeaveService,
+	),
+)
@@ -0,0 +1,306 @@
+package leave
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/server/middleware"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// LeaveService manages the leave types of companies, and the leave requests
+// and balances of their employees.
+type LeaveService struct {
+	leaveTypeController *managers.Controller[models.LeaveType, models.LeaveTypeRequest, models.LeaveTypeResource]
+	leaveController     *managers.Controller[models.LeaveRequest, models.LeaveRequestRequest, models.LeaveRequestResource]
+	db                  *providers.DatabaseService
+	engine              *providers.EngineService
+	middle              *middleware.Middleware
+	models              *models.ModelResource
+}
+
+func NewLeaveService(
+	db *providers.DatabaseService,
+	engine *providers.EngineService,
+	middle *middleware.Middleware,
+	models *models.ModelResource,
+) *LeaveService {
+	leaveTypeController := managers.NewController(
+		models.LeaveTypeDB,
+		models.ValidateLeaveTypeRequest,
+		models.LeaveTypeToResource,
+		models.LeaveTypeToResourceList,
+	)
+	leaveController := managers.NewController(
+		models.LeaveRequestDB,
+		models.ValidateLeaveRequestRequest,
+		models.LeaveRequestToResource,
+		models.LeaveRequestToResourceList,
+	)
+
+	return &LeaveService{
+		leaveTypeController: leaveTypeController,
+		leaveController:     leaveController,
+		db:                  db,
+		engine:              engine,
+		middle:              middle,
+		models:              models,
+	}
+}
+
+func userClaims(ctx *gin.Context) (*providers.UserClaims, bool) {
+	claims, exists := ctx.Get("claims")
+	userClaims, ok := claims.(*providers.UserClaims)
+	if !exists || !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
+		return nil, false
+	}
+	return userClaims, true
+}
+
+func tenantScope(ctx *gin.Context) managers.ScopeFunc {
+	scope, _ := ctx.Get(managers.TenantScopeKey)
+	scopeFunc, _ := scope.(managers.ScopeFunc)
+	return scopeFunc
+}
+
+// leaveError responds with the error of filing, reviewing or balancing
+// leave.
+func leaveError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Leave type not found"})
+	case errors.Is(err, models.ErrLeaveNoWorkingDays):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "The leave covers no working days"})
+	case errors.Is(err, models.ErrLeaveInsufficientBalance):
+		ctx.JSON(http.StatusConflict, gin.H{"error": "The leave balance is insufficient"})
+	case errors.Is(err, models.ErrLeaveOverlap):
+		ctx.JSON(http.StatusConflict, gin.H{"error": "The leave overlaps another leave request"})
+	case errors.Is(err, models.ErrPayPeriodLocked):
+		ctx.JSON(http.StatusConflict, gin.H{"error": "The leave falls in a locked pay period"})
+	case errors.Is(err, models.ErrLeaveReviewed):
+		ctx.JSON(http.StatusConflict, gin.H{"error": "The leave request has already been reviewed"})
+	case errors.Is(err, models.ErrLeaveAlreadyApproved):
+		ctx.JSON(http.StatusConflict, gin.H{"error": "You already approved this leave request"})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// leaveRequest returns the leave request of the id param within the tenant
+// scope, responding with an error when there is none.
+func (ls *LeaveService) leaveRequest(ctx *gin.Context) (*models.LeaveRequest, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return nil, false
+	}
+	request, err := ls.models.LeaveRequestGet(tenantScope(ctx), uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Entity not found"})
+		return nil, false
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return request, true
+}
+
+// pending only lets requests on leave that is not reviewed yet through.
+func (ls *LeaveService) pending(ctx *gin.Context) {
+	request, ok := ls.leaveRequest(ctx)
+	if !ok {
+		ctx.Abort()
+		return
+	}
+	if request.Status != models.LeavePending || request.ApprovedLevels > 0 {
+		ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "The leave request has already been reviewed"})
+		return
+	}
+	ctx.Next()
+}
+
+// employeeVisible responds with an error when the employee is outside of the
+// tenant scope.
+func (ls *LeaveService) employeeVisible(ctx *gin.Context, employeeId uint) bool {
+	visible, err := ls.models.TimesheetEmployeeVisible(tenantScope(ctx), employeeId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if !visible {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return false
+	}
+	return true
+}
+
+// Create files leave for the employee.
+func (ls *LeaveService) Create(ctx *gin.Context) {
+	claims, ok := userClaims(ctx)
+	if !ok {
+		return
+	}
+	if claims.AccountType != "Employee" {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Only employees can file leave"})
+		return
+	}
+	var req models.LeaveRequestRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := ls.models.ValidateLeaveRequestRequest(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"validation_error": err.Error()})
+		return
+	}
+
+	request, err := ls.models.LeaveRequestFile(claims.ID, &req)
+	if err != nil {
+		leaveError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, ls.models.LeaveRequestToResource(request))
+}
+
+// GetByID responds with the leave request along with its approvals.
+func (ls *LeaveService) GetByID(ctx *gin.Context) {
+	request, ok := ls.leaveRequest(ctx)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, ls.models.LeaveRequestToResource(request))
+}
+
+func (ls *LeaveService) review(ctx *gin.Context, approve bool) {
+	claims, ok := userClaims(ctx)
+	if !ok {
+		return
+	}
+	var req models.LeaveReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := ls.models.ValidateLeaveReviewRequest(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"validation_error": err.Error()})
+		return
+	}
+	request, ok := ls.leaveRequest(ctx)
+	if !ok {
+		return
+	}
+	if claims.AccountType == "Employee" && claims.ID == request.EmployeeID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You cannot review your own leave"})
+		return
+	}
+
+	err := ls.models.LeaveRequestReview(request, approve, claims.AccountType, claims.ID, req.Note)
+	if err != nil {
+		leaveError(ctx, err)
+		return
+	}
+	request, err = ls.models.LeaveRequestGet(nil, request.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, ls.models.LeaveRequestToResource(request))
+}
+
+// Approve approves the leave request at its next level, approving it once
+// every level has.
+func (ls *LeaveService) Approve(ctx *gin.Context) {
+	ls.review(ctx, true)
+}
+
+// Reject rejects the leave request.
+func (ls *LeaveService) Reject(ctx *gin.Context) {
+	ls.review(ctx, false)
+}
+
+// Balances responds with the leave balances of an employee. Employees get
+// their own by default.
+func (ls *LeaveService) Balances(ctx *gin.Context) {
+	claims, ok := userClaims(ctx)
+	if !ok {
+		return
+	}
+	employeeId := claims.ID
+	if param := ctx.Query("employeeId"); param != "" || claims.AccountType != "Employee" {
+		id, err := strconv.Atoi(param)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employeeId"})
+			return
+		}
+		employeeId = uint(id)
+	}
+	if !ls.employeeVisible(ctx, employeeId) {
+		return
+	}
+
+	balances, err := ls.models.LeaveBalances(employeeId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, balances)
+}
+
+// SetBalance sets the balance of a paid leave type of an employee.
+func (ls *LeaveService) SetBalance(ctx *gin.Context) {
+	var req models.LeaveBalanceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := ls.models.ValidateLeaveBalanceRequest(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"validation_error": err.Error()})
+		return
+	}
+	if !ls.employeeVisible(ctx, req.EmployeeID) {
+		return
+	}
+
+	if err := ls.models.LeaveBalanceSet(&req); err != nil {
+		leaveError(ctx, err)
+		return
+	}
+	balances, err := ls.models.LeaveBalances(req.EmployeeID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, balances)
+}
+
+func (ls *LeaveService) RegisterRoutes() {
+	leaveTypes := ls.engine.Client.Group("/api/v1/leave-type")
+	{
+		leaveTypes.Use(ls.middle.AuthMiddleware())
+		leaveTypes.POST("/", ls.middle.Permission(models.ResourceLeaveType, models.ActionCreate), ls.leaveTypeController.Create)
+		leaveTypes.GET("/", ls.middle.Permission(models.ResourceLeaveType, models.ActionRead), ls.leaveTypeController.GetAll)
+		leaveTypes.GET("/:id", ls.middle.Permission(models.ResourceLeaveType, models.ActionRead), ls.leaveTypeController.GetByID)
+		leaveTypes.PUT("/:id", ls.middle.Permission(models.ResourceLeaveType, models.ActionUpdate), ls.leaveTypeController.Update)
+		leaveTypes.DELETE("/:id", ls.middle.Permission(models.ResourceLeaveType, models.ActionDelete), ls.leaveTypeController.Delete)
+	}
+
+	routes := ls.engine.Client.Group("/api/v1/leave")
+	{
+		routes.Use(ls.middle.AuthMiddleware())
+		routes.POST("/", ls.middle.Permission(models.ResourceLeave, models.ActionCreate), ls.Create)
+		routes.GET("/", ls.middle.Permission(models.ResourceLeave, models.ActionRead), ls.leaveController.GetAll)
+		routes.GET("/balance", ls.middle.Permission(models.ResourceLeave, models.ActionRead), ls.Balances)
+		routes.PUT("/balance", ls.middle.Permission(models.ResourceLeave, models.ActionUpdate), ls.SetBalance)
+		routes.GET("/:id", ls.middle.Permission(models.ResourceLeave, models.ActionRead), ls.GetByID)
+		routes.DELETE("/:id", ls.middle.Permission(models.ResourceLeave, models.ActionDelete), ls.pending, ls.leaveController.Delete)
+		routes.POST("/:id/approve", ls.middle.Permission(models.ResourceLeave, models.ActionUpdate), ls.Approve)
+		routes.POST("/:id/reject", ls.middle.Permission(models.ResourceLeave, models.ActionUpdate), ls.Reject)
+	}
+}
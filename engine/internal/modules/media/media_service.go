@@ -7,6 +7,7 @@ import (
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/server/middleware"
 	"github.com/gin-gonic/gin"
 )
 
@@ -15,6 +16,7 @@ type MediaService struct {
 
 	db             *providers.DatabaseService
 	engine         *providers.EngineService
+	middle         *middleware.Middleware
 	storageService *providers.StorageProvider
 	modelsResource *models.ModelResource
 	models         *models.ModelResource
@@ -23,6 +25,7 @@ type MediaService struct {
 func NewMediaService(
 	db *providers.DatabaseService,
 	engine *providers.EngineService,
+	middle *middleware.Middleware,
 	storageService *providers.StorageProvider,
 	modelsResource *models.ModelResource,
 	models *models.ModelResource,
@@ -38,6 +41,7 @@ func NewMediaService(
 		controller:     controller,
 		db:             db,
 		engine:         engine,
+		middle:         middle,
 		storageService: storageService,
 		modelsResource: modelsResource,
 		models:         models,
@@ -97,13 +101,14 @@ func (h *MediaService) Delete(ctx *gin.Context) {
 
 func (as *MediaService) RegisterRoutes() {
 	routes := as.engine.Client.Group("/api/v1/media")
+	routes.Use(as.middle.AuthMiddleware())
 	{
 		// Common
-		routes.POST("", as.Create)
-		routes.DELETE("/:id", as.Delete)
+		routes.POST("", as.middle.Permission(models.ResourceMedia, models.ActionCreate), as.Create)
+		routes.DELETE("/:id", as.middle.Permission(models.ResourceMedia, models.ActionDelete), as.Delete)
 
-		routes.GET("/", as.controller.GetAll)
-		routes.GET("/:id", as.controller.GetByID)
-		routes.PUT("/:id", as.controller.Update)
+		routes.GET("/", as.middle.Permission(models.ResourceMedia, models.ActionRead), as.controller.GetAll)
+		routes.GET("/:id", as.middle.Permission(models.ResourceMedia, models.ActionRead), as.controller.GetByID)
+		routes.PUT("/:id", as.middle.Permission(models.ResourceMedia, models.ActionUpdate), as.controller.Update)
 	}
 }
@@ -1,21 +1,31 @@
 package member
 
 import (
+	"errors"
+	"net/http"
+
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/server/middleware"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type MemberService struct {
 	controller *managers.Controller[models.Member, models.MemberRequest, models.MemberResource]
+	creator    *managers.Controller[models.Member, models.MemberCreateRequest, models.MemberResource]
+	importer   *managers.Importer[models.Member, models.MemberCreateRequest]
 	db         *providers.DatabaseService
 	engine     *providers.EngineService
+	middle     *middleware.Middleware
 	models     *models.ModelResource
 }
 
 func NewMemberService(
 	db *providers.DatabaseService,
 	engine *providers.EngineService,
+	middle *middleware.Middleware,
 	models *models.ModelResource,
 ) *MemberService {
 	controller := managers.NewController(
@@ -24,22 +34,79 @@ func NewMemberService(
 		models.MemberToResource,
 		models.MemberToResourceList,
 	)
+	creator := managers.NewController(
+		models.MemberDB,
+		models.ValidateMemberCreateRequest,
+		models.MemberToResource,
+		models.MemberToResourceList,
+	)
+	importer := managers.NewImporter(
+		models.MemberDB,
+		models.ValidateMemberCreateRequest,
+		nil,
+	)
 
 	return &MemberService{
 		controller: controller,
+		creator:    creator,
+		importer:   importer,
 		db:         db,
 		engine:     engine,
+		middle:     middle,
 		models:     models,
 	}
 }
 
+// Branch moves the member to another branch. Placing a member in a branch
+// requires the permission to create members, and the move is rolled back when
+// the branch is outside of the caller's scope.
+func (as *MemberService) Branch(ctx *gin.Context) {
+	var req models.BranchAssignRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := as.models.ValidateBranchAssignRequest(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"validation_error": err.Error()})
+		return
+	}
+	as.controller.UpdateWith(ctx, &models.Member{BranchID: &req.BranchID})
+}
+
+// Role sets the role of the member. Roles are shared by every company, so
+// only admins may assign them.
+func (as *MemberService) Role(ctx *gin.Context) {
+	var req models.RoleAssignRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := as.models.ValidateRoleAssignRequest(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"validation_error": err.Error()})
+		return
+	}
+	if _, err := as.models.RoleDB.FindByID(req.RoleID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Role not found"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	as.controller.UpdateWith(ctx, &models.Member{RoleID: &req.RoleID})
+}
+
 func (as *MemberService) RegisterRoutes() {
 	routes := as.engine.Client.Group("/api/v1/member")
 	{
-		routes.POST("/", as.controller.Create)
-		routes.GET("/", as.controller.GetAll)
-		routes.GET("/:id", as.controller.GetByID)
-		routes.PUT("/:id", as.controller.Update)
-		routes.DELETE("/:id", as.controller.Delete)
+		routes.Use(as.middle.AuthMiddleware())
+		routes.POST("/", as.middle.Permission(models.ResourceMember, models.ActionCreate), as.creator.Create)
+		routes.GET("/", as.middle.Permission(models.ResourceMember, models.ActionRead), as.controller.GetAll)
+		routes.GET("/:id", as.middle.Permission(models.ResourceMember, models.ActionRead), as.controller.GetByID)
+		routes.PUT("/:id", as.middle.BlockImpersonation(), as.middle.Permission(models.ResourceMember, models.ActionUpdate), as.controller.Update)
+		routes.PUT("/:id/branch", as.middle.Permission(models.ResourceMember, models.ActionCreate), as.Branch)
+		routes.PUT("/:id/role", as.middle.AuthMiddlewareAdminOnly(), as.middle.Permission(models.ResourceMember, models.ActionUpdate), as.Role)
+		routes.DELETE("/:id", as.middle.Permission(models.ResourceMember, models.ActionDelete), as.controller.Delete)
+		routes.POST("/import", as.middle.Permission(models.ResourceMember, models.ActionCreate), as.importer.Import)
 	}
 }
@@ -2,6 +2,8 @@ package modules
 
 import (
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/admin"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/api_key"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/audit"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/auth"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/branch"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/company"
@@ -10,18 +12,25 @@ import (
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/feedback"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/footstep"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/gender"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/leave"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/media"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/member"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/owner"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/payroll"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/permission"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/role"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/schedule"
 
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/timesheet"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/timesheet_correction"
 	"go.uber.org/fx"
 )
 
 var Module = fx.Module(
 	"modules",
 	admin.Module,
+	api_key.Module,
+	audit.Module,
 	auth.Module,
 	branch.Module,
 	company.Module,
@@ -30,9 +39,14 @@ var Module = fx.Module(
 	feedback.Module,
 	footstep.Module,
 	gender.Module,
+	leave.Module,
 	media.Module,
 	member.Module,
 	owner.Module,
+	payroll.Module,
+	permission.Module,
 	role.Module,
+	schedule.Module,
 	timesheet.Module,
+	timesheet_correction.Module,
 )
@@ -1,21 +1,29 @@
 package owner
 
 import (
+	"errors"
+	"net/http"
+
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/server/middleware"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type OwnerService struct {
 	controller *managers.Controller[models.Owner, models.OwnerRequest, models.OwnerResource]
 	db         *providers.DatabaseService
 	engine     *providers.EngineService
+	middle     *middleware.Middleware
 	models     *models.ModelResource
 }
 
 func NewOwnerService(
 	db *providers.DatabaseService,
 	engine *providers.EngineService,
+	middle *middleware.Middleware,
 	models *models.ModelResource,
 ) *OwnerService {
 	controller := managers.NewController(
@@ -29,17 +37,43 @@ func NewOwnerService(
 		controller: controller,
 		db:         db,
 		engine:     engine,
+		middle:     middle,
 		models:     models,
 	}
 }
 
+// Role sets the role of the owner. Roles are shared by every company, so only
+// admins may assign them.
+func (as *OwnerService) Role(ctx *gin.Context) {
+	var req models.RoleAssignRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := as.models.ValidateRoleAssignRequest(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"validation_error": err.Error()})
+		return
+	}
+	if _, err := as.models.RoleDB.FindByID(req.RoleID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Role not found"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	as.controller.UpdateWith(ctx, &models.Owner{RoleID: &req.RoleID})
+}
+
 func (as *OwnerService) RegisterRoutes() {
 	routes := as.engine.Client.Group("/api/v1/owner")
 	{
-		routes.POST("/", as.controller.Create)
-		routes.GET("/", as.controller.GetAll)
-		routes.GET("/:id", as.controller.GetByID)
-		routes.PUT("/:id", as.controller.Update)
-		routes.DELETE("/:id", as.controller.Delete)
+		routes.Use(as.middle.AuthMiddleware())
+		routes.POST("/", as.middle.Permission(models.ResourceOwner, models.ActionCreate), as.controller.Create)
+		routes.GET("/", as.middle.Permission(models.ResourceOwner, models.ActionRead), as.controller.GetAll)
+		routes.GET("/:id", as.middle.Permission(models.ResourceOwner, models.ActionRead), as.controller.GetByID)
+		routes.PUT("/:id", as.middle.BlockImpersonation(), as.middle.Permission(models.ResourceOwner, models.ActionUpdate), as.controller.Update)
+		routes.PUT("/:id/role", as.middle.AuthMiddlewareAdminOnly(), as.middle.Permission(models.ResourceOwner, models.ActionUpdate), as.Role)
+		routes.DELETE("/:id", as.middle.Permission(models.ResourceOwner, models.ActionDelete), as.controller.Delete)
 	}
 }
@@ -0,0 +1,10 @@
+package payroll
+
+import "go.uber.org/fx"
+
+var Module = fx.Module(
+	"payroll-module",
+	fx.Provide(
+		NewPayrollService,
+	),
+)
@@ -0,0 +1,166 @@
+package payroll
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/server/middleware"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// PayrollService manages the pay periods of companies and summarizes the
+// timesheets of their employees per period.
+type PayrollService struct {
+	controller *managers.Controller[models.PayPeriod, models.PayPeriodRequest, models.PayPeriodResource]
+	db         *providers.DatabaseService
+	engine     *providers.EngineService
+	middle     *middleware.Middleware
+	models     *models.ModelResource
+}
+
+func NewPayrollService(
+	db *providers.DatabaseService,
+	engine *providers.EngineService,
+	middle *middleware.Middleware,
+	models *models.ModelResource,
+) *PayrollService {
+	controller := managers.NewController(
+		models.PayPeriodDB,
+		models.ValidatePayPeriodRequest,
+		models.PayPeriodToResource,
+		models.PayPeriodToResourceList,
+	)
+
+	return &PayrollService{
+		controller: controller,
+		db:         db,
+		engine:     engine,
+		middle:     middle,
+		models:     models,
+	}
+}
+
+// payPeriod returns the pay period of the id param within the tenant scope,
+// responding with an error when there is none.
+func (ps *PayrollService) payPeriod(ctx *gin.Context) (*models.PayPeriod, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return nil, false
+	}
+	scope, _ := ctx.Get(managers.TenantScopeKey)
+	scopeFunc, _ := scope.(managers.ScopeFunc)
+	period, err := ps.models.PayPeriodGet(scopeFunc, uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Entity not found"})
+		return nil, false
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return period, true
+}
+
+// unlocked only lets requests on pay periods that are not locked through.
+func (ps *PayrollService) unlocked(ctx *gin.Context) {
+	period, ok := ps.payPeriod(ctx)
+	if !ok {
+		ctx.Abort()
+		return
+	}
+	if period.LockedAt != nil {
+		ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "The pay period is locked"})
+		return
+	}
+	ctx.Next()
+}
+
+func (ps *PayrollService) setLocked(ctx *gin.Context, locked bool) {
+	period, ok := ps.payPeriod(ctx)
+	if !ok {
+		return
+	}
+	if err := ps.models.PayPeriodSetLocked(period, locked); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, ps.models.PayPeriodToResource(period))
+}
+
+// Lock closes the pay period, so that its timesheets can no longer be
+// changed.
+func (ps *PayrollService) Lock(ctx *gin.Context) {
+	ps.setLocked(ctx, true)
+}
+
+// Unlock reopens the pay period.
+func (ps *PayrollService) Unlock(ctx *gin.Context) {
+	ps.setLocked(ctx, false)
+}
+
+// Summary responds with the timesheet summary of every employee of the
+// company of the pay period.
+func (ps *PayrollService) Summary(ctx *gin.Context) {
+	period, ok := ps.payPeriod(ctx)
+	if !ok {
+		return
+	}
+	summaries, err := ps.models.TimesheetSummaries(period)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Summary: %v", err)})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"payPeriod": ps.models.PayPeriodToResource(period),
+		"data":      ps.models.TimesheetSummaryToResourceList(summaries),
+	})
+}
+
+// Export exports the timesheet summaries of the pay period.
+func (ps *PayrollService) Export(ctx *gin.Context) {
+	period, ok := ps.payPeriod(ctx)
+	if !ok {
+		return
+	}
+	summaries, err := ps.models.TimesheetSummaries(period)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Export: %v", err)})
+		return
+	}
+	stream := func(fn func(batch []*models.TimesheetSummary) error) error {
+		return fn(summaries)
+	}
+	fileName := fmt.Sprintf("timesheet-summary-%d", period.ID)
+	columns := ps.models.TimesheetSummaryExportColumns()
+	if err := managers.Export(ctx, fileName, columns, ps.models.TimesheetSummaryToExportRow, stream); err != nil {
+		if errors.Is(err, managers.ErrUnsupportedExportFormat) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.String(http.StatusInternalServerError, "Failed to generate export: %v", err)
+		return
+	}
+}
+
+func (ps *PayrollService) RegisterRoutes() {
+	routes := ps.engine.Client.Group("/api/v1/payroll")
+	{
+		routes.Use(ps.middle.AuthMiddleware())
+		routes.POST("/", ps.middle.Permission(models.ResourcePayroll, models.ActionCreate), ps.controller.Create)
+		routes.GET("/", ps.middle.Permission(models.ResourcePayroll, models.ActionRead), ps.controller.GetAll)
+		routes.GET("/:id", ps.middle.Permission(models.ResourcePayroll, models.ActionRead), ps.controller.GetByID)
+		routes.PUT("/:id", ps.middle.Permission(models.ResourcePayroll, models.ActionUpdate), ps.unlocked, ps.controller.Update)
+		routes.DELETE("/:id", ps.middle.Permission(models.ResourcePayroll, models.ActionDelete), ps.unlocked, ps.controller.Delete)
+		routes.POST("/:id/lock", ps.middle.Permission(models.ResourcePayroll, models.ActionUpdate), ps.Lock)
+		routes.POST("/:id/unlock", ps.middle.Permission(models.ResourcePayroll, models.ActionUpdate), ps.Unlock)
+		routes.GET("/:id/summary", ps.middle.Permission(models.ResourcePayroll, models.ActionRead), ps.Summary)
+		routes.GET("/:id/export", ps.middle.Permission(models.ResourcePayroll, models.ActionRead), ps.Export)
+	}
+}
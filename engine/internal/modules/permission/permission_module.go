@@ -0,0 +1,10 @@
+package permission
+
+import "go.uber.org/fx"
+
+var Module = fx.Module(
+	"permission-module",
+	fx.Provide(
+		NewPermissionService,
+	),
+)
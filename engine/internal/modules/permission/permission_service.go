@@ -0,0 +1,134 @@
+package permission
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/server/middleware"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type PermissionService struct {
+	middle        *middleware.Middleware
+	engine        *providers.EngineService
+	tokenProvider *providers.TokenService
+	modelResource *models.ModelResource
+}
+
+func NewPermissionService(
+	middle *middleware.Middleware,
+	engine *providers.EngineService,
+	tokenProvider *providers.TokenService,
+	modelResource *models.ModelResource,
+) *PermissionService {
+	return &PermissionService{
+		middle:        middle,
+		engine:        engine,
+		tokenProvider: tokenProvider,
+		modelResource: modelResource,
+	}
+}
+
+func (ps *PermissionService) getUserClaims(ctx *gin.Context) (*providers.UserClaims, error) {
+	claims, exists := ctx.Get("claims")
+	if !exists {
+		ps.tokenProvider.ClearTokenCookie(ctx)
+		return nil, fmt.Errorf("claims not found in context")
+	}
+
+	userClaims, ok := claims.(*providers.UserClaims)
+	if !ok {
+		ps.tokenProvider.ClearTokenCookie(ctx)
+		return nil, fmt.Errorf("failed to cast claims to *auth.UserClaims")
+	}
+	return userClaims, nil
+}
+
+// Effective lists the permissions that apply to the current user.
+func (ps *PermissionService) Effective(ctx *gin.Context) {
+	userClaims, err := ps.getUserClaims(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
+		return
+	}
+	permissions, err := ps.modelResource.PermissionGetEffective(userClaims.AccountType, userClaims.ID)
+	if errors.Is(err, models.ErrPermissionDenied) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "User account not found."})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve permissions."})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"accountType": userClaims.AccountType,
+		"permissions": permissions,
+	})
+}
+
+func (ps *PermissionService) GetForRole(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	permissions, err := ps.modelResource.PermissionGetForRole(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve permissions."})
+		return
+	}
+	ctx.JSON(http.StatusOK, ps.modelResource.PermissionToResourceList(permissions))
+}
+
+// ReplaceForRole replaces the whole permission set of a role. Roles are
+// shared by every company, so only admins may change what they grant.
+func (ps *PermissionService) ReplaceForRole(ctx *gin.Context) {
+	userClaims, err := ps.getUserClaims(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
+		return
+	}
+	if userClaims.AccountType != "Admin" {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Only admins can change the permissions of a role."})
+		return
+	}
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var req models.RolePermissionsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := ps.modelResource.ValidateRolePermissionsRequest(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"validation_error": err.Error()})
+		return
+	}
+	permissions, err := ps.modelResource.PermissionReplaceForRole(uint(id), &req)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Role not found"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update permissions."})
+		return
+	}
+	ctx.JSON(http.StatusOK, ps.modelResource.PermissionToResourceList(permissions))
+}
+
+func (ps *PermissionService) RegisterRoutes() {
+	routes := ps.engine.Client.Group("/api/v1/permission")
+	routes.Use(ps.middle.AuthMiddleware())
+	{
+		routes.GET("/effective", ps.Effective)
+		routes.GET("/role/:id", ps.middle.Permission(models.ResourceRole, models.ActionRead), ps.GetForRole)
+		routes.PUT("/role/:id", ps.middle.Permission(models.ResourceRole, models.ActionUpdate), ps.ReplaceForRole)
+	}
+}
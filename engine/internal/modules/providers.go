@@ -33,6 +33,7 @@ func NewModuleServiceProvider(
 	router.Use(middle.Config())
 	router.Use(middle.Secure())
 	router.Use(middle.RateLimiterMiddleware(20, 20))
+	router.Use(middle.AuditContext())
 
 	apiRoutes.APITestRoute()
 	apiRoutes.API()
@@ -1,21 +1,30 @@
 package role
 
 import (
+	"errors"
+	"net/http"
+	"strconv"
+
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/server/middleware"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type RoleService struct {
 	controller *managers.Controller[models.Role, models.RoleRequest, models.RoleResource]
 	db         *providers.DatabaseService
 	engine     *providers.EngineService
+	middle     *middleware.Middleware
 	models     *models.ModelResource
 }
 
 func NewRoleService(
 	db *providers.DatabaseService,
 	engine *providers.EngineService,
+	middle *middleware.Middleware,
 	models *models.ModelResource,
 ) *RoleService {
 	controller := managers.NewController(
@@ -29,17 +38,70 @@ func NewRoleService(
 		controller: controller,
 		db:         db,
 		engine:     engine,
+		middle:     middle,
 		models:     models,
 	}
 }
 
+// SetRequireTwoFactor sets whether holders of the role must use two-factor
+// authentication. It requires the permission to update roles; Admins may then
+// change any role, Owners only roles held solely by themselves and the staff
+// of their companies.
+func (as *RoleService) SetRequireTwoFactor(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var req models.RoleTwoFactorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	claims, exists := ctx.Get("claims")
+	userClaims, ok := claims.(*providers.UserClaims)
+	if !exists || !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
+		return
+	}
+	switch userClaims.AccountType {
+	case "Admin":
+	case "Owner":
+		outside, err := as.models.RoleHeldOutsideOwner(uint(id), userClaims.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if outside {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "Role is assigned outside of your companies"})
+			return
+		}
+	default:
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: insufficient permissions"})
+		return
+	}
+
+	role, err := as.models.RoleSetRequireTwoFactor(uint(id), req.Required)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Role not found"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, as.models.RoleToResource(role))
+}
+
 func (as *RoleService) RegisterRoutes() {
 	routes := as.engine.Client.Group("/api/v1/role")
 	{
-		routes.POST("/", as.controller.Create)
-		routes.GET("/", as.controller.GetAll)
-		routes.GET("/:id", as.controller.GetByID)
-		routes.PUT("/:id", as.controller.Update)
-		routes.DELETE("/:id", as.controller.Delete)
+		routes.Use(as.middle.AuthMiddleware())
+		routes.POST("/", as.middle.Permission(models.ResourceRole, models.ActionCreate), as.controller.Create)
+		routes.GET("/", as.middle.Permission(models.ResourceRole, models.ActionRead), as.controller.GetAll)
+		routes.GET("/:id", as.middle.Permission(models.ResourceRole, models.ActionRead), as.controller.GetByID)
+		routes.PUT("/:id", as.middle.Permission(models.ResourceRole, models.ActionUpdate), as.controller.Update)
+		routes.DELETE("/:id", as.middle.Permission(models.ResourceRole, models.ActionDelete), as.controller.Delete)
+		routes.PUT("/:id/two-factor", as.middle.Permission(models.ResourceRole, models.ActionUpdate), as.SetRequireTwoFactor)
 	}
 }
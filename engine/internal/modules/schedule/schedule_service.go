@@ -0,0 +1,88 @@
+package schedule
+
+import (
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/server/middleware"
+)
+
+// ScheduleService manages the shifts of companies, the assignments of
+// employees and branches to them, and the holidays of companies.
+type ScheduleService struct {
+	shiftController      *managers.Controller[models.Shift, models.ShiftRequest, models.ShiftResource]
+	assignmentController *managers.Controller[models.ShiftAssignment, models.ShiftAssignmentRequest, models.ShiftAssignmentResource]
+	holidayController    *managers.Controller[models.Holiday, models.HolidayRequest, models.HolidayResource]
+	db                   *providers.DatabaseService
+	engine               *providers.EngineService
+	middle               *middleware.Middleware
+	models               *models.ModelResource
+}
+
+func NewScheduleService(
+	db *providers.DatabaseService,
+	engine *providers.EngineService,
+	middle *middleware.Middleware,
+	models *models.ModelResource,
+) *ScheduleService {
+	shiftController := managers.NewController(
+		models.ShiftDB,
+		models.ValidateShiftRequest,
+		models.ShiftToResource,
+		models.ShiftToResourceList,
+	)
+	assignmentController := managers.NewController(
+		models.ShiftAssignmentDB,
+		models.ValidateShiftAssignmentRequest,
+		models.ShiftAssignmentToResource,
+		models.ShiftAssignmentToResourceList,
+	)
+	holidayController := managers.NewController(
+		models.HolidayDB,
+		models.ValidateHolidayRequest,
+		models.HolidayToResource,
+		models.HolidayToResourceList,
+	)
+
+	return &ScheduleService{
+		shiftController:      shiftController,
+		assignmentController: assignmentController,
+		holidayController:    holidayController,
+		db:                   db,
+		engine:               engine,
+		middle:               middle,
+		models:               models,
+	}
+}
+
+func (as *ScheduleService) RegisterRoutes() {
+	shifts := as.engine.Client.Group("/api/v1/shift")
+	{
+		shifts.Use(as.middle.AuthMiddleware())
+		shifts.POST("/", as.middle.Permission(models.ResourceShift, models.ActionCreate), as.shiftController.Create)
+		shifts.GET("/", as.middle.Permission(models.ResourceShift, models.ActionRead), as.shiftController.GetAll)
+		shifts.GET("/:id", as.middle.Permission(models.ResourceShift, models.ActionRead), as.shiftController.GetByID)
+		shifts.PUT("/:id", as.middle.Permission(models.ResourceShift, models.ActionUpdate), as.shiftController.Update)
+		shifts.DELETE("/:id", as.middle.Permission(models.ResourceShift, models.ActionDelete), as.shiftController.Delete)
+	}
+
+	assignments := as.engine.Client.Group("/api/v1/schedule")
+	{
+		assignments.Use(as.middle.AuthMiddleware())
+		assignments.POST("/", as.middle.Permission(models.ResourceSchedule, models.ActionCreate), as.assignmentController.Create)
+		assignments.GET("/", as.middle.Permission(models.ResourceSchedule, models.ActionRead), as.assignmentController.GetAll)
+		assignments.GET("/:id", as.middle.Permission(models.ResourceSchedule, models.ActionRead), as.assignmentController.GetByID)
+		assignments.PUT("/:id", as.middle.Permission(models.ResourceSchedule, models.ActionUpdate), as.assignmentController.Update)
+		assignments.DELETE("/:id", as.middle.Permission(models.ResourceSchedule, models.ActionDelete), as.assignmentController.Delete)
+	}
+
+	holidays := as.engine.Client.Group("/api/v1/holiday")
+	{
+		holidays.Use(as.middle.AuthMiddleware())
+		holidays.POST("/", as.middle.Permission(models.ResourceHoliday, models.ActionCreate), as.holidayController.Create)
+		holidays.GET("/", as.middle.Permission(models.ResourceHoliday, models.ActionRead), as.holidayController.GetAll)
+		holidays.GET("/:id", as.middle.Permission(models.ResourceHoliday, models.ActionRead), as.holidayController.GetByID)
+		holidays.PUT("/:id", as.middle.Permission(models.ResourceHoliday, models.ActionUpdate), as.holidayController.Update)
+		holidays.DELETE("/:id", as.middle.Permission(models.ResourceHoliday, models.ActionDelete), as.holidayController.Delete)
+	}
+}
@@ -0,0 +1,77 @@
+package timesheet
+
+import (
+	"context"
+	"time"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/config"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// autoCloseInterval is how often stale timesheets are looked for.
+const autoCloseInterval = 15 * time.Minute
+
+// TimesheetJob closes the timesheets of employees who forgot to time out,
+// once they have been open for TIMESHEET_AUTO_CLOSE, and flags them for
+// review.
+type TimesheetJob struct {
+	cfg           *config.AppConfig
+	logger        *providers.LoggerService
+	modelResource *models.ModelResource
+}
+
+func NewTimesheetJob(
+	lc fx.Lifecycle,
+	cfg *config.AppConfig,
+	logger *providers.LoggerService,
+	modelResource *models.ModelResource,
+) *TimesheetJob {
+	job := &TimesheetJob{
+		cfg:           cfg,
+		logger:        logger,
+		modelResource: modelResource,
+	}
+	if cfg.TimesheetAutoClose <= 0 {
+		return job
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go job.loop(ctx)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+	return job
+}
+
+// CloseStale closes the timesheets open for longer than TIMESHEET_AUTO_CLOSE.
+func (tj *TimesheetJob) CloseStale() {
+	closed, err := tj.modelResource.TimesheetCloseStale(time.Now().Add(-tj.cfg.TimesheetAutoClose))
+	if err != nil {
+		tj.logger.Error("Failed to close stale timesheets", zap.Error(err))
+	}
+	if closed > 0 {
+		tj.logger.Info("Closed stale timesheets", zap.Int("count", closed))
+	}
+}
+
+func (tj *TimesheetJob) loop(ctx context.Context) {
+	ticker := time.NewTicker(autoCloseInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			tj.CloseStale()
+		}
+	}
+}
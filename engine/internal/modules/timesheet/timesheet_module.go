@@ -7,4 +7,7 @@ var Module = fx.Module(
 	fx.Provide(
 		NewTimesheetService,
 	),
+	fx.Invoke(
+		NewTimesheetJob,
+	),
 )
@@ -1,12 +1,15 @@
 package timesheet
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/helpers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
 	"github.com/Lands-Horizon-Corp/horizon-corp/server/middleware"
 	"github.com/gin-gonic/gin"
@@ -90,12 +93,14 @@ func (ts *TimesheetService) findall(ctx *gin.Context) {
 	}
 	data := ts.modelResource.TimesheetToResourceList(timesheet.Data)
 	ctx.JSON(http.StatusOK, gin.H{
-		"data":      data,
-		"pageIndex": timesheet.PageIndex,
-		"totalPage": timesheet.TotalPage,
-		"pageSize":  timesheet.PageSize,
-		"totalSize": timesheet.TotalSize,
-		"pages":     timesheet.Pages,
+		"data":       data,
+		"pageIndex":  timesheet.PageIndex,
+		"totalPage":  timesheet.TotalPage,
+		"pageSize":   timesheet.PageSize,
+		"totalSize":  timesheet.TotalSize,
+		"pages":      timesheet.Pages,
+		"nextCursor": timesheet.NextCursor,
+		"prevCursor": timesheet.PrevCursor,
 	})
 }
 
@@ -142,13 +147,32 @@ func (ts *TimesheetService) timein(ctx *gin.Context) {
 		return
 	}
 
+	geofence, ok := ts.geofence(ctx, userClaims.ID, req.Punch)
+	if !ok {
+		return
+	}
+
+	// The punch is recorded at the server time, not the time of the device
+	timeIn := time.Now()
 	newTimesheet := &models.Timesheet{
-		EmployeeID: userClaims.ID,
-		TimeIn:     &req.TimeIn,
-		MediaInID:  req.MediaIn.ID,
+		EmployeeID:   userClaims.ID,
+		TimeIn:       &timeIn,
+		MediaInID:    req.MediaIn.ID,
+		LatitudeIn:   req.Latitude,
+		LongitudeIn:  req.Longitude,
+		AccuracyIn:   req.Accuracy,
+		DistanceIn:   geofence.Distance,
+		OutOfRangeIn: geofence.OutOfRange,
+	}
+	if err := ts.modelResource.TimesheetApplyAttendance(newTimesheet); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not compute attendance"})
+		return
+	}
+	if !ts.editable(ctx, newTimesheet) {
+		return
 	}
 
-	if err := ts.modelResource.TimesheetDB.Create(newTimesheet).Error; err != nil {
+	if err := ts.modelResource.TimesheetDB.Create(newTimesheet); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not create timesheet"})
 		return
 	}
@@ -186,10 +210,27 @@ func (ts *TimesheetService) timeout(ctx *gin.Context) {
 		return
 	}
 
-	currentTS.TimeOut = &req.TimeOut
+	if !ts.editable(ctx, &currentTS) {
+		return
+	}
+	geofence, ok := ts.geofence(ctx, userClaims.ID, req.Punch)
+	if !ok {
+		return
+	}
+	timeOut := time.Now()
+	currentTS.TimeOut = &timeOut
 	currentTS.MediaOutID = req.MediaOut.ID
+	currentTS.LatitudeOut = req.Latitude
+	currentTS.LongitudeOut = req.Longitude
+	currentTS.AccuracyOut = req.Accuracy
+	currentTS.DistanceOut = geofence.Distance
+	currentTS.OutOfRangeOut = geofence.OutOfRange
+	if err := ts.modelResource.TimesheetApplyAttendance(&currentTS); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not compute attendance"})
+		return
+	}
 
-	if err := ts.modelResource.TimesheetDB.Update(&currentTS, []string{}).Error; err != nil {
+	if err := ts.modelResource.TimesheetDB.Update(&currentTS, []string{}); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not update timesheet"})
 		return
 	}
@@ -197,14 +238,110 @@ func (ts *TimesheetService) timeout(ctx *gin.Context) {
 
 }
 
+// geofence checks the punch against the branch of the employee, responding
+// with an error when the company rejects it.
+func (ts *TimesheetService) geofence(ctx *gin.Context, employeeId uint, punch models.Punch) (*models.GeofenceResult, bool) {
+	result, err := ts.modelResource.GeofenceCheck(employeeId, punch)
+	switch {
+	case errors.Is(err, models.ErrGeofenceLocationRequired):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Your location is required to time in or out"})
+		return nil, false
+	case errors.Is(err, models.ErrGeofenceOutOfRange):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("You are %.0f meters away from your branch", *result.Distance)})
+		return nil, false
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not check your location"})
+		return nil, false
+	}
+	return result, true
+}
+
+// editable responds with a conflict when the timesheet is in a locked pay
+// period.
+func (ts *TimesheetService) editable(ctx *gin.Context, timesheet *models.Timesheet) bool {
+	err := ts.modelResource.TimesheetEditable(timesheet)
+	if errors.Is(err, models.ErrPayPeriodLocked) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "The pay period of the timesheet is locked"})
+		return false
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+// attendance responds with the attendance of an employee on every day from
+// the from date to the to date. Employees get their own by default.
+func (ts *TimesheetService) attendance(ctx *gin.Context) {
+	userClaims, err := ts.getUserClaims(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
+		return
+	}
+	employeeId := userClaims.ID
+	if param := ctx.Query("employeeId"); param != "" || userClaims.AccountType != "Employee" {
+		id, err := strconv.Atoi(param)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employeeId"})
+			return
+		}
+		employeeId = uint(id)
+	}
+	from, err := time.Parse(time.DateOnly, ctx.Query("from"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date (YYYY-MM-DD)"})
+		return
+	}
+	to, err := time.Parse(time.DateOnly, ctx.Query("to"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date (YYYY-MM-DD)"})
+		return
+	}
+
+	scope, _ := ctx.Get(managers.TenantScopeKey)
+	scopeFunc, _ := scope.(managers.ScopeFunc)
+	visible, err := ts.modelResource.TimesheetEmployeeVisible(scopeFunc, employeeId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !visible {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+
+	days, err := ts.modelResource.AttendanceForEmployee(employeeId, from, to)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Attendance: %v", err)})
+		return
+	}
+	ctx.JSON(http.StatusOK, days)
+}
+
+// review responds with the timesheets flagged for review, such as those
+// closed by the auto-close job.
+func (ts *TimesheetService) review(ctx *gin.Context) {
+	scope, _ := ctx.Get(managers.TenantScopeKey)
+	scopeFunc, _ := scope.(managers.ScopeFunc)
+	timesheets, err := ts.modelResource.TimesheetNeedsReview(scopeFunc)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, ts.modelResource.TimesheetToResourceList(timesheets))
+}
+
 func (ts *TimesheetService) RegisterRoutes() {
 	routes := ts.engine.Client.Group("/api/v1/timesheet")
 	{
 		routes.Use(ts.middle.AuthMiddleware())
-		routes.GET("/", ts.findall)
-		routes.GET("/current", ts.current)
-		routes.POST("/time-in", ts.timein)
-		routes.POST("/time-out", ts.timeout)
+		routes.GET("/", ts.middle.Permission(models.ResourceTimesheet, models.ActionRead), ts.findall)
+		routes.GET("/current", ts.middle.Permission(models.ResourceTimesheet, models.ActionRead), ts.current)
+		routes.GET("/attendance", ts.middle.Permission(models.ResourceTimesheet, models.ActionRead), ts.attendance)
+		routes.GET("/review", ts.middle.Permission(models.ResourceTimesheet, models.ActionRead), ts.review)
+		routes.POST("/time-in", ts.middle.Permission(models.ResourceTimesheet, models.ActionCreate), ts.timein)
+		routes.POST("/time-out", ts.middle.Permission(models.ResourceTimesheet, models.ActionCreate), ts.timeout)
 
 	}
 }
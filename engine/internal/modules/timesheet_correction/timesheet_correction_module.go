@@ -0,0 +1,10 @@
+package timesheet_correction
+
+import "go.uber.org/fx"
+
+var Module = fx.Module(
+	"timesheet-correction-module",
+	fx.Provide(
+		NewTimesheetCorrectionService,
+	),
+)
@@ -0,0 +1,200 @@
+package timesheet_correction
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/server/middleware"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TimesheetCorrectionService lets employees file corrections of their
+// timesheets, and supervisors and owners approve or reject them.
+type TimesheetCorrectionService struct {
+	controller *managers.Controller[models.TimesheetCorrection, models.TimesheetCorrectionRequest, models.TimesheetCorrectionResource]
+	db         *providers.DatabaseService
+	engine     *providers.EngineService
+	middle     *middleware.Middleware
+	models     *models.ModelResource
+}
+
+func NewTimesheetCorrectionService(
+	db *providers.DatabaseService,
+	engine *providers.EngineService,
+	middle *middleware.Middleware,
+	models *models.ModelResource,
+) *TimesheetCorrectionService {
+	controller := managers.NewController(
+		models.TimesheetCorrectionDB,
+		models.ValidateTimesheetCorrectionRequest,
+		models.TimesheetCorrectionToResource,
+		models.TimesheetCorrectionToResourceList,
+	)
+
+	return &TimesheetCorrectionService{
+		controller: controller,
+		db:         db,
+		engine:     engine,
+		middle:     middle,
+		models:     models,
+	}
+}
+
+func userClaims(ctx *gin.Context) (*providers.UserClaims, bool) {
+	claims, exists := ctx.Get("claims")
+	userClaims, ok := claims.(*providers.UserClaims)
+	if !exists || !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated."})
+		return nil, false
+	}
+	return userClaims, true
+}
+
+// correction returns the correction of the id param within the tenant scope,
+// responding with an error when there is none.
+func (tcs *TimesheetCorrectionService) correction(ctx *gin.Context) (*models.TimesheetCorrection, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return nil, false
+	}
+	scope, _ := ctx.Get(managers.TenantScopeKey)
+	scopeFunc, _ := scope.(managers.ScopeFunc)
+	correction, err := tcs.models.TimesheetCorrectionGet(scopeFunc, uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Entity not found"})
+		return nil, false
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return correction, true
+}
+
+// pending only lets requests on corrections that are not reviewed yet
+// through.
+func (tcs *TimesheetCorrectionService) pending(ctx *gin.Context) {
+	correction, ok := tcs.correction(ctx)
+	if !ok {
+		ctx.Abort()
+		return
+	}
+	if correction.Status != models.CorrectionPending {
+		ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "The correction has already been reviewed"})
+		return
+	}
+	ctx.Next()
+}
+
+// correctionError responds with the error of filing or reviewing a
+// correction.
+func correctionError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Timesheet not found"})
+	case errors.Is(err, models.ErrCorrectionTimes):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "The time out must be after the time in"})
+	case errors.Is(err, models.ErrPayPeriodLocked):
+		ctx.JSON(http.StatusConflict, gin.H{"error": "The pay period of the timesheet is locked"})
+	case errors.Is(err, models.ErrCorrectionPending):
+		ctx.JSON(http.StatusConflict, gin.H{"error": "The timesheet already has a pending correction"})
+	case errors.Is(err, models.ErrCorrectionReviewed):
+		ctx.JSON(http.StatusConflict, gin.H{"error": "The correction has already been reviewed"})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// Create files a correction of one of the timesheets of the employee, or of
+// a timesheet they missed.
+func (tcs *TimesheetCorrectionService) Create(ctx *gin.Context) {
+	claims, ok := userClaims(ctx)
+	if !ok {
+		return
+	}
+	if claims.AccountType != "Employee" {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Only employees can file timesheet corrections"})
+		return
+	}
+	var req models.TimesheetCorrectionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tcs.models.ValidateTimesheetCorrectionRequest(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"validation_error": err.Error()})
+		return
+	}
+
+	correction, err := tcs.models.TimesheetCorrectionFile(claims.ID, &req)
+	if err != nil {
+		correctionError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, tcs.models.TimesheetCorrectionToResource(correction))
+}
+
+func (tcs *TimesheetCorrectionService) review(ctx *gin.Context, approve bool) {
+	claims, ok := userClaims(ctx)
+	if !ok {
+		return
+	}
+	var req models.TimesheetCorrectionReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tcs.models.ValidateTimesheetCorrectionReviewRequest(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"validation_error": err.Error()})
+		return
+	}
+	correction, ok := tcs.correction(ctx)
+	if !ok {
+		return
+	}
+	if claims.AccountType == "Employee" && claims.ID == correction.EmployeeID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You cannot review your own correction"})
+		return
+	}
+
+	err := tcs.models.TimesheetCorrectionReview(correction, approve, claims.AccountType, claims.ID, req.Note)
+	if err != nil {
+		correctionError(ctx, err)
+		return
+	}
+	correction, err = tcs.models.TimesheetCorrectionGet(nil, correction.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, tcs.models.TimesheetCorrectionToResource(correction))
+}
+
+// Approve applies the correction to its timesheet.
+func (tcs *TimesheetCorrectionService) Approve(ctx *gin.Context) {
+	tcs.review(ctx, true)
+}
+
+// Reject closes the correction without changing its timesheet.
+func (tcs *TimesheetCorrectionService) Reject(ctx *gin.Context) {
+	tcs.review(ctx, false)
+}
+
+func (tcs *TimesheetCorrectionService) RegisterRoutes() {
+	routes := tcs.engine.Client.Group("/api/v1/timesheet-correction")
+	{
+		routes.Use(tcs.middle.AuthMiddleware())
+		routes.POST("/", tcs.middle.Permission(models.ResourceTimesheetCorrection, models.ActionCreate), tcs.Create)
+		routes.GET("/", tcs.middle.Permission(models.ResourceTimesheetCorrection, models.ActionRead), tcs.controller.GetAll)
+		routes.GET("/:id", tcs.middle.Permission(models.ResourceTimesheetCorrection, models.ActionRead), tcs.controller.GetByID)
+		routes.DELETE("/:id", tcs.middle.Permission(models.ResourceTimesheetCorrection, models.ActionDelete), tcs.pending, tcs.controller.Delete)
+		routes.POST("/:id/approve", tcs.middle.Permission(models.ResourceTimesheetCorrection, models.ActionUpdate), tcs.Approve)
+		routes.POST("/:id/reject", tcs.middle.Permission(models.ResourceTimesheetCorrection, models.ActionUpdate), tcs.Reject)
+	}
+}
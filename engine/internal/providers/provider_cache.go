@@ -23,6 +23,8 @@ const (
 	cacheMissErrString = "cache miss: key does not exist"
 )
 
+var ErrCacheMiss = errors.New(cacheMissErrString)
+
 func NewCacheProvider(
 	cfg *config.AppConfig,
 	logger *LoggerService,
@@ -120,7 +122,7 @@ func (cs *CacheService) Get(key string) (string, error) {
 	if err == redis.Nil {
 		cs.logger.Debug("Cache miss",
 			zap.String("key", key))
-		return "", errors.New(cacheMissErrString)
+		return "", ErrCacheMiss
 	} else if err != nil {
 		cs.logger.Error("Failed to get key from cache",
 			zap.String("key", key),
@@ -140,6 +142,40 @@ func (cs *CacheService) Increment(key string) (int64, error) {
 	return val, err
 }
 
+// IncrementWithExpiration increments key, starting it with the given
+// expiration when it did not exist, so that the count resets once it expires.
+func (cs *CacheService) IncrementWithExpiration(key string, expiration time.Duration) (int64, error) {
+	val, err := cs.Increment(key)
+	if err != nil {
+		return val, err
+	}
+	if val == 1 {
+		if err := cs.Client.Expire(key, expiration).Err(); err != nil {
+			cs.logger.Error("Failed to set expiration of key in cache",
+				zap.String("key", key),
+				zap.Error(err))
+			return val, err
+		}
+	}
+	return val, nil
+}
+
+// TTL returns the time left before key expires, or zero when it does not
+// exist or does not expire.
+func (cs *CacheService) TTL(key string) (time.Duration, error) {
+	val, err := cs.Client.TTL(key).Result()
+	if err != nil {
+		cs.logger.Error("Failed to get expiration of key from cache",
+			zap.String("key", key),
+			zap.Error(err))
+		return 0, err
+	}
+	if val < 0 {
+		return 0, nil
+	}
+	return val, nil
+}
+
 func (cs *CacheService) Decrement(key string) (int64, error) {
 	val, err := cs.Client.Decr(key).Result()
 	if err != nil {
@@ -0,0 +1,450 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/config"
+	"github.com/golang-jwt/jwt"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const (
+	// keyReloadInterval throttles reloading the keys directory when a token
+	// is signed with an unknown key, e.g. one rotated by another instance.
+	keyReloadInterval = time.Minute
+	// rsaKeySize is the size of generated RSA keys.
+	rsaKeySize = 2048
+)
+
+// SigningKey is a key used to sign tokens. Its ID, the "kid" header of the
+// tokens it signs, is the RFC 7638 thumbprint of the public key. Keys loaded
+// without their private part only verify tokens.
+type SigningKey struct {
+	ID        string
+	Algorithm string
+	Private   crypto.Signer
+	Public    crypto.PublicKey
+	CreatedAt time.Time
+
+	// path is the file the key was loaded from, empty for configured keys
+	path string
+}
+
+// JWK is the JSON Web Key representation of the public part of a SigningKey.
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+func newSigningKey(public crypto.PublicKey, private crypto.Signer, createdAt time.Time) (*SigningKey, error) {
+	key := &SigningKey{Public: public, Private: private, CreatedAt: createdAt}
+	switch public := public.(type) {
+	case ed25519.PublicKey:
+		key.Algorithm = jwt.SigningMethodEdDSA.Alg()
+	case *rsa.PublicKey:
+		if public.N.BitLen() < rsaKeySize {
+			return nil, fmt.Errorf("RSA keys must be at least %d bits", rsaKeySize)
+		}
+		key.Algorithm = jwt.SigningMethodRS256.Alg()
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", public)
+	}
+	jwk := key.JWK()
+	var thumbprint string
+	if jwk.Kty == "OKP" {
+		thumbprint = fmt.Sprintf(`{"crv":"%s","kty":"%s","x":"%s"}`, jwk.Crv, jwk.Kty, jwk.X)
+	} else {
+		thumbprint = fmt.Sprintf(`{"e":"%s","kty":"%s","n":"%s"}`, jwk.E, jwk.Kty, jwk.N)
+	}
+	sum := sha256.Sum256([]byte(thumbprint))
+	key.ID = base64.RawURLEncoding.EncodeToString(sum[:])
+	return key, nil
+}
+
+// Method returns the JWT signing method of the key.
+func (k *SigningKey) Method() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Algorithm)
+}
+
+func (k *SigningKey) JWK() JWK {
+	jwk := JWK{Kid: k.ID, Alg: k.Algorithm, Use: "sig"}
+	switch public := k.Public.(type) {
+	case ed25519.PublicKey:
+		jwk.Kty, jwk.Crv = "OKP", "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	}
+	return jwk
+}
+
+// parseSigningKeys parses every PEM block of data: PKCS #8 or PKCS #1 private
+// keys and PKIX public keys. Literal "\n" sequences are accepted as newlines
+// so keys fit in a single environment variable.
+func parseSigningKeys(data []byte, createdAt time.Time) ([]*SigningKey, error) {
+	data = bytes.ReplaceAll(data, []byte(`\n`), []byte("\n"))
+	var keys []*SigningKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		var public crypto.PublicKey
+		var private crypto.Signer
+		switch block.Type {
+		case "PRIVATE KEY":
+			parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			signer, ok := parsed.(crypto.Signer)
+			if !ok {
+				return nil, fmt.Errorf("unsupported private key type %T", parsed)
+			}
+			private, public = signer, signer.Public()
+		case "RSA PRIVATE KEY":
+			parsed, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			private, public = parsed, parsed.Public()
+		case "PUBLIC KEY":
+			parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			public = parsed
+		default:
+			return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+		}
+		key, err := newSigningKey(public, private, createdAt)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// KeyService holds the keys that sign and verify tokens. The signing key is
+// JWT_SIGNING_KEY when set; otherwise it is the newest private key of
+// JWT_KEYS_DIR, replaced by a new one every JWT_KEY_ROTATION. Earlier keys
+// keep verifying tokens until they are pruned, two rotations later. Public
+// keys in JWT_VERIFICATION_KEYS and *.pub.pem files of the directory are
+// only used for verification.
+type KeyService struct {
+	cfg    *config.AppConfig
+	logger *LoggerService
+
+	// diskMu serializes loading and rotating keys
+	diskMu sync.Mutex
+
+	mu         sync.RWMutex
+	keys       map[string]*SigningKey
+	signing    *SigningKey
+	configured bool
+	loadedAt   time.Time
+}
+
+// NewKeyProvider loads the token keys, generating the first signing key when
+// there is none, and schedules their rotation.
+func NewKeyProvider(
+	lc fx.Lifecycle,
+	cfg *config.AppConfig,
+	logger *LoggerService,
+) (*KeyService, error) {
+	s := &KeyService{
+		cfg:    cfg,
+		logger: logger,
+		keys:   map[string]*SigningKey{},
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	if s.signing == nil {
+		if err := s.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.JWTKeyRotation > 0 && !s.configured {
+		ctx, cancel := context.WithCancel(context.Background())
+		lc.Append(fx.Hook{
+			OnStart: func(context.Context) error {
+				go s.rotateLoop(ctx)
+				return nil
+			},
+			OnStop: func(context.Context) error {
+				cancel()
+				return nil
+			},
+		})
+	}
+	return s, nil
+}
+
+// Signing returns the key new tokens are signed with.
+func (s *KeyService) Signing() *SigningKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.signing
+}
+
+// Verification returns the key with the given ID. Unknown IDs reload the keys
+// directory, at most once per keyReloadInterval.
+func (s *KeyService) Verification(kid string) (*SigningKey, bool) {
+	s.mu.RLock()
+	key, ok := s.keys[kid]
+	stale := time.Since(s.loadedAt) > keyReloadInterval
+	s.mu.RUnlock()
+	if ok || !stale || s.cfg.JWTKeysDir == "" {
+		return key, ok
+	}
+	if err := s.load(); err != nil {
+		s.logger.Error("Failed to reload token keys", zap.Error(err))
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok = s.keys[kid]
+	return key, ok
+}
+
+// JWKS returns the public keys that verify tokens, newest first.
+func (s *KeyService) JWKS() JWKSet {
+	s.mu.RLock()
+	keys := make([]*SigningKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		return newerKey(keys[i], keys[j])
+	})
+	set := JWKSet{Keys: make([]JWK, 0, len(keys))}
+	for _, key := range keys {
+		set.Keys = append(set.Keys, key.JWK())
+	}
+	return set
+}
+
+// Rotate generates a new signing key with JWT_ALGORITHM, stores it in the keys
+// directory and prunes the keys that no longer sign or verify tokens.
+func (s *KeyService) Rotate() error {
+	s.diskMu.Lock()
+	defer s.diskMu.Unlock()
+
+	var private crypto.Signer
+	var err error
+	switch s.cfg.JWTAlgorithm {
+	case jwt.SigningMethodEdDSA.Alg():
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	case jwt.SigningMethodRS256.Alg():
+		private, err = rsa.GenerateKey(rand.Reader, rsaKeySize)
+	default:
+		return fmt.Errorf("unsupported JWT_ALGORITHM %q, use EdDSA or RS256", s.cfg.JWTAlgorithm)
+	}
+	if err != nil {
+		return fmt.Errorf("error generating signing key: %w", err)
+	}
+	key, err := newSigningKey(private.Public(), private, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if s.cfg.JWTKeysDir == "" {
+		s.logger.Warn("JWT_KEYS_DIR is not set, tokens will not survive a restart")
+	} else {
+		der, err := x509.MarshalPKCS8PrivateKey(private)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(s.cfg.JWTKeysDir, 0o700); err != nil {
+			return fmt.Errorf("error creating keys directory: %w", err)
+		}
+		key.path = filepath.Join(s.cfg.JWTKeysDir, key.ID+".pem")
+		if err := os.WriteFile(key.path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+			return fmt.Errorf("error storing signing key: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	s.keys[key.ID] = key
+	s.signing = key
+	s.mu.Unlock()
+	s.logger.Info("Token signing key rotated", zap.String("kid", key.ID))
+
+	s.prune()
+	return nil
+}
+
+// load reads the configured keys and the keys directory.
+func (s *KeyService) load() error {
+	s.diskMu.Lock()
+	defer s.diskMu.Unlock()
+
+	keys := map[string]*SigningKey{}
+	var signing *SigningKey
+	configured := false
+
+	if len(s.cfg.JWTSigningKey) > 0 {
+		parsed, err := parseSigningKeys(s.cfg.JWTSigningKey, time.Time{})
+		if err != nil {
+			return fmt.Errorf("invalid JWT_SIGNING_KEY: %w", err)
+		}
+		if len(parsed) != 1 || parsed[0].Private == nil {
+			return errors.New("invalid JWT_SIGNING_KEY: expected a single private key")
+		}
+		signing, configured = parsed[0], true
+		keys[signing.ID] = signing
+	}
+	if len(s.cfg.JWTVerificationKeys) > 0 {
+		parsed, err := parseSigningKeys(s.cfg.JWTVerificationKeys, time.Time{})
+		if err != nil {
+			return fmt.Errorf("invalid JWT_VERIFICATION_KEYS: %w", err)
+		}
+		for _, key := range parsed {
+			key.Private = nil
+			keys[key.ID] = key
+		}
+	}
+
+	if s.cfg.JWTKeysDir != "" {
+		entries, err := os.ReadDir(s.cfg.JWTKeysDir)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("error reading keys directory: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".pem") {
+				continue
+			}
+			path := filepath.Join(s.cfg.JWTKeysDir, entry.Name())
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			parsed, err := parseSigningKeys(data, info.ModTime())
+			if err != nil {
+				return fmt.Errorf("invalid key %s: %w", path, err)
+			}
+			for _, key := range parsed {
+				key.path = path
+				if strings.HasSuffix(entry.Name(), ".pub.pem") {
+					key.Private = nil
+				}
+				if _, exists := keys[key.ID]; !exists {
+					keys[key.ID] = key
+				}
+				if !configured && key.Private != nil && newerKey(key, signing) {
+					signing = key
+				}
+			}
+		}
+	}
+
+	s.mu.Lock()
+	s.keys, s.signing, s.configured, s.loadedAt = keys, signing, configured, time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+// newerKey reports whether key was created after current, breaking ties by ID
+// so that every instance sharing the keys directory picks the same key.
+func newerKey(key, current *SigningKey) bool {
+	if current == nil {
+		return true
+	}
+	if key.CreatedAt.Equal(current.CreatedAt) {
+		return key.ID > current.ID
+	}
+	return key.CreatedAt.After(current.CreatedAt)
+}
+
+// prune removes the generated keys that stopped signing tokens more than one
+// rotation ago, by then every token they signed has expired.
+func (s *KeyService) prune() {
+	rotation := s.cfg.JWTKeyRotation
+	if rotation <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, key := range s.keys {
+		if key == s.signing || key.Private == nil || key.path == "" || time.Since(key.CreatedAt) < 2*rotation {
+			continue
+		}
+		if err := os.Remove(key.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			s.logger.Error("Failed to remove retired signing key", zap.String("kid", id), zap.Error(err))
+			continue
+		}
+		delete(s.keys, id)
+		s.logger.Info("Retired signing key removed", zap.String("kid", id))
+	}
+}
+
+// rotateLoop rotates the signing key once it is older than JWT_KEY_ROTATION,
+// reloading the keys directory first to pick up keys rotated by other
+// instances.
+func (s *KeyService) rotateLoop(ctx context.Context) {
+	interval := time.Hour
+	if s.cfg.JWTKeyRotation < 2*interval {
+		interval = s.cfg.JWTKeyRotation / 2
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if s.cfg.JWTKeysDir != "" {
+				if err := s.load(); err != nil {
+					s.logger.Error("Failed to reload token keys", zap.Error(err))
+					continue
+				}
+			}
+			if time.Since(s.Signing().CreatedAt) < s.cfg.JWTKeyRotation {
+				continue
+			}
+			if err := s.Rotate(); err != nil {
+				s.logger.Error("Failed to rotate token signing key", zap.Error(err))
+			}
+		}
+	}
+}
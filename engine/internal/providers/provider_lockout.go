@@ -0,0 +1,133 @@
+package providers
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// LockoutPolicy bounds the failed attempts of one kind. The first
+// FreeAttempts failures are not delayed, every further failure doubles the
+// wait before the next attempt, and MaxAttempts failures within Window lock
+// the subject out for Duration.
+type LockoutPolicy struct {
+	Scope        string
+	MaxAttempts  int64
+	FreeAttempts int64
+	Window       time.Duration
+	Duration     time.Duration
+}
+
+var (
+	// SignInLockout counts wrong passwords and two-factor codes per account.
+	SignInLockout = LockoutPolicy{
+		Scope:        "signin",
+		MaxAttempts:  10,
+		FreeAttempts: 3,
+		Window:       time.Minute * 15,
+		Duration:     time.Minute * 15,
+	}
+	// OTPLockout counts wrong OTPs per account and medium. Sending a new OTP
+	// does not reset it, so that requesting codes does not buy more guesses.
+	OTPLockout = LockoutPolicy{
+		Scope:        "otp",
+		MaxAttempts:  5,
+		FreeAttempts: 2,
+		Window:       otpExpiration,
+		Duration:     time.Minute * 15,
+	}
+)
+
+const (
+	lockoutBaseDelay = time.Second
+	lockoutMaxDelay  = time.Second * 30
+)
+
+// LockoutError is returned while a subject has to wait before its next
+// attempt. Locked is set when the wait is a lockout rather than a delay.
+type LockoutError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LockoutError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("too many failed attempts, locked for %s", e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("too many failed attempts, try again in %s", e.RetryAfter.Round(time.Second))
+}
+
+// LockoutService keeps the failed attempt counters in the cache.
+type LockoutService struct {
+	logger *LoggerService
+	cache  *CacheService
+}
+
+func NewLockoutProvider(
+	logger *LoggerService,
+	cache *CacheService,
+) *LockoutService {
+	return &LockoutService{
+		logger: logger,
+		cache:  cache,
+	}
+}
+
+func (ls *LockoutService) key(policy LockoutPolicy, subject, kind string) string {
+	return fmt.Sprintf("lockout:%s:%s:%s", policy.Scope, subject, kind)
+}
+
+// Check returns a LockoutError when subject is locked out or has to wait
+// before its next attempt.
+func (ls *LockoutService) Check(policy LockoutPolicy, subject string) error {
+	for _, kind := range []string{"locked", "delay"} {
+		wait, err := ls.cache.TTL(ls.key(policy, subject, kind))
+		if err != nil {
+			return err
+		}
+		if wait > 0 {
+			return &LockoutError{RetryAfter: wait, Locked: kind == "locked"}
+		}
+	}
+	return nil
+}
+
+// Fail records a failed attempt of subject. It returns the LockoutError the
+// next attempt will get, if any; Locked is only set by the attempt that
+// locks the subject out, so that it is reported once.
+func (ls *LockoutService) Fail(policy LockoutPolicy, subject string) (*LockoutError, error) {
+	failures, err := ls.cache.IncrementWithExpiration(ls.key(policy, subject, "failures"), policy.Window)
+	if err != nil {
+		return nil, err
+	}
+	if failures >= policy.MaxAttempts {
+		if err := ls.cache.Set(ls.key(policy, subject, "locked"), failures, policy.Duration); err != nil {
+			return nil, err
+		}
+		_ = ls.cache.Delete(ls.key(policy, subject, "failures"))
+		ls.logger.Warn("Locked out after failed attempts",
+			zap.String("scope", policy.Scope),
+			zap.String("subject", subject),
+			zap.Int64("failures", failures))
+		return &LockoutError{RetryAfter: policy.Duration, Locked: failures == policy.MaxAttempts}, nil
+	}
+	if failures <= policy.FreeAttempts {
+		return nil, nil
+	}
+	delay := lockoutMaxDelay
+	if shift := failures - policy.FreeAttempts - 1; shift < 5 {
+		delay = min(lockoutBaseDelay<<shift, lockoutMaxDelay)
+	}
+	if err := ls.cache.Set(ls.key(policy, subject, "delay"), failures, delay); err != nil {
+		return nil, err
+	}
+	return &LockoutError{RetryAfter: delay}, nil
+}
+
+// Reset clears the failed attempts and any lockout of subject.
+func (ls *LockoutService) Reset(policy LockoutPolicy, subject string) {
+	for _, kind := range []string{"failures", "delay", "locked"} {
+		_ = ls.cache.Delete(ls.key(policy, subject, kind))
+	}
+}
@@ -12,7 +12,11 @@ var Module = fx.Module(
 		NewStorageProvider,
 		NewSMSProvider,
 		NewEngineProvider,
+		NewLockoutProvider,
+		NewPasswordPolicyProvider,
+		NewOIDCProvider,
 		NewOTPProvider,
+		NewKeyProvider,
 		NewTokenProvider,
 	),
 )
@@ -0,0 +1,522 @@
+package providers
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/config"
+	"github.com/golang-jwt/jwt"
+	"go.uber.org/zap"
+)
+
+const (
+	// OIDCStateExpiration bounds the time a user has to sign in at the
+	// identity provider.
+	OIDCStateExpiration = time.Minute * 10
+	// oidcMetadataExpiration is how long the discovery document of a provider
+	// is cached.
+	oidcMetadataExpiration = time.Hour
+	// oidcKeysReloadInterval throttles reloading the keys of a provider when
+	// an ID token is signed with an unknown key.
+	oidcKeysReloadInterval = time.Minute
+	// oidcClockSkew is the leeway given to the time claims of ID tokens.
+	oidcClockSkew = time.Minute
+)
+
+var (
+	ErrOIDCProviderNotFound = errors.New("identity provider not found")
+	ErrOIDCState            = errors.New("invalid or expired sign-in state")
+)
+
+// OIDCClaims is the identity an ID token asserts, along with the provider and
+// account type the sign-in was started for.
+type OIDCClaims struct {
+	Provider      *config.OIDCProvider
+	AccountType   string
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// oidcMetadata is the part of the discovery document of a provider that the
+// authorization code flow uses, along with its signing keys.
+type oidcMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+
+	fetchedAt     time.Time
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+// oidcState is kept in the cache between the redirect to the provider and
+// the callback.
+type oidcState struct {
+	Provider    string `json:"provider"`
+	AccountType string `json:"accountType"`
+	Verifier    string `json:"verifier"`
+	Nonce       string `json:"nonce"`
+	// Binding is the hash of the secret kept by the browser that started
+	// the sign-in
+	Binding string `json:"binding"`
+}
+
+// OIDCService is the OpenID Connect relying party of the configured identity
+// providers. It implements the authorization code flow with PKCE.
+type OIDCService struct {
+	cfg    *config.AppConfig
+	logger *LoggerService
+	cache  *CacheService
+	client *http.Client
+
+	mu       sync.Mutex
+	metadata map[string]*oidcMetadata
+}
+
+func NewOIDCProvider(
+	cfg *config.AppConfig,
+	logger *LoggerService,
+	cache *CacheService,
+) *OIDCService {
+	return &OIDCService{
+		cfg:      cfg,
+		logger:   logger,
+		cache:    cache,
+		client:   &http.Client{Timeout: time.Second * 10},
+		metadata: map[string]*oidcMetadata{},
+	}
+}
+
+// Providers returns the configured identity providers.
+func (os *OIDCService) Providers() []config.OIDCProvider {
+	return os.cfg.OIDCProviders
+}
+
+// Provider returns the identity provider named name.
+func (os *OIDCService) Provider(name string) (*config.OIDCProvider, error) {
+	for i := range os.cfg.OIDCProviders {
+		if os.cfg.OIDCProviders[i].Name == name {
+			return &os.cfg.OIDCProviders[i], nil
+		}
+	}
+	return nil, ErrOIDCProviderNotFound
+}
+
+// AuthorizationURL starts a sign-in of accountType at the provider named name
+// and returns the URL to send the user to, along with a secret binding the
+// sign-in to the browser that started it. The browser has to keep the secret,
+// in a cookie, and present it to Exchange.
+func (os *OIDCService) AuthorizationURL(ctx context.Context, name, accountType string) (string, string, error) {
+	provider, err := os.Provider(name)
+	if err != nil {
+		return "", "", err
+	}
+	allowed := false
+	for _, providerAccountType := range provider.AccountTypes {
+		allowed = allowed || providerAccountType == accountType
+	}
+	if !allowed {
+		return "", "", fmt.Errorf("%s accounts cannot sign in with %s", accountType, name)
+	}
+	metadata, err := os.discover(ctx, provider)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := oidcRandom()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := oidcRandom()
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := oidcRandom()
+	if err != nil {
+		return "", "", err
+	}
+	binding, err := oidcRandom()
+	if err != nil {
+		return "", "", err
+	}
+	stored, err := json.Marshal(oidcState{
+		Provider:    name,
+		AccountType: accountType,
+		Verifier:    verifier,
+		Nonce:       nonce,
+		Binding:     oidcBindingHash(binding),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if err := os.cache.Set(oidcStateKey(state), stored, OIDCStateExpiration); err != nil {
+		return "", "", err
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+	scopes := append([]string{"openid", "email", "profile"}, provider.Scopes...)
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {provider.ClientID},
+		"redirect_uri":          {os.cfg.OIDCRedirectURL},
+		"scope":                 {strings.Join(scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return metadata.AuthorizationEndpoint + separator + query.Encode(), binding, nil
+}
+
+// Exchange completes the sign-in of state by redeeming code at the provider
+// and validating the ID token it returns. Only the browser holding the
+// binding AuthorizationURL returned may redeem the state, and only once.
+func (os *OIDCService) Exchange(ctx context.Context, state, binding, code string) (*OIDCClaims, error) {
+	stored, err := os.cache.Get(oidcStateKey(state))
+	if errors.Is(err, ErrCacheMiss) {
+		return nil, ErrOIDCState
+	}
+	if err != nil {
+		return nil, err
+	}
+	var signIn oidcState
+	if err := json.Unmarshal([]byte(stored), &signIn); err != nil {
+		return nil, ErrOIDCState
+	}
+	// A state posted from another browser is left for the one that started it
+	expected := oidcBindingHash(binding)
+	if binding == "" || subtle.ConstantTimeCompare([]byte(signIn.Binding), []byte(expected)) != 1 {
+		return nil, ErrOIDCState
+	}
+	deleted, err := os.cache.Client.Del(oidcStateKey(state)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if deleted != 1 {
+		return nil, ErrOIDCState
+	}
+	provider, err := os.Provider(signIn.Provider)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := os.discover(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	idToken, err := os.redeem(ctx, provider, metadata, code, signIn.Verifier)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := os.verifyIDToken(ctx, provider, metadata, idToken, signIn.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %w", err)
+	}
+	claims.AccountType = signIn.AccountType
+	return claims, nil
+}
+
+// redeem exchanges the authorization code for the ID token at the token
+// endpoint, authenticating with client_secret_basic when the client has a
+// secret.
+func (os *OIDCService) redeem(ctx context.Context, provider *config.OIDCProvider, metadata *oidcMetadata, code, verifier string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {os.cfg.OIDCRedirectURL},
+		"client_id":     {provider.ClientID},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if provider.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(provider.ClientID), url.QueryEscape(provider.ClientSecret))
+	}
+	res, err := os.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&body); err != nil {
+		return "", fmt.Errorf("token response unreadable: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request rejected: %s %s", body.Error, body.ErrorDescription)
+	}
+	if body.IDToken == "" {
+		return "", errors.New("token response has no ID token")
+	}
+	return body.IDToken, nil
+}
+
+// verifyIDToken checks the signature, issuer, audience, lifetime and nonce of
+// an ID token as OpenID Connect Core 3.1.3.7 requires.
+func (os *OIDCService) verifyIDToken(ctx context.Context, provider *config.OIDCProvider, metadata *oidcMetadata, idToken, nonce string) (*OIDCClaims, error) {
+	parser := &jwt.Parser{
+		ValidMethods:         []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"},
+		SkipClaimsValidation: true,
+	}
+	mapClaims := jwt.MapClaims{}
+	_, err := parser.ParseWithClaims(idToken, mapClaims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return os.key(ctx, provider, metadata, kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !mapClaims.VerifyIssuer(metadata.Issuer, true) {
+		return nil, errors.New("issuer mismatch")
+	}
+	if !mapClaims.VerifyAudience(provider.ClientID, true) {
+		return nil, errors.New("audience mismatch")
+	}
+	if aud, ok := mapClaims["aud"].([]interface{}); ok && len(aud) > 1 {
+		if azp, _ := mapClaims["azp"].(string); azp != provider.ClientID {
+			return nil, errors.New("authorized party mismatch")
+		}
+	}
+	if !mapClaims.VerifyExpiresAt(now.Add(-oidcClockSkew).Unix(), true) {
+		return nil, errors.New("token expired")
+	}
+	if !mapClaims.VerifyIssuedAt(now.Add(oidcClockSkew).Unix(), true) {
+		return nil, errors.New("token issued in the future")
+	}
+	if !mapClaims.VerifyNotBefore(now.Add(oidcClockSkew).Unix(), false) {
+		return nil, errors.New("token not valid yet")
+	}
+	if tokenNonce, _ := mapClaims["nonce"].(string); tokenNonce != nonce {
+		return nil, errors.New("nonce mismatch")
+	}
+
+	claims := &OIDCClaims{Provider: provider, Issuer: metadata.Issuer}
+	claims.Subject, _ = mapClaims["sub"].(string)
+	claims.Email, _ = mapClaims["email"].(string)
+	claims.Name, _ = mapClaims["name"].(string)
+	// Some providers send email_verified as a string
+	switch verified := mapClaims["email_verified"].(type) {
+	case bool:
+		claims.EmailVerified = verified
+	case string:
+		claims.EmailVerified = verified == "true"
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+	return claims, nil
+}
+
+// discover returns the cached discovery document of provider, fetching it
+// when it is missing or stale.
+func (os *OIDCService) discover(ctx context.Context, provider *config.OIDCProvider) (*oidcMetadata, error) {
+	os.mu.Lock()
+	cached := os.metadata[provider.Name]
+	os.mu.Unlock()
+	if cached != nil && time.Since(cached.fetchedAt) < oidcMetadataExpiration {
+		return cached, nil
+	}
+
+	metadata := &oidcMetadata{}
+	discoveryURL := strings.TrimSuffix(provider.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := os.getJSON(ctx, discoveryURL, metadata); err != nil {
+		if cached != nil {
+			os.logger.Warn("Using stale OpenID Connect discovery document", zap.String("provider", provider.Name), zap.Error(err))
+			return cached, nil
+		}
+		return nil, fmt.Errorf("discovery failed: %w", err)
+	}
+	if metadata.Issuer != provider.Issuer {
+		return nil, fmt.Errorf("discovery issuer %q does not match %q", metadata.Issuer, provider.Issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, errors.New("discovery document is missing endpoints")
+	}
+	metadata.fetchedAt = time.Now()
+	if cached != nil && cached.JWKSURI == metadata.JWKSURI {
+		metadata.keys, metadata.keysFetchedAt = cached.keys, cached.keysFetchedAt
+	}
+
+	os.mu.Lock()
+	os.metadata[provider.Name] = metadata
+	os.mu.Unlock()
+	return metadata, nil
+}
+
+// key returns the signing key kid of the provider. An unknown kid reloads the
+// keys, at most once per oidcKeysReloadInterval, to pick up rotated keys.
+func (os *OIDCService) key(ctx context.Context, provider *config.OIDCProvider, metadata *oidcMetadata, kid string) (crypto.PublicKey, error) {
+	os.mu.Lock()
+	keys, fetchedAt := metadata.keys, metadata.keysFetchedAt
+	os.mu.Unlock()
+	if key := oidcFindKey(keys, kid); key != nil {
+		return key, nil
+	}
+	if time.Since(fetchedAt) < oidcKeysReloadInterval {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	var set struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := os.getJSON(ctx, metadata.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("signing keys unavailable: %w", err)
+	}
+	keys = map[string]crypto.PublicKey{}
+	for _, raw := range set.Keys {
+		keyID, key, err := parseOIDCKey(raw)
+		if err != nil {
+			os.logger.Debug("Skipping signing key", zap.String("provider", provider.Name), zap.Error(err))
+			continue
+		}
+		keys[keyID] = key
+	}
+	os.mu.Lock()
+	metadata.keys, metadata.keysFetchedAt = keys, time.Now()
+	os.mu.Unlock()
+
+	if key := oidcFindKey(keys, kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// oidcFindKey returns the key kid, or the only key when the token names none.
+func oidcFindKey(keys map[string]crypto.PublicKey, kid string) crypto.PublicKey {
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key
+		}
+	}
+	return keys[kid]
+}
+
+// parseOIDCKey parses a JSON Web Key used for signatures.
+func parseOIDCKey(raw json.RawMessage) (string, crypto.PublicKey, error) {
+	var jwk struct {
+		JWK
+		Y string `json:"y"`
+	}
+	if err := json.Unmarshal(raw, &jwk); err != nil {
+		return "", nil, err
+	}
+	if jwk.Use != "" && jwk.Use != "sig" {
+		return "", nil, fmt.Errorf("key %q is not for signatures", jwk.Kid)
+	}
+	decode := base64.RawURLEncoding.DecodeString
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decode(jwk.N)
+		if err != nil {
+			return "", nil, err
+		}
+		e, err := decode(jwk.E)
+		if err != nil {
+			return "", nil, err
+		}
+		return jwk.Kid, &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return "", nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := decode(jwk.X)
+		if err != nil {
+			return "", nil, err
+		}
+		y, err := decode(jwk.Y)
+		if err != nil {
+			return "", nil, err
+		}
+		return jwk.Kid, &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "OKP":
+		x, err := decode(jwk.X)
+		if err != nil {
+			return "", nil, err
+		}
+		if jwk.Crv != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return "", nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		return jwk.Kid, ed25519.PublicKey(x), nil
+	}
+	return "", nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+}
+
+func (os *OIDCService) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := os.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded with %s", url, res.Status)
+	}
+	return json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(v)
+}
+
+func oidcStateKey(state string) string {
+	return "oidc_state:" + state
+}
+
+func oidcBindingHash(binding string) string {
+	sum := sha256.Sum256([]byte(binding))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// oidcRandom returns 32 random bytes encoded as URL-safe base64, suitable for
+// states, nonces and PKCE code verifiers.
+func oidcRandom() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
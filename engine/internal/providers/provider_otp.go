@@ -10,6 +10,17 @@ import (
 	"go.uber.org/zap"
 )
 
+// otpExpiration is how long a sent OTP stays valid.
+const otpExpiration = 10 * time.Minute
+
+// Mediums of the OTPs that sign users in without a password. They are kept
+// apart from the "email" and "sms" OTPs that verify the email and contact
+// number of an account.
+const (
+	OTPMediumSignInEmail = "signin-email"
+	OTPMediumSignInSMS   = "signin-sms"
+)
+
 // OTPService handles OTP generation, storage, validation, and sending via email or SMS.
 type OTPService struct {
 	cfg          *config.AppConfig
@@ -18,6 +29,7 @@ type OTPService struct {
 	mail         *EmailService
 	sms          *SMSService
 	cacheService *CacheService
+	lockout      *LockoutService
 }
 
 // NewOTPProvider initializes a new instance of OTPService.
@@ -28,6 +40,7 @@ func NewOTPProvider(
 	cacheService *CacheService,
 	mail *EmailService,
 	sms *SMSService,
+	lockout *LockoutService,
 ) *OTPService {
 	return &OTPService{
 		cfg:          cfg,
@@ -36,6 +49,7 @@ func NewOTPProvider(
 		mail:         mail,
 		sms:          sms,
 		cacheService: cacheService,
+		lockout:      lockout,
 	}
 }
 
@@ -46,7 +60,12 @@ func (os *OTPService) cacheKey(accountType string, id uint, mediumType string) s
 
 // SendEmailOTP generates an OTP, stores it, and sends it via email.
 func (os *OTPService) SendEmailOTP(accountType string, id uint, req EmailRequest) error {
-	otp, err := os.generateAndStoreOTP(accountType, id, "email")
+	return os.SendEmailOTPFor(accountType, id, "email", req)
+}
+
+// SendEmailOTPFor is SendEmailOTP for OTPs validated with mediumType.
+func (os *OTPService) SendEmailOTPFor(accountType string, id uint, mediumType string, req EmailRequest) error {
+	otp, err := os.generateAndStoreOTP(accountType, id, mediumType)
 	if err != nil {
 		return err
 	}
@@ -67,7 +86,13 @@ func (os *OTPService) SendEmailOTP(accountType string, id uint, req EmailRequest
 
 // SendContactNumberOTP generates an OTP, stores it, and sends it via SMS.
 func (os *OTPService) SendContactNumberOTP(accountType string, id uint, req SMSRequest) error {
-	otp, err := os.generateAndStoreOTP(accountType, id, "sms")
+	return os.SendContactNumberOTPFor(accountType, id, "sms", req)
+}
+
+// SendContactNumberOTPFor is SendContactNumberOTP for OTPs validated with
+// mediumType.
+func (os *OTPService) SendContactNumberOTPFor(accountType string, id uint, mediumType string, req SMSRequest) error {
+	otp, err := os.generateAndStoreOTP(accountType, id, mediumType)
 	if err != nil {
 		return err
 	}
@@ -96,7 +121,6 @@ func (os *OTPService) generateAndStoreOTP(accountType string, id uint, mediumTyp
 
 	otpStr := fmt.Sprintf("%06d", otp)
 	key := os.cacheKey(accountType, id, mediumType)
-	expiration := 10 * time.Minute
 
 	storedOTP := otpStr
 	if os.isHashingEnabled() {
@@ -107,7 +131,7 @@ func (os *OTPService) generateAndStoreOTP(accountType string, id uint, mediumTyp
 		}
 	}
 
-	if err := os.cacheService.Set(key, storedOTP, expiration); err != nil {
+	if err := os.cacheService.Set(key, storedOTP, otpExpiration); err != nil {
 		os.logger.Error("Failed to store OTP in cache", zap.Error(err))
 		return "", err
 	}
@@ -116,16 +140,24 @@ func (os *OTPService) generateAndStoreOTP(accountType string, id uint, mediumTyp
 }
 
 // ValidateOTP validates the provided OTP against the stored OTP in the cache.
+// Wrong OTPs are counted under OTPLockout: a *LockoutError is returned while
+// the account has to wait, and by the attempt that locks it out, which also
+// discards the stored OTP.
 func (os *OTPService) ValidateOTP(accountType string, id uint, providedOTP, mediumType string) (bool, error) {
 	if providedOTP == "" {
 		os.logger.Warn("Provided OTP is empty", zap.Uint("user_id", id))
 		return false, errors.New("provided OTP is invalid: empty input")
 	}
 
+	subject := fmt.Sprintf("%s:%d:%s", accountType, id, mediumType)
+	if err := os.lockout.Check(OTPLockout, subject); err != nil {
+		return false, err
+	}
+
 	key := os.cacheKey(accountType, id, mediumType)
 	storedOTP, err := os.cacheService.Get(key)
 	if err != nil {
-		if errors.Is(err, errors.New("cache miss: key does not exist")) {
+		if errors.Is(err, ErrCacheMiss) {
 			os.logger.Warn("OTP not found or expired", zap.Uint("user_id", id))
 			return false, nil
 		}
@@ -133,19 +165,29 @@ func (os *OTPService) ValidateOTP(accountType string, id uint, providedOTP, medi
 		return false, err
 	}
 
+	matches := providedOTP == storedOTP
 	if os.isHashingEnabled() {
-		if !os.helpers.VerifyPassword(storedOTP, providedOTP) {
-			os.logger.Warn("Provided OTP does not match stored OTP", zap.Uint("user_id", id))
-			return false, nil
-		}
-	} else if providedOTP != storedOTP {
+		matches = os.helpers.VerifyPassword(storedOTP, providedOTP)
+	}
+	if !matches {
 		os.logger.Warn("Provided OTP does not match stored OTP", zap.Uint("user_id", id))
+		lockErr, err := os.lockout.Fail(OTPLockout, subject)
+		if err != nil {
+			return false, err
+		}
+		if lockErr != nil && lockErr.Locked {
+			if err := os.cacheService.Delete(key); err != nil {
+				os.logger.Error("Failed to delete OTP from cache after lockout", zap.Error(err), zap.Uint("user_id", id))
+			}
+			return false, lockErr
+		}
 		return false, nil
 	}
 
 	if err := os.cacheService.Delete(key); err != nil {
 		os.logger.Error("Failed to delete OTP from cache after validation", zap.Error(err), zap.Uint("user_id", id))
 	}
+	os.lockout.Reset(OTPLockout, subject)
 
 	return true, nil
 }
@@ -0,0 +1,200 @@
+package providers
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/config"
+	"go.uber.org/zap"
+)
+
+// breachedPrefixLength is the number of hex digits of the SHA-1 hash that
+// name a range, as in the k-anonymity API of Have I Been Pwned.
+const breachedPrefixLength = 5
+
+// ErrPasswordBreached is returned for passwords found in the breached list.
+var ErrPasswordBreached = errors.New("password appears in a list of breached passwords, choose another one")
+
+// PasswordPolicyError lists the rules of the policy a password breaks.
+type PasswordPolicyError struct {
+	Reasons []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "password does not meet the policy: " + strings.Join(e.Reasons, "; ")
+}
+
+// PasswordPolicyService checks new passwords against the configured policy
+// and the breached password list. The list is either a single file of SHA-1
+// hashes, loaded at startup and grouped by range, or a directory of range
+// files read on demand, so that only the range of a password is ever looked
+// at.
+type PasswordPolicyService struct {
+	cfg    *config.AppConfig
+	logger *LoggerService
+
+	// ranges maps the prefix of each hash of a breached list file to the
+	// sorted suffixes of the range
+	ranges map[string][]string
+	// rangeDir is the directory of range files, if the list is one
+	rangeDir string
+}
+
+func NewPasswordPolicyProvider(
+	cfg *config.AppConfig,
+	logger *LoggerService,
+) (*PasswordPolicyService, error) {
+	ps := &PasswordPolicyService{cfg: cfg, logger: logger}
+	if cfg.PasswordBreachedList == "" {
+		return ps, nil
+	}
+	info, err := os.Stat(cfg.PasswordBreachedList)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached password list: %w", err)
+	}
+	if info.IsDir() {
+		ps.rangeDir = cfg.PasswordBreachedList
+		logger.Info("Using breached password ranges", zap.String("dir", ps.rangeDir))
+		return ps, nil
+	}
+	if err := ps.loadList(cfg.PasswordBreachedList); err != nil {
+		return nil, err
+	}
+	return ps, nil
+}
+
+// loadList reads a file of SHA-1 hashes, optionally followed by ":count" as
+// in the downloads of Have I Been Pwned.
+func (ps *PasswordPolicyService) loadList(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open breached password list: %w", err)
+	}
+	defer file.Close()
+
+	ps.ranges = map[string][]string{}
+	count := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		hash := breachedHash(scanner.Text())
+		if len(hash) != sha1.Size*2 {
+			continue
+		}
+		prefix := hash[:breachedPrefixLength]
+		ps.ranges[prefix] = append(ps.ranges[prefix], hash[breachedPrefixLength:])
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read breached password list: %w", err)
+	}
+	for _, suffixes := range ps.ranges {
+		sort.Strings(suffixes)
+	}
+	ps.logger.Info("Loaded breached password list", zap.String("path", path), zap.Int("hashes", count))
+	return nil
+}
+
+// breachedHash returns the upper case hash of a line of a breached list.
+func breachedHash(line string) string {
+	hash, _, _ := strings.Cut(strings.TrimSpace(line), ":")
+	return strings.ToUpper(hash)
+}
+
+// rangeSuffixes returns the sorted suffixes of the hashes of a range.
+func (ps *PasswordPolicyService) rangeSuffixes(prefix string) ([]string, error) {
+	if ps.rangeDir == "" {
+		return ps.ranges[prefix], nil
+	}
+	for _, name := range []string{prefix, prefix + ".txt", strings.ToLower(prefix), strings.ToLower(prefix) + ".txt"} {
+		file, err := os.Open(filepath.Join(ps.rangeDir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		var suffixes []string
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			suffix := breachedHash(scanner.Text())
+			// Lines may hold the full hash or only its suffix
+			if len(suffix) == sha1.Size*2 {
+				suffix = suffix[breachedPrefixLength:]
+			}
+			suffixes = append(suffixes, suffix)
+		}
+		sort.Strings(suffixes)
+		return suffixes, scanner.Err()
+	}
+	return nil, nil
+}
+
+// Breached reports whether password is in the breached list.
+func (ps *PasswordPolicyService) Breached(password string) (bool, error) {
+	if ps.ranges == nil && ps.rangeDir == "" {
+		return false, nil
+	}
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	suffixes, err := ps.rangeSuffixes(hash[:breachedPrefixLength])
+	if err != nil {
+		return false, err
+	}
+	suffix := hash[breachedPrefixLength:]
+	i := sort.SearchStrings(suffixes, suffix)
+	return i < len(suffixes) && suffixes[i] == suffix, nil
+}
+
+// Validate returns a *PasswordPolicyError when password is too short or
+// mixes too few character classes, and ErrPasswordBreached when it is
+// breached.
+func (ps *PasswordPolicyService) Validate(password string) error {
+	var reasons []string
+	if len([]rune(password)) < ps.cfg.PasswordMinLength {
+		reasons = append(reasons, fmt.Sprintf("it must be at least %d characters long", ps.cfg.PasswordMinLength))
+	}
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	classes := 0
+	for _, present := range []bool{lower, upper, digit, symbol} {
+		if present {
+			classes++
+		}
+	}
+	if classes < ps.cfg.PasswordMinClasses {
+		reasons = append(reasons, fmt.Sprintf("it must mix at least %d of lowercase letters, uppercase letters, digits and symbols", ps.cfg.PasswordMinClasses))
+	}
+	if len(reasons) > 0 {
+		return &PasswordPolicyError{Reasons: reasons}
+	}
+
+	breached, err := ps.Breached(password)
+	if err != nil {
+		ps.logger.Error("Failed to check breached password list", zap.Error(err))
+		return nil
+	}
+	if breached {
+		return ErrPasswordBreached
+	}
+	return nil
+}
@@ -15,12 +15,19 @@ import (
 type UserClaims struct {
 	ID          uint   `json:"id"`
 	AccountType string `json:"accountType"`
+	// Impersonator is the ID of the Admin acting as the user, set on the
+	// tokens of an impersonation only
+	Impersonator *uint `json:"impersonator,omitempty"`
+	// Purpose is set on tokens that do not sign in, like password reset
+	// tokens, and names the only use they are good for
+	Purpose string `json:"purpose,omitempty"`
 	jwt.StandardClaims
 }
 
 type TokenService struct {
 	cfg    *config.AppConfig
 	cache  *CacheService
+	keys   *KeyService
 	logger *LoggerService
 }
 
@@ -28,11 +35,13 @@ type TokenService struct {
 func NewTokenProvider(
 	cfg *config.AppConfig,
 	cache *CacheService,
+	keys *KeyService,
 	logger *LoggerService,
 ) *TokenService {
 	return &TokenService{
 		cfg:    cfg,
 		cache:  cache,
+		keys:   keys,
 		logger: logger,
 	}
 }
@@ -43,15 +52,20 @@ func (s *TokenService) GenerateToken(claims *UserClaims, expiration time.Duratio
 		expiration = 12 * time.Hour
 	}
 
-	// Set registered claims
+	// Set registered claims, keeping the JTI of session tokens
+	now := time.Now()
 	claims.StandardClaims = jwt.StandardClaims{
-		Issuer:   "horizon-server",
-		Subject:  fmt.Sprintf("%d", claims.ID),
-		IssuedAt: time.Now().Unix(),
+		Id:        claims.Id,
+		Issuer:    "horizon-server",
+		Subject:   fmt.Sprintf("%d", claims.ID),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(expiration).Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(s.cfg.AppToken))
+	key := s.keys.Signing()
+	token := jwt.NewWithClaims(key.Method(), claims)
+	token.Header["kid"] = key.ID
+	tokenString, err := token.SignedString(key.Private)
 	if err != nil {
 		s.logger.Error("Error signing token", zap.Error(err))
 		return "", fmt.Errorf("error signing token: %w", err)
@@ -71,11 +85,17 @@ func (s *TokenService) VerifyToken(tokenString string) (*UserClaims, error) {
 	}
 
 	token, err := jwt.ParseWithClaims(tokenString, &UserClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			s.logger.Warn("Unexpected signing method", zap.String("method", token.Header["alg"].(string)))
+		kid, _ := token.Header["kid"].(string)
+		key, ok := s.keys.Verification(kid)
+		if !ok {
+			s.logger.Warn("Unknown signing key", zap.String("kid", kid))
+			return nil, fmt.Errorf("unknown signing key: %q", kid)
+		}
+		if token.Method.Alg() != key.Algorithm {
+			s.logger.Warn("Unexpected signing method", zap.String("method", token.Method.Alg()))
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(s.cfg.AppToken), nil
+		return key.Public, nil
 	})
 
 	if err != nil {
@@ -105,15 +125,42 @@ func (s *TokenService) VerifyToken(tokenString string) (*UserClaims, error) {
 	return claims, nil
 }
 
-// StoreToken stores a token-related value in the cache.
-// Consider storing JTI (unique token ID) rather than the entire token string.
-func (s *TokenService) StoreToken(tokenString string, userId uint) error {
-	expiration := 24 * time.Hour
-	if err := s.cache.Set(tokenString, userId, expiration); err != nil {
-		s.logger.Error("Error storing token in Redis", zap.Error(err))
-		return fmt.Errorf("error storing token in Redis: %w", err)
+// JWKS returns the public keys that verify the tokens, for other services.
+func (s *TokenService) JWKS() JWKSet {
+	return s.keys.JWKS()
+}
+
+func sessionKey(jti string) string {
+	return "session:" + jti
+}
+
+// ActivateSession lets access tokens of the session through SessionActive
+// until expiration. The session table remains the source of truth; the cache
+// only spares a query on every request.
+func (s *TokenService) ActivateSession(jti string, expiration time.Duration) error {
+	if err := s.cache.Set(sessionKey(jti), 1, expiration); err != nil {
+		s.logger.Error("Error storing session in Redis", zap.Error(err))
+		return fmt.Errorf("error storing session in Redis: %w", err)
+	}
+	return nil
+}
+
+// SessionActive reports whether the session was activated and not revoked
+// since.
+func (s *TokenService) SessionActive(jti string) bool {
+	if jti == "" {
+		return false
+	}
+	exists, err := s.cache.Exists(sessionKey(jti))
+	return err == nil && exists
+}
+
+// RevokeSession stops the access tokens of the session from being accepted.
+func (s *TokenService) RevokeSession(jti string) error {
+	if err := s.cache.Delete(sessionKey(jti)); err != nil {
+		s.logger.Error("Error deleting session from Redis", zap.Error(err))
+		return fmt.Errorf("error deleting session from Redis: %w", err)
 	}
-	s.logger.Info("Token reference stored successfully in Redis", zap.Uint("userId", userId))
 	return nil
 }
 
@@ -126,14 +173,44 @@ func (s *TokenService) DeleteToken(tokenString string) error {
 	return nil
 }
 
-func (s *TokenService) ClearTokenCookie(ctx *gin.Context) {
-	cookie, err := ctx.Cookie(s.cfg.AppTokenName)
-	if err == nil && cookie != "" {
-		if delErr := s.DeleteToken(cookie); delErr != nil {
-			s.logger.Error("Failed to delete token from Redis", zap.Error(delErr))
-		}
-	}
+// RefreshTokenName is the cookie holding the refresh token. It is only sent
+// to the auth routes, where sessions are refreshed and ended.
+func (s *TokenService) RefreshTokenName() string {
+	return s.cfg.AppTokenName + "_refresh"
+}
+
+const refreshTokenPath = "/api/v1/auth"
+
+// SetTokenCookies sets the access and refresh token cookies of a session.
+func (s *TokenService) SetTokenCookies(ctx *gin.Context, accessToken, refreshToken string, refreshExpiration time.Duration) {
+	s.SetAccessTokenCookie(ctx, accessToken)
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     s.RefreshTokenName(),
+		Value:    refreshToken,
+		Path:     refreshTokenPath,
+		HttpOnly: true,
+		Secure:   true,
+		MaxAge:   int(refreshExpiration.Seconds()),
+		SameSite: http.SameSiteNoneMode,
+	})
+}
+
+// SetAccessTokenCookie sets the access token cookie alone, leaving the refresh
+// token cookie of the session as it is.
+func (s *TokenService) SetAccessTokenCookie(ctx *gin.Context, accessToken string) {
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     s.cfg.AppTokenName,
+		Value:    accessToken,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteNoneMode,
+	})
+}
 
+// ClearTokenCookie removes the access token cookie. The refresh token cookie
+// is kept so the client can still refresh an expired access token.
+func (s *TokenService) ClearTokenCookie(ctx *gin.Context) {
 	http.SetCookie(ctx.Writer, &http.Cookie{
 		Name:     s.cfg.AppTokenName,
 		Value:    "",
@@ -145,3 +222,18 @@ func (s *TokenService) ClearTokenCookie(ctx *gin.Context) {
 		SameSite: http.SameSiteNoneMode,
 	})
 }
+
+// ClearSessionCookies removes both the access and the refresh token cookies.
+func (s *TokenService) ClearSessionCookies(ctx *gin.Context) {
+	s.ClearTokenCookie(ctx)
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     s.RefreshTokenName(),
+		Value:    "",
+		Path:     refreshTokenPath,
+		HttpOnly: true,
+		Secure:   true,
+		Expires:  time.Unix(0, 0),
+		MaxAge:   -1,
+		SameSite: http.SameSiteNoneMode,
+	})
+}
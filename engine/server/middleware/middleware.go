@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/config"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/helpers"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
 )
 
@@ -17,6 +19,8 @@ type Middleware struct {
 	logger        *providers.LoggerService
 	cache         *providers.CacheService
 	tokenProvider *providers.TokenService
+	models        *models.ModelResource
+	cryptoHelpers *helpers.HelpersCryptography
 	suspicious    []string
 }
 
@@ -25,6 +29,8 @@ func NewMiddleware(
 	logger *providers.LoggerService,
 	cache *providers.CacheService,
 	tokenProvider *providers.TokenService,
+	models *models.ModelResource,
+	cryptoHelpers *helpers.HelpersCryptography,
 ) *Middleware {
 	return &Middleware{
 		cfg:           cfg,
@@ -32,5 +38,7 @@ func NewMiddleware(
 		cache:         cache,
 		suspicious:    suspicious,
 		tokenProvider: tokenProvider,
+		models:        models,
+		cryptoHelpers: cryptoHelpers,
 	}
 }
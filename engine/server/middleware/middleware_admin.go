@@ -27,12 +27,19 @@ func (m *Middleware) AuthMiddlewareAdminOnly() gin.HandlerFunc {
 			ctx.Abort()
 			return
 		}
+		if !m.verifySession(ctx, claims) {
+			m.tokenProvider.ClearTokenCookie(ctx)
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: session expired or revoked"})
+			ctx.Abort()
+			return
+		}
 		if claims.AccountType != "Admin" {
 			m.tokenProvider.ClearTokenCookie(ctx)
 			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: token verification failed"})
 			ctx.Abort()
 		}
 		ctx.Set("claims", claims)
+		m.setAuditActor(ctx, claims)
 		ctx.Next()
 	}
 }
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// RequestIDHeader carries the request ID, taken from the client when it
+	// sends a reasonable one and generated otherwise.
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDKey is the context key holding the request ID.
+	RequestIDKey = "requestID"
+)
+
+// AuditContext assigns a request ID and stores it with the client IP as the
+// audit actor of the request context. AuthMiddleware completes the actor with
+// the authenticated user.
+func (m *Middleware) AuditContext() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 64 {
+			requestID = uuid.NewString()
+		}
+		ctx.Set(RequestIDKey, requestID)
+		ctx.Header(RequestIDHeader, requestID)
+
+		actor := managers.AuditActor{RequestID: requestID, IP: m.getClientIP(ctx)}
+		ctx.Request = ctx.Request.WithContext(managers.WithAuditActor(ctx.Request.Context(), actor))
+		ctx.Next()
+	}
+}
+
+// setAuditActor attributes the changes made by the request to the user, and
+// to the Admin impersonating them if any.
+func (m *Middleware) setAuditActor(ctx *gin.Context, claims *providers.UserClaims) {
+	actor, _ := managers.AuditActorFromContext(ctx.Request.Context())
+	id := claims.ID
+	actor.AccountType = claims.AccountType
+	actor.ID = &id
+	actor.ImpersonatorID = claims.Impersonator
+	ctx.Request = ctx.Request.WithContext(managers.WithAuditActor(ctx.Request.Context(), actor))
+}
@@ -9,6 +9,10 @@ import (
 
 func (m *Middleware) AuthMiddleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		if key, ok := apiKeyHeader(ctx); ok {
+			m.authenticateApiKey(ctx, key)
+			return
+		}
 		cookie, err := ctx.Request.Cookie(m.cfg.AppTokenName)
 		if err != nil {
 			m.tokenProvider.ClearTokenCookie(ctx)
@@ -27,7 +31,14 @@ func (m *Middleware) AuthMiddleware() gin.HandlerFunc {
 			ctx.Abort()
 			return
 		}
+		if !m.verifySession(ctx, claims) {
+			m.tokenProvider.ClearTokenCookie(ctx)
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: session expired or revoked"})
+			ctx.Abort()
+			return
+		}
 		ctx.Set("claims", claims)
+		m.setAuditActor(ctx, claims)
 		ctx.Next()
 	}
 }
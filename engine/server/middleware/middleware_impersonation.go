@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/gin-gonic/gin"
+)
+
+// BlockImpersonation rejects the request when an Admin is impersonating the
+// user. It guards actions that take over the account, such as changing its
+// password or email, and runs after AuthMiddleware.
+func (m *Middleware) BlockImpersonation() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		claims, _ := ctx.Get("claims")
+		if userClaims, ok := claims.(*providers.UserClaims); ok && userClaims.Impersonator != nil {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden: not allowed while impersonating"})
+			return
+		}
+		ctx.Next()
+	}
+}
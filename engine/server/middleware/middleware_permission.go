@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/managers"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionScopeKey is the context key holding the models.PermissionScope
+// granted to the current request by Permission.
+const PermissionScopeKey = "permissionScope"
+
+// Permission allows the request only if the effective permissions of the
+// authenticated user grant action on resource, and restricts the generic
+// controller to the caller's tenant within the granted scope. It must run
+// after AuthMiddleware, which sets the claims.
+func (m *Middleware) Permission(resource string, action models.PermissionAction) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		claims, exists := ctx.Get("claims")
+		userClaims, ok := claims.(*providers.UserClaims)
+		if !exists || !ok {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: claims not found"})
+			ctx.Abort()
+			return
+		}
+		scope, err := m.models.PermissionCheck(userClaims.AccountType, userClaims.ID, resource, action)
+		if errors.Is(err, models.ErrPermissionDenied) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: insufficient permissions"})
+			ctx.Abort()
+			return
+		}
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			ctx.Abort()
+			return
+		}
+		tenant, err := m.models.TenantResolve(userClaims.AccountType, userClaims.ID)
+		if errors.Is(err, models.ErrPermissionDenied) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: insufficient permissions"})
+			ctx.Abort()
+			return
+		}
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			ctx.Abort()
+			return
+		}
+		ctx.Set(PermissionScopeKey, scope)
+		if tenantScope := m.models.TenantScope(resource, tenant, scope); tenantScope != nil {
+			ctx.Set(managers.TenantScopeKey, tenantScope)
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/database/models"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// sessionTouchInterval throttles the last seen updates of a session.
+const sessionTouchInterval = time.Minute
+
+// verifySession reports whether the session of the access token is still
+// active. Sessions missing from the cache, e.g. after a restart of Redis, are
+// looked up in the session table. The use of the session is recorded at most
+// once per sessionTouchInterval, except for impersonations, which have no row.
+func (m *Middleware) verifySession(ctx *gin.Context, claims *providers.UserClaims) bool {
+	if claims.Id == "" {
+		return false
+	}
+	if !m.tokenProvider.SessionActive(claims.Id) {
+		session, err := m.models.SessionGet(claims.Id)
+		if err != nil || session == nil || session.AccountType != claims.AccountType || session.UserID != claims.ID {
+			return false
+		}
+		if err := m.tokenProvider.ActivateSession(claims.Id, time.Until(session.ExpiresAt)); err != nil {
+			m.logger.Warn("Failed to cache active session", zap.Error(err))
+		}
+	}
+
+	// Impersonation sessions live in the cache only, without a row to touch
+	if claims.Impersonator != nil {
+		return true
+	}
+
+	// The touch also catches sessions left in the cache by a revocation that
+	// failed to reach it, so they outlive their revocation by one interval at
+	// most.
+	touch, err := m.cache.Client.SetNX("session_seen:"+claims.Id, 1, sessionTouchInterval).Result()
+	if err == nil && touch {
+		err := m.models.SessionTouch(claims.Id, m.getClientIP(ctx))
+		if errors.Is(err, models.ErrSessionNotFound) {
+			if err := m.tokenProvider.RevokeSession(claims.Id); err != nil {
+				m.logger.Error("Failed to revoke stale session", zap.Error(err))
+			}
+			return false
+		}
+		if err != nil {
+			m.logger.Warn("Failed to update session last seen", zap.Error(err))
+		}
+	}
+	return true
+}
@@ -2,6 +2,8 @@ package routes
 
 import (
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/admin"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/api_key"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/audit"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/auth"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/branch"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/company"
@@ -10,11 +12,16 @@ import (
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/feedback"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/footstep"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/gender"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/leave"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/media"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/member"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/owner"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/payroll"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/permission"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/role"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/schedule"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/timesheet"
+	"github.com/Lands-Horizon-Corp/horizon-corp/internal/modules/timesheet_correction"
 	"github.com/Lands-Horizon-Corp/horizon-corp/internal/providers"
 	"github.com/gin-gonic/gin"
 )
@@ -26,20 +33,27 @@ type APIRoutes struct {
 	router        *gin.Engine
 
 	// Services
-	adminService     *admin.AdminService
-	branchService    *branch.BranchService
-	companyService   *company.CompanyService
-	authService      *auth.AuthService
-	contactService   *contact.ContactService
-	employeeService  *employee.EmployeeService
-	feedbackService  *feedback.FeedbackService
-	footstepService  *footstep.FootstepService
-	genderservice    *gender.GenderService
-	mediaService     *media.MediaService
-	memberService    *member.MemberService
-	ownerService     *owner.OwnerService
-	roleService      *role.RoleService
-	timesheetService *timesheet.TimesheetService
+	adminService               *admin.AdminService
+	apiKeyService              *api_key.ApiKeyService
+	auditService               *audit.AuditService
+	branchService              *branch.BranchService
+	companyService             *company.CompanyService
+	authService                *auth.AuthService
+	contactService             *contact.ContactService
+	employeeService            *employee.EmployeeService
+	feedbackService            *feedback.FeedbackService
+	footstepService            *footstep.FootstepService
+	genderservice              *gender.GenderService
+	leaveService               *leave.LeaveService
+	mediaService               *media.MediaService
+	memberService              *member.MemberService
+	ownerService               *owner.OwnerService
+	payrollService             *payroll.PayrollService
+	permissionService          *permission.PermissionService
+	roleService                *role.RoleService
+	scheduleService            *schedule.ScheduleService
+	timesheetService           *timesheet.TimesheetService
+	timesheetCorrectionService *timesheet_correction.TimesheetCorrectionService
 }
 
 func NewAPIRoutes(
@@ -49,6 +63,8 @@ func NewAPIRoutes(
 
 	// Services
 	adminService *admin.AdminService,
+	apiKeyService *api_key.ApiKeyService,
+	auditService *audit.AuditService,
 	branchService *branch.BranchService,
 	companyService *company.CompanyService,
 	authService *auth.AuthService,
@@ -57,11 +73,16 @@ func NewAPIRoutes(
 	feedbackService *feedback.FeedbackService,
 	footstepService *footstep.FootstepService,
 	genderservice *gender.GenderService,
+	leaveService *leave.LeaveService,
 	mediaService *media.MediaService,
 	memberService *member.MemberService,
 	ownerService *owner.OwnerService,
+	payrollService *payroll.PayrollService,
+	permissionService *permission.PermissionService,
 	roleService *role.RoleService,
+	scheduleService *schedule.ScheduleService,
 	timesheetService *timesheet.TimesheetService,
+	timesheetCorrectionService *timesheet_correction.TimesheetCorrectionService,
 
 ) *APIRoutes {
 	return &APIRoutes{
@@ -71,35 +92,50 @@ func NewAPIRoutes(
 		router:        engineService.Client,
 
 		// Services
-		adminService:     adminService,
-		branchService:    branchService,
-		companyService:   companyService,
-		authService:      authService,
-		contactService:   contactService,
-		employeeService:  employeeService,
-		feedbackService:  feedbackService,
-		footstepService:  footstepService,
-		genderservice:    genderservice,
-		mediaService:     mediaService,
-		memberService:    memberService,
-		ownerService:     ownerService,
-		roleService:      roleService,
-		timesheetService: timesheetService,
+		adminService:               adminService,
+		apiKeyService:              apiKeyService,
+		auditService:               auditService,
+		branchService:              branchService,
+		companyService:             companyService,
+		authService:                authService,
+		contactService:             contactService,
+		employeeService:            employeeService,
+		feedbackService:            feedbackService,
+		footstepService:            footstepService,
+		genderservice:              genderservice,
+		leaveService:               leaveService,
+		mediaService:               mediaService,
+		memberService:              memberService,
+		ownerService:               ownerService,
+		payrollService:             payrollService,
+		permissionService:          permissionService,
+		roleService:                roleService,
+		scheduleService:            scheduleService,
+		timesheetService:           timesheetService,
+		timesheetCorrectionService: timesheetCorrectionService,
 	}
 }
 
 func (ar *APIRoutes) API() {
 	ar.adminService.RegisterRoutes()
+	ar.apiKeyService.RegisterRoutes()
+	ar.auditService.RegisterRoutes()
 	ar.branchService.RegisterRoutes()
 	ar.companyService.RegisterRoutes()
 	ar.authService.RegisterRoutes()
 	ar.contactService.RegisterRoutes()
 	ar.employeeService.RegisterRoutes()
+	ar.feedbackService.RegisterRoutes()
 	ar.footstepService.RegisterRoutes()
 	ar.genderservice.RegisterRoutes()
+	ar.leaveService.RegisterRoutes()
 	ar.mediaService.RegisterRoutes()
 	ar.memberService.RegisterRoutes()
 	ar.ownerService.RegisterRoutes()
+	ar.payrollService.RegisterRoutes()
+	ar.permissionService.RegisterRoutes()
 	ar.roleService.RegisterRoutes()
+	ar.scheduleService.RegisterRoutes()
 	ar.timesheetService.RegisterRoutes()
+	ar.timesheetCorrectionService.RegisterRoutes()
 }